@@ -1,42 +1,71 @@
 package config
 
 import (
+	"crypto/rand"
 	"fmt"
 	"github.com/joho/godotenv"
 	"log"
 	"os"
 	"strconv"
+	"sync"
 )
 
 // Config struct holds all the configurations for the application
 type Config struct {
-	DBEnv            string
-	ServerPort       string
-	Neo4jURI         string
-	Neo4jUsername    string
-	Neo4jPassword    string
-	RedisAddress     string
-	RedisPassword    string
-	KafkaBrokers     []string
-	MinREDThreshold  int
-	MaxREDThreshold  int
-	MaxREDProb       float64
-	AESEncryptionKey []byte
+	DBEnv           string
+	ServerPort      string
+	Neo4jURI        string
+	Neo4jUsername   string
+	Neo4jPassword   string
+	RedisAddress    string
+	RedisPassword   string
+	KafkaBrokers    []string
+	MinREDThreshold int
+	MaxREDThreshold int
+	MaxREDProb      float64
+
+	// Blob storage for uploads: "local", "s3" or "memory".
+	StorageBackend    string
+	StorageDir        string
+	StorageURL        string
+	StorageSigningKey []byte
+	S3Endpoint        string
+	S3Region          string
+	S3Bucket          string
+	S3AccessKey       string
+	S3SecretKey       string
+	S3PathStyle       bool
 }
 
 // LoadConfig loads configuration values from environment variables or defaults
 func LoadConfig() Config {
-	// Load .env file if available
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables or defaults")
-	}
-
 	dbEnv := GetEnv("DB_ENV", "local")
 
 	config := Config{
-		DBEnv:            dbEnv,
-		ServerPort:       fmt.Sprintf(":%s", GetEnv("PORT", "3000")),
-		AESEncryptionKey: []byte(GetEnv("AES_ENCRYPTION_KEY", "thisis32byteslongkeyforaes256!")),
+		DBEnv:      dbEnv,
+		ServerPort: fmt.Sprintf(":%s", GetEnv("PORT", "3000")),
+
+		StorageBackend:    GetEnv("STORAGE_BACKEND", "local"),
+		StorageDir:        GetEnv("STORAGE_DIR", "/app/storage"),
+		StorageURL:        GetEnv("STORAGE_URL", "/v1/files"),
+		StorageSigningKey: []byte(GetEnv("STORAGE_SIGNING_KEY", "")),
+		S3Endpoint:        GetEnv("S3_ENDPOINT", ""),
+		S3Region:          GetEnv("S3_REGION", "us-east-1"),
+		S3Bucket:          GetEnv("S3_BUCKET", ""),
+		S3AccessKey:       GetEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:       GetEnv("S3_SECRET_KEY", ""),
+		S3PathStyle:       GetEnv("S3_PATH_STYLE", "true") == "true",
+	}
+
+	if len(config.StorageSigningKey) == 0 {
+		if !DevOrTest() {
+			log.Fatal("STORAGE_SIGNING_KEY must be set")
+		}
+		log.Println("No STORAGE_SIGNING_KEY set, signing file links with a temporary key")
+		config.StorageSigningKey = make([]byte, 32)
+		if _, err := rand.Read(config.StorageSigningKey); err != nil {
+			log.Fatalf("Failed to generate a storage signing key: %v", err)
+		}
 	}
 
 	if dbEnv == "aura" {
@@ -52,7 +81,26 @@ func LoadConfig() Config {
 	return config
 }
 
+// DevOrTest reports whether ENV marks this as a development or test run.
+// Only then may missing secrets be replaced with temporary ones; everywhere
+// else the server refuses to start without them.
+func DevOrTest() bool {
+	env := GetEnv("ENV", "")
+	return env == "dev" || env == "test"
+}
+
+var loadEnvFile sync.Once
+
+// GetEnv reads key from the environment, loading the .env file, if there is
+// one, the first time. Packages read their settings while initializing, so
+// this is the one place that is sure to run first.
 func GetEnv(key, defaultValue string) string {
+	loadEnvFile.Do(func() {
+		if err := godotenv.Load(); err != nil {
+			log.Println("No .env file found, using environment variables or defaults")
+		}
+	})
+
 	if value, exists := os.LookupEnv(key); exists {
 		return value
 	}
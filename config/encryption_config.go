@@ -0,0 +1,53 @@
+package config
+
+// EncryptionConfig picks the KeyProvider that wraps the per-record data keys
+// field data is encrypted with. Provider is "local" or "vault".
+//
+// The local provider's master keys are a comma or newline separated list of
+// version:key pairs with base64 encoded 32 byte keys, e.g. "2:<key>,1:<key>",
+// either inline in ENCRYPTION_KEYS or in the file at ENCRYPTION_KEYS_FILE.
+// The first key wraps; every other key still unwraps, which is how keys are
+// rotated. The vault provider wraps with the Vault transit key VaultKey and
+// leaves versions to Vault; any local keys are then only used to read data
+// written before envelope encryption.
+//
+// LegacyKey is the AES_ENCRYPTION_KEY data was encrypted with before keys had
+// versions; it is only used to read that data until it has been re-encrypted.
+//
+// BlindIndexKey, base64 encoded, keys the HMACs of the blind indexes that
+// make some encrypted fields searchable. Changing it orphans every index
+// until the re-encryption job has rebuilt them.
+type EncryptionConfig struct {
+	Provider      string
+	Keys          string
+	KeysFile      string
+	LegacyKey     []byte
+	BlindIndexKey string
+
+	VaultAddress string
+	VaultToken   string
+	VaultMount   string
+	VaultKey     string
+
+	// Reencrypt runs the re-encryption job in the background at startup.
+	Reencrypt bool
+}
+
+// LoadEncryptionConfig reads the encryption settings from the environment.
+func LoadEncryptionConfig() EncryptionConfig {
+	return EncryptionConfig{
+		Provider:  GetEnv("ENCRYPTION_PROVIDER", "local"),
+		Keys:      GetEnv("ENCRYPTION_KEYS", ""),
+		KeysFile:  GetEnv("ENCRYPTION_KEYS_FILE", ""),
+		LegacyKey: []byte(GetEnv("AES_ENCRYPTION_KEY", "")),
+
+		BlindIndexKey: GetEnv("BLIND_INDEX_KEY", ""),
+
+		VaultAddress: GetEnv("VAULT_ADDR", "http://127.0.0.1:8200"),
+		VaultToken:   GetEnv("VAULT_TOKEN", ""),
+		VaultMount:   GetEnv("VAULT_TRANSIT_MOUNT", "transit"),
+		VaultKey:     GetEnv("VAULT_TRANSIT_KEY", "alumni_api"),
+
+		Reencrypt: GetEnv("ENCRYPTION_REENCRYPT", "false") == "true",
+	}
+}
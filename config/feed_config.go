@@ -0,0 +1,34 @@
+package config
+
+// FeedConfig holds the weights used to rank /v1/post/feed. Every signal is
+// normalised to roughly [0, 1] before it is multiplied by its weight, so the
+// weights can be compared with each other directly.
+type FeedConfig struct {
+	Recency         float64
+	Friend          float64
+	Generation      float64
+	StudentType     float64
+	Company         float64
+	Likes           float64
+	Views           float64
+	Comments        float64
+	HalfLifeHours   float64
+	CandidateWindow int
+}
+
+// LoadFeedConfig reads the feed weights from FEED_* environment variables,
+// falling back to the defaults below.
+func LoadFeedConfig() FeedConfig {
+	return FeedConfig{
+		Recency:         getEnvAsFloat("FEED_WEIGHT_RECENCY", 3),
+		Friend:          getEnvAsFloat("FEED_WEIGHT_FRIEND", 2),
+		Generation:      getEnvAsFloat("FEED_WEIGHT_GENERATION", 1),
+		StudentType:     getEnvAsFloat("FEED_WEIGHT_STUDENT_TYPE", 0.5),
+		Company:         getEnvAsFloat("FEED_WEIGHT_COMPANY", 1),
+		Likes:           getEnvAsFloat("FEED_WEIGHT_LIKES", 0.5),
+		Views:           getEnvAsFloat("FEED_WEIGHT_VIEWS", 0.1),
+		Comments:        getEnvAsFloat("FEED_WEIGHT_COMMENTS", 0.5),
+		HalfLifeHours:   getEnvAsFloat("FEED_HALF_LIFE_HOURS", 48),
+		CandidateWindow: getEnvAsInt("FEED_CANDIDATE_WINDOW", 500),
+	}
+}
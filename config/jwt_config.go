@@ -0,0 +1,21 @@
+package config
+
+// JWTConfig holds the keys tokens are signed with. Keys is a PEM bundle,
+// either inline in JWT_KEYS (base64 encoded, to fit on one .env line) or in
+// the file at JWT_KEYS_FILE. The first private key signs; every other key in
+// the bundle, private or public, is still accepted and published in the
+// JWKS, which is how keys are rotated.
+type JWTConfig struct {
+	Keys     string
+	KeysFile string
+	Issuer   string
+}
+
+// LoadJWTConfig reads the signing keys and issuer from the environment.
+func LoadJWTConfig() JWTConfig {
+	return JWTConfig{
+		Keys:     GetEnv("JWT_KEYS", ""),
+		KeysFile: GetEnv("JWT_KEYS_FILE", ""),
+		Issuer:   GetEnv("JWT_ISSUER", "alumni_api"),
+	}
+}
@@ -0,0 +1,31 @@
+package config
+
+import "time"
+
+// LockoutConfig sets how an account is protected against password guessing,
+// independently of the IP the guesses come from. The first FreeAttempts
+// failures cost nothing; after that each attempt must wait BaseDelay, doubled
+// for every further failure up to MaxDelay. At LockThreshold failures the
+// account is locked for LockDuration. Failures older than FailureWindow are
+// forgotten.
+type LockoutConfig struct {
+	FreeAttempts  int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	LockThreshold int
+	LockDuration  time.Duration
+	FailureWindow time.Duration
+}
+
+// LoadLockoutConfig reads the lockout settings from LOGIN_* environment
+// variables.
+func LoadLockoutConfig() LockoutConfig {
+	return LockoutConfig{
+		FreeAttempts:  getEnvAsInt("LOGIN_FREE_ATTEMPTS", 3),
+		BaseDelay:     time.Duration(getEnvAsInt("LOGIN_BACKOFF_BASE_SECONDS", 2)) * time.Second,
+		MaxDelay:      time.Duration(getEnvAsInt("LOGIN_BACKOFF_MAX_SECONDS", 300)) * time.Second,
+		LockThreshold: getEnvAsInt("LOGIN_LOCK_THRESHOLD", 10),
+		LockDuration:  time.Duration(getEnvAsInt("LOGIN_LOCK_MINUTES", 30)) * time.Minute,
+		FailureWindow: time.Duration(getEnvAsInt("LOGIN_FAILURE_WINDOW_MINUTES", 60)) * time.Minute,
+	}
+}
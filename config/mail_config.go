@@ -0,0 +1,58 @@
+package config
+
+import "time"
+
+// MailConfig picks how mail is delivered and how the outbox worker retries
+// it. Driver is "smtp", "sendgrid", "file" or "memory". A mail that fails is
+// retried after BaseBackoff, doubled for every further failure up to
+// MaxBackoff, and becomes a dead letter after MaxAttempts.
+type MailConfig struct {
+	Driver      string
+	FromName    string
+	FromAddress string
+	Client      string
+
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+
+	SendGridAPIKey string
+
+	FileDir string
+
+	PollInterval time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+// LoadMailConfig reads the mail settings from MAIL_*, SMTP_* and SENDGRID_*
+// environment variables. SENDER_GMAIL and SENDGUN_API_KEY, used before the
+// settings had their own names, are still honoured.
+func LoadMailConfig() MailConfig {
+	from := GetEnv("MAIL_FROM_ADDRESS", GetEnv("SENDER_GMAIL", ""))
+
+	return MailConfig{
+		Driver:      GetEnv("MAIL_DRIVER", "smtp"),
+		FromName:    GetEnv("MAIL_FROM_NAME", "CPE Alumni"),
+		FromAddress: from,
+		Client:      GetEnv("CLIENT", "https://alumni.cpe.kmutt.ac.th"),
+
+		SMTPHost:     GetEnv("SMTP_HOST", "smtp.gmail.com"),
+		SMTPPort:     getEnvAsInt("SMTP_PORT", 587),
+		SMTPUsername: GetEnv("SMTP_USERNAME", from),
+		SMTPPassword: GetEnv("SMTP_PASSWORD", ""),
+
+		SendGridAPIKey: GetEnv("SENDGRID_API_KEY", GetEnv("SENDGUN_API_KEY", "")),
+
+		FileDir: GetEnv("MAIL_FILE_DIR", "/app/mail"),
+
+		PollInterval: time.Duration(getEnvAsInt("MAIL_POLL_SECONDS", 5)) * time.Second,
+		BatchSize:    getEnvAsInt("MAIL_BATCH_SIZE", 20),
+		MaxAttempts:  getEnvAsInt("MAIL_MAX_ATTEMPTS", 8),
+		BaseBackoff:  time.Duration(getEnvAsInt("MAIL_BACKOFF_BASE_SECONDS", 30)) * time.Second,
+		MaxBackoff:   time.Duration(getEnvAsInt("MAIL_BACKOFF_MAX_SECONDS", 3600)) * time.Second,
+	}
+}
@@ -0,0 +1,25 @@
+package config
+
+import "time"
+
+// MFAConfig holds the settings of TOTP two-factor authentication.
+type MFAConfig struct {
+	Issuer          string
+	ChallengeTTL    time.Duration
+	BackupCodeCount int
+	MaxFailures     int
+	FailureWindow   time.Duration
+}
+
+// LoadMFAConfig reads the two-factor settings from MFA_* environment
+// variables. After MaxFailures wrong codes within FailureWindow, codes are
+// refused until the window has passed.
+func LoadMFAConfig() MFAConfig {
+	return MFAConfig{
+		Issuer:          GetEnv("MFA_ISSUER", "Alumni"),
+		ChallengeTTL:    time.Duration(getEnvAsInt("MFA_CHALLENGE_TTL_SECONDS", 300)) * time.Second,
+		BackupCodeCount: getEnvAsInt("MFA_BACKUP_CODE_COUNT", 10),
+		MaxFailures:     getEnvAsInt("MFA_MAX_FAILURES", 5),
+		FailureWindow:   time.Duration(getEnvAsInt("MFA_FAILURE_WINDOW_MINUTES", 15)) * time.Minute,
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"strings"
+	"time"
+)
+
+// OIDCProviderConfig is one OpenID Connect identity provider users can sign
+// in with, such as the university SSO or Google.
+type OIDCProviderConfig struct {
+	Name         string
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OIDCConfig lists the enabled providers and how long a sign-in may take
+// between leaving for the provider and coming back with a code.
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	StateTTL  time.Duration
+}
+
+// LoadOIDCConfig reads the comma separated provider names from
+// OIDC_PROVIDERS, then each provider from OIDC_<NAME>_* variables, e.g.
+// OIDC_GOOGLE_ISSUER and OIDC_GOOGLE_CLIENT_ID. Providers without an issuer or
+// client ID are skipped.
+func LoadOIDCConfig() OIDCConfig {
+	cfg := OIDCConfig{
+		StateTTL: time.Duration(getEnvAsInt("OIDC_STATE_TTL_SECONDS", 600)) * time.Second,
+	}
+
+	for _, name := range strings.Split(GetEnv("OIDC_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		provider := OIDCProviderConfig{
+			Name:         name,
+			DisplayName:  GetEnv(prefix+"DISPLAY_NAME", name),
+			Issuer:       GetEnv(prefix+"ISSUER", ""),
+			ClientID:     GetEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: GetEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  GetEnv(prefix+"REDIRECT_URL", ""),
+			Scopes:       strings.Fields(GetEnv(prefix+"SCOPES", "openid email profile")),
+		}
+		if provider.Issuer == "" || provider.ClientID == "" {
+			continue
+		}
+
+		cfg.Providers = append(cfg.Providers, provider)
+	}
+
+	return cfg
+}
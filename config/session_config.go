@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// SessionConfig sets how long access tokens and refresh tokens live. Access
+// tokens are kept short because they are only checked against the session
+// store, not re-issued, until they expire; refresh tokens slide forward every
+// time they are rotated.
+type SessionConfig struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration
+}
+
+// LoadSessionConfig reads the token lifetimes from the environment.
+func LoadSessionConfig() SessionConfig {
+	return SessionConfig{
+		AccessTokenTTL:  time.Duration(getEnvAsInt("ACCESS_TOKEN_TTL_MINUTES", 15)) * time.Minute,
+		RefreshTokenTTL: time.Duration(getEnvAsInt("REFRESH_TOKEN_TTL_DAYS", 30)) * 24 * time.Hour,
+	}
+}
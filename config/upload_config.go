@@ -0,0 +1,18 @@
+package config
+
+import "time"
+
+// UploadConfig limits uploads and sets how long signed download URLs stay
+// valid. Where the files go is chosen by Config.StorageBackend.
+type UploadConfig struct {
+	MaxAttachmentSize int
+	SignedURLTTL      time.Duration
+}
+
+// LoadUploadConfig reads the upload settings from the environment.
+func LoadUploadConfig() UploadConfig {
+	return UploadConfig{
+		MaxAttachmentSize: getEnvAsInt("ATTACHMENT_MAX_SIZE", 10*1024*1024),
+		SignedURLTTL:      time.Duration(getEnvAsInt("SIGNED_URL_TTL_SECONDS", 300)) * time.Second,
+	}
+}
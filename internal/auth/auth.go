@@ -4,8 +4,12 @@ import (
 	"alumni_api/config"
 	"alumni_api/internal/models"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,7 +17,59 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-var jwtSecret = []byte(config.GetEnv("JWT_SECRET_KEY", "secret_key"))
+var jwtConfig = config.LoadJWTConfig()
+
+var keys = loadKeySet(jwtConfig)
+
+var sessionConfig = config.LoadSessionConfig()
+
+var mfaConfig = config.LoadMFAConfig()
+
+// Every kind of token carries its own audience and is only accepted where
+// that audience is expected, so a verification link can never be replayed as
+// a login, and the other way round.
+const (
+	AudienceLogin         = "alumni:login"
+	AudienceMFA           = "alumni:mfa"
+	AudienceRegistry      = "alumni:registry"
+	AudienceVerification  = "alumni:verification"
+	AudienceEmailChange   = "alumni:email_change"
+	AudiencePasswordReset = "alumni:password_reset"
+	AudienceOIDCState     = "alumni:oidc_state"
+)
+
+// registeredClaims are the standard claims of a new token for audience.
+func registeredClaims(audience string, ttl time.Duration) jwt.RegisteredClaims {
+	now := time.Now()
+	return jwt.RegisteredClaims{
+		Issuer:    jwtConfig.Issuer,
+		Audience:  jwt.ClaimStrings{audience},
+		ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		IssuedAt:  jwt.NewNumericDate(now),
+	}
+}
+
+// parseToken verifies a token signed by one of our keys for audience.
+func parseToken(tokenString string, claims jwt.Claims, audience string) error {
+	token, err := jwt.ParseWithClaims(tokenString, claims, keys.keyFunc,
+		jwt.WithAudience(audience),
+		jwt.WithIssuer(jwtConfig.Issuer),
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+	)
+	if err != nil {
+		return err
+	}
+	if !token.Valid {
+		return errors.New("invalid token")
+	}
+	return nil
+}
+
+// JWKS returns the public keys tokens are verified with, current and
+// retiring, for /.well-known/jwks.json.
+func JWKS() map[string]interface{} {
+	return keys.JWKS()
+}
 
 func ExtractJWT(c *fiber.Ctx) (string, bool) {
 	authHeader := c.Get("Authorization")
@@ -33,33 +89,119 @@ func ExtractJWT_Cookie(c *fiber.Ctx) (string, bool) {
 	return tokenString, true
 }
 
-// GenerateJWT generates a JWT for a user
-func GenerateJWT(userID, role string, admitYear int) (string, error) {
+// ExtractRefreshToken reads the refresh token cookie, which is only sent to
+// the /v1/auth routes.
+func ExtractRefreshToken(c *fiber.Ctx) (string, bool) {
+	token := c.Cookies("refresh_token")
+	if token == "" {
+		return "", false
+	}
+
+	return token, true
+}
+
+// GenerateJWT generates a short-lived access token for a user's session
+func GenerateJWT(userID, role, departmentID, language string, admitYear int, sessionID string) (string, error) {
 	claims := models.Claims{
-		UserID:    userID,
-		Role:      role,
-		AdmitYear: admitYear,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * time.Hour)), // Token expires in 24 hours
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+		UserID:           userID,
+		Role:             role,
+		DepartmentID:     departmentID,
+		Language:         language,
+		AdmitYear:        admitYear,
+		SessionID:        sessionID,
+		RegisteredClaims: registeredClaims(AudienceLogin, sessionConfig.AccessTokenTTL),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return keys.sign(claims)
 }
 
-// ParseJWT validates and parses a JWT
+// ParseJWT validates and parses an access token
 func ParseJWT(tokenString string) (*models.Claims, error) {
 	claims := &models.Claims{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+	if err := parseToken(tokenString, claims, AudienceLogin); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
 
-	if err != nil || !token.Valid {
+// ParseExpiredJWT checks an access token's signature and audience but not
+// its expiry, so a session can still be signed out once its access token has
+// lapsed.
+func ParseExpiredJWT(tokenString string) (*models.Claims, error) {
+	claims := &models.Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, keys.keyFunc,
+		jwt.WithoutClaimsValidation(),
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+	)
+	if err != nil {
 		return nil, err
 	}
 
+	if !slices.Contains(claims.Audience, AudienceLogin) || claims.Issuer != jwtConfig.Issuer {
+		return nil, errors.New("not an access token")
+	}
+
+	return claims, nil
+}
+
+// GenerateRefreshToken returns a new opaque refresh token for a session. The
+// session ID prefix lets the token be looked up; only its hash is stored.
+func GenerateRefreshToken(sessionID string) string {
+	return sessionID + "." + GenerateVerificationToken()
+}
+
+// ParseRefreshToken returns the session a refresh token belongs to and the
+// hash to compare with the stored one.
+func ParseRefreshToken(token string) (sessionID, hash string, ok bool) {
+	sessionID, secret, found := strings.Cut(token, ".")
+	if !found || sessionID == "" || secret == "" {
+		return "", "", false
+	}
+
+	return sessionID, HashRefreshToken(token), true
+}
+
+// HashRefreshToken hashes a refresh token for storage. The token carries 256
+// random bits, so a plain SHA-256 is enough.
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// RefreshTokenTTL is how long a refresh token, and so its session, stays
+// valid after it is issued.
+func RefreshTokenTTL() time.Duration {
+	return sessionConfig.RefreshTokenTTL
+}
+
+// AccessTokenTTL is how long an access token issued by GenerateJWT is valid.
+func AccessTokenTTL() time.Duration {
+	return sessionConfig.AccessTokenTTL
+}
+
+// GenerateMFAChallenge issues the short-lived token that stands between a
+// correct password and a session on accounts with two-factor authentication.
+func GenerateMFAChallenge(userID, purpose string) (string, error) {
+	challenge := models.MFAChallenge{
+		Purpose:          purpose,
+		RegisteredClaims: registeredClaims(AudienceMFA, mfaConfig.ChallengeTTL),
+	}
+	challenge.Subject = userID
+
+	return keys.sign(challenge)
+}
+
+func ParseMFAChallenge(tokenString string) (*models.MFAChallenge, error) {
+	claims := &models.MFAChallenge{}
+	if err := parseToken(tokenString, claims, AudienceMFA); err != nil {
+		return nil, err
+	}
+
+	if claims.Subject == "" || (claims.Purpose != "mfa" && claims.Purpose != "mfa_setup") {
+		return nil, fmt.Errorf("not an MFA challenge")
+	}
+
 	return claims, nil
 }
 
@@ -83,24 +225,16 @@ func GenerateRefNum() string {
 
 func GenerateOneTimeRegistryJWT(email string) (string, error) {
 	OTR := models.OneTimeRegistryJWT{
-		Email: email,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(72 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+		Email:            email,
+		RegisteredClaims: registeredClaims(AudienceRegistry, 72*time.Hour),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, OTR)
-	return token.SignedString(jwtSecret)
+	return keys.sign(OTR)
 }
 
 func ParseOTRJWT(tokenString string) (*models.OneTimeRegistryJWT, error) {
 	claims := &models.OneTimeRegistryJWT{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
-
-	if err != nil || !token.Valid {
+	if err := parseToken(tokenString, claims, AudienceRegistry); err != nil {
 		return nil, err
 	}
 
@@ -108,41 +242,41 @@ func ParseOTRJWT(tokenString string) (*models.OneTimeRegistryJWT, error) {
 }
 
 func GenerateVerificationJWT(userID, verifyToken string) (string, error) {
-	verify := models.Verify{
-		UserID:            userID,
-		VerificationToken: verifyToken,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(72 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
+	return generateVerify(models.Verify{UserID: userID, VerificationToken: verifyToken}, AudienceVerification)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, verify)
-	return token.SignedString(jwtSecret)
+// GenerateResetPasswordJWT issues the link of a password reset email.
+func GenerateResetPasswordJWT(userID, verifyToken string) (string, error) {
+	return generateVerify(models.Verify{UserID: userID, VerificationToken: verifyToken}, AudiencePasswordReset)
 }
 
 func GenerateVerifyEmailJWT(userID, email, verifyToken string) (string, error) {
-	verify := models.Verify{
-		UserID:            userID,
-		Email:						 email,
-		VerificationToken: verifyToken,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(72 * time.Hour)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
-	}
+	return generateVerify(models.Verify{UserID: userID, Email: email, VerificationToken: verifyToken}, AudienceEmailChange)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, verify)
-	return token.SignedString(jwtSecret)
+func generateVerify(verify models.Verify, audience string) (string, error) {
+	verify.RegisteredClaims = registeredClaims(audience, 72*time.Hour)
+	return keys.sign(verify)
 }
 
+// ParseVerification parses the link of an account verification email.
 func ParseVerification(tokenString string) (*models.Verify, error) {
-	claims := &models.Verify{}
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+	return parseVerify(tokenString, AudienceVerification)
+}
+
+// ParseResetPassword parses the link of a password reset email.
+func ParseResetPassword(tokenString string) (*models.Verify, error) {
+	return parseVerify(tokenString, AudiencePasswordReset)
+}
+
+// ParseVerifyEmail parses the link of an email change confirmation.
+func ParseVerifyEmail(tokenString string) (*models.Verify, error) {
+	return parseVerify(tokenString, AudienceEmailChange)
+}
 
-	if err != nil || !token.Valid {
+func parseVerify(tokenString, audience string) (*models.Verify, error) {
+	claims := &models.Verify{}
+	if err := parseToken(tokenString, claims, audience); err != nil {
 		return nil, err
 	}
 
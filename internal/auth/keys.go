@@ -0,0 +1,237 @@
+package auth
+
+import (
+	"alumni_api/config"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// signingKey is one entry of a KeySet. Keys loaded from a PUBLIC KEY block
+// have no private half and only verify.
+type signingKey struct {
+	kid     string
+	method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// KeySet holds every key tokens are accepted from, indexed by kid, and the
+// one new tokens are signed with.
+type KeySet struct {
+	signer *signingKey
+	keys   []*signingKey
+	byKID  map[string]*signingKey
+}
+
+// NewKeySet parses a PEM bundle of RSA (RS256) and Ed25519 (EdDSA) keys.
+// The first private key signs. Each key's kid is its RFC 7638 thumbprint, so
+// it stays the same wherever the key is loaded.
+func NewKeySet(bundle []byte) (*KeySet, error) {
+	set := &KeySet{byKID: make(map[string]*signingKey)}
+
+	for {
+		var block *pem.Block
+		block, bundle = pem.Decode(bundle)
+		if block == nil {
+			break
+		}
+
+		key, err := parseKeyBlock(block)
+		if err != nil {
+			return nil, err
+		}
+
+		if _, ok := set.byKID[key.kid]; ok {
+			continue
+		}
+		set.keys = append(set.keys, key)
+		set.byKID[key.kid] = key
+
+		if set.signer == nil && key.private != nil {
+			set.signer = key
+		}
+	}
+
+	if set.signer == nil {
+		return nil, errors.New("no private key to sign with")
+	}
+
+	return set, nil
+}
+
+// GenerateKeySet returns a set with a single new Ed25519 key.
+func GenerateKeySet() (*KeySet, error) {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKeySet(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func parseKeyBlock(block *pem.Block) (*signingKey, error) {
+	var parsed interface{}
+	var err error
+
+	switch block.Type {
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", block.Type, err)
+	}
+
+	key := &signingKey{}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", parsed)
+	}
+
+	if public, ok := key.public.(*rsa.PublicKey); ok && public.N.BitLen() < 2048 {
+		return nil, fmt.Errorf("RSA keys must be at least 2048 bits, got %d", public.N.BitLen())
+	}
+
+	key.kid = thumbprint(jwk(key))
+	return key, nil
+}
+
+// jwk is the public half of a key as a JSON Web Key, without the kid.
+func jwk(key *signingKey) map[string]string {
+	b64 := base64.RawURLEncoding.EncodeToString
+
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		return map[string]string{
+			"kty": "RSA",
+			"n":   b64(public.N.Bytes()),
+			"e":   b64(big.NewInt(int64(public.E)).Bytes()),
+		}
+	case ed25519.PublicKey:
+		return map[string]string{
+			"kty": "OKP",
+			"crv": "Ed25519",
+			"x":   b64(public),
+		}
+	}
+	return nil
+}
+
+// thumbprint is the RFC 7638 JWK thumbprint: the SHA-256 of the required
+// members in lexicographic order. encoding/json sorts map keys, which is
+// exactly that order.
+func thumbprint(members map[string]string) string {
+	canonical, _ := json.Marshal(members)
+	sum := sha256.Sum256(canonical)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// SigningKID is the kid new tokens carry.
+func (s *KeySet) SigningKID() string {
+	return s.signer.kid
+}
+
+func (s *KeySet) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(s.signer.method, claims)
+	token.Header["kid"] = s.signer.kid
+	return token.SignedString(s.signer.private)
+}
+
+// keyFunc picks the verification key named by the token's kid and refuses
+// tokens whose alg does not match that key.
+func (s *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	kid, _ := token.Header["kid"].(string)
+	key, ok := s.byKID[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+	}
+
+	return key.public, nil
+}
+
+// JWKS is the JSON Web Key Set other services verify our tokens with.
+func (s *KeySet) JWKS() map[string]interface{} {
+	keys := make([]map[string]string, 0, len(s.keys))
+	for _, key := range s.keys {
+		entry := jwk(key)
+		entry["kid"] = key.kid
+		entry["alg"] = key.method.Alg()
+		entry["use"] = "sig"
+		keys = append(keys, entry)
+	}
+
+	return map[string]interface{}{"keys": keys}
+}
+
+// loadKeySet reads the configured key bundle. Without one the server
+// refuses to start, unless ENV is dev or test: then a throwaway key is
+// generated, and every token is invalidated by a restart.
+func loadKeySet(cfg config.JWTConfig) *KeySet {
+	var bundle []byte
+
+	switch {
+	case cfg.Keys != "":
+		decoded, err := base64.StdEncoding.DecodeString(cfg.Keys)
+		if err != nil {
+			log.Fatalf("JWT_KEYS is not valid base64: %v", err)
+		}
+		bundle = decoded
+	case cfg.KeysFile != "":
+		data, err := os.ReadFile(cfg.KeysFile)
+		if err != nil {
+			log.Fatalf("Failed to read JWT_KEYS_FILE: %v", err)
+		}
+		bundle = data
+	case !config.DevOrTest():
+		log.Fatal("JWT_KEYS or JWT_KEYS_FILE must be set")
+	default:
+		log.Println("No JWT_KEYS or JWT_KEYS_FILE set, signing tokens with a temporary key")
+		set, err := GenerateKeySet()
+		if err != nil {
+			log.Fatalf("Failed to generate a JWT signing key: %v", err)
+		}
+		return set
+	}
+
+	set, err := NewKeySet(bundle)
+	if err != nil {
+		log.Fatalf("Invalid JWT signing keys: %v", err)
+	}
+	return set
+}
@@ -0,0 +1,172 @@
+package auth
+
+import (
+	"alumni_api/config"
+	"alumni_api/internal/models"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// ErrProviderUnavailable is returned when an identity provider's discovery
+// document cannot be fetched, as opposed to the sign-in itself being bad.
+var ErrProviderUnavailable = errors.New("identity provider unavailable")
+
+// OIDCProvider signs users in with the authorization code flow and PKCE.
+// The discovery document is fetched on first use rather than at startup, so
+// a provider that is down does not keep the API from starting.
+type OIDCProvider struct {
+	Name        string
+	DisplayName string
+
+	cfg      config.OIDCProviderConfig
+	stateTTL time.Duration
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// OIDCProviders are the configured providers, in configuration order.
+type OIDCProviders struct {
+	providers []*OIDCProvider
+	byName    map[string]*OIDCProvider
+}
+
+func NewOIDCProviders(cfg config.OIDCConfig) *OIDCProviders {
+	set := &OIDCProviders{byName: make(map[string]*OIDCProvider)}
+
+	for _, providerConfig := range cfg.Providers {
+		provider := &OIDCProvider{
+			Name:        providerConfig.Name,
+			DisplayName: providerConfig.DisplayName,
+			cfg:         providerConfig,
+			stateTTL:    cfg.StateTTL,
+		}
+		set.providers = append(set.providers, provider)
+		set.byName[provider.Name] = provider
+	}
+
+	return set
+}
+
+func (s *OIDCProviders) Get(name string) (*OIDCProvider, bool) {
+	provider, ok := s.byName[name]
+	return provider, ok
+}
+
+func (s *OIDCProviders) List() []*OIDCProvider {
+	return s.providers
+}
+
+func (p *OIDCProvider) discover(ctx context.Context) (*oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.provider == nil {
+		provider, err := oidc.NewProvider(ctx, p.cfg.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+		}
+		p.provider = provider
+	}
+
+	return p.provider, nil
+}
+
+func (p *OIDCProvider) oauth2Config(provider *oidc.Provider) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     p.cfg.ClientID,
+		ClientSecret: p.cfg.ClientSecret,
+		RedirectURL:  p.cfg.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.cfg.Scopes,
+	}
+}
+
+// AuthCodeURL starts a sign-in. It returns the provider URL to send the user
+// to and a signed state token that must come back with the callback.
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context) (string, string, error) {
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return "", "", err
+	}
+
+	state := models.OIDCState{
+		Provider:         p.Name,
+		State:            GenerateVerificationToken(),
+		Nonce:            GenerateVerificationToken(),
+		Verifier:         oauth2.GenerateVerifier(),
+		RegisteredClaims: registeredClaims(AudienceOIDCState, p.stateTTL),
+	}
+
+	stateToken, err := keys.sign(state)
+	if err != nil {
+		return "", "", err
+	}
+
+	url := p.oauth2Config(provider).AuthCodeURL(state.State,
+		oidc.Nonce(state.Nonce),
+		oauth2.S256ChallengeOption(state.Verifier),
+	)
+
+	return url, stateToken, nil
+}
+
+// Exchange finishes a sign-in started by AuthCodeURL: it checks the state
+// against stateToken, redeems the code with the PKCE verifier and verifies
+// the ID token it gets back, nonce included.
+func (p *OIDCProvider) Exchange(ctx context.Context, stateToken, code, state string) (models.Identity, error) {
+	saved := &models.OIDCState{}
+	if err := parseToken(stateToken, saved, AudienceOIDCState); err != nil {
+		return models.Identity{}, fmt.Errorf("invalid sign-in state: %w", err)
+	}
+
+	if saved.Provider != p.Name || subtle.ConstantTimeCompare([]byte(saved.State), []byte(state)) != 1 {
+		return models.Identity{}, errors.New("sign-in state does not match")
+	}
+
+	provider, err := p.discover(ctx)
+	if err != nil {
+		return models.Identity{}, err
+	}
+
+	token, err := p.oauth2Config(provider).Exchange(ctx, code, oauth2.VerifierOption(saved.Verifier))
+	if err != nil {
+		return models.Identity{}, fmt.Errorf("code exchange failed: %w", err)
+	}
+
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return models.Identity{}, errors.New("no id_token in token response")
+	}
+
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.cfg.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return models.Identity{}, fmt.Errorf("invalid id_token: %w", err)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(idToken.Nonce), []byte(saved.Nonce)) != 1 {
+		return models.Identity{}, errors.New("id_token nonce does not match")
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return models.Identity{}, fmt.Errorf("invalid id_token claims: %w", err)
+	}
+
+	return models.Identity{
+		Provider:      p.Name,
+		Subject:       idToken.Subject,
+		Email:         claims.Email,
+		EmailVerified: claims.EmailVerified && claims.Email != "",
+	}, nil
+}
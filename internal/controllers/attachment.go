@@ -0,0 +1,210 @@
+package controllers
+
+import (
+	"alumni_api/config"
+	"alumni_api/internal/encrypt"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/storage"
+	"alumni_api/internal/validators"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"path/filepath"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// checkAttachment makes sure a message only references an upload of its own
+// sender that has not been sent with another message yet.
+func checkAttachment(ctx context.Context, store *repositories.Store, attachmentID, senderID string, logger *zap.Logger) error {
+	if attachmentID == "" {
+		return nil
+	}
+
+	attachment, err := store.Attachment.GetAttachment(ctx, attachmentID, logger)
+	if err != nil {
+		return err
+	}
+
+	if attachment["uploader_id"] != senderID {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Attachment: %s not found", attachmentID))
+	}
+
+	if attachment["message_id"] != nil {
+		return fiber.NewError(fiber.StatusConflict, "Attachment is already attached to a message")
+	}
+
+	return nil
+}
+
+// attachmentPrefix is the storage prefix of message attachments. They are
+// only readable through DownloadAttachment or a signed URL.
+const attachmentPrefix = "attachments/"
+
+// UploadAttachment stores a file for a later message. Unlike Upload, the file
+// is not publicly served and can only be read back by the participants of
+// the message it is sent with.
+func UploadAttachment(store *repositories.Store, files storage.Storage, logger *zap.Logger) fiber.Handler {
+	cfg := config.LoadUploadConfig()
+
+	return func(c *fiber.Ctx) error {
+		id := c.Params("user_id")
+
+		if err := validators.UUID(id); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if err := validators.SameUser(c, id); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		file, err := c.FormFile("file")
+		if err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "File is required", logger, err)
+		}
+
+		if file.Size > int64(cfg.MaxAttachmentSize) {
+			return HandleFail(c, fiber.StatusRequestEntityTooLarge, "File too large", logger, nil)
+		}
+
+		contentType := file.Header.Get(fiber.HeaderContentType)
+		if contentType == "" {
+			contentType = fiber.MIMEOctetStream
+		}
+
+		src, err := file.Open()
+		if err != nil {
+			return HandleError(c, fiber.StatusInternalServerError, "Failed to save file", logger, err)
+		}
+		defer src.Close()
+
+		// The storage key never contains the client's file name, which is
+		// kept encrypted on the node instead.
+		storageKey := attachmentPrefix + uuid.New().String()
+
+		if err := files.Put(c.Context(), storageKey, src, file.Size, contentType); err != nil {
+			return HandleError(c, fiber.StatusInternalServerError, "Failed to save file", logger, err)
+		}
+
+		attachment := models.Attachment{
+			UploaderID:  id,
+			ContentType: contentType,
+			Size:        file.Size,
+			StorageKey:  storageKey,
+		}
+		attachment.FileName.Value = filepath.Base(file.Filename)
+
+		if err := encrypt.EncryptStruct(&attachment); err != nil {
+			files.Delete(c.Context(), storageKey)
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		ret, err := store.Attachment.CreateAttachment(c.Context(), attachment, logger)
+		if err != nil {
+			files.Delete(c.Context(), storageKey)
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Upload Attachment Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
+
+// accessibleAttachment loads an attachment the caller may read. Callers
+// without access get the same 404 as for a missing attachment.
+func accessibleAttachment(c *fiber.Ctx, store *repositories.Store, logger *zap.Logger) (map[string]interface{}, error) {
+	attachmentID := c.Params("attachment_id")
+
+	if err := validators.UUID(attachmentID); err != nil {
+		return nil, err
+	}
+
+	claims, ok := c.Locals("claims").(*models.Claims)
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Unauthorized claim")
+	}
+
+	allowed, err := store.Attachment.CanAccessAttachment(c.Context(), attachmentID, claims.UserID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if !allowed {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Attachment: %s not found", attachmentID))
+	}
+
+	attachment, err := store.Attachment.GetAttachment(c.Context(), attachmentID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := encrypt.DecryptMaps(attachment); err != nil {
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// DownloadAttachment streams an attachment to its uploader or to a
+// participant of the conversation it was sent in.
+func DownloadAttachment(store *repositories.Store, files storage.Storage, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		attachment, err := accessibleAttachment(c, store, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		storageKey, _ := attachment["storage_key"].(string)
+		body, obj, err := files.Get(c.Context(), storageKey)
+		if errors.Is(err, storage.ErrNotFound) {
+			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("Attachment: %s not found", attachment["attachment_id"]), logger, nil)
+		}
+		if err != nil {
+			return HandleError(c, fiber.StatusInternalServerError, "Failed to read attachment", logger, err)
+		}
+
+		fileName, _ := attachment["file_name"].(string)
+		contentType, _ := attachment["content_type"].(string)
+
+		c.Set(fiber.HeaderContentType, contentType)
+		c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": fileName}))
+		c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+
+		// SendStream closes the body once it has been written.
+		return c.SendStream(body, int(obj.Size))
+	}
+}
+
+// GetAttachmentURL hands a participant a short-lived link to the attachment,
+// for clients that cannot send the JWT along, such as an <img> tag or a
+// download manager.
+func GetAttachmentURL(store *repositories.Store, files storage.Storage, logger *zap.Logger) fiber.Handler {
+	cfg := config.LoadUploadConfig()
+
+	return func(c *fiber.Ctx) error {
+		attachment, err := accessibleAttachment(c, store, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		storageKey, _ := attachment["storage_key"].(string)
+		url, err := files.SignedURL(c.Context(), storageKey, cfg.SignedURLTTL)
+		if err != nil {
+			return HandleError(c, fiber.StatusInternalServerError, "Failed to sign attachment URL", logger, err)
+		}
+
+		ret := map[string]interface{}{
+			"url":        url,
+			"expires_at": time.Now().Add(cfg.SignedURLTTL).UnixMilli(),
+		}
+
+		successMessage := "Get Attachment URL Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
@@ -1,18 +1,18 @@
 package controllers
 
 import (
+	"alumni_api/config"
 	"alumni_api/internal/auth"
 	"alumni_api/internal/models"
 	"alumni_api/internal/repositories"
-	"alumni_api/internal/services"
 	"alumni_api/internal/validators"
+	"errors"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func VerifyToken(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func VerifyToken(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claim, ok := c.Locals("claims").(*models.Claims)
 		if !ok {
@@ -32,7 +32,9 @@ func VerifyToken(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 	}
 }
 
-func Login(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func Login(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	cfg := config.LoadLockoutConfig()
+
 	return func(c *fiber.Ctx) error {
 		var req models.LoginRequest
 
@@ -40,7 +42,7 @@ func Login(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
 			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
 		}
 
-		ok, err := services.UsernameVerify(c.Context(), driver, req.Username, logger)
+		ok, err := store.Auth.UsernameVerify(c.Context(), req.Username, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, err)
 		}
@@ -48,33 +50,42 @@ func Login(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
 			return HandleError(c, fiber.StatusUnauthorized, "User Is Not Verify", logger, err)
 		}
 
-		user, err := repositories.Login(c.Context(), driver, req.Username, logger)
+		user, err := store.Auth.Login(c.Context(), req.Username, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
 
+		throttle, err := store.Lockout.GetLoginThrottle(c.Context(), user.UserID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if err := loginThrottled(c, throttle, cfg); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
 		err = auth.CheckPasswordHash(req.Password, user.Password)
 		if err != nil {
+			if err := recordLoginFailure(c, store, user.UserID, securityEvent(c), cfg, logger); err != nil {
+				return HandleErrorWithStatus(c, err, logger)
+			}
 			return HandleError(c, fiber.StatusUnauthorized, "invalid password", logger, err)
 		}
 
-		token, err := auth.GenerateJWT(user.UserID, user.Role, int(user.AdmitYear))
-		if err != nil {
-			return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, nil)
+		if throttle.Failures > 0 {
+			if err := store.Lockout.ResetLoginFailures(c.Context(), user.UserID, logger); err != nil {
+				return HandleErrorWithStatus(c, err, logger)
+			}
 		}
 
-		c.Cookie(&fiber.Cookie{
-			Name:     "jwt",
-			Value:    token,
-			HTTPOnly: true,
-			// TODO: Turn back to strict when frontend in production
-			// Secure:   true, // Enable in production (HTTPS only)
-			// SameSite: "Strict",
-			Secure:   false,
-			SameSite: "None",
-			Path:     "/",
-			MaxAge:   24 * 60 * 60,
-		})
+		if user.MFAEnabled || user.MFARequired {
+			return mfaChallenge(c, user, logger)
+		}
+
+		token, err := startSession(c, store, user, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
 
 		ret := map[string]interface{}{
 			"token":     token,
@@ -87,24 +98,29 @@ func Login(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
 	}
 }
 
-func Logout(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+// Logout revokes the caller's session as well as clearing its cookies, so
+// the access token stops working even if it was copied elsewhere.
+func Logout(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		c.Cookie(&fiber.Cookie{
-			Name:     "jwt",
-			Value:    "",
-			HTTPOnly: true,
-			Secure:   false,
-			SameSite: "None",
-			Path:     "/",
-			MaxAge:   -1,
-		})
+		if tokenString, ok := auth.ExtractJWT_Cookie(c); ok {
+			if claims, err := auth.ParseExpiredJWT(tokenString); err == nil && claims.SessionID != "" {
+				// A session that is already revoked is as good as signed out.
+				var fe *fiber.Error
+				err := store.Session.RevokeSession(c.Context(), claims.UserID, claims.SessionID, logger)
+				if err != nil && !(errors.As(err, &fe) && fe.Code == fiber.StatusNotFound) {
+					return HandleErrorWithStatus(c, err, logger)
+				}
+			}
+		}
+
+		clearSessionCookies(c)
 		successMessage := "Logout Succesfully"
 
 		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
 	}
 }
 
-func RegistryUser(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func RegistryUser(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.RegistryRequest
 
@@ -112,7 +128,7 @@ func RegistryUser(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Hand
 			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
 		}
 
-		exists, err := services.EmailExist(c.Context(), driver, req.Email, logger)
+		exists, err := store.Auth.EmailExist(c.Context(), req.Email, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -121,17 +137,21 @@ func RegistryUser(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Hand
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("Email %s Already Exist", req.Email), logger, nil)
 		}
 
-		user, err := repositories.RegistryUser(c.Context(), driver, req, logger)
+		user, mail, err := store.Auth.RegistryUser(c.Context(), req, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
 
+		if err := enqueueMail(c, store, mail, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
 		successMessage := "Registry Succesfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, user, logger)
 	}
 }
 
-func RegistryAlumnus(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func RegistryAlumnus(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.RegistryOneTimeRequest
 
@@ -144,7 +164,7 @@ func RegistryAlumnus(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.H
 			return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, nil)
 		}
 
-		user, err := repositories.RegistryAlumnus(c.Context(), driver, req, claim.Email, logger)
+		user, err := store.Auth.RegistryAlumnus(c.Context(), req, claim.Email, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
@@ -153,30 +173,16 @@ func RegistryAlumnus(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.H
 			return HandleError(c, fiber.StatusInternalServerError, "Invalid user_id returned", logger, nil)
 		}
 
-		token, err := auth.GenerateJWT(userID, "alumnus", 0)
-		if err != nil {
-			return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, nil)
+		if _, err := startSession(c, store, models.LoginResponse{UserID: userID, Role: "alumnus"}, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		c.Cookie(&fiber.Cookie{
-			Name:     "jwt",
-			Value:    token,
-			HTTPOnly: true,
-			// TODO: Turn back to strict when frontend in production
-			// Secure:   true, // Enable in production (HTTPS only)
-			// SameSite: "Strict",
-			Secure:   false,
-			SameSite: "None",
-			Path:     "/",
-			MaxAge:   24 * 60 * 60,
-		})
-
 		successMessage := "Registry Succesfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
 	}
 }
 
-func VerifyAccount(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func VerifyAccount(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.TokenVerify
 		if err := validators.Request(c, &req); err != nil {
@@ -188,7 +194,7 @@ func VerifyAccount(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Han
 			return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, nil)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, claim.UserID, logger)
+		exists, err := store.User.UserExist(c.Context(), claim.UserID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -197,35 +203,26 @@ func VerifyAccount(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Han
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", claim.UserID), logger, nil)
 		}
 
-		data, err := repositories.VerifyAccount(c.Context(), driver, claim.UserID, claim.VerificationToken, logger)
+		data, err := store.Auth.VerifyAccount(c.Context(), claim.UserID, claim.VerificationToken, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
 
 		Username, _ := data["username"]
 
-		user, err := repositories.Login(c.Context(), driver, Username.(string), logger)
+		user, err := store.Auth.Login(c.Context(), Username.(string), logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
 
-		JWT, err := auth.GenerateJWT(user.UserID, user.Role, int(user.AdmitYear))
-		if err != nil {
-			return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, nil)
+		if user.MFAEnabled || user.MFARequired {
+			return mfaChallenge(c, user, logger)
 		}
 
-		c.Cookie(&fiber.Cookie{
-			Name:     "jwt",
-			Value:    JWT,
-			HTTPOnly: true,
-			// TODO: Turn back to strict when frontend in production
-			// Secure:   true, // Enable in production (HTTPS only)
-			// SameSite: "Strict",
-			Secure:   false,
-			SameSite: "None",
-			Path:     "/",
-			MaxAge:   24 * 60 * 60,
-		})
+		JWT, err := startSession(c, store, user, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
 
 		ret := map[string]interface{}{
 			"token":     JWT,
@@ -239,7 +236,7 @@ func VerifyAccount(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Han
 	}
 }
 
-func RequestChangePassword(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func RequestChangePassword(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.EmailRequest
 
@@ -247,17 +244,21 @@ func RequestChangePassword(driver neo4j.DriverWithContext, logger *zap.Logger) f
 			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
 		}
 
-		data, err := repositories.RequestChangePassword(c.Context(), driver, req.Email, logger)
+		data, mail, err := store.Auth.RequestChangePassword(c.Context(), req.Email, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
 
+		if err := enqueueMail(c, store, mail, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
 		successMessage := "Request Reset Password Succesfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, data, logger)
 	}
 }
 
-func ChangePassword(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func ChangePassword(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.ResetPassword
 
@@ -265,12 +266,12 @@ func ChangePassword(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
 		}
 
-		claim, err := auth.ParseVerification(req.ResetJWT)
+		claim, err := auth.ParseResetPassword(req.ResetJWT)
 		if err != nil {
 			return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, nil)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, claim.UserID, logger)
+		exists, err := store.User.UserExist(c.Context(), claim.UserID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -279,27 +280,24 @@ func ChangePassword(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", claim.UserID), logger, nil)
 		}
 
-		err = repositories.ChangePassword(c.Context(), driver, claim.UserID, req.Password, claim.VerificationToken, logger)
+		err = store.Auth.ChangePassword(c.Context(), claim.UserID, req.Password, claim.VerificationToken, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
 
-		c.Cookie(&fiber.Cookie{
-			Name:     "jwt",
-			Value:    "",
-			HTTPOnly: true,
-			Secure:   false,
-			SameSite: "None",
-			Path:     "/",
-			MaxAge:   -1,
-		})
+		// A new password signs out every device that knew the old one.
+		if err := store.Session.RevokeAllSessions(c.Context(), claim.UserID, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		clearSessionCookies(c)
 
 		successMessage := "Change Password Succesfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
 	}
 }
 
-func RequestChangeEmail(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func RequestChangeEmail(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.EmailRequest
 
@@ -312,7 +310,7 @@ func RequestChangeEmail(driver neo4j.DriverWithContext, logger *zap.Logger) fibe
 			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
 		}
 
-		exists, err := services.EmailExist(c.Context(), driver, req.Email, logger)
+		exists, err := store.Auth.EmailExist(c.Context(), req.Email, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -321,7 +319,7 @@ func RequestChangeEmail(driver neo4j.DriverWithContext, logger *zap.Logger) fibe
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("Email %s Already Exist", req.Email), logger, nil)
 		}
 
-		exists, err = services.UserExist(c.Context(), driver, claim.UserID, logger)
+		exists, err = store.User.UserExist(c.Context(), claim.UserID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -330,26 +328,30 @@ func RequestChangeEmail(driver neo4j.DriverWithContext, logger *zap.Logger) fibe
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", claim.UserID), logger, nil)
 		}
 
-		data, err := repositories.RequestChangeMail(c.Context(), driver, claim.UserID, req.Email, logger)
+		data, mail, err := store.Auth.RequestChangeMail(c.Context(), claim.UserID, req.Email, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
 
+		if err := enqueueMail(c, store, mail, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
 		successMessage := "Request Reset Password Succesfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, data, logger)
 	}
 }
 
-func VerifyEmail(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func VerifyEmail(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		token := c.Query("token")
 
-		claim, err := auth.ParseVerification(token)
+		claim, err := auth.ParseVerifyEmail(token)
 		if err != nil {
 			return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, nil)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, claim.UserID, logger)
+		exists, err := store.User.UserExist(c.Context(), claim.UserID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -358,7 +360,7 @@ func VerifyEmail(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", claim.UserID), logger, nil)
 		}
 
-		err = repositories.VerifyEmail(c.Context(), driver, claim.UserID, claim.Email, claim.VerificationToken, logger)
+		err = store.Auth.VerifyEmail(c.Context(), claim.UserID, claim.Email, claim.VerificationToken, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
@@ -368,7 +370,7 @@ func VerifyEmail(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 	}
 }
 
-func RequestAlumniOneTimeRegistry(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func RequestAlumniOneTimeRegistry(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.EmailRequest
 
@@ -376,23 +378,28 @@ func RequestAlumniOneTimeRegistry(driver neo4j.DriverWithContext, logger *zap.Lo
 			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
 		}
 
-		data, err := repositories.RequestAlumniOneTimeRegistry(c.Context(), driver, req.Email, logger)
+		data, mail, err := store.Auth.RequestAlumniOneTimeRegistry(c.Context(), req.Email, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
 
+		if err := enqueueMail(c, store, mail, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
 		successMessage := "If this email match in the database the one time request will be send to your email"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, data, logger)
 	}
 }
 
-func GetAllRequest(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetAllRequest(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		if err := validators.UserAdmin(c); err != nil {
+		scope, err := DepartmentScope(c, store, logger)
+		if err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		data, err := repositories.GetAllRequest(c.Context(), driver, logger)
+		data, err := store.Auth.GetAllRequest(c.Context(), scope, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
@@ -402,14 +409,14 @@ func GetAllRequest(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Han
 	}
 }
 
-func RequestAlumnusRole(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func RequestAlumnusRole(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claim, ok := c.Locals("claims").(*models.Claims)
 		if !ok {
 			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, claim.UserID, logger)
+		exists, err := store.User.UserExist(c.Context(), claim.UserID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -418,7 +425,7 @@ func RequestAlumnusRole(driver neo4j.DriverWithContext, logger *zap.Logger) fibe
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", claim.UserID), logger, nil)
 		}
 
-		isApproved, err := services.IsRequestApproved(c.Context(), driver, claim.UserID, logger)
+		isApproved, err := store.Auth.IsRequestApproved(c.Context(), claim.UserID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -427,7 +434,7 @@ func RequestAlumnusRole(driver neo4j.DriverWithContext, logger *zap.Logger) fibe
 			return HandleFail(c, fiber.StatusBadRequest, "Already Approved Request", logger, nil)
 		}
 
-		err = repositories.RequestAlumnusRole(c.Context(), driver, claim.UserID, logger)
+		err = store.Auth.RequestAlumnusRole(c.Context(), claim.UserID, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
 		}
@@ -437,7 +444,7 @@ func RequestAlumnusRole(driver neo4j.DriverWithContext, logger *zap.Logger) fibe
 	}
 }
 
-func ApproveAlumnusRole(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func ApproveAlumnusRole(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		request_id := c.Params("request_id")
 
@@ -445,13 +452,14 @@ func ApproveAlumnusRole(driver neo4j.DriverWithContext, logger *zap.Logger) fibe
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		if err := validators.UserAdmin(c); err != nil {
+		scope, err := DepartmentScope(c, store, logger)
+		if err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err := repositories.ApproveAlumnusRole(c.Context(), driver, request_id, logger)
+		err = store.Auth.ApproveAlumnusRole(c.Context(), request_id, scope, logger)
 		if err != nil {
-			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
+			return HandleErrorWithStatus(c, err, logger)
 		}
 
 		successMessage := "Request Email Checkup Succesfully"
@@ -459,7 +467,7 @@ func ApproveAlumnusRole(driver neo4j.DriverWithContext, logger *zap.Logger) fibe
 	}
 }
 
-func RejectAlumnusRole(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func RejectAlumnusRole(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		request_id := c.Params("request_id")
 
@@ -467,13 +475,14 @@ func RejectAlumnusRole(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		if err := validators.UserAdmin(c); err != nil {
+		scope, err := DepartmentScope(c, store, logger)
+		if err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err := repositories.RejectAlumnusRole(c.Context(), driver, request_id, logger)
+		err = store.Auth.RejectAlumnusRole(c.Context(), request_id, scope, logger)
 		if err != nil {
-			return HandleError(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
+			return HandleErrorWithStatus(c, err, logger)
 		}
 
 		successMessage := "Request Email Checkup Succesfully"
@@ -4,20 +4,18 @@ import (
 	"alumni_api/internal/encrypt"
 	"alumni_api/internal/models"
 	"alumni_api/internal/repositories"
-	"alumni_api/internal/services"
 	"alumni_api/internal/validators"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func CompanyFullTextSearch(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func CompanyFullTextSearch(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		query := c.Query("query")
 
-		users, err := repositories.CompanyFullTextSearch(c.Context(), driver, query, logger)
+		users, err := store.Company.CompanyFullTextSearch(c.Context(), query, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -27,7 +25,7 @@ func CompanyFullTextSearch(driver neo4j.DriverWithContext, logger *zap.Logger) f
 	}
 }
 
-func AddUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func AddUserCompany(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.UserRequestCompany
 
@@ -41,7 +39,7 @@ func AddUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -50,22 +48,20 @@ func AddUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", id), logger, nil)
 		}
 
-		err1 := validators.SameUser(c, id)
-		err2 := validators.UserAdmin(c)
-		if err1 != nil && err2 != nil {
-			return HandleFailWithStatus(c, err1, logger)
+		if err := ownerOr(c, store, id, models.PermissionProfileEditAny, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.EncryptStruct(&req, models.CompanyEncryptField); err != nil {
+		if err := encrypt.EncryptStruct(&req); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		companies, err := repositories.AddUserCompany(c.Context(), driver, id, req, logger)
+		companies, err := store.Company.AddUserCompany(c.Context(), id, req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.DecryptMaps(companies, models.CompanyDecryptField); err != nil {
+		if err := encrypt.DecryptMaps(companies); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
@@ -74,7 +70,7 @@ func AddUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 	}
 }
 
-func UpdateUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func UpdateUserCompany(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.UserCompanyUpdateRequest
 
@@ -88,7 +84,7 @@ func UpdateUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, userID, logger)
+		exists, err := store.User.UserExist(c.Context(), userID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -101,11 +97,11 @@ func UpdateUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.EncryptStruct(&req, models.CompanyEncryptField); err != nil {
+		if err := encrypt.EncryptStruct(&req); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.UpdateUserCompany(c.Context(), driver, userID, companyID, req, logger)
+		err = store.Company.UpdateUserCompany(c.Context(), userID, companyID, req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -115,7 +111,7 @@ func UpdateUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 	}
 }
 
-func DeleteUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func DeleteUserCompany(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userID := c.Params("user_id")
 		companyID := c.Params("company_id")
@@ -123,7 +119,7 @@ func DeleteUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, userID, logger)
+		exists, err := store.User.UserExist(c.Context(), userID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -136,7 +132,7 @@ func DeleteUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.DeleteUserCompany(c.Context(), driver, userID, companyID, logger)
+		err = store.Company.DeleteUserCompany(c.Context(), userID, companyID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -145,7 +141,7 @@ func DeleteUserCompany(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 	}
 }
 
-func FindCompanyAssociate(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func FindCompanyAssociate(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.Company
 
@@ -153,15 +149,26 @@ func FindCompanyAssociate(driver neo4j.DriverWithContext, logger *zap.Logger) fi
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.EncryptStruct(&req, models.CompanyEncryptField); err != nil {
+		if err := encrypt.EncryptStruct(&req); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		viewer, err := viewerOf(c, store, logger)
+		if err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		users, err := repositories.FindCompanyAssociate(c.Context(), driver, req, logger)
+		users, err := store.Company.FindCompanyAssociate(c.Context(), req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
+		// Working at the company is part of the career group.
+		users = viewer.matchedVisibly(users, []string{models.PrivacyCareer})
+		if err := serializeProfiles(c, store, viewer, users, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
 		successMessage := "Find Associate Users successfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, users, logger)
 	}
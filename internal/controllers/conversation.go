@@ -0,0 +1,481 @@
+package controllers
+
+import (
+	"alumni_api/internal/encrypt"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/validators"
+	"alumni_api/internal/websockets"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// AuthorizeRoom admits members of a group conversation to its room, and the
+// two users of a direct chat to theirs if they are friends or already share
+// a conversation.
+func AuthorizeRoom(store *repositories.Store, logger *zap.Logger) func(userID, room string) bool {
+	return func(userID, room string) bool {
+		conversationID, ok := websockets.ConversationID(room)
+		if !ok {
+			peerID, ok := websockets.DirectRoomPeer(userID, room)
+			if !ok {
+				return false
+			}
+			if peerID == userID {
+				return true
+			}
+
+			friends, err := friendIDs(context.Background(), store, userID, logger)
+			if err != nil {
+				return false
+			}
+			if slices.Contains(friends, peerID) {
+				return true
+			}
+
+			shared, err := store.Conversation.ShareConversation(context.Background(), userID, peerID, logger)
+			return err == nil && shared
+		}
+
+		role, err := store.Conversation.GetMemberRole(context.Background(), conversationID, userID, logger)
+		return err == nil && role != ""
+	}
+}
+
+// memberRole returns the caller's role in the conversation. Non-members get
+// a 404 so conversation IDs cannot be probed.
+func memberRole(c *fiber.Ctx, store *repositories.Store, conversationID string, logger *zap.Logger) (string, *models.Claims, error) {
+	claims, ok := c.Locals("claims").(*models.Claims)
+	if !ok {
+		return "", nil, fiber.NewError(fiber.StatusUnauthorized, "Unauthorized claim")
+	}
+
+	if err := validators.UUID(conversationID); err != nil {
+		return "", nil, err
+	}
+
+	role, err := store.Conversation.GetMemberRole(c.Context(), conversationID, claims.UserID, logger)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if role == "" {
+		return "", nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Conversation: %s not found", conversationID))
+	}
+
+	return role, claims, nil
+}
+
+// publishToMembers sends a frame to the conversation room and to every
+// member, so members who have not subscribed yet still hear about it.
+func publishToMembers(ctx context.Context, store *repositories.Store, hub *websockets.Hub, conversationID, frameType string, payload interface{}, logger *zap.Logger) {
+	memberIDs, err := store.Conversation.GetMemberIDs(ctx, conversationID, logger)
+	if err != nil {
+		logger.Error("Failed to load conversation members", zap.Error(err))
+		return
+	}
+
+	env, err := websockets.NewEnvelope(frameType, payload)
+	if err != nil {
+		logger.Error("Failed to encode conversation frame", zap.Error(err))
+		return
+	}
+
+	hub.Publish(websockets.ConversationRoom(conversationID), env, memberIDs...)
+}
+
+// conversationChanged reloads the conversation and pushes it to its members.
+func conversationChanged(c *fiber.Ctx, store *repositories.Store, hub *websockets.Hub, conversationID string, logger *zap.Logger) (map[string]interface{}, error) {
+	conv, err := store.Conversation.GetConversationByID(c.Context(), conversationID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	publishToMembers(c.Context(), store, hub, conversationID, websockets.TypeConversation, conv, logger)
+	return conv, nil
+}
+
+func CreateConversation(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claim, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		var req models.Conversation
+
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		data, err := store.Conversation.CreateConversation(c.Context(), claim.UserID, req, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		conv, err := conversationChanged(c, store, hub, data["conversation_id"].(string), logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Create Conversation Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, conv, logger)
+	}
+}
+
+func GetConversationByID(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conversationID := c.Params("conversation_id")
+
+		if _, _, err := memberRole(c, store, conversationID, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		conv, err := store.Conversation.GetConversationByID(c.Context(), conversationID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Get Conversation Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, conv, logger)
+	}
+}
+
+func UpdateConversation(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conversationID := c.Params("conversation_id")
+
+		role, _, err := memberRole(c, store, conversationID, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := validators.ConversationRole(role, models.ConversationOwner, models.ConversationAdmin); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		var req models.UpdateConversationRequest
+
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.Conversation.UpdateConversation(c.Context(), conversationID, req.Title, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		conv, err := conversationChanged(c, store, hub, conversationID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Update Conversation Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, conv, logger)
+	}
+}
+
+func InviteConversationMembers(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conversationID := c.Params("conversation_id")
+
+		role, _, err := memberRole(c, store, conversationID, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := validators.ConversationRole(role, models.ConversationOwner, models.ConversationAdmin); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		var req models.ConversationInviteRequest
+
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		for _, userID := range req.UserIDs {
+			exists, err := store.User.UserExist(c.Context(), userID, logger)
+			if err != nil {
+				return HandleErrorWithStatus(c, err, logger)
+			}
+
+			if !exists {
+				return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", userID), logger, nil)
+			}
+		}
+
+		if err := store.Conversation.AddMembers(c.Context(), conversationID, req.UserIDs, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		conv, err := conversationChanged(c, store, hub, conversationID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Invite Members Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, conv, logger)
+	}
+}
+
+func SetConversationRole(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conversationID := c.Params("conversation_id")
+		userID := c.Params("user_id")
+
+		role, claims, err := memberRole(c, store, conversationID, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := validators.ConversationRole(role, models.ConversationOwner); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := validators.UUID(userID); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if userID == claims.UserID {
+			return HandleFail(c, fiber.StatusBadRequest, "The owner cannot change their own role", logger, nil)
+		}
+
+		var req models.ConversationRoleRequest
+
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		targetRole, err := store.Conversation.GetMemberRole(c.Context(), conversationID, userID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if targetRole == "" {
+			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s is not a member", userID), logger, nil)
+		}
+
+		if err := store.Conversation.SetMemberRole(c.Context(), conversationID, userID, req.Role, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		conv, err := conversationChanged(c, store, hub, conversationID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Update Member Role Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, conv, logger)
+	}
+}
+
+// removeMember drops the user from the conversation and its room. The
+// returned conversation is nil when the last member left and it was deleted.
+func removeMember(c *fiber.Ctx, store *repositories.Store, hub *websockets.Hub, conversationID, userID string, logger *zap.Logger) (map[string]interface{}, error) {
+	if err := store.Conversation.RemoveMember(c.Context(), conversationID, userID, logger); err != nil {
+		return nil, err
+	}
+
+	hub.Evict(websockets.ConversationRoom(conversationID), userID)
+
+	memberIDs, err := store.Conversation.GetMemberIDs(c.Context(), conversationID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(memberIDs) == 0 {
+		return nil, nil
+	}
+
+	return conversationChanged(c, store, hub, conversationID, logger)
+}
+
+func LeaveConversation(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conversationID := c.Params("conversation_id")
+
+		_, claims, err := memberRole(c, store, conversationID, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		conv, err := removeMember(c, store, hub, conversationID, claims.UserID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Leave Conversation Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, conv, logger)
+	}
+}
+
+// RemoveConversationMember lets the owner remove anyone and admins remove
+// plain members.
+func RemoveConversationMember(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conversationID := c.Params("conversation_id")
+		userID := c.Params("user_id")
+
+		role, claims, err := memberRole(c, store, conversationID, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := validators.UUID(userID); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if userID != claims.UserID {
+			targetRole, err := store.Conversation.GetMemberRole(c.Context(), conversationID, userID, logger)
+			if err != nil {
+				return HandleErrorWithStatus(c, err, logger)
+			}
+
+			if targetRole == "" {
+				return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s is not a member", userID), logger, nil)
+			}
+
+			allowed := []string{models.ConversationOwner}
+			if targetRole == models.ConversationMember {
+				allowed = append(allowed, models.ConversationAdmin)
+			}
+
+			if err := validators.ConversationRole(role, allowed...); err != nil {
+				return HandleFailWithStatus(c, err, logger)
+			}
+		}
+
+		conv, err := removeMember(c, store, hub, conversationID, userID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Remove Member Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, conv, logger)
+	}
+}
+
+func SendGroupMessage(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conversationID := c.Params("conversation_id")
+
+		_, claims, err := memberRole(c, store, conversationID, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		var req models.GroupMessage
+
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		req.ConversationID = conversationID
+		req.SenderID = claims.UserID
+
+		if err := checkAttachment(c.Context(), store, req.AttachmentID, req.SenderID, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := encrypt.EncryptStruct(&req); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		msg, err := store.Conversation.SendGroupMessage(c.Context(), req, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if err := encrypt.DecryptMaps(msg); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		publishToMembers(c.Context(), store, hub, conversationID, websockets.TypeMessage, msg, logger)
+
+		successMessage := "Send Message Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, msg, logger)
+	}
+}
+
+func GetGroupMessages(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		conversationID := c.Params("conversation_id")
+
+		if _, _, err := memberRole(c, store, conversationID, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		page, err := validators.Page(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		messages, nextCursor, err := store.Conversation.GetGroupMessages(c.Context(), conversationID, page, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if err := encrypt.DecryptMaps(messages); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		successMessage := "Get Messages Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, pageData(messages, nextCursor), logger)
+	}
+}
+
+// JoinEventChat adds the caller to the group chat of an event post they can
+// see.
+func JoinEventChat(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claim, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		postID := c.Params("post_id")
+
+		if err := validators.UUID(postID); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		visibility, err := postVisibility(c, store, claim, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		visible, err := store.Post.PostVisible(c.Context(), postID, claim.UserID, visibility, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if !visible {
+			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("Post: %s not found", postID), logger, nil)
+		}
+
+		conversationID, err := store.Conversation.GetEventConversationID(c.Context(), postID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if conversationID == "" {
+			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("Post: %s has no group chat", postID), logger, nil)
+		}
+
+		if err := store.Conversation.AddMembers(c.Context(), conversationID, []string{claim.UserID}, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		conv, err := conversationChanged(c, store, hub, conversationID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Join Event Chat Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, conv, logger)
+	}
+}
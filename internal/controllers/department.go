@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/validators"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+func GetDepartments(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		departments, err := store.Department.GetDepartments(c.Context(), logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Get Departments Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, departments, logger)
+	}
+}
+
+// departmentAdmin reads the department and user of an appointment route.
+// Only callers of global scope appoint department admins.
+func departmentAdmin(c *fiber.Ctx, store *repositories.Store, logger *zap.Logger) (string, string, error) {
+	departmentID, userID := c.Params("department_id"), c.Params("user_id")
+
+	scope, err := DepartmentScope(c, store, logger)
+	if err != nil {
+		return "", "", err
+	}
+	if scope != "" {
+		return "", "", fiber.NewError(fiber.StatusForbidden, "Department admins cannot appoint department admins")
+	}
+
+	if err := validators.UUID(departmentID); err != nil {
+		return "", "", err
+	}
+
+	if err := validators.UUID(userID); err != nil {
+		return "", "", err
+	}
+
+	return departmentID, userID, nil
+}
+
+// AppointDepartmentAdmin scopes the user's department admin permissions to
+// the department. Their sessions are signed out so the next access token
+// carries the department.
+func AppointDepartmentAdmin(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		departmentID, userID, err := departmentAdmin(c, store, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.Department.AppointDepartmentAdmin(c.Context(), departmentID, userID, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.Session.RevokeAllSessions(c.Context(), userID, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Appoint Department Admin Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
+
+func RemoveDepartmentAdmin(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		departmentID, userID, err := departmentAdmin(c, store, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.Department.RemoveDepartmentAdmin(c.Context(), departmentID, userID, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.Session.RevokeAllSessions(c.Context(), userID, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Remove Department Admin Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
@@ -3,16 +3,14 @@ package controllers
 import (
 	"alumni_api/internal/models"
 	"alumni_api/internal/repositories"
-	"alumni_api/internal/services"
 	"alumni_api/internal/validators"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func GetUserFriendByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetUserFriendByID(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id := c.Params("id")
 
@@ -20,7 +18,7 @@ func GetUserFriendByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -29,7 +27,7 @@ func GetUserFriendByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", id), logger, nil)
 		}
 
-		user, err := repositories.GetUserFriendByID(c.Context(), driver, id, logger)
+		user, err := store.Friend.GetUserFriendByID(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -44,7 +42,7 @@ func GetUserFriendByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 	}
 }
 
-func AddFriend(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func AddFriend(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.UserFriendRequest
 		userID1 := c.Params("id")
@@ -53,7 +51,7 @@ func AddFriend(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, userID1, logger)
+		exists, err := store.User.UserExist(c.Context(), userID1, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -72,7 +70,7 @@ func AddFriend(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler
 
 		userID2 := req.UserID
 
-		exists, err = services.UserExist(c.Context(), driver, userID2, logger)
+		exists, err = store.User.UserExist(c.Context(), userID2, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -81,7 +79,7 @@ func AddFriend(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", userID2), logger, nil)
 		}
 
-		err = repositories.AddFriend(c.Context(), driver, userID1, userID2, logger)
+		err = store.Friend.AddFriend(c.Context(), userID1, userID2, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -91,7 +89,7 @@ func AddFriend(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler
 	}
 }
 
-func Unfriend(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func Unfriend(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.UserFriendRequest
 		userID1 := c.Params("id")
@@ -100,7 +98,7 @@ func Unfriend(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, userID1, logger)
+		exists, err := store.User.UserExist(c.Context(), userID1, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -119,7 +117,7 @@ func Unfriend(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler
 
 		userID2 := req.UserID
 
-		exists, err = services.UserExist(c.Context(), driver, userID2, logger)
+		exists, err = store.User.UserExist(c.Context(), userID2, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -128,7 +126,7 @@ func Unfriend(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", userID2), logger, nil)
 		}
 
-		err = repositories.Unfriend(c.Context(), driver, userID1, userID2, logger)
+		err = store.Friend.Unfriend(c.Context(), userID1, userID2, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -138,7 +136,7 @@ func Unfriend(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler
 	}
 }
 
-func GetFOAF(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetFOAF(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.UserFOAFRequest
 
@@ -153,7 +151,7 @@ func GetFOAF(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
 		// 	return HandleFailWithStatus(c, err, logger)
 		// }
 
-		exists, err := services.UserExist(c.Context(), driver, user_id, logger)
+		exists, err := store.User.UserExist(c.Context(), user_id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -162,7 +160,7 @@ func GetFOAF(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", user_id), logger, nil)
 		}
 
-		exists, err = services.UserExist(c.Context(), driver, other_id, logger)
+		exists, err = store.User.UserExist(c.Context(), other_id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -180,8 +178,17 @@ func GetFOAF(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
 			degree = 3
 		}
 
-		foaf, err := repositories.GetFOAF(c.Context(), driver, user_id, other_id, degree, logger)
+		viewer, err := viewerOf(c, store, logger)
 		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		foaf, err := store.Friend.GetFOAF(c.Context(), user_id, other_id, degree, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if err := serializeProfiles(c, store, viewer, foaf, logger); err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
@@ -1,18 +1,21 @@
 package controllers
 
 import (
+	"alumni_api/internal/i18n"
 	"errors"
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 )
 
+// HandleSuccess sends a JSend success. Like the other Handle functions it
+// logs message in English and sends it in the caller's Language.
 func HandleSuccess(c *fiber.Ctx, statusCode int, message string, data interface{}, logger *zap.Logger) error {
 	logger.Info(message)
 
 	c.Locals("message", message)
 	return c.Status(statusCode).JSON(fiber.Map{
 		"status":  "success",
-		"message": message,
+		"message": i18n.T(Language(c), message),
 		"data":    data,
 	})
 }
@@ -27,7 +30,7 @@ func HandleError(c *fiber.Ctx, statusCode int, message string, logger *zap.Logge
 	c.Locals("message", message)
 	return c.Status(statusCode).JSON(fiber.Map{
 		"status":  "error",
-		"message": message,
+		"message": i18n.T(Language(c), message),
 		"data":    nil,
 	})
 }
@@ -42,7 +45,7 @@ func HandleFail(c *fiber.Ctx, statusCode int, message string, logger *zap.Logger
 	c.Locals("message", message)
 	return c.Status(statusCode).JSON(fiber.Map{
 		"status":  "Fail",
-		"message": message,
+		"message": i18n.T(Language(c), message),
 		"data":    nil,
 	})
 }
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"alumni_api/internal/i18n"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/validators"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Language picks the language to answer the caller in: the preference
+// stored on their account, carried in their access token, or else the best
+// match for their Accept-Language header.
+func Language(c *fiber.Ctx) string {
+	if claims, ok := c.Locals("claims").(*models.Claims); ok && i18n.Supported(claims.Language) {
+		return claims.Language
+	}
+
+	if language := c.AcceptsLanguages(i18n.Languages...); language != "" {
+		return language
+	}
+	return i18n.Default
+}
+
+// SetLanguage stores the caller's language preference. Their access token
+// carries the old one until it is refreshed, so this response and the next
+// refresh are the first to follow it.
+func SetLanguage(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		var req models.LanguageRequest
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
+		}
+
+		if err := store.User.SetLanguage(c.Context(), claims.UserID, req.Language, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		claims.Language = req.Language
+
+		successMessage := "Update Language Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, req, logger)
+	}
+}
@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"alumni_api/config"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/validators"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// securityEventLimit is how many security events GetSecurityEvents returns.
+const securityEventLimit = 50
+
+func securityEvent(c *fiber.Ctx) models.SecurityEvent {
+	return models.SecurityEvent{
+		IP:        c.IP(),
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+	}
+}
+
+// loginBackoff is how long after its last failure an account with failures
+// recent failures has to wait before the next attempt.
+func loginBackoff(failures int64, cfg config.LockoutConfig) time.Duration {
+	extra := failures - int64(cfg.FreeAttempts)
+	if extra < 0 {
+		return 0
+	}
+
+	delay := float64(cfg.BaseDelay) * math.Pow(2, float64(extra))
+	if delay > float64(cfg.MaxDelay) {
+		return cfg.MaxDelay
+	}
+	return time.Duration(delay)
+}
+
+func retryAfter(c *fiber.Ctx, until time.Time) {
+	seconds := int(math.Ceil(time.Until(until).Seconds()))
+	c.Set(fiber.HeaderRetryAfter, strconv.Itoa(max(seconds, 1)))
+}
+
+// loginThrottled refuses an attempt while the account is locked or still
+// waiting out its backoff. It runs before the password is checked, so the
+// answer is the same whether the guess was right or not.
+func loginThrottled(c *fiber.Ctx, throttle models.LoginThrottle, cfg config.LockoutConfig) error {
+	now := time.Now()
+
+	if lockedUntil := time.UnixMilli(throttle.LockedUntil); lockedUntil.After(now) {
+		retryAfter(c, lockedUntil)
+		return fiber.NewError(fiber.StatusLocked, "Account is temporarily locked")
+	}
+
+	failed := time.UnixMilli(throttle.FailedTimestamp)
+	if failed.Before(now.Add(-cfg.FailureWindow)) {
+		return nil
+	}
+
+	if next := failed.Add(loginBackoff(throttle.Failures, cfg)); next.After(now) {
+		retryAfter(c, next)
+		return fiber.NewError(fiber.StatusTooManyRequests, "Too many failed logins, try again later")
+	}
+
+	return nil
+}
+
+// recordLoginFailure counts a wrong password and locks the account once it
+// reaches the threshold. The owner is told by email; failing to queue it
+// does not undo the lock.
+func recordLoginFailure(c *fiber.Ctx, store *repositories.Store, userID string, event models.SecurityEvent, cfg config.LockoutConfig, logger *zap.Logger) error {
+	throttle, err := store.Lockout.RecordLoginFailure(c.Context(), userID, cfg.FailureWindow, event, logger)
+	if err != nil {
+		return err
+	}
+
+	if throttle.Failures < int64(cfg.LockThreshold) {
+		return nil
+	}
+
+	until := time.Now().Add(cfg.LockDuration).UnixMilli()
+	mail, err := store.Lockout.LockAccount(c.Context(), userID, until, event, logger)
+	if err != nil {
+		return err
+	}
+
+	if err := enqueueMail(c, store, mail, logger); err != nil {
+		logger.Warn("Failed to queue account locked email", zap.String("user_id", userID), zap.Error(err))
+	}
+
+	return nil
+}
+
+// UnlockAccount lets an admin lift a lockout before it expires.
+func UnlockAccount(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Params("user_id")
+
+		if err := validators.UUID(userID); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		event := securityEvent(c)
+		event.ActorID = claims.UserID
+
+		if err := store.Lockout.UnlockAccount(c.Context(), userID, event, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		successMessage := "Unlock Account Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
+
+// GetSecurityEvents lists the caller's recent security events.
+func GetSecurityEvents(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		events, err := store.Lockout.GetSecurityEvents(c.Context(), claims.UserID, securityEventLimit, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Get Security Events Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, events, logger)
+	}
+}
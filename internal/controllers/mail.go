@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/validators"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// enqueueMail puts a mail returned by a repository in the outbox for the
+// mail worker to send. Mails without a recipient are dropped. Unless the
+// repository knew the recipient's language, the mail is written in the
+// caller's.
+func enqueueMail(c *fiber.Ctx, store *repositories.Store, mail models.Mail, logger *zap.Logger) error {
+	if mail.To == "" {
+		return nil
+	}
+
+	if mail.Language == "" {
+		mail.Language = Language(c)
+	}
+	return store.Outbox.EnqueueMail(c.Context(), mail, logger)
+}
+
+// GetDeadLetters lists the mails the worker gave up on. Their data, which
+// may hold tokens, is never included.
+func GetDeadLetters(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		page, err := validators.Page(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		mails, nextCursor, err := store.Outbox.GetDeadLetters(c.Context(), page, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Get Dead Letters Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, pageData(mails, nextCursor), logger)
+	}
+}
+
+// RetryDeadLetter puts a dead letter back in the outbox.
+func RetryDeadLetter(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		mailID := c.Params("mail_id")
+
+		if err := validators.UUID(mailID); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.Outbox.RetryDeadLetter(c.Context(), mailID, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		successMessage := "Retry Dead Letter Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
@@ -7,14 +7,27 @@ import (
 
 	"alumni_api/internal/encrypt"
 	"alumni_api/internal/repositories"
-	"alumni_api/internal/services"
+	"context"
+	"encoding/json"
 	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func SendMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+// publishMessage pushes a new chat message to both users' connections and
+// to anyone subscribed to their direct room, so other tabs of the sender
+// stay in sync too.
+func publishMessage(hub *websockets.Hub, senderID, receiverID string, msg map[string]interface{}, logger *zap.Logger) {
+	env, err := websockets.NewEnvelope(websockets.TypeMessage, msg)
+	if err != nil {
+		logger.Error("Failed to encode message frame", zap.Error(err))
+		return
+	}
+
+	hub.Publish(websockets.DirectRoom(senderID, receiverID), env, senderID, receiverID)
+}
+
+func SendMessage(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.Message
 		id := c.Params("user_id")
@@ -23,7 +36,7 @@ func SendMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -42,7 +55,7 @@ func SendMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err = services.UserExist(c.Context(), driver, req.ReceiverID, logger)
+		exists, err = store.User.UserExist(c.Context(), req.ReceiverID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -51,16 +64,24 @@ func SendMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("Receive User: %s not found", id), logger, nil)
 		}
 
-		if err := encrypt.EncryptStruct(&req, models.MessageEncryptField); err != nil {
+		if err := checkAttachment(c.Context(), store, req.AttachmentID, req.SenderID, logger); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		msg, err := repositories.SendMessage(c.Context(), driver, req, logger)
+		if err := encrypt.EncryptStruct(&req); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		msg, err := store.Message.SendMessage(c.Context(), req, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusInternalServerError, "Failed to Send Message", logger, err)
 		}
 
-		websockets.SendNotification(req.ReceiverID, msg)
+		if err := encrypt.DecryptMaps(msg); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		publishMessage(hub, req.SenderID, req.ReceiverID, msg, logger)
 
 		successMessage := "Send Message Successfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, msg, logger)
@@ -68,7 +89,7 @@ func SendMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 	}
 }
 
-func ReplyMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func ReplyMessage(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.ReplyMessage
 		id := c.Params("user_id")
@@ -77,7 +98,7 @@ func ReplyMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Hand
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -96,7 +117,7 @@ func ReplyMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Hand
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err = services.UserExist(c.Context(), driver, req.ReceiverID, logger)
+		exists, err = store.User.UserExist(c.Context(), req.ReceiverID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -105,20 +126,24 @@ func ReplyMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Hand
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("Receive User: %s not found", id), logger, nil)
 		}
 
-		if err := encrypt.EncryptStruct(&req, models.MessageEncryptField); err != nil {
+		if err := checkAttachment(c.Context(), store, req.AttachmentID, req.SenderID, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := encrypt.EncryptStruct(&req); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		msg, err := repositories.ReplyMessage(c.Context(), driver, req, logger)
+		msg, err := store.Message.ReplyMessage(c.Context(), req, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusInternalServerError, "Failed to Send Message", logger, err)
 		}
 
-		if err := encrypt.DecryptMaps(msg, models.MessageDecryptField); err != nil {
+		if err := encrypt.DecryptMaps(msg); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		websockets.SendNotification(req.ReceiverID, msg)
+		publishMessage(hub, req.SenderID, req.ReceiverID, msg, logger)
 
 		successMessage := "Send Reply Message Successfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, msg, logger)
@@ -126,7 +151,7 @@ func ReplyMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Hand
 	}
 }
 
-func EditMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func EditMessage(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.EditMessage
 		id := c.Params("user_id")
@@ -136,7 +161,7 @@ func EditMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -155,11 +180,11 @@ func EditMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.EncryptStruct(&req, models.MessageEncryptField); err != nil {
+		if err := encrypt.EncryptStruct(&req); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.EditMessage(c.Context(), driver, req, logger)
+		err = store.Message.EditMessage(c.Context(), req, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusInternalServerError, "Failed to Send Message", logger, err)
 		}
@@ -169,7 +194,7 @@ func EditMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 	}
 }
 
-func DeleteMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func DeleteMessage(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.DeleteMessage
 		id := c.Params("user_id")
@@ -179,7 +204,7 @@ func DeleteMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Han
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -198,7 +223,7 @@ func DeleteMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Han
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.DeleteMessage(c.Context(), driver, req, logger)
+		err = store.Message.DeleteMessage(c.Context(), req, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusInternalServerError, "Failed to Send Message", logger, err)
 		}
@@ -208,7 +233,7 @@ func DeleteMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Han
 	}
 }
 
-func GetChatMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetChatMessage(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id := c.Params("user_id")
 		other_id := c.Params("other_user_id")
@@ -217,7 +242,7 @@ func GetChatMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -230,7 +255,7 @@ func GetChatMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err = services.UserExist(c.Context(), driver, other_id, logger)
+		exists, err = store.User.UserExist(c.Context(), other_id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -239,17 +264,101 @@ func GetChatMessage(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("Receive User: %s not found", id), logger, nil)
 		}
 
-		chatMsg, err := repositories.GetMessage(c.Context(), driver, id, other_id, logger)
+		page, err := validators.Page(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		chatMsg, nextCursor, err := store.Message.GetMessage(c.Context(), id, other_id, page, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusInternalServerError, "Failed to Send Message", logger, err)
 		}
 
-		if err := encrypt.DecryptMaps(chatMsg, models.ChatMessageDecryptField); err != nil {
+		if err := encrypt.DecryptMaps(chatMsg); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
+		chatMsg["next_cursor"] = cursorValue(nextCursor)
+
 		successMessage := "Get Chat Message Successfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, chatMsg, logger)
 
 	}
 }
+
+type readFrame struct {
+	MessageID string `json:"message_id"`
+}
+
+// ReadMessageFrame handles "read" frames. It marks the message and every
+// earlier one from the same sender as read and tells both users' devices.
+func ReadMessageFrame(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) websockets.HandlerFunc {
+	return func(client *websockets.Client, env websockets.Envelope) error {
+		var req readFrame
+		if err := json.Unmarshal(env.Payload, &req); err != nil {
+			return fiber.NewError(fiber.StatusBadRequest, "Invalid read payload")
+		}
+
+		if err := validators.UUID(req.MessageID); err != nil {
+			return err
+		}
+
+		receipt, err := store.Message.MarkRead(context.Background(), client.UserID, req.MessageID, logger)
+		if err != nil {
+			return err
+		}
+
+		if len(receipt.MessageIDs) == 0 {
+			return nil
+		}
+
+		event, err := websockets.NewEnvelope(websockets.TypeRead, receipt)
+		if err != nil {
+			logger.Error("Failed to encode read frame", zap.Error(err))
+			return err
+		}
+
+		hub.Publish(websockets.DirectRoom(receipt.ReaderID, receipt.SenderID), event, receipt.ReaderID, receipt.SenderID)
+		return nil
+	}
+}
+
+func GetConversations(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("user_id")
+
+		if err := validators.UUID(id); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		exists, err := store.User.UserExist(c.Context(), id, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if !exists {
+			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", id), logger, nil)
+		}
+
+		if err := validators.SameUser(c, id); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		page, err := validators.Page(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		conversations, nextCursor, err := store.Message.GetConversations(c.Context(), id, page, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if err := encrypt.DecryptMaps(conversations); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		successMessage := "Get Conversations Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, pageData(conversations, nextCursor), logger)
+	}
+}
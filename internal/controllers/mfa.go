@@ -0,0 +1,489 @@
+package controllers
+
+import (
+	"alumni_api/config"
+	"alumni_api/internal/auth"
+	"alumni_api/internal/encrypt"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/utils"
+	"alumni_api/internal/validators"
+	"alumni_api/pkg/customtypes"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// loadMFA returns the caller's two-factor state with its secrets decrypted.
+func loadMFA(ctx context.Context, store *repositories.Store, userID string, logger *zap.Logger) (map[string]interface{}, error) {
+	mfa, err := store.MFA.GetMFA(ctx, userID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := encrypt.DecryptMaps(mfa); err != nil {
+		return nil, err
+	}
+
+	return mfa, nil
+}
+
+// mfaChallenge answers a correct password on an account with two-factor
+// authentication: no session yet, only a challenge token to send the code
+// with, or to enroll with first when the account is required to.
+func mfaChallenge(c *fiber.Ctx, user models.LoginResponse, logger *zap.Logger) error {
+	purpose, status := "mfa", "mfa_required"
+	if !user.MFAEnabled {
+		purpose, status = "mfa_setup", "mfa_setup_required"
+	}
+
+	token, err := auth.GenerateMFAChallenge(user.UserID, purpose)
+	if err != nil {
+		return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, nil)
+	}
+
+	ret := map[string]interface{}{
+		"status":          status,
+		"challenge_token": token,
+		"user_id":         user.UserID,
+	}
+
+	successMessage := "Two-Factor Authentication Required"
+	return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+}
+
+// startMFAEnrollment generates a new secret and keeps it pending until a
+// code from it is confirmed, so a half-finished enrollment never locks the
+// user out.
+func startMFAEnrollment(ctx context.Context, store *repositories.Store, mfa map[string]interface{}, cfg config.MFAConfig, logger *zap.Logger) (map[string]interface{}, error) {
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to generate two-factor secret")
+	}
+
+	secrets := models.MFASecrets{PendingSecret: customtypes.Encrypted[string]{Value: secret}}
+	if err := encrypt.EncryptStruct(&secrets); err != nil {
+		return nil, err
+	}
+
+	userID, _ := mfa["user_id"].(string)
+	if err := store.MFA.SetPendingMFASecret(ctx, userID, secrets.PendingSecret.Raw, logger); err != nil {
+		return nil, err
+	}
+
+	account, _ := mfa["username"].(string)
+	if account == "" {
+		account = userID
+	}
+
+	return map[string]interface{}{
+		"secret":           secret,
+		"provisioning_uri": auth.TOTPProvisioningURI(secret, cfg.Issuer, account),
+	}, nil
+}
+
+// finishMFAEnrollment checks a code against the pending secret, turns
+// two-factor authentication on and returns the new backup codes. They are
+// only ever shown here.
+func finishMFAEnrollment(ctx context.Context, store *repositories.Store, mfa map[string]interface{}, code string, cfg config.MFAConfig, logger *zap.Logger) ([]string, error) {
+	userID, _ := mfa["user_id"].(string)
+
+	pending, _ := mfa["mfa_pending_secret"].(string)
+	if pending == "" {
+		return nil, fiber.NewError(fiber.StatusConflict, "Two-factor enrollment has not been started")
+	}
+
+	if err := mfaThrottled(mfa, cfg); err != nil {
+		return nil, err
+	}
+
+	step, ok := auth.VerifyTOTP(pending, code, time.Now())
+	if !ok {
+		if err := store.MFA.RecordMFAFailure(ctx, userID, cfg.FailureWindow, logger); err != nil {
+			return nil, err
+		}
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid two-factor code")
+	}
+
+	codes, hashes, err := newBackupCodes(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.MFA.EnableMFA(ctx, userID, hashes, step, logger); err != nil {
+		return nil, err
+	}
+
+	return codes, nil
+}
+
+func newBackupCodes(cfg config.MFAConfig) ([]string, []string, error) {
+	codes, err := auth.GenerateBackupCodes(cfg.BackupCodeCount)
+	if err != nil {
+		return nil, nil, fiber.NewError(fiber.StatusInternalServerError, "Failed to generate backup codes")
+	}
+
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = auth.HashBackupCode(code)
+	}
+
+	return codes, hashes, nil
+}
+
+// mfaThrottled refuses any further code once too many wrong ones were sent
+// within the failure window.
+func mfaThrottled(mfa map[string]interface{}, cfg config.MFAConfig) error {
+	failures, _ := mfa["mfa_failures"].(int64)
+	failed, _ := mfa["mfa_failed_timestamp"].(int64)
+
+	if failures >= int64(cfg.MaxFailures) && failed > time.Now().Add(-cfg.FailureWindow).UnixMilli() {
+		return fiber.NewError(fiber.StatusTooManyRequests, "Too many invalid two-factor codes, try again later")
+	}
+
+	return nil
+}
+
+// checkMFACode accepts a current TOTP code, or an unused backup code when
+// allowBackup is set. Each code works once.
+func checkMFACode(ctx context.Context, store *repositories.Store, mfa map[string]interface{}, code string, allowBackup bool, cfg config.MFAConfig, logger *zap.Logger) error {
+	userID, _ := mfa["user_id"].(string)
+
+	if mfa["mfa_enabled"] != true {
+		return fiber.NewError(fiber.StatusConflict, "Two-factor authentication is not enabled")
+	}
+
+	if err := mfaThrottled(mfa, cfg); err != nil {
+		return err
+	}
+
+	secret, _ := mfa["mfa_secret"].(string)
+	if step, ok := auth.VerifyTOTP(secret, code, time.Now()); ok {
+		fresh, err := store.MFA.UseTOTPStep(ctx, userID, step, logger)
+		if err != nil {
+			return err
+		}
+		if fresh {
+			return nil
+		}
+	} else if allowBackup {
+		used, err := store.MFA.UseBackupCode(ctx, userID, auth.HashBackupCode(code), logger)
+		if err != nil {
+			return err
+		}
+		if used {
+			return nil
+		}
+	}
+
+	if err := store.MFA.RecordMFAFailure(ctx, userID, cfg.FailureWindow, logger); err != nil {
+		return err
+	}
+
+	return fiber.NewError(fiber.StatusUnauthorized, "Invalid two-factor code")
+}
+
+// LoginMFA completes a login that got an mfa_required challenge. For an
+// mfa_setup_required challenge the code confirms the enrollment started by
+// LoginMFASetup, and the backup codes are returned along with the session.
+func LoginMFA(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	cfg := config.LoadMFAConfig()
+
+	return func(c *fiber.Ctx) error {
+		var req models.MFALoginRequest
+
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
+		}
+
+		challenge, err := auth.ParseMFAChallenge(req.ChallengeToken)
+		if err != nil {
+			return HandleFail(c, fiber.StatusUnauthorized, "Invalid or expired challenge", logger, nil)
+		}
+
+		mfa, err := loadMFA(c.Context(), store, challenge.Subject, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		var backupCodes []string
+		if challenge.Purpose == "mfa_setup" && mfa["mfa_enabled"] != true {
+			backupCodes, err = finishMFAEnrollment(c.Context(), store, mfa, req.Code, cfg, logger)
+		} else {
+			err = checkMFACode(c.Context(), store, mfa, req.Code, true, cfg, logger)
+		}
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		var user models.LoginResponse
+		if err := utils.MapToStruct(mfa, &user); err != nil {
+			return HandleError(c, fiber.StatusInternalServerError, "Error decoding user properties", logger, err)
+		}
+
+		token, err := startSession(c, store, user, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		ret := map[string]interface{}{
+			"token":     token,
+			"user_id":   user.UserID,
+			"user_role": user.Role,
+		}
+		if backupCodes != nil {
+			ret["backup_codes"] = backupCodes
+		}
+
+		successMessage := "Login Succesfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
+
+// LoginMFASetup starts the enrollment of an account that is required to use
+// two-factor authentication but has not enrolled yet.
+func LoginMFASetup(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	cfg := config.LoadMFAConfig()
+
+	return func(c *fiber.Ctx) error {
+		var req models.MFAChallengeRequest
+
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
+		}
+
+		challenge, err := auth.ParseMFAChallenge(req.ChallengeToken)
+		if err != nil || challenge.Purpose != "mfa_setup" {
+			return HandleFail(c, fiber.StatusUnauthorized, "Invalid or expired challenge", logger, nil)
+		}
+
+		mfa, err := loadMFA(c.Context(), store, challenge.Subject, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if mfa["mfa_enabled"] == true {
+			return HandleFail(c, fiber.StatusConflict, "Two-factor authentication is already enabled", logger, nil)
+		}
+
+		ret, err := startMFAEnrollment(c.Context(), store, mfa, cfg, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Start Two-Factor Enrollment Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
+
+func GetMFAStatus(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		mfa, err := store.MFA.GetMFA(c.Context(), claims.UserID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		ret := map[string]interface{}{
+			"enabled":                mfa["mfa_enabled"],
+			"required":               mfa["mfa_required"],
+			"backup_codes_remaining": mfa["backup_codes_remaining"],
+		}
+
+		successMessage := "Get Two-Factor Status Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
+
+// EnrollMFA returns a new secret and its otpauth:// provisioning URI for the
+// client to render as a QR code. Nothing changes until EnableMFA confirms it.
+func EnrollMFA(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	cfg := config.LoadMFAConfig()
+
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		mfa, err := loadMFA(c.Context(), store, claims.UserID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if mfa["mfa_enabled"] == true {
+			return HandleFail(c, fiber.StatusConflict, "Two-factor authentication is already enabled", logger, nil)
+		}
+
+		ret, err := startMFAEnrollment(c.Context(), store, mfa, cfg, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Start Two-Factor Enrollment Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
+
+func EnableMFA(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	cfg := config.LoadMFAConfig()
+
+	return func(c *fiber.Ctx) error {
+		var req models.MFACodeRequest
+
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
+		}
+
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		mfa, err := loadMFA(c.Context(), store, claims.UserID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if mfa["mfa_enabled"] == true {
+			return HandleFail(c, fiber.StatusConflict, "Two-factor authentication is already enabled", logger, nil)
+		}
+
+		codes, err := finishMFAEnrollment(c.Context(), store, mfa, req.Code, cfg, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		ret := map[string]interface{}{
+			"backup_codes": codes,
+		}
+
+		successMessage := "Enable Two-Factor Authentication Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
+
+// DisableMFA turns two-factor authentication off after one last code. It is
+// refused on accounts an admin requires it on.
+func DisableMFA(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	cfg := config.LoadMFAConfig()
+
+	return func(c *fiber.Ctx) error {
+		var req models.MFACodeRequest
+
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
+		}
+
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		mfa, err := loadMFA(c.Context(), store, claims.UserID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if mfa["mfa_required"] == true {
+			return HandleFail(c, fiber.StatusForbidden, "Two-factor authentication is required on this account", logger, nil)
+		}
+
+		if err := checkMFACode(c.Context(), store, mfa, req.Code, true, cfg, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.MFA.DisableMFA(c.Context(), claims.UserID, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Disable Two-Factor Authentication Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
+
+// RegenerateBackupCodes replaces every backup code. It takes an authenticator
+// code, not a backup code, so a leaked backup code cannot mint new ones.
+func RegenerateBackupCodes(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	cfg := config.LoadMFAConfig()
+
+	return func(c *fiber.Ctx) error {
+		var req models.MFACodeRequest
+
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
+		}
+
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		mfa, err := loadMFA(c.Context(), store, claims.UserID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if err := checkMFACode(c.Context(), store, mfa, req.Code, false, cfg, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		codes, hashes, err := newBackupCodes(cfg)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if err := store.MFA.SetBackupCodes(c.Context(), claims.UserID, hashes, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		ret := map[string]interface{}{
+			"backup_codes": codes,
+		}
+
+		successMessage := "Regenerate Backup Codes Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
+
+// SetMFARequired lets an admin require two-factor authentication on an
+// account. Requiring it on an account that has not enrolled signs it out
+// everywhere, so the next login goes through enrollment.
+func SetMFARequired(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Params("user_id")
+
+		if err := validators.UUID(userID); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		var req models.MFARequiredRequest
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
+		}
+
+		mfa, err := store.MFA.GetMFA(c.Context(), userID, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.MFA.SetMFARequired(c.Context(), userID, *req.Required, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if *req.Required && mfa["mfa_enabled"] != true {
+			if err := store.Session.RevokeAllSessions(c.Context(), userID, logger); err != nil {
+				return HandleErrorWithStatus(c, err, logger)
+			}
+		}
+
+		successMessage := "Update Two-Factor Requirement Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
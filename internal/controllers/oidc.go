@@ -0,0 +1,138 @@
+package controllers
+
+import (
+	"alumni_api/internal/auth"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/validators"
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// oidcStatePath keeps the sign-in state cookie on the /v1/auth/oidc requests
+// that start and finish a provider sign-in.
+const oidcStatePath = "/v1/auth/oidc"
+
+func setOIDCStateCookie(c *fiber.Ctx, value string, maxAge int) {
+	c.Cookie(&fiber.Cookie{
+		Name:     "oidc_state",
+		Value:    value,
+		HTTPOnly: true,
+		Secure:   false,
+		SameSite: "None",
+		Path:     oidcStatePath,
+		MaxAge:   maxAge,
+	})
+}
+
+func oidcProvider(c *fiber.Ctx, providers *auth.OIDCProviders) (*auth.OIDCProvider, error) {
+	name := c.Params("provider")
+	provider, ok := providers.Get(name)
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Provider: %s not found", name))
+	}
+	return provider, nil
+}
+
+// GetOIDCProviders lists the providers the login page can offer.
+func GetOIDCProviders(providers *auth.OIDCProviders, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		ret := []map[string]interface{}{}
+		for _, provider := range providers.List() {
+			ret = append(ret, map[string]interface{}{
+				"provider": provider.Name,
+				"name":     provider.DisplayName,
+			})
+		}
+
+		successMessage := "Get Providers Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
+
+// StartOIDCLogin returns the provider URL to send the browser to, and sets
+// the state cookie the callback is checked against.
+func StartOIDCLogin(providers *auth.OIDCProviders, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		provider, err := oidcProvider(c, providers)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		url, stateToken, err := provider.AuthCodeURL(c.Context())
+		if err != nil {
+			if errors.Is(err, auth.ErrProviderUnavailable) {
+				return HandleError(c, fiber.StatusServiceUnavailable, err.Error(), logger, err)
+			}
+			return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, err)
+		}
+
+		setOIDCStateCookie(c, stateToken, 0)
+
+		ret := map[string]interface{}{
+			"authorization_url": url,
+		}
+
+		successMessage := "Start Login Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
+
+// OIDCCallback finishes a provider sign-in with the code and state the
+// provider redirected back with. From there it behaves like Login: a second
+// factor is still asked for when the account has one. "pending" tells the
+// client the profile has not been approved as an alumnus yet.
+func OIDCCallback(store *repositories.Store, providers *auth.OIDCProviders, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		provider, err := oidcProvider(c, providers)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		var req models.OIDCCallbackRequest
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
+		}
+
+		stateToken := c.Cookies("oidc_state")
+		if stateToken == "" {
+			return HandleFail(c, fiber.StatusUnauthorized, "Missing sign-in state", logger, nil)
+		}
+		setOIDCStateCookie(c, "", -1)
+
+		identity, err := provider.Exchange(c.Context(), stateToken, req.Code, req.State)
+		if err != nil {
+			if errors.Is(err, auth.ErrProviderUnavailable) {
+				return HandleError(c, fiber.StatusServiceUnavailable, err.Error(), logger, err)
+			}
+			return HandleFail(c, fiber.StatusUnauthorized, err.Error(), logger, nil)
+		}
+
+		user, err := store.Identity.SignInWithIdentity(c.Context(), identity, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if user.MFAEnabled || user.MFARequired {
+			return mfaChallenge(c, user, logger)
+		}
+
+		token, err := startSession(c, store, user, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		ret := map[string]interface{}{
+			"token":     token,
+			"user_id":   user.UserID,
+			"user_role": user.Role,
+			"pending":   user.Role == "user",
+		}
+
+		successMessage := "Login Succesfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
@@ -0,0 +1,20 @@
+package controllers
+
+import "github.com/gofiber/fiber/v2"
+
+// pageData wraps one page of a list endpoint together with the cursor of
+// the next page.
+func pageData(items interface{}, nextCursor string) fiber.Map {
+	return fiber.Map{
+		"items":       items,
+		"next_cursor": cursorValue(nextCursor),
+	}
+}
+
+// cursorValue renders an empty cursor, meaning the last page, as null.
+func cursorValue(nextCursor string) interface{} {
+	if nextCursor == "" {
+		return nil
+	}
+	return nextCursor
+}
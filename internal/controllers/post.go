@@ -1,63 +1,122 @@
 package controllers
 
 import (
+	"alumni_api/config"
 	"alumni_api/internal/auth"
 	"alumni_api/internal/models"
 	"alumni_api/internal/repositories"
 	"alumni_api/internal/services"
 	"alumni_api/internal/validators"
 	"fmt"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func GetAllPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+// optionalClaims returns the caller of a public route, or empty claims when
+// the request carries no valid token or its session has been revoked.
+func optionalClaims(c *fiber.Ctx, store *repositories.Store, logger *zap.Logger) *models.Claims {
+	if tokenString, ok := auth.ExtractJWT_Cookie(c); ok {
+		if claims, err := SessionClaims(c, store, tokenString, logger); err == nil {
+			return claims
+		}
+	}
+	return &models.Claims{}
+}
+
+func GetAllPost(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		userID := ""
+		claims := optionalClaims(c, store, logger)
 
-		if tokenString, ok := auth.ExtractJWT_Cookie(c); ok {
-			if claims, err := auth.ParseJWT(tokenString); err == nil {
-				userID = claims.UserID
-			}
+		page, err := validators.Page(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		visibility, err := postVisibility(c, store, claims, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		posts, err := repositories.GetAllPosts(c.Context(), driver, userID, logger)
+		posts, nextCursor, err := store.Post.GetAllPosts(c.Context(), claims.UserID, visibility, page, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
 		successMessage := "Get Post Sucessfully"
-		return HandleSuccess(c, fiber.StatusOK, successMessage, posts, logger)
+		return HandleSuccess(c, fiber.StatusOK, successMessage, pageData(posts, nextCursor), logger)
+	}
+}
+
+// GetFeed ranks recent posts for the caller by recency and by how the post
+// relates to them in the graph. Anonymous callers get recency and
+// engagement only.
+func GetFeed(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	cfg := config.LoadFeedConfig()
+
+	return func(c *fiber.Ctx) error {
+		claims := optionalClaims(c, store, logger)
+
+		page, err := validators.Page(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		visibility, err := postVisibility(c, store, claims, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		candidates, err := store.Post.GetFeedCandidates(c.Context(), claims.UserID, visibility, cfg.CandidateWindow, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		posts, nextCursor := services.RankFeed(candidates, cfg, time.Now(), page)
+
+		successMessage := "Get Feed Sucessfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, pageData(posts, nextCursor), logger)
 	}
 }
 
-func GetPostByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetPostByID(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		postID := c.Params("post_id")
-		userID := ""
 
 		if err := validators.UUID(postID); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		if tokenString, ok := auth.ExtractJWT_Cookie(c); ok {
-			if claims, err := auth.ParseJWT(tokenString); err == nil {
-				userID = claims.UserID
-				err = services.AddView(c.Context(), driver, claims.UserID, postID, logger)
-				if err != nil {
-					return HandleErrorWithStatus(c, err, logger)
-				}
+		claims := optionalClaims(c, store, logger)
+
+		visibility, err := postVisibility(c, store, claims, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		visible, err := store.Post.PostVisible(c.Context(), postID, claims.UserID, visibility, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if !visible {
+			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("Post: %s not found", postID), logger, nil)
+		}
+
+		if claims.UserID != "" {
+			err = store.Post.AddView(c.Context(), claims.UserID, postID, logger)
+			if err != nil {
+				return HandleErrorWithStatus(c, err, logger)
 			}
 		}
 
-		// posts, err := repositories.GetPostByID(c.Context(), driver, postID, logger)
+		// posts, err := store.Post.GetPostByID(c.Context(), postID, logger)
 		// if err != nil {
 		// 	return HandleErrorWithStatus(c, err, logger)
 		// }
 
-		posts, err := repositories.GetPostByID(c.Context(), driver, postID, userID, logger)
+		posts, err := store.Post.GetPostByID(c.Context(), postID, claims.UserID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -67,32 +126,46 @@ func GetPostByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 	}
 }
 
-func GetCommentByPostID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetCommentByPostID(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		postID := c.Params("post_id")
-		userID := ""
 
 		if err := validators.UUID(postID); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		if tokenString, ok := auth.ExtractJWT_Cookie(c); ok {
-			if claims, err := auth.ParseJWT(tokenString); err == nil {
-				userID = claims.UserID
-			}
+		claims := optionalClaims(c, store, logger)
+
+		page, err := validators.Page(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		visibility, err := postVisibility(c, store, claims, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		visible, err := store.Post.PostVisible(c.Context(), postID, claims.UserID, visibility, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if !visible {
+			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("Post: %s not found", postID), logger, nil)
 		}
 
-		comment, err := repositories.GetCommentByPostID(c.Context(), driver, postID, userID, logger)
+		comment, nextCursor, err := store.Post.GetCommentByPostID(c.Context(), postID, claims.UserID, page, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
 		successMessage := "Get Comment Sucessfully"
-		return HandleSuccess(c, fiber.StatusOK, successMessage, comment, logger)
+		return HandleSuccess(c, fiber.StatusOK, successMessage, pageData(comment, nextCursor), logger)
 	}
 }
 
-func CreatePost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func CreatePost(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claim, ok := c.Locals("claims").(*models.Claims)
 		if !ok {
@@ -105,17 +178,33 @@ func CreatePost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handle
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		data, err := repositories.CreatePost(c.Context(), driver, claim.UserID, req, logger)
+		if req.CreateChat && req.PostType != "event" {
+			return HandleFail(c, fiber.StatusBadRequest, "Only event posts can have a group chat", logger, nil)
+		}
+
+		data, err := store.Post.CreatePost(c.Context(), claim.UserID, req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
+		if req.CreateChat {
+			conv, err := store.Conversation.CreateConversation(c.Context(), claim.UserID, models.Conversation{
+				Title:  req.Title,
+				PostID: data["post_id"].(string),
+			}, logger)
+			if err != nil {
+				return HandleErrorWithStatus(c, err, logger)
+			}
+
+			data["conversation_id"] = conv["conversation_id"]
+		}
+
 		successMessage := "Create post Succesfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, data, logger)
 	}
 }
 
-func UpdatePostByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func UpdatePostByID(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		postID := c.Params("post_id")
 
@@ -123,7 +212,7 @@ func UpdatePostByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		userID, err := services.GetAuthorUserID(c.Context(), driver, postID, logger)
+		userID, err := store.Post.GetAuthorUserID(c.Context(), postID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -138,7 +227,7 @@ func UpdatePostByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.UpdatePostByID(c.Context(), driver, postID, req, logger)
+		err = store.Post.UpdatePostByID(c.Context(), postID, req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -148,7 +237,7 @@ func UpdatePostByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 	}
 }
 
-func DeletePostByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func DeletePostByID(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		postID := c.Params("post_id")
 
@@ -156,16 +245,16 @@ func DeletePostByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		userID, err := services.GetAuthorUserID(c.Context(), driver, postID, logger)
+		userID, err := store.Post.GetAuthorUserID(c.Context(), postID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		if err := validators.SameUser(c, userID); err != nil {
+		if err := ownerOr(c, store, userID, models.PermissionPostDeleteAny, logger); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.DeletePostByID(c.Context(), driver, postID, logger)
+		err = store.Post.DeletePostByID(c.Context(), postID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -175,7 +264,7 @@ func DeletePostByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 	}
 }
 
-func LikePost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func LikePost(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		postID := c.Params("post_id")
 
@@ -188,7 +277,7 @@ func LikePost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler
 			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
 		}
 
-		err := repositories.LikePost(c.Context(), driver, claim.UserID, postID, logger)
+		err := store.Post.LikePost(c.Context(), claim.UserID, postID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -198,7 +287,7 @@ func LikePost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler
 	}
 }
 
-func UnlikePost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func UnlikePost(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		postID := c.Params("post_id")
 
@@ -211,7 +300,7 @@ func UnlikePost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handle
 			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
 		}
 
-		err := repositories.UnlikePost(c.Context(), driver, claim.UserID, postID, logger)
+		err := store.Post.UnlikePost(c.Context(), claim.UserID, postID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -221,7 +310,7 @@ func UnlikePost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handle
 	}
 }
 
-func CommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func CommentPost(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		postID := c.Params("post_id")
 
@@ -234,7 +323,7 @@ func CommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, claim.UserID, logger)
+		exists, err := store.User.UserExist(c.Context(), claim.UserID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -249,7 +338,7 @@ func CommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		data, err := repositories.CommentPost(c.Context(), driver, claim.UserID, postID, req.Comment, logger)
+		data, err := store.Post.CommentPost(c.Context(), claim.UserID, postID, req.Comment, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -259,7 +348,7 @@ func CommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 	}
 }
 
-func ReplyComment(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func ReplyComment(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		commentID := c.Params("comment_id")
 
@@ -272,7 +361,7 @@ func ReplyComment(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Hand
 			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, claim.UserID, logger)
+		exists, err := store.User.UserExist(c.Context(), claim.UserID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -287,7 +376,7 @@ func ReplyComment(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Hand
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		data, err := repositories.ReplyComment(c.Context(), driver, claim.UserID, commentID, req.Comment, logger)
+		data, err := store.Post.ReplyComment(c.Context(), claim.UserID, commentID, req.Comment, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -297,7 +386,7 @@ func ReplyComment(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Hand
 	}
 }
 
-func UpdateCommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func UpdateCommentPost(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		commentID := c.Params("comment_id")
 
@@ -305,7 +394,7 @@ func UpdateCommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		userID, err := services.GetCommentUserID(c.Context(), driver, commentID, logger)
+		userID, err := store.Post.GetCommentUserID(c.Context(), commentID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -320,7 +409,7 @@ func UpdateCommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.UpdateCommentPost(c.Context(), driver, commentID, req.Comment, logger)
+		err = store.Post.UpdateCommentPost(c.Context(), commentID, req.Comment, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -330,7 +419,7 @@ func UpdateCommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 	}
 }
 
-func DeleteCommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func DeleteCommentPost(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		commentID := c.Params("comment_id")
 
@@ -338,16 +427,16 @@ func DeleteCommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		userID, err := services.GetCommentUserID(c.Context(), driver, commentID, logger)
+		userID, err := store.Post.GetCommentUserID(c.Context(), commentID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		if err := validators.SameUser(c, userID); err != nil {
+		if err := ownerOr(c, store, userID, models.PermissionCommentDeleteAny, logger); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.DeleteCommentPost(c.Context(), driver, commentID, logger)
+		err = store.Post.DeleteCommentPost(c.Context(), commentID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -357,7 +446,7 @@ func DeleteCommentPost(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 	}
 }
 
-func LikeComment(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func LikeComment(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		commentID := c.Params("comment_id")
 
@@ -370,7 +459,7 @@ func LikeComment(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
 		}
 
-		err := repositories.LikeComment(c.Context(), driver, claim.UserID, commentID, logger)
+		err := store.Post.LikeComment(c.Context(), claim.UserID, commentID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -380,7 +469,7 @@ func LikeComment(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 	}
 }
 
-func UnlikeComment(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func UnlikeComment(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		commentID := c.Params("comment_id")
 
@@ -393,7 +482,7 @@ func UnlikeComment(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Han
 			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
 		}
 
-		err := repositories.UnlikeComment(c.Context(), driver, claim.UserID, commentID, logger)
+		err := store.Post.UnlikeComment(c.Context(), claim.UserID, commentID, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/validators"
+	"alumni_api/internal/websockets"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type presenceFrame struct {
+	UserIDs []string `json:"user_ids"`
+}
+
+func friendIDs(ctx context.Context, store *repositories.Store, userID string, logger *zap.Logger) ([]string, error) {
+	friends, err := store.Friend.GetUserFriendByID(ctx, userID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(friends))
+	for _, friend := range friends {
+		if id, ok := friend["user_id"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// NotifyFriendsPresence tells a user's online friends when the user comes
+// online or goes offline.
+func NotifyFriendsPresence(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) func(userID string, online bool) {
+	return func(userID string, online bool) {
+		friends, err := friendIDs(context.Background(), store, userID, logger)
+		if err != nil {
+			logger.Error("Failed to load friends for presence", zap.String("user_id", userID), zap.Error(err))
+			return
+		}
+
+		event, err := websockets.NewEvent(websockets.TypePresence, hub.Presence(userID))
+		if err != nil {
+			logger.Error("Failed to encode presence frame", zap.Error(err))
+			return
+		}
+
+		for _, friendID := range friends {
+			hub.SendToUser(friendID, event)
+		}
+	}
+}
+
+// PresenceFrame handles "presence" frames by replying with the presence of
+// the requested friends, or of every friend when none are listed. Users who
+// are not friends of the caller are skipped.
+func PresenceFrame(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) websockets.HandlerFunc {
+	return func(client *websockets.Client, env websockets.Envelope) error {
+		var req presenceFrame
+		if len(env.Payload) > 0 {
+			if err := json.Unmarshal(env.Payload, &req); err != nil {
+				return fiber.NewError(fiber.StatusBadRequest, "Invalid presence payload")
+			}
+		}
+
+		friends, err := friendIDs(context.Background(), store, client.UserID, logger)
+		if err != nil {
+			return err
+		}
+
+		wanted := make(map[string]bool, len(req.UserIDs))
+		for _, id := range req.UserIDs {
+			wanted[id] = true
+		}
+
+		for _, friendID := range friends {
+			if len(wanted) > 0 && !wanted[friendID] {
+				continue
+			}
+
+			event, err := websockets.NewEvent(websockets.TypePresence, hub.Presence(friendID))
+			if err != nil {
+				return err
+			}
+			client.Send(event)
+		}
+
+		return nil
+	}
+}
+
+func GetOnlineFriends(store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("user_id")
+
+		if err := validators.UUID(id); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		exists, err := store.User.UserExist(c.Context(), id, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		if !exists {
+			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", id), logger, nil)
+		}
+
+		if err := validators.SameUser(c, id); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		friends, err := store.Friend.GetUserFriendByID(c.Context(), id, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		online := []map[string]interface{}{}
+		for _, friend := range friends {
+			friendID, _ := friend["user_id"].(string)
+			if hub.Online(friendID) {
+				online = append(online, friend)
+			}
+		}
+
+		successMessage := "Online friends retrieved successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, online, logger)
+	}
+}
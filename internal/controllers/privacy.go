@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/validators"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// profileViewer is who profiles are being serialized for.
+type profileViewer struct {
+	claims      *models.Claims
+	permissions []string
+	friends     map[string]bool
+
+	// audit is the reason an admin gave for reading the fields owners hide
+	// from them. Every profile it reveals something on is recorded.
+	audit string
+}
+
+// viewerOf describes the caller to the privacy settings of the profiles
+// they read. Anonymous callers of public routes are viewers too. Auditing
+// takes the audit query parameter from a caller holding
+// PermissionProfileAudit, on authenticated routes only.
+func viewerOf(c *fiber.Ctx, store *repositories.Store, logger *zap.Logger) (profileViewer, error) {
+	claims, authenticated := c.Locals("claims").(*models.Claims)
+	if !authenticated {
+		claims = optionalClaims(c, store, logger)
+	}
+
+	permissions, err := claimsPermissions(c, store, claims, logger)
+	if err != nil {
+		return profileViewer{}, err
+	}
+	viewer := profileViewer{claims: claims, permissions: permissions, friends: map[string]bool{}}
+
+	if claims.UserID != "" {
+		friends, err := store.Friend.GetUserFriendByID(c.Context(), claims.UserID, logger)
+		if err != nil {
+			return viewer, err
+		}
+		for _, friend := range friends {
+			if id, ok := friend["user_id"].(string); ok {
+				viewer.friends[id] = true
+			}
+		}
+	}
+
+	if reason := strings.TrimSpace(c.Query("audit")); reason != "" && authenticated {
+		if err := RequirePermissions(c, store, logger, models.PermissionProfileAudit); err != nil {
+			return viewer, err
+		}
+		viewer.audit = reason
+	}
+
+	return viewer, nil
+}
+
+// hidden lists the field groups of ownerID's profile the viewer may not see
+// under settings, auditing aside.
+func (v profileViewer) hidden(ownerID string, settings models.PrivacySettings) []string {
+	if ownerID != "" && ownerID == v.claims.UserID {
+		return nil
+	}
+
+	var groups []string
+	for _, group := range []string{models.PrivacyPersonal, models.PrivacyContact, models.PrivacyEducation, models.PrivacyCareer, models.PrivacySalary} {
+		if !models.PrivacyVisible(settings.Group(group), v.permissions, v.friends[ownerID]) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// matchedVisibly leaves out of users those matched on field groups they hide
+// from the viewer, since listing them would give those fields away.
+func (v profileViewer) matchedVisibly(users []map[string]interface{}, groups []string) []map[string]interface{} {
+	visible := make([]map[string]interface{}, 0, len(users))
+	for _, user := range users {
+		ownerID, _ := user["user_id"].(string)
+		if slices.ContainsFunc(v.hidden(ownerID, models.PrivacyOf(user)), func(group string) bool {
+			return slices.Contains(groups, group)
+		}) {
+			continue
+		}
+		visible = append(visible, user)
+	}
+	return visible
+}
+
+// redactProfile removes the field groups the owner hides from the viewer
+// from a serialized profile, unless the viewer is auditing, and returns the
+// hidden groups it held. The owner gets their settings back under privacy;
+// nobody else sees them.
+func redactProfile(profile map[string]interface{}, viewer profileViewer) []string {
+	ownerID, _ := profile["user_id"].(string)
+	settings := models.PrivacyOf(profile)
+
+	for group := range models.PrivacyGroups {
+		delete(profile, models.PrivacyProperty(group))
+	}
+	if ownerID != "" && ownerID == viewer.claims.UserID {
+		profile["privacy"] = settings
+	}
+
+	var held []string
+	for _, group := range viewer.hidden(ownerID, settings) {
+		// Salaries are kept on each of the companies.
+		fields := []map[string]interface{}{profile}
+		if group == models.PrivacySalary {
+			fields = nil
+			companies, _ := profile["companies"].([]interface{})
+			for _, company := range companies {
+				if company, ok := company.(map[string]interface{}); ok {
+					fields = append(fields, company)
+				}
+			}
+		}
+
+		found := false
+		for _, field := range fields {
+			for _, key := range models.PrivacyGroups[group] {
+				if _, ok := field[key]; ok {
+					found = true
+					if viewer.audit == "" {
+						delete(field, key)
+					}
+				}
+			}
+		}
+		if found {
+			held = append(held, group)
+		}
+	}
+	return held
+}
+
+// serializeProfiles applies the owners' privacy settings to profiles before
+// they are sent to the viewer. Whatever an audit reveals is recorded on the
+// owner's account first, and nothing is revealed if that fails.
+func serializeProfiles(c *fiber.Ctx, store *repositories.Store, viewer profileViewer, profiles []map[string]interface{}, logger *zap.Logger) error {
+	audited := map[string]bool{}
+
+	for _, profile := range profiles {
+		held := redactProfile(profile, viewer)
+		ownerID, _ := profile["user_id"].(string)
+		if viewer.audit == "" || len(held) == 0 || audited[ownerID] {
+			continue
+		}
+		audited[ownerID] = true
+
+		event := securityEvent(c)
+		event.Type = models.SecurityEventAudited
+		event.ActorID = viewer.claims.UserID
+		event.Reason = viewer.audit
+		if err := store.Lockout.RecordSecurityEvent(c.Context(), ownerID, event, logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetPrivacy stores who may see each field group of the user's profile.
+// Groups left out go back to their default.
+func SetPrivacy(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Params("id")
+
+		if err := validators.UUID(id); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := validators.SameUser(c, id); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		var req models.PrivacySettings
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
+		}
+
+		if err := store.User.SetPrivacy(c.Context(), id, req, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Update Privacy Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, req.OrDefault(), logger)
+	}
+}
@@ -0,0 +1,237 @@
+package controllers
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/validators"
+	"fmt"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// Permissions returns what the caller may do. They are looked up once per
+// request, like the session, so a role change applies at once.
+func Permissions(c *fiber.Ctx, store *repositories.Store, logger *zap.Logger) ([]string, error) {
+	if permissions, ok := c.Locals("permissions").([]string); ok {
+		return permissions, nil
+	}
+
+	claims, ok := c.Locals("claims").(*models.Claims)
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Unauthorized claim")
+	}
+
+	permissions, err := store.Role.GetPermissions(c.Context(), claims.UserID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Locals("permissions", permissions)
+	return permissions, nil
+}
+
+// RequirePermissions fails with 403 unless the caller holds every one of
+// permissions.
+func RequirePermissions(c *fiber.Ctx, store *repositories.Store, logger *zap.Logger, permissions ...string) error {
+	granted, err := Permissions(c, store, logger)
+	if err != nil {
+		return err
+	}
+
+	for _, permission := range permissions {
+		if !slices.Contains(granted, permission) {
+			return fiber.NewError(fiber.StatusForbidden, fmt.Sprintf("Missing permission: %s", permission))
+		}
+	}
+
+	return nil
+}
+
+// claimsPermissions returns the permissions of claims. Public routes read
+// their caller with optionalClaims rather than the JWT middleware, so these
+// need not be the request's claims. Anonymous callers hold none.
+func claimsPermissions(c *fiber.Ctx, store *repositories.Store, claims *models.Claims, logger *zap.Logger) ([]string, error) {
+	if claims.UserID == "" {
+		return nil, nil
+	}
+	if current, ok := c.Locals("claims").(*models.Claims); ok && current.UserID == claims.UserID {
+		return Permissions(c, store, logger)
+	}
+	return store.Role.GetPermissions(c.Context(), claims.UserID, logger)
+}
+
+// postVisibility lists the post visibility values claims may read.
+func postVisibility(c *fiber.Ctx, store *repositories.Store, claims *models.Claims, logger *zap.Logger) ([]string, error) {
+	permissions, err := claimsPermissions(c, store, claims, logger)
+	if err != nil {
+		return nil, err
+	}
+	return models.PostVisibility(permissions), nil
+}
+
+// DepartmentScope returns the department the caller's permissions are
+// limited to, or "" when they reach everyone: the caller administers no
+// department, or also holds PermissionDepartmentAny.
+func DepartmentScope(c *fiber.Ctx, store *repositories.Store, logger *zap.Logger) (string, error) {
+	claims, ok := c.Locals("claims").(*models.Claims)
+	if !ok {
+		return "", fiber.NewError(fiber.StatusUnauthorized, "Unauthorized claim")
+	}
+	if claims.DepartmentID == "" {
+		return "", nil
+	}
+
+	permissions, err := Permissions(c, store, logger)
+	if err != nil {
+		return "", err
+	}
+	if slices.Contains(permissions, models.PermissionDepartmentAny) {
+		return "", nil
+	}
+	return claims.DepartmentID, nil
+}
+
+// ownerOr lets the owner of something through, or anyone else holding the
+// permission that extends the action to everyone's.
+func ownerOr(c *fiber.Ctx, store *repositories.Store, ownerID, permission string, logger *zap.Logger) error {
+	err := validators.SameUser(c, ownerID)
+	if err == nil {
+		return nil
+	}
+
+	if fiberErr, ok := err.(*fiber.Error); ok && fiberErr.Code == fiber.StatusUnauthorized {
+		return err
+	}
+
+	if RequirePermissions(c, store, logger, permission) != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetMyPermissions lists the caller's permissions, for clients to decide
+// what to show.
+func GetMyPermissions(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		permissions, err := Permissions(c, store, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		successMessage := "Get Permissions Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, permissions, logger)
+	}
+}
+
+func GetRoles(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		roles, err := store.Role.GetRoles(c.Context(), logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		successMessage := "Get Roles Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, roles, logger)
+	}
+}
+
+// SaveRole creates a role or replaces its permissions.
+func SaveRole(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		name := c.Params("name")
+
+		if err := validators.RoleName(name); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		var req models.RoleRequest
+		if err := validators.Request(c, &req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, "Validation failed", logger, err)
+		}
+
+		role := models.Role{Name: name, Permissions: req.Permissions}
+		if err := store.Role.SaveRole(c.Context(), role, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		successMessage := "Save Role Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, role, logger)
+	}
+}
+
+func DeleteRole(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		name := c.Params("name")
+
+		if err := validators.RoleName(name); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.Role.DeleteRole(c.Context(), name, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		successMessage := "Delete Role Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
+
+// roleAssignment reads the role and user of an assignment route. Base roles
+// come with the profile and are not assigned on top of it, and the department
+// admin role comes with a department.
+func roleAssignment(c *fiber.Ctx) (string, string, error) {
+	name, userID := c.Params("name"), c.Params("user_id")
+
+	if err := validators.RoleName(name); err != nil {
+		return "", "", err
+	}
+
+	if err := validators.UUID(userID); err != nil {
+		return "", "", err
+	}
+
+	if slices.Contains(models.BaseRoles, name) {
+		return "", "", fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Role: %s is a base role", name))
+	}
+
+	if name == models.DepartmentAdminRole {
+		return "", "", fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Role: %s is assigned per department", name))
+	}
+
+	return name, userID, nil
+}
+
+func AssignRole(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		name, userID, err := roleAssignment(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.Role.AssignRole(c.Context(), userID, name, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		successMessage := "Assign Role Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
+
+func RevokeRole(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		name, userID, err := roleAssignment(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if err := store.Role.RevokeRole(c.Context(), userID, name, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		successMessage := "Revoke Role Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
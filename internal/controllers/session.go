@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"alumni_api/internal/auth"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/validators"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// refreshCookiePath keeps the refresh token cookie off every request but the
+// /v1/auth ones that rotate or revoke it.
+const refreshCookiePath = "/v1/auth"
+
+// SessionClaims parses an access token and makes sure the session it was
+// issued for is still active, so a revoked session loses access at once
+// instead of when its token expires.
+func SessionClaims(c *fiber.Ctx, store *repositories.Store, tokenString string, logger *zap.Logger) (*models.Claims, error) {
+	claims, err := auth.ParseJWT(tokenString)
+	if err != nil || claims.SessionID == "" {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Invalid or expired token")
+	}
+
+	active, err := store.Session.IsSessionActive(c.Context(), claims.UserID, claims.SessionID, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if !active {
+		return nil, fiber.NewError(fiber.StatusUnauthorized, "Session has been revoked")
+	}
+
+	return claims, nil
+}
+
+func setSessionCookies(c *fiber.Ctx, accessToken, refreshToken string) {
+	c.Cookie(&fiber.Cookie{
+		Name:     "jwt",
+		Value:    accessToken,
+		HTTPOnly: true,
+		// TODO: Turn back to strict when frontend in production
+		// Secure:   true, // Enable in production (HTTPS only)
+		// SameSite: "Strict",
+		Secure:   false,
+		SameSite: "None",
+		Path:     "/",
+		MaxAge:   int(auth.AccessTokenTTL().Seconds()),
+	})
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "refresh_token",
+		Value:    refreshToken,
+		HTTPOnly: true,
+		Secure:   false,
+		SameSite: "None",
+		Path:     refreshCookiePath,
+		MaxAge:   int(auth.RefreshTokenTTL().Seconds()),
+	})
+}
+
+func clearSessionCookies(c *fiber.Ctx) {
+	for name, path := range map[string]string{"jwt": "/", "refresh_token": refreshCookiePath} {
+		c.Cookie(&fiber.Cookie{
+			Name:     name,
+			Value:    "",
+			HTTPOnly: true,
+			Secure:   false,
+			SameSite: "None",
+			Path:     path,
+			MaxAge:   -1,
+		})
+	}
+}
+
+// startSession opens a Session for the device making the request, sets the
+// access and refresh token cookies and returns the access token.
+func startSession(c *fiber.Ctx, store *repositories.Store, user models.LoginResponse, logger *zap.Logger) (string, error) {
+	sessionID := uuid.New().String()
+	refreshToken := auth.GenerateRefreshToken(sessionID)
+
+	session := models.Session{
+		SessionID:        sessionID,
+		UserID:           user.UserID,
+		RefreshHash:      auth.HashRefreshToken(refreshToken),
+		UserAgent:        c.Get(fiber.HeaderUserAgent),
+		IP:               c.IP(),
+		ExpiresTimestamp: time.Now().Add(auth.RefreshTokenTTL()).UnixMilli(),
+	}
+
+	if err := store.Session.CreateSession(c.Context(), session, logger); err != nil {
+		return "", err
+	}
+
+	departmentID, err := store.Department.GetAdministeredDepartment(c.Context(), user.UserID, logger)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := auth.GenerateJWT(user.UserID, user.Role, departmentID, user.Language, int(user.AdmitYear), sessionID)
+	if err != nil {
+		return "", fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	setSessionCookies(c, token, refreshToken)
+	return token, nil
+}
+
+// RefreshSession trades the refresh token cookie for a new access token and
+// a new refresh token. The old refresh token stops working; presenting it
+// again revokes the session.
+func RefreshSession(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		refreshToken, ok := auth.ExtractRefreshToken(c)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Missing refresh token", logger, nil)
+		}
+
+		sessionID, refreshHash, ok := auth.ParseRefreshToken(refreshToken)
+		if !ok {
+			clearSessionCookies(c)
+			return HandleFail(c, fiber.StatusUnauthorized, "Invalid refresh token", logger, nil)
+		}
+
+		nextToken := auth.GenerateRefreshToken(sessionID)
+		next := models.Session{
+			RefreshHash:      auth.HashRefreshToken(nextToken),
+			UserAgent:        c.Get(fiber.HeaderUserAgent),
+			IP:               c.IP(),
+			ExpiresTimestamp: time.Now().Add(auth.RefreshTokenTTL()).UnixMilli(),
+		}
+
+		user, err := store.Session.RotateSession(c.Context(), sessionID, refreshHash, next, logger)
+		if err != nil {
+			clearSessionCookies(c)
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		departmentID, err := store.Department.GetAdministeredDepartment(c.Context(), user.UserID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		token, err := auth.GenerateJWT(user.UserID, user.Role, departmentID, user.Language, int(user.AdmitYear), sessionID)
+		if err != nil {
+			return HandleError(c, fiber.StatusInternalServerError, err.Error(), logger, nil)
+		}
+
+		setSessionCookies(c, token, nextToken)
+
+		ret := map[string]interface{}{
+			"token":     token,
+			"user_id":   user.UserID,
+			"user_role": user.Role,
+		}
+
+		successMessage := "Refresh Session Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, ret, logger)
+	}
+}
+
+// GetSessions lists the caller's signed-in devices and flags the one making
+// the request.
+func GetSessions(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		sessions, err := store.Session.GetSessions(c.Context(), claims.UserID, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		for _, session := range sessions {
+			session["current"] = session["session_id"] == claims.SessionID
+		}
+
+		successMessage := "Get Sessions Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, sessions, logger)
+	}
+}
+
+// RevokeSession signs one of the caller's devices out. Its access token is
+// rejected from the next request on.
+func RevokeSession(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		sessionID := c.Params("session_id")
+
+		if err := validators.UUID(sessionID); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		if err := store.Session.RevokeSession(c.Context(), claims.UserID, sessionID, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		if sessionID == claims.SessionID {
+			clearSessionCookies(c)
+		}
+
+		successMessage := "Revoke Session Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
+
+// RevokeAllSessions signs the caller out on every device, this one included.
+func RevokeAllSessions(store *repositories.Store, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		if err := store.Session.RevokeAllSessions(c.Context(), claims.UserID, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		clearSessionCookies(c)
+
+		successMessage := "Revoke Sessions Successfully"
+		return HandleSuccess(c, fiber.StatusOK, successMessage, nil, logger)
+	}
+}
+
+// JWKS publishes the keys access tokens are verified with. It is plain JSON
+// rather than JSend, since JWKS clients expect the RFC 7517 shape.
+func JWKS(logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Set(fiber.HeaderCacheControl, "public, max-age=300")
+		return c.JSON(auth.JWKS())
+	}
+}
@@ -7,17 +7,27 @@ import (
 	"alumni_api/internal/validators"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func GetPostStat(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetPostStat(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		if err := validators.UserAdmin(c); err != nil {
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		visibility, err := postVisibility(c, store, claims, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		scope, err := DepartmentScope(c, store, logger)
+		if err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		posts, err := repositories.GetPostStat(c.Context(), driver, logger)
+		posts, err := store.Statistic.GetPostStat(c.Context(), visibility, scope, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -27,13 +37,14 @@ func GetPostStat(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 	}
 }
 
-func GetRegistryStat(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetRegistryStat(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		if err := validators.UserAdmin(c); err != nil {
+		scope, err := DepartmentScope(c, store, logger)
+		if err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		posts, err := repositories.GetRegistryStat(c.Context(), driver, logger)
+		posts, err := store.Statistic.GetRegistryStat(c.Context(), scope, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -43,9 +54,16 @@ func GetRegistryStat(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.H
 	}
 }
 
-func GetActivityStat(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetActivityStat(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		posts, err := repositories.GetActivityStat(c.Context(), driver, logger)
+		claims := optionalClaims(c, store, logger)
+
+		visibility, err := postVisibility(c, store, claims, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		posts, err := store.Statistic.GetActivityStat(c.Context(), visibility, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -55,7 +73,7 @@ func GetActivityStat(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.H
 	}
 }
 
-func GetGenerationSTStat(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetGenerationSTStat(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.GenerationStat
 
@@ -63,7 +81,7 @@ func GetGenerationSTStat(driver neo4j.DriverWithContext, logger *zap.Logger) fib
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		posts, err := repositories.GetGenerationSTStat(c.Context(), driver, req.CPE, logger)
+		posts, err := store.Statistic.GetGenerationSTStat(c.Context(), req.CPE, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -73,18 +91,31 @@ func GetGenerationSTStat(driver neo4j.DriverWithContext, logger *zap.Logger) fib
 	}
 }
 
-func GetUserSalary(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+// GetUserSalary lists the salaries their owners let the caller see. Friends
+// are not told apart here, so a salary only shown to friends is left out.
+func GetUserSalary(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		if err := validators.UserAdmin(c); err != nil {
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		permissions, err := claimsPermissions(c, store, claims, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		scope, err := DepartmentScope(c, store, logger)
+		if err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		user, err := repositories.GetUserSalary(c.Context(), driver, logger)
+		user, err := store.Statistic.GetUserSalary(c.Context(), models.PrivacyVisibility(permissions, false), scope, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.DecryptMaps(user, models.CompanyDecryptField); err != nil {
+		if err := encrypt.DecryptMaps(user); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
@@ -93,14 +124,24 @@ func GetUserSalary(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Han
 	}
 }
 
-func GetUserJob(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetUserJob(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		user, err := repositories.GetUserJob(c.Context(), driver, logger)
+		claims, ok := c.Locals("claims").(*models.Claims)
+		if !ok {
+			return HandleFail(c, fiber.StatusUnauthorized, "Unauthorized claim", logger, nil)
+		}
+
+		permissions, err := claimsPermissions(c, store, claims, logger)
+		if err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+
+		user, err := store.Statistic.GetUserJob(c.Context(), models.PrivacyVisibility(permissions, false), logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.DecryptMaps(user, models.CompanyDecryptField); err != nil {
+		if err := encrypt.DecryptMaps(user); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
@@ -3,16 +3,14 @@ package controllers
 import (
 	"alumni_api/internal/models"
 	"alumni_api/internal/repositories"
-	"alumni_api/internal/services"
 	"alumni_api/internal/validators"
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func AddStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func AddStudentInfo(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.CollegeInfo
 
@@ -22,7 +20,7 @@ func AddStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -31,17 +29,15 @@ func AddStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", id), logger, nil)
 		}
 
-		err1 := validators.SameUser(c, id)
-		err2 := validators.UserAdmin(c)
-		if err1 != nil && err2 != nil {
-			return HandleFailWithStatus(c, err1, logger)
+		if err := ownerOr(c, store, id, models.PermissionProfileEditAny, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
 		}
 
 		if err := validators.Request(c, &req); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.AddStudentInfo(c.Context(), driver, id, req, logger)
+		err = store.User.AddStudentInfo(c.Context(), id, req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -51,7 +47,7 @@ func AddStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 	}
 }
 
-func UpdateStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func UpdateStudentInfo(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.CollegeInfo
 
@@ -61,7 +57,7 @@ func UpdateStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -70,17 +66,15 @@ func UpdateStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", id), logger, nil)
 		}
 
-		err1 := validators.SameUser(c, id)
-		err2 := validators.UserAdmin(c)
-		if err1 != nil && err2 != nil {
-			return HandleFailWithStatus(c, err1, logger)
+		if err := ownerOr(c, store, id, models.PermissionProfileEditAny, logger); err != nil {
+			return HandleFailWithStatus(c, err, logger)
 		}
 
 		if err := validators.Request(c, &req); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.UpdateStudentInfo(c.Context(), driver, id, req, logger)
+		err = store.User.UpdateStudentInfo(c.Context(), id, req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -90,7 +84,7 @@ func UpdateStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 	}
 }
 
-func DeleteStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func DeleteStudentInfo(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id := c.Params("id")
 
@@ -98,7 +92,7 @@ func DeleteStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -111,7 +105,7 @@ func DeleteStudentInfo(driver neo4j.DriverWithContext, logger *zap.Logger) fiber
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.DeleteStudentInfo(c.Context(), driver, id, logger)
+		err = store.User.DeleteStudentInfo(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
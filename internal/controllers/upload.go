@@ -1,57 +1,140 @@
 package controllers
 
 import (
-	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
-	"time"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/services"
+	"alumni_api/internal/storage"
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"path"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
-const uploadDir = "/app/uploads/"
+// uploadPrefix is the storage prefix of public images, served as-is by
+// ServeUpload under /uploads.
+const uploadPrefix = "uploads/"
 
-func Upload(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+// Upload validates an image, strips its metadata and stores it with its
+// renditions. The response carries the URL of the cleaned original and of
+// every rendition, which share its name: <id>.jpg, <id>_thumbnail.jpg, ...
+func Upload(store *repositories.Store, files storage.Storage, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		file, err := c.FormFile("file")
 		if err != nil {
-			return HandleErrorWithStatus(c, err, logger)
+			return HandleFail(c, fiber.StatusBadRequest, "File is required", logger, err)
 		}
 
 		// Validate size (e.g., limit to 5MB)
 		if file.Size > 5*1024*1024 {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "File too large"})
+			return HandleFail(c, fiber.StatusRequestEntityTooLarge, "File too large", logger, nil)
 		}
 
-		// Optional: validate MIME type (basic check)
-		contentType := file.Header.Get("Content-Type")
-		if !strings.HasPrefix(contentType, "image/") {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Invalid file type"})
+		src, err := file.Open()
+		if err != nil {
+			return HandleError(c, fiber.StatusInternalServerError, "Failed to read file", logger, err)
+		}
+		data, err := io.ReadAll(src)
+		src.Close()
+		if err != nil {
+			return HandleError(c, fiber.StatusInternalServerError, "Failed to read file", logger, err)
 		}
 
-		// Ensure upload directory exists
-		if _, err := os.Stat(uploadDir); os.IsNotExist(err) {
-			os.MkdirAll(uploadDir, os.ModePerm)
+		// The client's Content-Type and file name are ignored: the type
+		// is sniffed from the bytes and the image must decode.
+		img, err := services.ProcessImage(data)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
 		}
 
-		// Use a safe, unique filename
-		extension := filepath.Ext(file.Filename)
-		uniqueName := fmt.Sprintf("%d%s", time.Now().UnixNano(), extension)
-		filePath := filepath.Join(uploadDir, uniqueName)
+		id := uuid.New().String()
+		objects := map[string][]byte{id + img.Extension: img.Original}
+		renditions := map[string]interface{}{}
+		for _, rendition := range services.ImageRenditions {
+			name := id + "_" + rendition.Name + services.RenditionExtension(img.ContentType)
+			objects[name] = img.Renditions[rendition.Name]
+			renditions[rendition.Name] = "/uploads/" + name
+		}
 
-		// Save the file
-		if err := c.SaveFile(file, filePath); err != nil {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Failed to save file"})
+		for name, content := range objects {
+			if err := files.Put(c.Context(), uploadPrefix+name, bytes.NewReader(content), int64(len(content)), mime.TypeByExtension(path.Ext(name))); err != nil {
+				return HandleError(c, fiber.StatusInternalServerError, "Failed to save file", logger, err)
+			}
 		}
 
 		ret := map[string]interface{}{
-			"url": "/uploads/" + uniqueName,
+			"url":          "/uploads/" + id + img.Extension,
+			"content_type": img.ContentType,
+			"width":        img.Width,
+			"height":       img.Height,
+			"renditions":   renditions,
 		}
 
 		// Return public URL
 		return HandleSuccess(c, fiber.StatusOK, "Upload successfully", ret, logger)
 	}
 }
+
+// sendObject streams a stored object. Backends that do not keep the content
+// type fall back to the one implied by the key's extension.
+func sendObject(c *fiber.Ctx, files storage.Storage, key string, logger *zap.Logger) error {
+	body, obj, err := files.Get(c.Context(), key)
+	if errors.Is(err, storage.ErrNotFound) || errors.Is(err, storage.ErrInvalidKey) {
+		return HandleFail(c, fiber.StatusNotFound, "File not found", logger, nil)
+	}
+	if err != nil {
+		return HandleError(c, fiber.StatusInternalServerError, "Failed to read file", logger, err)
+	}
+
+	contentType := obj.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(path.Ext(key))
+	}
+	if contentType == "" {
+		contentType = fiber.MIMEOctetStream
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+
+	// SendStream closes the body once it has been written.
+	return c.SendStream(body, int(obj.Size))
+}
+
+// ServeUpload serves the public images stored by Upload.
+func ServeUpload(files storage.Storage, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return sendObject(c, files, uploadPrefix+c.Params("*"), logger)
+	}
+}
+
+// ServeSignedFile serves objects through the expiring URLs issued by the
+// local and in-memory backends. S3 URLs point at the bucket and never reach
+// this handler.
+func ServeSignedFile(files storage.Storage, logger *zap.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		verifier, ok := files.(storage.URLVerifier)
+		if !ok {
+			return HandleFail(c, fiber.StatusNotFound, "File not found", logger, nil)
+		}
+
+		key := c.Params("*")
+		err := verifier.VerifyURL(key, c.Query("expires"), c.Query("signature"))
+		if errors.Is(err, storage.ErrExpired) {
+			return HandleFail(c, fiber.StatusGone, "Link has expired", logger, nil)
+		}
+		if err != nil {
+			return HandleFail(c, fiber.StatusForbidden, "Invalid signature", logger, nil)
+		}
+
+		// Signed links are handed out for downloads, never for inline
+		// rendering on the API's origin.
+		c.Set(fiber.HeaderContentDisposition, "attachment")
+
+		return sendObject(c, files, key, logger)
+	}
+}
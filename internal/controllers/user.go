@@ -4,28 +4,28 @@ import (
 	"alumni_api/internal/encrypt"
 	"alumni_api/internal/models"
 	"alumni_api/internal/repositories"
-	"alumni_api/internal/services"
 	"alumni_api/internal/validators"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func CreateProfile(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func CreateProfile(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.CreateProfileRequest
 
-		if err := validators.UserAdmin(c); err != nil {
+		if err := validators.Request(c, &req); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		if err := validators.Request(c, &req); err != nil {
+		if err := encrypt.EncryptStruct(&req); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		data, err := repositories.CreateProfile(c.Context(), driver, req, logger)
+		data, err := store.User.CreateProfile(c.Context(), req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -36,25 +36,38 @@ func CreateProfile(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Han
 }
 
 // GetUserByID handles the request to get a user by ID from the Neo4j database.
-func GetAllUser(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetAllUser(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 
-		users, err := repositories.GetAllUser(c.Context(), driver, logger)
+		page, err := validators.Page(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		viewer, err := viewerOf(c, store, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		users, nextCursor, err := store.User.GetAllUser(c.Context(), page, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.DecryptMaps(users, models.UserDecryptField); err != nil {
+		if err := serializeProfiles(c, store, viewer, users, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+		if err := encrypt.DecryptMaps(users); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
 		successMessage := "User retrieved successfully"
-		return HandleSuccess(c, fiber.StatusOK, successMessage, users, logger)
+		return HandleSuccess(c, fiber.StatusOK, successMessage, pageData(users, nextCursor), logger)
 	}
 }
 
 // GetUserByID handles the request to get a user by ID from the Neo4j database.
-func GetUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func GetUserByID(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id := c.Params("id")
 
@@ -62,7 +75,7 @@ func GetUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -71,16 +84,20 @@ func GetUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 			return HandleFail(c, fiber.StatusNotFound, fmt.Sprintf("User: %s not found", id), logger, nil)
 		}
 
-		if err := validators.SameUser(c, id); err != nil {
+		viewer, err := viewerOf(c, store, logger)
+		if err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		user, err := repositories.FetchUserByID(c.Context(), driver, id, logger)
+		user, err := store.User.FetchUserByID(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.DecryptMaps(user, models.UserDecryptField); err != nil {
+		if err := serializeProfiles(c, store, viewer, []map[string]interface{}{user}, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+		if err := encrypt.DecryptMaps(user); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
@@ -90,7 +107,7 @@ func GetUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handl
 }
 
 // UpdateUserProfile handles updating a user's profile in the Neo4j database.
-func UpdateUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func UpdateUserByID(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.UpdateUserProfileRequest
 
@@ -100,7 +117,7 @@ func UpdateUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -117,16 +134,17 @@ func UpdateUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.EncryptStruct(&req, models.UserEncryptField); err != nil {
+		if err := encrypt.EncryptStruct(&req); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		user, err := repositories.UpdateUserByID(c.Context(), driver, id, req, logger)
+		user, err := store.User.UpdateUserByID(c.Context(), id, req, logger)
 		if err != nil {
 			return HandleError(c, fiber.StatusInternalServerError, "Failed to Update users", logger, err)
 		}
 
-		if err := encrypt.DecryptMaps(user, models.StudentInfoDecryptField); err != nil {
+		dropPrivate(user)
+		if err := encrypt.DecryptMaps(user); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
@@ -135,7 +153,7 @@ func UpdateUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 	}
 }
 
-func DeleteUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func DeleteUserByID(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id := c.Params("id")
 
@@ -143,7 +161,7 @@ func DeleteUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		exists, err := services.UserExist(c.Context(), driver, id, logger)
+		exists, err := store.User.UserExist(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -156,7 +174,7 @@ func DeleteUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err = repositories.DeleteUserByID(c.Context(), driver, id, logger)
+		err = store.User.DeleteUserByID(c.Context(), id, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -177,7 +195,7 @@ func DeleteUserByID(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Ha
 	}
 }
 
-func FindUserByFilter(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func FindUserByFilter(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.UserRequestFilter
 
@@ -185,21 +203,52 @@ func FindUserByFilter(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		users, err := repositories.FetchUserByFilter(c.Context(), driver, req, logger)
+		if err := indexUserFilter(&req); err != nil {
+			return HandleFail(c, fiber.StatusBadRequest, err.Error(), logger, nil)
+		}
+
+		viewer, err := viewerOf(c, store, logger)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		users, err := store.User.FetchUserByFilter(c.Context(), req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
-		if err := encrypt.DecryptMaps(users, models.UserDecryptField); err != nil {
+		users = viewer.matchedVisibly(users, filterGroups(req))
+		for _, user := range users {
+			dropPrivate(user)
+		}
+
+		if err := serializeProfiles(c, store, viewer, users, logger); err != nil {
+			return HandleErrorWithStatus(c, err, logger)
+		}
+		if err := encrypt.DecryptMaps(users); err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
+		// GPAX is indexed by quarter point, so the buckets at either end
+		// may hold users just outside the range.
+		matched := make([]map[string]interface{}, 0, len(users))
+		for _, user := range users {
+			if req.GPAXMin != 0 || req.GPAXMax != 0 {
+				gpax, ok := user["gpax"].(float32)
+				if !ok || (req.GPAXMin != 0 && gpax < req.GPAXMin) || (req.GPAXMax != 0 && gpax > req.GPAXMax) {
+					continue
+				}
+			}
+			matched = append(matched, user)
+		}
+		users = matched
+
 		successMessage := "User(s) retrieved successfully"
 		return HandleSuccess(c, fiber.StatusOK, successMessage, users, logger)
 	}
 }
 
-func NameFullTextSearch(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func NameFullTextSearch(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var req models.UserFulltextSearch
 
@@ -207,7 +256,7 @@ func NameFullTextSearch(driver neo4j.DriverWithContext, logger *zap.Logger) fibe
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		users, err := repositories.FullTextSeach(c.Context(), driver, req, logger)
+		users, err := store.User.FullTextSearch(c.Context(), req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
@@ -216,3 +265,60 @@ func NameFullTextSearch(driver neo4j.DriverWithContext, logger *zap.Logger) fibe
 		return HandleSuccess(c, fiber.StatusOK, successMessage, users, logger)
 	}
 }
+
+// filterGroups lists the privacy field groups the criteria of req match on.
+func filterGroups(req models.UserRequestFilter) []string {
+	var groups []string
+	if req.StudentType != "" || req.Field != "" || req.AdmitYearFrom != 0 || req.AdmitYearTo != 0 ||
+		req.GraduateYearFrom != 0 || req.GraduateYearTo != 0 || req.GPAXMin != 0 || req.GPAXMax != 0 {
+		groups = append(groups, models.PrivacyEducation)
+	}
+	if req.Company != "" || req.Position != "" {
+		groups = append(groups, models.PrivacyCareer)
+	}
+	return groups
+}
+
+// indexUserFilter turns the encrypted field criteria of req into the blind
+// index tokens the repositories match on.
+func indexUserFilter(req *models.UserRequestFilter) error {
+	var err error
+
+	if req.Position != "" {
+		if req.PositionIndex, err = models.PositionIndex.Token(reflect.ValueOf(req.Position)); err != nil {
+			return err
+		}
+	}
+	if req.AdmitYearFrom != 0 || req.AdmitYearTo != 0 {
+		if req.AdmitYearIndexes, err = models.AdmitYearIndex.Range(float64(req.AdmitYearFrom), float64(req.AdmitYearTo)); err != nil {
+			return err
+		}
+	}
+	if req.GraduateYearFrom != 0 || req.GraduateYearTo != 0 {
+		if req.GraduateYearIndexes, err = models.GraduateYearIndex.Range(float64(req.GraduateYearFrom), float64(req.GraduateYearTo)); err != nil {
+			return err
+		}
+	}
+	if req.GPAXMin != 0 || req.GPAXMax != 0 {
+		if req.GPAXIndexes, err = models.GPAXIndex.Range(float64(req.GPAXMin), float64(req.GPAXMax)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dropPrivate removes what a UserProfile returned as stored holds besides
+// the profile: the password hash, two-factor state and blind indexes.
+func dropPrivate(user map[string]interface{}) {
+	delete(user, "user_password")
+	for key := range user {
+		if strings.HasPrefix(key, "mfa_") {
+			delete(user, key)
+		}
+	}
+	for _, prop := range models.EncryptedProperties {
+		if prop.Index.Name != "" {
+			delete(user, prop.Index.Property())
+		}
+	}
+}
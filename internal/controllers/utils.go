@@ -7,27 +7,32 @@ import (
 	"fmt"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func FetchReport(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func FetchReport(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		if err := validators.UserAdmin(c); err != nil {
+		page, err := validators.Page(c)
+		if err != nil {
+			return HandleFailWithStatus(c, err, logger)
+		}
+
+		scope, err := DepartmentScope(c, store, logger)
+		if err != nil {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		data, err := repositories.FetchReport(c.Context(), driver, logger)
+		data, nextCursor, err := store.Report.FetchReport(c.Context(), page, scope, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
 
 		successMessage := "Fetch Report Succesfully"
-		return HandleSuccess(c, fiber.StatusOK, successMessage, data, logger)
+		return HandleSuccess(c, fiber.StatusOK, successMessage, pageData(data, nextCursor), logger)
 	}
 }
 
-func Report(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
+func Report(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		claim, ok := c.Locals("claims").(*models.Claims)
 		if !ok {
@@ -41,7 +46,7 @@ func Report(driver neo4j.DriverWithContext, logger *zap.Logger) fiber.Handler {
 			return HandleFailWithStatus(c, err, logger)
 		}
 
-		err := repositories.Report(c.Context(), driver, req, logger)
+		err := store.Report.Report(c.Context(), req, logger)
 		if err != nil {
 			return HandleErrorWithStatus(c, err, logger)
 		}
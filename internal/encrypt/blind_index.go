@@ -0,0 +1,130 @@
+package encrypt
+
+import (
+	"alumni_api/config"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// maxBucketTokens caps how many buckets a range search may list.
+const maxBucketTokens = 1000
+
+// BlindIndex is a searchable stand-in for an encrypted field: an HMAC of the
+// value under a key of its own, stored next to the ciphertext. Strings are
+// indexed case-insensitively and can only be matched exactly. Numbers are
+// indexed by the bucket of Width they fall in, between Min and Max, so a
+// range is searched by listing its buckets; a Width above 1 trades precision,
+// which callers make up for by filtering the decrypted values, for leaking
+// less about them.
+type BlindIndex struct {
+	Name  string
+	Width float64
+	Min   float64
+	Max   float64
+}
+
+// Property is the name of the property the index is stored in.
+func (b BlindIndex) Property() string {
+	return b.Name + "_index"
+}
+
+func (b BlindIndex) token(term string) string {
+	mac := hmac.New(sha256.New, blindIndexKey)
+	mac.Write([]byte(b.Name))
+	mac.Write([]byte{0})
+	mac.Write([]byte(term))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+func (b BlindIndex) bucket(value float64) int64 {
+	return int64(math.Floor(value / b.Width))
+}
+
+// Token returns the index of value, a string or a number.
+func (b BlindIndex) Token(value reflect.Value) (string, error) {
+	switch value.Kind() {
+	case reflect.String:
+		return b.token(strings.ToLower(strings.Join(strings.Fields(value.String()), " "))), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return b.token(strconv.FormatInt(b.bucket(float64(value.Int())), 10)), nil
+	case reflect.Float32, reflect.Float64:
+		return b.token(strconv.FormatInt(b.bucket(value.Float()), 10)), nil
+	}
+	return "", fmt.Errorf("unsupported type: %s", value.Kind())
+}
+
+// Range returns the index of every bucket overlapping from..to. A zero bound
+// stands for Min or Max.
+func (b BlindIndex) Range(from, to float64) ([]string, error) {
+	if from == 0 || from < b.Min {
+		from = b.Min
+	}
+	if to == 0 || to > b.Max {
+		to = b.Max
+	}
+	if from > to {
+		return nil, fmt.Errorf("%s range is empty", b.Name)
+	}
+
+	first, last := b.bucket(from), b.bucket(to)
+	if last-first >= maxBucketTokens {
+		return nil, fmt.Errorf("%s range is too wide", b.Name)
+	}
+
+	tokens := make([]string, 0, last-first+1)
+	for bucket := first; bucket <= last; bucket++ {
+		tokens = append(tokens, b.token(strconv.FormatInt(bucket, 10)))
+	}
+	return tokens, nil
+}
+
+// IndexRaw decrypts a stored value and returns its blind index, for
+// indexing data written before it had one.
+func IndexRaw(ctx context.Context, raw []byte, index BlindIndex) (string, error) {
+	data, err := envelope.Load().open(ctx, raw)
+	if err != nil {
+		return "", err
+	}
+
+	value, err := convertFromBytesWithHeader(data)
+	if err != nil {
+		return "", err
+	}
+	return index.Token(value)
+}
+
+var blindIndexKey = loadBlindIndexKey(config.LoadEncryptionConfig())
+
+// loadBlindIndexKey reads the base64 encoded BLIND_INDEX_KEY. Without one
+// the server refuses to start, unless ENV is dev or test: then a throwaway
+// key is generated, which leaves every stored index unmatched after a
+// restart until the re-encryption job has rebuilt them.
+func loadBlindIndexKey(cfg config.EncryptionConfig) []byte {
+	switch {
+	case cfg.BlindIndexKey != "":
+		key, err := base64.StdEncoding.DecodeString(cfg.BlindIndexKey)
+		if err != nil || len(key) < 32 {
+			log.Fatalf("BLIND_INDEX_KEY must be at least 32 base64 encoded bytes")
+		}
+		return key
+	case !config.DevOrTest():
+		log.Fatal("BLIND_INDEX_KEY must be set")
+	}
+
+	log.Println("No BLIND_INDEX_KEY set, indexing with a temporary key")
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatalf("Failed to generate a blind index key: %v", err)
+	}
+	return key
+}
@@ -1,14 +1,10 @@
 package encrypt
 
 import (
-	"alumni_api/config"
 	"reflect"
 	"strings"
 )
 
-var config_value = config.LoadConfig()
-var encryptionKey = config_value.AESEncryptionKey
-
 // AES encrypt function for any type of reflect.Value (string, []byte, int, float)
 func AESEncrypt(input reflect.Value) (reflect.Value, error) {
 	if input.Kind() == reflect.Ptr {
@@ -32,7 +28,7 @@ func AESEncrypt(input reflect.Value) (reflect.Value, error) {
 	}
 
 	// Encrypt the data
-	encrypted, err := encryptAES(data, encryptionKey)
+	encrypted, err := encryptAES(data)
 	if err != nil {
 		return reflect.Value{}, err
 	}
@@ -56,7 +52,7 @@ func AESDecrypt(input reflect.Value, originalType reflect.Kind) (reflect.Value,
 	}
 
 	// Decrypt the data
-	data, err := decryptAES(input.Bytes(), encryptionKey)
+	data, err := decryptAES(input.Bytes())
 	if err != nil {
 		return reflect.Value{}, err
 	}
@@ -88,7 +84,7 @@ func AESEncryptWithHeader(input reflect.Value) (reflect.Value, error) {
 	}
 
 	// Encrypt the data
-	encrypted, err := encryptAES(data, encryptionKey)
+	encrypted, err := encryptAES(data)
 	if err != nil {
 		return reflect.Value{}, err
 	}
@@ -103,12 +99,8 @@ func AESDecryptWithHeader(input reflect.Value) (reflect.Value, error) {
 		input = input.Elem()
 	}
 
-	if !IsSliceOfByte(input) {
-		return input, nil
-	}
-
 	// Check if the type is customtype.Encrypted with any type parameter
-	if strings.HasPrefix(input.Type().String(), "customtypes.Encrypted[") && input.Kind() == reflect.Struct {
+	if input.Kind() == reflect.Struct && strings.HasPrefix(input.Type().String(), "customtypes.Encrypted[") {
 		// Extract actual value (e.g., `Value` field from a struct)
 		var err error
 		input, err = extractActualValue(input, "Raw")
@@ -117,8 +109,12 @@ func AESDecryptWithHeader(input reflect.Value) (reflect.Value, error) {
 		}
 	}
 
+	if !IsSliceOfByte(input) {
+		return input, nil
+	}
+
 	// Decrypt the data
-	decryptedData, err := decryptAES(input.Bytes(), encryptionKey)
+	decryptedData, err := decryptAES(input.Bytes())
 	if err != nil {
 		return reflect.Value{}, err
 	}
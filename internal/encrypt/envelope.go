@@ -0,0 +1,330 @@
+package encrypt
+
+import (
+	"alumni_api/config"
+	"container/list"
+	"context"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"log"
+	"sync"
+	"sync/atomic"
+)
+
+// KeyProvider wraps data keys with a master key it keeps to itself.
+type KeyProvider interface {
+	WrapKey(ctx context.Context, key []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error)
+	// RewrapKey wraps the key again under the current master key and
+	// reports whether that changed anything.
+	RewrapKey(ctx context.Context, wrapped []byte) ([]byte, bool, error)
+}
+
+// envelopeFormat starts every envelope. KeyRing versions start at 1, so it
+// tells envelopes apart from values encrypted directly with a master key.
+const envelopeFormat byte = 0x00
+
+// unwrappedKeysLimit bounds the cache of unwrapped data keys, which saves a
+// round trip to the provider for every other field of a record, and every
+// time the record is read again.
+const unwrappedKeysLimit = 4096
+
+// Envelope encrypts the fields of a record, such as a UserProfile, a
+// HAS_WORK_WITH edge or a Message, with one AES-256-GCM data key per record
+// write, wrapped by the provider. Every value carries the wrapped key, so it
+// can be read on its own: an envelope is the format byte, the length of the
+// wrapped key as two bytes, the wrapped key, the nonce and the sealed data.
+// The provider wraps the key once when the record is written, and unwraps it
+// once when the record is read, the cache holding it for the other fields.
+// Only the format byte is authenticated as additional data, so a key can be
+// rewrapped without touching the data.
+type Envelope struct {
+	provider KeyProvider
+
+	// direct reads values encrypted with a master key before envelope
+	// encryption; it may be nil.
+	direct *KeyRing
+
+	unwrapped *keyCache
+}
+
+func NewEnvelope(provider KeyProvider, direct *KeyRing) *Envelope {
+	return &Envelope{
+		provider:  provider,
+		direct:    direct,
+		unwrapped: newKeyCache(unwrappedKeysLimit),
+	}
+}
+
+// dataKey seals the fields of one record.
+type dataKey struct {
+	wrapped []byte
+	aead    cipher.AEAD
+}
+
+// newDataKey generates a data key and has the provider wrap it.
+func (e *Envelope) newDataKey(ctx context.Context) (*dataKey, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	wrapped, err := e.provider.WrapKey(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) > 0xFFFF {
+		return nil, errors.New("wrapped data key too long")
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	// The record is usually read back straight away.
+	e.unwrapped.put(wrapped, aead)
+	return &dataKey{wrapped: wrapped, aead: aead}, nil
+}
+
+func (k *dataKey) seal(data []byte) ([]byte, error) {
+	out := make([]byte, 3, 3+len(k.wrapped)+k.aead.NonceSize()+len(data)+k.aead.Overhead())
+	out[0] = envelopeFormat
+	binary.BigEndian.PutUint16(out[1:3], uint16(len(k.wrapped)))
+	out = append(out, k.wrapped...)
+
+	nonce := make([]byte, k.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out = append(out, nonce...)
+
+	return k.aead.Seal(out, nonce, data, out[:1]), nil
+}
+
+// seal encrypts a value that is a record of its own.
+func (e *Envelope) seal(ctx context.Context, data []byte) ([]byte, error) {
+	key, err := e.newDataKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return key.seal(data)
+}
+
+// splitEnvelope returns the wrapped key and the nonce and sealed data of an
+// envelope, or false when data is not one.
+func splitEnvelope(data []byte) (wrapped, rest []byte, ok bool) {
+	if len(data) < 3 || data[0] != envelopeFormat {
+		return nil, nil, false
+	}
+
+	end := 3 + int(binary.BigEndian.Uint16(data[1:3]))
+	if len(data) < end {
+		return nil, nil, false
+	}
+	return data[3:end], data[end:], true
+}
+
+func (e *Envelope) open(ctx context.Context, data []byte) ([]byte, error) {
+	if wrapped, rest, ok := splitEnvelope(data); ok {
+		plain, err := e.openEnvelope(ctx, data[:1], wrapped, rest)
+		if err == nil || e.direct == nil {
+			return plain, err
+		}
+	}
+
+	// Legacy AES-CBC values start with a random IV, which may look like an
+	// envelope, so the direct keys are tried whenever the envelope fails.
+	if e.direct != nil {
+		return e.direct.open(data)
+	}
+	return nil, errors.New("ciphertext is not an envelope")
+}
+
+func (e *Envelope) openEnvelope(ctx context.Context, header, wrapped, rest []byte) ([]byte, error) {
+	aead, err := e.unwrap(ctx, wrapped)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return nil, errors.New("ciphertext too short")
+	}
+
+	return aead.Open(nil, rest[:aead.NonceSize()], rest[aead.NonceSize():], header)
+}
+
+func (e *Envelope) unwrap(ctx context.Context, wrapped []byte) (cipher.AEAD, error) {
+	if aead, ok := e.unwrapped.get(wrapped); ok {
+		return aead, nil
+	}
+
+	key, err := e.provider.UnwrapKey(ctx, wrapped)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	e.unwrapped.put(wrapped, aead)
+	return aead, nil
+}
+
+// keyCache holds the most recently used data keys, by wrapped key, and
+// evicts the least recently used one when it is full.
+type keyCache struct {
+	mu      sync.Mutex
+	limit   int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type cachedKey struct {
+	wrapped string
+	aead    cipher.AEAD
+}
+
+func newKeyCache(limit int) *keyCache {
+	return &keyCache{
+		limit:   limit,
+		order:   list.New(),
+		entries: make(map[string]*list.Element),
+	}
+}
+
+func (c *keyCache) get(wrapped []byte) (cipher.AEAD, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[string(wrapped)]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(entry)
+	return entry.Value.(*cachedKey).aead, true
+}
+
+func (c *keyCache) put(wrapped []byte, aead cipher.AEAD) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[string(wrapped)]; ok {
+		c.order.MoveToFront(entry)
+		return
+	}
+
+	c.entries[string(wrapped)] = c.order.PushFront(&cachedKey{wrapped: string(wrapped), aead: aead})
+	if c.order.Len() > c.limit {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cachedKey).wrapped)
+	}
+}
+
+// reencrypt brings data up to date: values written before envelope
+// encryption are sealed in an envelope, and envelopes whose data key is
+// wrapped by an old master key get it rewrapped.
+func (e *Envelope) reencrypt(ctx context.Context, data []byte) ([]byte, bool, error) {
+	if wrapped, rest, ok := splitEnvelope(data); ok {
+		if _, err := e.openEnvelope(ctx, data[:1], wrapped, rest); err == nil {
+			rewrapped, changed, err := e.provider.RewrapKey(ctx, wrapped)
+			if err != nil || !changed {
+				return data, false, err
+			}
+			if len(rewrapped) > 0xFFFF {
+				return nil, false, errors.New("wrapped data key too long")
+			}
+
+			out := make([]byte, 3, 3+len(rewrapped)+len(rest))
+			out[0] = envelopeFormat
+			binary.BigEndian.PutUint16(out[1:3], uint16(len(rewrapped)))
+			out = append(out, rewrapped...)
+			return append(out, rest...), true, nil
+		}
+	}
+
+	plain, err := e.open(ctx, data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	sealed, err := e.seal(ctx, plain)
+	if err != nil {
+		return nil, false, err
+	}
+	return sealed, true, nil
+}
+
+var envelope atomic.Pointer[Envelope]
+
+func init() {
+	envelope.Store(loadEnvelope(config.LoadEncryptionConfig()))
+}
+
+// SetEnvelope replaces the envelope every encryption and decryption uses.
+func SetEnvelope(e *Envelope) {
+	envelope.Store(e)
+}
+
+// loadEnvelope sets up the configured provider. A deployment that only has
+// the legacy AES_ENCRYPTION_KEY gets local key version 1 derived from it,
+// see NewLegacyKeyRing. Without any key the server refuses to start, unless
+// ENV is dev or test: then a throwaway key is generated, and data encrypted
+// with it is unreadable after a restart.
+func loadEnvelope(cfg config.EncryptionConfig) *Envelope {
+	var direct *KeyRing
+	var err error
+
+	switch {
+	case cfg.Keys != "":
+		direct, err = NewKeyRing(cfg.Keys, cfg.LegacyKey)
+	case cfg.KeysFile != "":
+		direct, err = NewFileProvider(cfg.KeysFile, cfg.LegacyKey)
+	case len(cfg.LegacyKey) > 0:
+		direct, err = NewLegacyKeyRing(cfg.LegacyKey)
+	case cfg.Provider == "vault":
+	case !config.DevOrTest():
+		log.Fatal("ENCRYPTION_KEYS, ENCRYPTION_KEYS_FILE or AES_ENCRYPTION_KEY must be set")
+	default:
+		log.Println("No ENCRYPTION_KEYS, ENCRYPTION_KEYS_FILE or AES_ENCRYPTION_KEY set, encrypting with a temporary key")
+		key := make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			log.Fatalf("Failed to generate an encryption key: %v", err)
+		}
+		direct, err = NewKeyRing("1:"+base64.StdEncoding.EncodeToString(key), nil)
+	}
+	if err != nil {
+		log.Fatalf("Invalid encryption keys: %v", err)
+	}
+
+	switch cfg.Provider {
+	case "", "local":
+		return NewEnvelope(direct, direct)
+	case "vault":
+		vault, err := NewVault(VaultConfig{
+			Address: cfg.VaultAddress,
+			Token:   cfg.VaultToken,
+			Mount:   cfg.VaultMount,
+			Key:     cfg.VaultKey,
+		})
+		if err != nil {
+			log.Fatalf("Invalid Vault settings: %v", err)
+		}
+		return NewEnvelope(vault, direct)
+	}
+
+	log.Fatalf("Unknown ENCRYPTION_PROVIDER %q", cfg.Provider)
+	return nil
+}
+
+// Reencrypt brings data up to date with the current keys, see
+// Envelope.reencrypt. It reports false, and returns data unchanged, when
+// there was nothing to do.
+func Reencrypt(ctx context.Context, data []byte) ([]byte, bool, error) {
+	return envelope.Load().reencrypt(ctx, data)
+}
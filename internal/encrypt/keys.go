@@ -0,0 +1,208 @@
+package encrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/hkdf"
+)
+
+// KeyRing holds versioned AES-GCM master keys. It is the local KeyProvider,
+// and also reads values encrypted directly with a master key before envelope
+// encryption. Ciphertext is the key version, the nonce and the sealed data,
+// with the version authenticated as additional data, so old versions keep
+// decrypting after a new key takes over.
+type KeyRing struct {
+	current byte
+	keys    map[byte]cipher.AEAD
+
+	// legacy reads the unversioned AES-CBC ciphertext written before keys
+	// had versions.
+	legacy cipher.Block
+}
+
+// NewKeyRing parses keys, a comma or newline separated list of version:key
+// pairs with base64 encoded keys, the first of which encrypts. legacyKey may
+// be nil.
+func NewKeyRing(keys string, legacyKey []byte) (*KeyRing, error) {
+	ring := &KeyRing{keys: make(map[byte]cipher.AEAD)}
+
+	entries := strings.FieldsFunc(keys, func(r rune) bool { return r == ',' || r == '\n' })
+	if len(entries) == 0 {
+		return nil, errors.New("no keys given")
+	}
+
+	for i, entry := range entries {
+		versionText, encoded, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok {
+			return nil, fmt.Errorf("key %d is not version:key", i+1)
+		}
+
+		version, err := strconv.ParseUint(versionText, 10, 8)
+		if err != nil || version == 0 {
+			return nil, fmt.Errorf("key %d: version must be between 1 and 255", i+1)
+		}
+		if _, ok := ring.keys[byte(version)]; ok {
+			return nil, fmt.Errorf("key version %d is listed twice", version)
+		}
+
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("key version %d is not valid base64: %w", version, err)
+		}
+
+		aead, err := newGCM(key)
+		if err != nil {
+			return nil, fmt.Errorf("key version %d: %w", version, err)
+		}
+
+		ring.keys[byte(version)] = aead
+		if i == 0 {
+			ring.current = byte(version)
+		}
+	}
+
+	if len(legacyKey) > 0 {
+		if err := checkLegacyKey(legacyKey); err != nil {
+			return nil, err
+		}
+		block, err := aes.NewCipher(legacyKey)
+		if err != nil {
+			return nil, fmt.Errorf("legacy key: %w", err)
+		}
+		ring.legacy = block
+	}
+
+	return ring, nil
+}
+
+// checkLegacyKey rejects a legacy key AES-CBC cannot have been used with,
+// which most likely means it is not the key the old data was written with.
+func checkLegacyKey(key []byte) error {
+	switch len(key) {
+	case 16, 24, 32:
+		return nil
+	}
+	return fmt.Errorf("legacy key (AES_ENCRYPTION_KEY) is %d bytes, but data written before key versions was encrypted with a 16, 24 or 32 byte key; "+
+		"set it to that key, or leave it out once the re-encryption job has run", len(key))
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, errors.New("key must be 32 bytes")
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (r *KeyRing) seal(data []byte) ([]byte, error) {
+	aead := r.keys[r.current]
+
+	out := make([]byte, 1+aead.NonceSize(), 1+aead.NonceSize()+len(data)+aead.Overhead())
+	out[0] = r.current
+	if _, err := rand.Read(out[1:]); err != nil {
+		return nil, err
+	}
+
+	return aead.Seal(out, out[1:], data, out[:1]), nil
+}
+
+func (r *KeyRing) open(data []byte) ([]byte, error) {
+	if len(data) > 0 {
+		if aead, ok := r.keys[data[0]]; ok && len(data) >= 1+aead.NonceSize()+aead.Overhead() {
+			nonce := data[1 : 1+aead.NonceSize()]
+			plain, err := aead.Open(nil, nonce, data[1+aead.NonceSize():], data[:1])
+			if err == nil {
+				return plain, nil
+			}
+		}
+	}
+
+	// Legacy ciphertext starts with a random IV, which may look like a key
+	// version, so it is only tried once no key opened the data.
+	if r.legacy != nil {
+		if plain, err := decryptAESCBC(data, r.legacy); err == nil {
+			return plain, nil
+		}
+	}
+
+	return nil, errors.New("ciphertext cannot be decrypted with any known key")
+}
+
+// isCurrent reports whether data was sealed with the key that encrypts.
+func (r *KeyRing) isCurrent(data []byte) bool {
+	if len(data) == 0 || data[0] != r.current {
+		return false
+	}
+
+	aead := r.keys[r.current]
+	if len(data) < 1+aead.NonceSize()+aead.Overhead() {
+		return false
+	}
+	_, err := aead.Open(nil, data[1:1+aead.NonceSize()], data[1+aead.NonceSize():], data[:1])
+	return err == nil
+}
+
+// NewFileProvider reads the master keys from the file at path, in the format
+// NewKeyRing takes.
+func NewFileProvider(path string, legacyKey []byte) (*KeyRing, error) {
+	keys, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewKeyRing(string(keys), legacyKey)
+}
+
+// NewLegacyKeyRing is the key ring of a deployment that only has the legacy
+// key. It reads the legacy ciphertext with that key and encrypts with key
+// version 1, derived from it with HKDF so the two ciphers never share a key.
+func NewLegacyKeyRing(legacyKey []byte) (*KeyRing, error) {
+	if err := checkLegacyKey(legacyKey); err != nil {
+		return nil, err
+	}
+
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, legacyKey, nil, []byte("alumni_api key version 1")), key); err != nil {
+		return nil, err
+	}
+	return NewKeyRing("1:"+base64.StdEncoding.EncodeToString(key), legacyKey)
+}
+
+func (r *KeyRing) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	return r.seal(key)
+}
+
+func (r *KeyRing) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	return r.open(wrapped)
+}
+
+func (r *KeyRing) RewrapKey(ctx context.Context, wrapped []byte) ([]byte, bool, error) {
+	if r.isCurrent(wrapped) {
+		return wrapped, false, nil
+	}
+
+	key, err := r.open(wrapped)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rewrapped, err := r.seal(key)
+	if err != nil {
+		return nil, false, err
+	}
+	return rewrapped, true, nil
+}
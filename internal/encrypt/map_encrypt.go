@@ -1,87 +1,56 @@
 package encrypt
 
 import (
-	"alumni_api/internal/utils"
-	"fmt"
 	"reflect"
 )
 
-func EncryptMaps(inputMaps interface{}, fieldsGroups ...[]string) error {
-	// Iterate over each group of fields
-	for _, fieldsToEncrypt := range fieldsGroups {
-		// Iterate over each field in the current group
-		for _, field := range fieldsToEncrypt {
-			// Find the field in the map
-			fieldMappings := utils.FindMapFields(inputMaps, []string{field})
+// DecryptMaps decrypts, in place, the ciphertext under every key a field
+// was registered with, however deep in the maps and slices it is.
+func DecryptMaps(inputMaps interface{}) error {
+	registry.RLock()
+	defer registry.RUnlock()
 
-			if len(fieldMappings) == 0 {
-				continue
-			}
-
-			// Encrypt all occurrences of the field
-			for _, f := range fieldMappings {
-				// Get the field value
-				value := f.Parent.MapIndex(reflect.ValueOf(f.Field))
-				if !value.IsValid() {
-					return fmt.Errorf("Field '%s' not found in the map", f.Field)
-				}
-
-				// Dereference pointers or interfaces
-				for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
-					value = value.Elem()
-				}
-
-				// Encrypt the value
-				encryptValue, err := AESEncryptWithHeader(value)
-				if err != nil {
-					return err
-				}
+	return decryptMaps(reflect.ValueOf(inputMaps))
+}
 
-				// Update the map with the encrypted value
-				f.Parent.SetMapIndex(reflect.ValueOf(f.Field), encryptValue)
-			}
+func decryptMaps(value reflect.Value) error {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
 		}
+		value = value.Elem()
 	}
 
-	return nil
-}
-
-func DecryptMaps(inputMaps interface{}, fieldsGroups ...[]string) error {
-	// Iterate over each group of fields
-	for _, fieldsToDecrypt := range fieldsGroups {
-		// Iterate over each field in the current group
-		for _, field := range fieldsToDecrypt {
-			// Find the field in the map
-			fieldMappings := utils.FindMapFields(inputMaps, []string{field})
-
-			if len(fieldMappings) == 0 {
-				continue
-			}
-
-			// Decrypt all occurrences of the field
-			for _, f := range fieldMappings {
-				// Get the field value
-				value := f.Parent.MapIndex(reflect.ValueOf(f.Field))
-				if !value.IsValid() {
-					return fmt.Errorf("Field '%s' not found in the map", f.Field)
-				}
-
-				// Dereference pointers or interfaces
-				for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
-					value = value.Elem()
-				}
+	switch value.Kind() {
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil
+		}
+		for _, key := range value.MapKeys() {
+			item := value.MapIndex(key)
 
-				// Decrypt the value
-				decryptedValue, err := AESDecryptWithHeader(value)
+			if raw, ok := item.Interface().([]byte); ok && registry.names[key.String()] {
+				decrypted, err := AESDecryptWithHeader(reflect.ValueOf(raw))
 				if err != nil {
 					return err
 				}
+				value.SetMapIndex(key, decrypted)
+				continue
+			}
 
-				// Update the map with the decrypted value
-				f.Parent.SetMapIndex(reflect.ValueOf(f.Field), decryptedValue)
+			if err := decryptMaps(item); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return nil
+		}
+		for i := 0; i < value.Len(); i++ {
+			if err := decryptMaps(value.Index(i)); err != nil {
+				return err
 			}
 		}
 	}
-
 	return nil
 }
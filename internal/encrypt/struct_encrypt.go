@@ -1,69 +1,248 @@
 package encrypt
 
 import (
-	"alumni_api/internal/utils"
+	"context"
 	"fmt"
 	"reflect"
+	"sort"
+	"strings"
+	"sync"
 )
 
-// EncryptStruct encrypts specified fields in a struct, handling nested fields, arrays, and maps.
-func EncryptStruct(inputStruct interface{}, fieldsGroups ...[]string) error {
-	for _, fieldsToEncrypt := range fieldsGroups {
-		// Find the fields to encrypt in the struct
-		for _, field := range fieldsToEncrypt {
-			fieldValue := utils.FindStructFields(inputStruct, []string{field})
+// Fields are declared encrypted with a tag naming the property they are
+// stored as, then any other keys queries return them under:
+//
+//	Content customtypes.Encrypted[string] `mapstructure:"content" encrypt:"content,reply_content"`
+//
+// A field kept searchable also names the string field its blind index is
+// written to, with index:"ContentIndex".
+var registry = struct {
+	sync.RWMutex
 
-			if len(fieldValue) == 0 {
-				continue
-			}
+	// properties are the stored names, names add the aliases.
+	properties map[string]bool
+	names      map[string]bool
+	indexes    map[string]BlindIndex
+}{
+	properties: make(map[string]bool),
+	names:      make(map[string]bool),
+	indexes:    make(map[string]BlindIndex),
+}
 
-			for _, f := range fieldValue {
-				// Ensure the field is addressable (pointers or values)
-				if f.Kind() != reflect.Ptr && f.CanAddr() {
-					f = f.Addr()
-				}
+func isEncryptedType(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && strings.HasPrefix(t.String(), "customtypes.Encrypted[")
+}
 
-				// Call the 'Encrypt' method
-				encryptMethod := f.MethodByName("Encrypt")
-				if !encryptMethod.IsValid() {
-					return fmt.Errorf("Method 'Encrypt' not found for field '%s'", field)
-				}
+// Register records the encrypted fields of the given structs, and of the
+// structs they hold, along with the blind indexes of the indexed ones. It
+// fails when a tag does not match its field, so a model can't drift from
+// what is stored: every Encrypted field needs a tag, every tag an Encrypted
+// field named as it is stored, and every index a string field and a
+// BlindIndex of the property's name.
+func Register(indexes []BlindIndex, models ...interface{}) error {
+	registry.Lock()
+	defer registry.Unlock()
 
-				// Call Encrypt method
-				encryptMethod.Call([]reflect.Value{})
-			}
+	for _, index := range indexes {
+		registry.indexes[index.Name] = index
+	}
+
+	seen := make(map[reflect.Type]bool)
+	for _, model := range models {
+		if err := registerType(reflect.TypeOf(model), seen); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
-// DecryptStruct decrypts specified fields in a struct and reassigns them.
-func DecryptStruct(inputStruct interface{}, fieldsGroups ...[]string) error {
-	for _, fieldsToDecrypt := range fieldsGroups {
-		// Find the fields to decrypt in the struct
-		for _, field := range fieldsToDecrypt {
-			fieldValue := utils.FindStructFields(inputStruct, []string{field})
+func registerType(t reflect.Type, seen map[reflect.Type]bool) error {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || seen[t] {
+		return nil
+	}
+	seen[t] = true
 
-			if len(fieldValue) == 0 {
-				continue
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		tag, tagged := field.Tag.Lookup("encrypt")
+		if !isEncryptedType(field.Type) {
+			if tagged {
+				return fmt.Errorf("%s.%s is tagged encrypt but is not Encrypted", t.Name(), field.Name)
 			}
+			if err := registerType(field.Type, seen); err != nil {
+				return err
+			}
+			continue
+		}
 
-			for _, f := range fieldValue {
-				// Ensure the field is addressable (pointers or values)
-				if f.Kind() != reflect.Ptr && f.CanAddr() {
-					f = f.Addr()
-				}
+		if tag == "" {
+			return fmt.Errorf("%s.%s is Encrypted but has no encrypt tag", t.Name(), field.Name)
+		}
+		names := strings.Split(tag, ",")
+		if stored := strings.Split(field.Tag.Get("mapstructure"), ",")[0]; stored != names[0] {
+			return fmt.Errorf("%s.%s is stored as %q but its encrypt tag says %q", t.Name(), field.Name, stored, names[0])
+		}
 
-				// Call the 'Decrypt' method
-				decryptMethod := f.MethodByName("Decrypt")
-				if !decryptMethod.IsValid() {
-					return fmt.Errorf("Method 'Decrypt' not found for field '%s'", field)
-				}
+		if index, ok := field.Tag.Lookup("index"); ok {
+			sibling, found := t.FieldByName(index)
+			if !found || sibling.Type.Kind() != reflect.String {
+				return fmt.Errorf("%s.%s has no string field %s to index into", t.Name(), field.Name, index)
+			}
+			if _, ok := registry.indexes[names[0]]; !ok {
+				return fmt.Errorf("%s.%s has no blind index %q", t.Name(), field.Name, names[0])
+			}
+		}
+
+		registry.properties[names[0]] = true
+		for _, name := range names {
+			registry.names[name] = true
+		}
+	}
+	return nil
+}
+
+// EncryptedProperties returns the names registered fields are stored as.
+func EncryptedProperties() []string {
+	registry.RLock()
+	defer registry.RUnlock()
+
+	properties := make([]string, 0, len(registry.properties))
+	for property := range registry.properties {
+		properties = append(properties, property)
+	}
+	sort.Strings(properties)
+	return properties
+}
+
+// walkEncrypted calls fn with every field tagged encrypt in value, which is
+// reached through a pointer so the fields can be set, and its parent struct.
+func walkEncrypted(value reflect.Value, fn func(parent reflect.Value, field reflect.StructField, encrypted reflect.Value) error) error {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
 
-				// Call Decrypt method
-				decryptMethod.Call([]reflect.Value{})
+	switch value.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			if err := walkEncrypted(value.Index(i), fn); err != nil {
+				return err
 			}
 		}
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if _, ok := field.Tag.Lookup("encrypt"); ok {
+				if err := fn(value, field, value.Field(i)); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := walkEncrypted(value.Field(i), fn); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// callMethod calls the Encrypt or Decrypt method of an Encrypted field.
+func callMethod(field reflect.StructField, value reflect.Value, name string) error {
+	if !value.CanAddr() {
+		return fmt.Errorf("Field '%s' cannot be set, pass a pointer", field.Name)
+	}
+	out := value.Addr().MethodByName(name).Call(nil)
+	if err, _ := out[0].Interface().(error); err != nil {
+		return fmt.Errorf("Field '%s': %w", field.Name, err)
 	}
 	return nil
 }
+
+// record identifies a struct holding encrypted fields. Its address alone
+// is not enough: a struct shares it with its first field.
+type record struct {
+	address uintptr
+	t       reflect.Type
+}
+
+// EncryptStruct encrypts every field tagged encrypt that is set, in a struct
+// and the structs and slices it holds, and fills in their blind indexes. The
+// fields of one struct are one record, such as a profile's StudentInfo or
+// each of its Companies, and are sealed under one data key, so the provider
+// is asked to wrap one key per record rather than one per field.
+func EncryptStruct(inputStruct interface{}) error {
+	e := envelope.Load()
+	keys := make(map[record]*dataKey)
+
+	return walkEncrypted(reflect.ValueOf(inputStruct), func(parent reflect.Value, field reflect.StructField, encrypted reflect.Value) error {
+		value := encrypted.FieldByName("Value")
+		if value.IsZero() {
+			return nil
+		}
+		if !parent.CanAddr() {
+			return fmt.Errorf("Field '%s' cannot be set, pass a pointer", field.Name)
+		}
+
+		if index, ok := field.Tag.Lookup("index"); ok {
+			property := strings.Split(field.Tag.Get("encrypt"), ",")[0]
+
+			registry.RLock()
+			blindIndex, found := registry.indexes[property]
+			registry.RUnlock()
+			if !found {
+				return fmt.Errorf("No blind index registered for '%s'", property)
+			}
+
+			token, err := blindIndex.Token(value)
+			if err != nil {
+				return err
+			}
+			parent.FieldByName(index).SetString(token)
+		}
+
+		data, err := convertToBytesWithHeader(value)
+		if err != nil {
+			return fmt.Errorf("Field '%s': %w", field.Name, err)
+		}
+
+		// Encrypted[T] has no context to pass on, so calls to the key
+		// provider are bounded by its own timeout.
+		id := record{address: parent.Addr().Pointer(), t: parent.Type()}
+		key, ok := keys[id]
+		if !ok {
+			if key, err = e.newDataKey(context.Background()); err != nil {
+				return fmt.Errorf("Field '%s': %w", field.Name, err)
+			}
+			keys[id] = key
+		}
+
+		raw, err := key.seal(data)
+		if err != nil {
+			return fmt.Errorf("Field '%s': %w", field.Name, err)
+		}
+		encrypted.FieldByName("Raw").SetBytes(raw)
+		return nil
+	})
+}
+
+// DecryptStruct decrypts every field tagged encrypt that holds ciphertext.
+func DecryptStruct(inputStruct interface{}) error {
+	return walkEncrypted(reflect.ValueOf(inputStruct), func(parent reflect.Value, field reflect.StructField, encrypted reflect.Value) error {
+		if encrypted.FieldByName("Raw").Len() == 0 {
+			return nil
+		}
+		return callMethod(field, encrypted, "Decrypt")
+	})
+}
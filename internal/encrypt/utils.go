@@ -2,9 +2,9 @@ package encrypt
 
 import (
 	"bytes"
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
-	"crypto/rand"
 	"encoding/binary"
 	"errors"
 	"fmt"
@@ -111,17 +111,17 @@ func convertFromBytesWithHeader(data []byte) (reflect.Value, error) {
 }
 
 // Encrypt data with AES and a type header
-func encryptAESWithHeader(input reflect.Value, key []byte) ([]byte, error) {
+func encryptAESWithHeader(input reflect.Value) ([]byte, error) {
 	dataWithHeader, err := convertToBytesWithHeader(input)
 	if err != nil {
 		return nil, err
 	}
-	return encryptAES(dataWithHeader, key)
+	return encryptAES(dataWithHeader)
 }
 
 // Decrypt data with AES and extract type using the header
-func decryptAESWithHeader(data []byte, key []byte) (reflect.Value, error) {
-	decrypted, err := decryptAES(data, key)
+func decryptAESWithHeader(data []byte) (reflect.Value, error) {
+	decrypted, err := decryptAES(data)
 	if err != nil {
 		return reflect.Value{}, err
 	}
@@ -164,36 +164,26 @@ func convertFromBytes(data []byte, kind reflect.Kind) (reflect.Value, error) {
 	}
 }
 
-func encryptAES(data []byte, key []byte) ([]byte, error) {
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, err
-	}
-
-	iv := make([]byte, aes.BlockSize)
-	if _, err := rand.Read(iv); err != nil {
-		return nil, err
-	}
-
-	data = PKCS7Pad(data, aes.BlockSize)
-	ciphertext := make([]byte, len(data))
-	mode := cipher.NewCBCEncrypter(block, iv)
-	mode.CryptBlocks(ciphertext, data)
+// encryptAES seals data in an envelope. Encrypted[T] has no context to
+// pass on, so calls to the key provider are bounded by its own timeout.
+func encryptAES(data []byte) ([]byte, error) {
+	return envelope.Load().seal(context.Background(), data)
+}
 
-	return append(iv, ciphertext...), nil
+// decryptAES opens an envelope, or a value written before envelope
+// encryption.
+func decryptAES(data []byte) ([]byte, error) {
+	return envelope.Load().open(context.Background(), data)
 }
 
-func decryptAES(data []byte, key []byte) ([]byte, error) {
-	if len(data) < aes.BlockSize {
-		return nil, errors.New("ciphertext too short")
+// decryptAESCBC reads the IV-prefixed, PKCS7 padded AES-CBC ciphertext data
+// was encrypted to before keys had versions.
+func decryptAESCBC(data []byte, block cipher.Block) ([]byte, error) {
+	if len(data) < 2*aes.BlockSize || len(data)%aes.BlockSize != 0 {
+		return nil, errors.New("invalid ciphertext length")
 	}
 	iv, ciphertext := data[:aes.BlockSize], data[aes.BlockSize:]
 
-	block, err := aes.NewCipher(key)
-	if err != nil {
-		return nil, err
-	}
-
 	mode := cipher.NewCBCDecrypter(block, iv)
 	decrypted := make([]byte, len(ciphertext))
 	mode.CryptBlocks(decrypted, ciphertext)
@@ -0,0 +1,140 @@
+package encrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// VaultConfig points at a transit secrets engine of HashiCorp Vault, or of
+// anything speaking its API such as OpenBao.
+type VaultConfig struct {
+	Address string
+	Token   string
+	Mount   string
+	Key     string
+}
+
+// Vault wraps data keys with a Vault transit key. Vault versions the key
+// itself and prefixes every ciphertext with the version, "vault:v1:...", so
+// rotation is "vault write -f transit/keys/<key>/rotate" followed by the
+// re-encryption job.
+type Vault struct {
+	cfg    VaultConfig
+	base   *url.URL
+	client *http.Client
+}
+
+func NewVault(cfg VaultConfig) (*Vault, error) {
+	base, err := url.Parse(cfg.Address)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("encrypt: invalid Vault address %q", cfg.Address)
+	}
+	if cfg.Token == "" {
+		return nil, errors.New("encrypt: Vault token is required")
+	}
+	if cfg.Mount == "" {
+		cfg.Mount = "transit"
+	}
+	if cfg.Key == "" {
+		return nil, errors.New("encrypt: Vault transit key is required")
+	}
+
+	return &Vault{
+		cfg:    cfg,
+		base:   base,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+type vaultResponse struct {
+	Data struct {
+		Ciphertext string `json:"ciphertext"`
+		Plaintext  string `json:"plaintext"`
+	} `json:"data"`
+	Errors []string `json:"errors"`
+}
+
+// call POSTs body to the transit endpoint op, e.g. "encrypt", for the
+// configured key.
+func (v *Vault) call(ctx context.Context, op string, body map[string]string) (vaultResponse, error) {
+	var out vaultResponse
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return out, err
+	}
+
+	u := v.base.JoinPath("v1", v.cfg.Mount, op, v.cfg.Key)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u.String(), bytes.NewReader(payload))
+	if err != nil {
+		return out, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Vault-Token", v.cfg.Token)
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return out, fmt.Errorf("encrypt: Vault %s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil && resp.StatusCode == http.StatusOK {
+		return out, fmt.Errorf("encrypt: Vault %s: %w", op, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return out, fmt.Errorf("encrypt: Vault %s: %s: %s", op, resp.Status, strings.Join(out.Errors, "; "))
+	}
+
+	return out, nil
+}
+
+func (v *Vault) WrapKey(ctx context.Context, key []byte) ([]byte, error) {
+	out, err := v.call(ctx, "encrypt", map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(out.Data.Ciphertext), nil
+}
+
+func (v *Vault) UnwrapKey(ctx context.Context, wrapped []byte) ([]byte, error) {
+	out, err := v.call(ctx, "decrypt", map[string]string{
+		"ciphertext": string(wrapped),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(out.Data.Plaintext)
+}
+
+// RewrapKey has Vault wrap the key again under the latest version of the
+// transit key, without the key ever leaving Vault.
+func (v *Vault) RewrapKey(ctx context.Context, wrapped []byte) ([]byte, bool, error) {
+	out, err := v.call(ctx, "rewrap", map[string]string{
+		"ciphertext": string(wrapped),
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	rewrapped := []byte(out.Data.Ciphertext)
+	return rewrapped, vaultKeyVersion(rewrapped) != vaultKeyVersion(wrapped), nil
+}
+
+// vaultKeyVersion returns the "v1" of "vault:v1:...".
+func vaultKeyVersion(ciphertext []byte) string {
+	parts := strings.SplitN(string(ciphertext), ":", 3)
+	if len(parts) != 3 {
+		return ""
+	}
+	return parts[1]
+}
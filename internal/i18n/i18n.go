@@ -0,0 +1,88 @@
+// Package i18n translates the messages the API answers with. Messages are
+// keyed by their English text, so English needs no catalog and a message
+// missing from one is sent in English.
+package i18n
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Default is the language used when the client asks for none we support.
+const Default = "en"
+
+// Languages are the supported languages, Default first.
+var Languages = []string{Default, "th"}
+
+var catalogs = map[string]map[string]string{
+	"th": th,
+}
+
+// pattern matches messages built with fmt.Sprintf from a catalog entry such
+// as "User: %s not found".
+type pattern struct {
+	re          *regexp.Regexp
+	translation string
+}
+
+var patterns = map[string][]pattern{}
+
+func init() {
+	for language, catalog := range catalogs {
+		for message, translation := range catalog {
+			if !strings.Contains(message, "%s") {
+				continue
+			}
+
+			parts := strings.Split(message, "%s")
+			for i, part := range parts {
+				parts[i] = regexp.QuoteMeta(part)
+			}
+			re := regexp.MustCompile("^" + strings.Join(parts, "(.+?)") + "$")
+			patterns[language] = append(patterns[language], pattern{re: re, translation: translation})
+		}
+
+		// Longer formats first, so "Receive User: %s not found" is not taken
+		// for "User: %s not found".
+		sort.Slice(patterns[language], func(i, j int) bool {
+			a, b := patterns[language][i].re.String(), patterns[language][j].re.String()
+			if len(a) != len(b) {
+				return len(a) > len(b)
+			}
+			return a < b
+		})
+	}
+}
+
+// Supported reports whether language is one of Languages.
+func Supported(language string) bool {
+	return slices.Contains(Languages, language)
+}
+
+// T translates message into language, keeping the arguments of a formatted
+// message. Messages without a translation are returned unchanged.
+func T(language, message string) string {
+	catalog, ok := catalogs[language]
+	if !ok {
+		return message
+	}
+
+	if translation, ok := catalog[message]; ok {
+		return translation
+	}
+
+	for _, p := range patterns[language] {
+		if match := p.re.FindStringSubmatch(message); match != nil {
+			args := make([]interface{}, len(match)-1)
+			for i, arg := range match[1:] {
+				args[i] = arg
+			}
+			return fmt.Sprintf(p.translation, args...)
+		}
+	}
+
+	return message
+}
@@ -0,0 +1,198 @@
+package i18n
+
+// th is the Thai catalog. Formats keep their verbs in order, or number them
+// when Thai puts the arguments the other way round.
+var th = map[string]string{
+	// Authentication and sessions
+	"Login Succesfully":                       "เข้าสู่ระบบสำเร็จ",
+	"Logout Succesfully":                      "ออกจากระบบสำเร็จ",
+	"Registry Succesfully":                    "ลงทะเบียนสำเร็จ",
+	"Verify Succesfully":                      "ยืนยันบัญชีสำเร็จ",
+	"Start Login Successfully":                "เริ่มการเข้าสู่ระบบสำเร็จ",
+	"Refresh Session Successfully":            "ต่ออายุเซสชันสำเร็จ",
+	"Get Sessions Successfully":               "ดึงข้อมูลเซสชันสำเร็จ",
+	"Revoke Session Successfully":             "ยกเลิกเซสชันสำเร็จ",
+	"Revoke Sessions Successfully":            "ยกเลิกเซสชันทั้งหมดสำเร็จ",
+	"Get Providers Successfully":              "ดึงรายชื่อผู้ให้บริการเข้าสู่ระบบสำเร็จ",
+	"Change Password Succesfully":             "เปลี่ยนรหัสผ่านสำเร็จ",
+	"Change Email Succesfully":                "เปลี่ยนอีเมลสำเร็จ",
+	"Request Reset Password Succesfully":      "ส่งคำขอรีเซ็ตรหัสผ่านสำเร็จ",
+	"Request Email Checkup Succesfully":       "ตรวจสอบอีเมลสำเร็จ",
+	"Update Language Successfully":            "เปลี่ยนภาษาสำเร็จ",
+	"Missing Token":                           "ไม่พบโทเค็น",
+	"Missing refresh token":                   "ไม่พบโทเค็นสำหรับต่ออายุ",
+	"Missing sign-in state":                   "ไม่พบสถานะการเข้าสู่ระบบ",
+	"Invalid refresh token":                   "โทเค็นสำหรับต่ออายุไม่ถูกต้อง",
+	"Invalid or expired token":                "โทเค็นไม่ถูกต้องหรือหมดอายุ",
+	"Invalid or expired challenge":            "คำขอยืนยันตัวตนไม่ถูกต้องหรือหมดอายุ",
+	"Refresh token reuse detected":            "ตรวจพบการใช้โทเค็นสำหรับต่ออายุซ้ำ",
+	"Session expired or revoked":              "เซสชันหมดอายุหรือถูกยกเลิก",
+	"Session has been revoked":                "เซสชันถูกยกเลิกแล้ว",
+	"Unauthorized":                            "ไม่ได้รับอนุญาต",
+	"Unauthorized claim":                      "ข้อมูลยืนยันตัวตนไม่ถูกต้อง",
+	"invalid password":                        "รหัสผ่านไม่ถูกต้อง",
+	"User Is Not Verify":                      "ผู้ใช้ยังไม่ได้ยืนยันบัญชี",
+	"Email already used":                      "อีเมลนี้ถูกใช้แล้ว",
+	"User already exist":                      "มีผู้ใช้นี้อยู่แล้ว",
+	"Email %s Already Exist":                  "อีเมล %s มีอยู่แล้ว",
+	"Link has expired":                        "ลิงก์หมดอายุแล้ว",
+	"Account is temporarily locked":           "บัญชีถูกล็อกชั่วคราว",
+	"Too many failed logins, try again later": "เข้าสู่ระบบไม่สำเร็จหลายครั้งเกินไป กรุณาลองใหม่ภายหลัง",
+	"Unlock Account Successfully":             "ปลดล็อกบัญชีสำเร็จ",
+	"Get Security Events Successfully":        "ดึงประวัติความปลอดภัยสำเร็จ",
+	"If this email match in the database the one time request will be send to your email": "หากอีเมลนี้ตรงกับในระบบ เราจะส่งลิงก์ลงทะเบียนแบบครั้งเดียวไปยังอีเมลของคุณ",
+	"Provider: %s not found": "ไม่พบผู้ให้บริการเข้าสู่ระบบ: %s",
+	"Session: %s not found":  "ไม่พบเซสชัน: %s",
+
+	// Two-factor authentication
+	"Two-Factor Authentication Required":                    "ต้องยืนยันตัวตนแบบสองขั้นตอน",
+	"Start Two-Factor Enrollment Successfully":              "เริ่มตั้งค่าการยืนยันตัวตนแบบสองขั้นตอนสำเร็จ",
+	"Enable Two-Factor Authentication Successfully":         "เปิดใช้การยืนยันตัวตนแบบสองขั้นตอนสำเร็จ",
+	"Disable Two-Factor Authentication Successfully":        "ปิดการยืนยันตัวตนแบบสองขั้นตอนสำเร็จ",
+	"Get Two-Factor Status Successfully":                    "ดึงสถานะการยืนยันตัวตนแบบสองขั้นตอนสำเร็จ",
+	"Update Two-Factor Requirement Successfully":            "ปรับข้อกำหนดการยืนยันตัวตนแบบสองขั้นตอนสำเร็จ",
+	"Regenerate Backup Codes Successfully":                  "สร้างรหัสสำรองใหม่สำเร็จ",
+	"Invalid two-factor code":                               "รหัสยืนยันตัวตนไม่ถูกต้อง",
+	"Too many invalid two-factor codes, try again later":    "ใส่รหัสยืนยันตัวตนผิดหลายครั้งเกินไป กรุณาลองใหม่ภายหลัง",
+	"Two-factor authentication is already enabled":          "เปิดใช้การยืนยันตัวตนแบบสองขั้นตอนอยู่แล้ว",
+	"Two-factor authentication is not enabled":              "ยังไม่ได้เปิดใช้การยืนยันตัวตนแบบสองขั้นตอน",
+	"Two-factor authentication is required on this account": "บัญชีนี้ต้องใช้การยืนยันตัวตนแบบสองขั้นตอน",
+	"Two-factor enrollment has not been started":            "ยังไม่ได้เริ่มตั้งค่าการยืนยันตัวตนแบบสองขั้นตอน",
+	"Failed to generate backup codes":                       "สร้างรหัสสำรองไม่สำเร็จ",
+	"Failed to generate two-factor secret":                  "สร้างรหัสลับสำหรับการยืนยันตัวตนไม่สำเร็จ",
+
+	// Roles, permissions and departments
+	"Get Permissions Successfully":                       "ดึงสิทธิ์สำเร็จ",
+	"Get Roles Successfully":                             "ดึงบทบาทสำเร็จ",
+	"Save Role Successfully":                             "บันทึกบทบาทสำเร็จ",
+	"Delete Role Successfully":                           "ลบบทบาทสำเร็จ",
+	"Assign Role Successfully":                           "มอบบทบาทสำเร็จ",
+	"Revoke Role Successfully":                           "ถอนบทบาทสำเร็จ",
+	"Get Departments Successfully":                       "ดึงข้อมูลภาควิชาสำเร็จ",
+	"Appoint Department Admin Successfully":              "แต่งตั้งผู้ดูแลภาควิชาสำเร็จ",
+	"Remove Department Admin Successfully":               "ถอดถอนผู้ดูแลภาควิชาสำเร็จ",
+	"Department admins cannot appoint department admins": "ผู้ดูแลภาควิชาไม่สามารถแต่งตั้งผู้ดูแลภาควิชาได้",
+	"Get Request Succesfully":                            "ดึงคำขอสำเร็จ",
+	"Already Approved Request":                           "คำขอนี้ได้รับการอนุมัติแล้ว",
+	"Missing permission: %s":                             "ไม่มีสิทธิ์: %s",
+	"Invalid role name: %s":                              "ชื่อบทบาทไม่ถูกต้อง: %s",
+	"Role: %s not found":                                 "ไม่พบบทบาท: %s",
+	"Role: %s is built in":                               "บทบาท %s เป็นบทบาทของระบบ",
+	"Role: %s is a base role":                            "บทบาท %s เป็นบทบาทพื้นฐาน",
+	"Role: %s is assigned per department":                "บทบาท %s ต้องมอบผ่านภาควิชา",
+	"Role: %s not assigned to user %s":                   "ผู้ใช้ %[2]s ไม่มีบทบาท %[1]s",
+	"Department: %s not found":                           "ไม่พบภาควิชา: %s",
+	"User: %s does not administer department %s":         "ผู้ใช้ %s ไม่ได้เป็นผู้ดูแลภาควิชา %s",
+	"Request: %s not found":                              "ไม่พบคำขอ: %s",
+	"You do not have permission to this profile":         "คุณไม่มีสิทธิ์เข้าถึงโปรไฟล์นี้",
+
+	// Users, companies and friends
+	"User created successfully":                "สร้างผู้ใช้สำเร็จ",
+	"User retrieved successfully":              "ดึงข้อมูลผู้ใช้สำเร็จ",
+	"User(s) retrieved successfully":           "ดึงข้อมูลผู้ใช้สำเร็จ",
+	"User profile updated successfully":        "อัปเดตโปรไฟล์สำเร็จ",
+	"User removed successfully":                "ลบผู้ใช้สำเร็จ",
+	"User company updated successfully":        "อัปเดตข้อมูลบริษัทสำเร็จ",
+	"User company removed successfully":        "ลบข้อมูลบริษัทสำเร็จ",
+	"User student info updated successfully":   "อัปเดตข้อมูลนักศึกษาสำเร็จ",
+	"User student info removed successfully":   "ลบข้อมูลนักศึกษาสำเร็จ",
+	"Company retrieved successfully":           "ดึงข้อมูลบริษัทสำเร็จ",
+	"Find Associate Users successfully":        "ค้นหาผู้ใช้ที่เกี่ยวข้องสำเร็จ",
+	"Online friends retrieved successfully":    "ดึงรายชื่อเพื่อนที่ออนไลน์สำเร็จ",
+	"Successfully retrieve friend of a friend": "ดึงข้อมูลเพื่อนของเพื่อนสำเร็จ",
+	"Successfully add user %s to user %s":      "เพิ่มผู้ใช้ %s เป็นเพื่อนของผู้ใช้ %s สำเร็จ",
+	"Successfully remove user %s from user %s": "ลบผู้ใช้ %s ออกจากเพื่อนของผู้ใช้ %s สำเร็จ",
+	"No friends found for this user":           "ไม่พบเพื่อนของผู้ใช้นี้",
+	"Cannot unfriend oneself":                  "ไม่สามารถเลิกเป็นเพื่อนกับตัวเองได้",
+	"User not found":                           "ไม่พบผู้ใช้",
+	"UserProfile not found":                    "ไม่พบโปรไฟล์ผู้ใช้",
+	"No user found with that ID":               "ไม่พบผู้ใช้ที่มี ID นี้",
+	"User or Company not found":                "ไม่พบผู้ใช้หรือบริษัท",
+	"User: %s not found":                       "ไม่พบผู้ใช้: %s",
+	"Receive User: %s not found":               "ไม่พบผู้รับ: %s",
+	"User: %s is not a member":                 "ผู้ใช้ %s ไม่ได้เป็นสมาชิก",
+
+	// Posts, comments and reports
+	"Create post Succesfully":                "สร้างโพสต์สำเร็จ",
+	"Update post Succesfully":                "อัปเดตโพสต์สำเร็จ",
+	"Deleted post Succesfully":               "ลบโพสต์สำเร็จ",
+	"Get Post Sucessfully":                   "ดึงโพสต์สำเร็จ",
+	"Get Feed Sucessfully":                   "ดึงฟีดสำเร็จ",
+	"Create comment Succesfully":             "แสดงความคิดเห็นสำเร็จ",
+	"Get Comment Sucessfully":                "ดึงความคิดเห็นสำเร็จ",
+	"Update comment %s Succesfully":          "อัปเดตความคิดเห็น %s สำเร็จ",
+	"Delete comment %s Succesfully":          "ลบความคิดเห็น %s สำเร็จ",
+	"Create like Succesfully":                "กดถูกใจสำเร็จ",
+	"Remove like Succesfully":                "ยกเลิกการถูกใจสำเร็จ",
+	"Fetch Report Succesfully":               "ดึงรายงานสำเร็จ",
+	"Report %s Succesfully":                  "รายงาน %s สำเร็จ",
+	"Post not found":                         "ไม่พบโพสต์",
+	"Comment not found":                      "ไม่พบความคิดเห็น",
+	"Post: %s not found":                     "ไม่พบโพสต์: %s",
+	"Post: %s has no group chat":             "โพสต์ %s ไม่มีแชทกลุ่ม",
+	"Only event posts can have a group chat": "เฉพาะโพสต์กิจกรรมเท่านั้นที่มีแชทกลุ่มได้",
+
+	// Messages, conversations and attachments
+	"Send Message Successfully":                         "ส่งข้อความสำเร็จ",
+	"Send Reply Message Successfully":                   "ตอบกลับข้อความสำเร็จ",
+	"Edit Message Successfully":                         "แก้ไขข้อความสำเร็จ",
+	"Delete Message Successfully":                       "ลบข้อความสำเร็จ",
+	"Get Messages Successfully":                         "ดึงข้อความสำเร็จ",
+	"Get Chat Message Successfully":                     "ดึงข้อความแชทสำเร็จ",
+	"Create Conversation Successfully":                  "สร้างการสนทนาสำเร็จ",
+	"Get Conversation Successfully":                     "ดึงการสนทนาสำเร็จ",
+	"Get Conversations Successfully":                    "ดึงรายการสนทนาสำเร็จ",
+	"Update Conversation Successfully":                  "อัปเดตการสนทนาสำเร็จ",
+	"Invite Members Successfully":                       "เชิญสมาชิกสำเร็จ",
+	"Remove Member Successfully":                        "นำสมาชิกออกสำเร็จ",
+	"Update Member Role Successfully":                   "เปลี่ยนบทบาทสมาชิกสำเร็จ",
+	"Leave Conversation Successfully":                   "ออกจากการสนทนาสำเร็จ",
+	"Join Event Chat Successfully":                      "เข้าร่วมแชทกิจกรรมสำเร็จ",
+	"Your conversation role does not allow this action": "บทบาทของคุณในการสนทนานี้ไม่อนุญาตให้ทำรายการนี้",
+	"The owner cannot change their own role":            "เจ้าของการสนทนาไม่สามารถเปลี่ยนบทบาทของตนเองได้",
+	"Conversation: %s not found":                        "ไม่พบการสนทนา: %s",
+	"Message: %s not found":                             "ไม่พบข้อความ: %s",
+	"Reply message: %s not found":                       "ไม่พบข้อความที่ตอบกลับ: %s",
+	"Upload Attachment Successfully":                    "อัปโหลดไฟล์แนบสำเร็จ",
+	"Get Attachment URL Successfully":                   "ดึงลิงก์ไฟล์แนบสำเร็จ",
+	"Upload successfully":                               "อัปโหลดสำเร็จ",
+	"Attachment is already attached to a message":       "ไฟล์แนบนี้ถูกแนบกับข้อความแล้ว",
+	"Attachment: %s not found":                          "ไม่พบไฟล์แนบ: %s",
+	"File is required":                                  "กรุณาแนบไฟล์",
+	"File not found":                                    "ไม่พบไฟล์",
+	"File too large":                                    "ไฟล์มีขนาดใหญ่เกินไป",
+	"Invalid image":                                     "รูปภาพไม่ถูกต้อง",
+	"Image dimensions are too large":                    "รูปภาพมีขนาดใหญ่เกินไป",
+	"Invalid signature":                                 "ลายเซ็นไม่ถูกต้อง",
+
+	// Statistics and mail
+	"Get Post Statistic Sucessfully":     "ดึงสถิติโพสต์สำเร็จ",
+	"Get Registry Statistic Sucessfully": "ดึงสถิติการลงทะเบียนสำเร็จ",
+	"Get Activity Statistic Sucessfully": "ดึงสถิติกิจกรรมสำเร็จ",
+	"Get Dead Letters Successfully":      "ดึงอีเมลที่ส่งไม่สำเร็จสำเร็จ",
+	"Retry Dead Letter Successfully":     "ส่งอีเมลที่ส่งไม่สำเร็จใหม่สำเร็จ",
+	"Dead letter: %s not found":          "ไม่พบอีเมลที่ส่งไม่สำเร็จ: %s",
+
+	// Validation
+	"Validation failed":                "ข้อมูลไม่ถูกต้อง",
+	"Invalid request payload":          "ข้อมูลคำขอไม่ถูกต้อง",
+	"Invalid query parameters":         "พารามิเตอร์ไม่ถูกต้อง",
+	"Invalid pagination cursor":        "ตำแหน่งหน้าไม่ถูกต้อง",
+	"Invalid data format":              "รูปแบบข้อมูลไม่ถูกต้อง",
+	"Invalid ID format":                "รูปแบบ ID ไม่ถูกต้อง",
+	"ID is required":                   "กรุณาระบุ ID",
+	"Field '%s' is required":           "กรุณาระบุ '%s'",
+	"Query parameter '%s' is required": "กรุณาระบุพารามิเตอร์ '%s'",
+
+	// Server errors
+	"Internal server error":  "เกิดข้อผิดพลาดภายในระบบ",
+	"Error retrieving data":  "ดึงข้อมูลไม่สำเร็จ",
+	"Failed to create post":  "สร้างโพสต์ไม่สำเร็จ",
+	"Failed to update post":  "อัปเดตโพสต์ไม่สำเร็จ",
+	"Failed to delete post":  "ลบโพสต์ไม่สำเร็จ",
+	"Failed to send message": "ส่งข้อความไม่สำเร็จ",
+	"Failed to Send Message": "ส่งข้อความไม่สำเร็จ",
+	"Failed to save file":    "บันทึกไฟล์ไม่สำเร็จ",
+	"Failed to read file":    "อ่านไฟล์ไม่สำเร็จ",
+	"Failed to enqueue mail": "เพิ่มอีเมลเข้าคิวไม่สำเร็จ",
+}
@@ -0,0 +1,31 @@
+package mailer
+
+import (
+	"context"
+	"net/mail"
+	"os"
+	"path/filepath"
+)
+
+// File writes each message as an .eml file in a directory instead of
+// sending it, for development and staging.
+type File struct {
+	dir  string
+	from mail.Address
+}
+
+func NewFile(dir string, from mail.Address) (*File, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	return &File{dir: dir, from: from}, nil
+}
+
+func (f *File) Send(ctx context.Context, msg Message) error {
+	data, err := encode(f.from, msg)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(f.dir, messageID(msg)+".eml"), data, 0o600)
+}
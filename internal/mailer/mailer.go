@@ -0,0 +1,92 @@
+// Package mailer renders outbox mails from the mail_format templates and
+// delivers them through a transport chosen by config.MailConfig: SMTP,
+// SendGrid, a directory of .eml files or memory.
+package mailer
+
+import (
+	"alumni_api/config"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"time"
+)
+
+var ErrUnknownTemplate = errors.New("mailer: unknown template")
+
+// Message is a rendered mail, ready to be sent.
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+}
+
+// Mailer delivers messages. Send returning nil means the message was
+// accepted for delivery, not that it reached the inbox.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// New returns the mailer selected by cfg.Driver.
+func New(cfg config.MailConfig) (Mailer, error) {
+	from := mail.Address{Name: cfg.FromName, Address: cfg.FromAddress}
+
+	switch cfg.Driver {
+	case "", "smtp":
+		return NewSMTP(SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     from,
+		}), nil
+	case "sendgrid":
+		return NewSendGrid(cfg.SendGridAPIKey, from), nil
+	case "file":
+		return NewFile(cfg.FileDir, from)
+	case "memory":
+		return NewMemory(), nil
+	}
+
+	return nil, fmt.Errorf("mailer: unknown driver %q", cfg.Driver)
+}
+
+// encode builds the RFC 5322 form of msg. The subject is encoded for
+// non-ASCII text such as Thai, and the body is quoted-printable so it passes
+// servers without 8BITMIME.
+func encode(from mail.Address, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+
+	headers := [][2]string{
+		{"From", from.String()},
+		{"To", msg.To},
+		{"Subject", mime.QEncoding.Encode("UTF-8", msg.Subject)},
+		{"Date", time.Now().Format(time.RFC1123Z)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", `text/html; charset="UTF-8"`},
+		{"Content-Transfer-Encoding", "quoted-printable"},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&buf, "%s: %s\r\n", header[0], header[1])
+	}
+	buf.WriteString("\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	if _, err := body.Write([]byte(msg.HTML)); err != nil {
+		return nil, err
+	}
+	if err := body.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// messageID names a message for files and logs.
+func messageID(msg Message) string {
+	return fmt.Sprintf("%d-%s", time.Now().UnixNano(), base64.RawURLEncoding.EncodeToString([]byte(msg.To)))
+}
@@ -0,0 +1,42 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+
+	"github.com/sendgrid/sendgrid-go"
+	sgmail "github.com/sendgrid/sendgrid-go/helpers/mail"
+)
+
+// SendGrid sends through the SendGrid v3 API.
+type SendGrid struct {
+	client *sendgrid.Client
+	from   mail.Address
+}
+
+func NewSendGrid(apiKey string, from mail.Address) *SendGrid {
+	return &SendGrid{client: sendgrid.NewSendClient(apiKey), from: from}
+}
+
+func (s *SendGrid) Send(ctx context.Context, msg Message) error {
+	message := sgmail.NewSingleEmail(
+		sgmail.NewEmail(s.from.Name, s.from.Address),
+		msg.Subject,
+		sgmail.NewEmail("", msg.To),
+		"",
+		msg.HTML,
+	)
+
+	res, err := s.client.SendWithContext(ctx, message)
+	if err != nil {
+		return fmt.Errorf("sendgrid: %w", err)
+	}
+
+	// The API answers errors such as a bad key with a status, not an error.
+	if res.StatusCode >= 300 {
+		return fmt.Errorf("sendgrid: status %d: %s", res.StatusCode, res.Body)
+	}
+
+	return nil
+}
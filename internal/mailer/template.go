@@ -0,0 +1,81 @@
+package mailer
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/utils/mail_format"
+	"bytes"
+	"fmt"
+	"html/template"
+	"maps"
+)
+
+type localizedTemplate struct {
+	subject string
+	title   string
+	page    *template.Template
+}
+
+// Renderer turns outbox mails into messages. Every template is parsed up
+// front so a broken one stops the server from starting rather than failing
+// in the worker.
+type Renderer struct {
+	client    string
+	templates map[string]map[string]localizedTemplate
+}
+
+// NewRenderer parses mail_format.Templates. Links in the mails point to
+// client, the web app's base URL.
+func NewRenderer(client string) (*Renderer, error) {
+	r := &Renderer{client: client, templates: map[string]map[string]localizedTemplate{}}
+
+	for name, versions := range mail_format.Templates {
+		r.templates[name] = map[string]localizedTemplate{}
+		for language, version := range versions {
+			page, err := template.New("layout").Parse(mail_format.Layout)
+			if err == nil {
+				_, err = page.New("content").Parse(version.Content)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("mailer: template %s (%s): %w", name, language, err)
+			}
+
+			r.templates[name][language] = localizedTemplate{subject: version.Subject, title: version.Title, page: page}
+		}
+	}
+
+	return r, nil
+}
+
+// Render renders mail in its language, or in mail_format.DefaultLanguage when
+// the template has no version in it.
+func (r *Renderer) Render(mail models.Mail) (Message, error) {
+	versions, ok := r.templates[mail.Template]
+	if !ok {
+		return Message{}, fmt.Errorf("%w: %s", ErrUnknownTemplate, mail.Template)
+	}
+
+	language := mail.Language
+	version, ok := versions[language]
+	if !ok {
+		language = mail_format.DefaultLanguage
+		version, ok = versions[language]
+	}
+	if !ok {
+		return Message{}, fmt.Errorf("%w: %s (%s)", ErrUnknownTemplate, mail.Template, mail.Language)
+	}
+
+	data := maps.Clone(mail.Data)
+	if data == nil {
+		data = map[string]string{}
+	}
+	data["Client"] = r.client
+	data["Lang"] = language
+	data["Title"] = version.title
+
+	var html bytes.Buffer
+	if err := version.page.Execute(&html, data); err != nil {
+		return Message{}, fmt.Errorf("mailer: render %s: %w", mail.Template, err)
+	}
+
+	return Message{To: mail.To, Subject: version.subject, HTML: html.String()}, nil
+}
@@ -0,0 +1,113 @@
+package mailer
+
+import (
+	"alumni_api/config"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"context"
+	"errors"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// claimLease is how long a claimed mail is left alone before it is due
+// again, should the worker sending it die.
+const claimLease = 5 * time.Minute
+
+// Worker sends the mails waiting in the outbox. Failed mails are retried
+// with exponential backoff and become dead letters after
+// config.MailConfig.MaxAttempts.
+type Worker struct {
+	outbox   repositories.OutboxRepository
+	mailer   Mailer
+	renderer *Renderer
+	cfg      config.MailConfig
+	logger   *zap.Logger
+}
+
+func NewWorker(outbox repositories.OutboxRepository, mailer Mailer, cfg config.MailConfig, logger *zap.Logger) (*Worker, error) {
+	renderer, err := NewRenderer(cfg.Client)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Worker{outbox: outbox, mailer: mailer, renderer: renderer, cfg: cfg, logger: logger}, nil
+}
+
+// Run flushes the outbox every poll interval until ctx is done.
+func (w *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.Flush(ctx); err != nil {
+			w.logger.Error("Failed to flush outbox", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush sends the mails that are due, a batch at a time, until none are
+// left.
+func (w *Worker) Flush(ctx context.Context) error {
+	for {
+		mails, err := w.outbox.ClaimMails(ctx, w.cfg.BatchSize, claimLease, w.logger)
+		if err != nil {
+			return err
+		}
+
+		for _, mail := range mails {
+			w.deliver(ctx, mail)
+		}
+
+		if len(mails) < w.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+func (w *Worker) deliver(ctx context.Context, mail models.Mail) {
+	msg, err := w.renderer.Render(mail)
+	if err == nil {
+		err = w.mailer.Send(ctx, msg)
+	}
+
+	if err == nil {
+		if err := w.outbox.MarkMailSent(ctx, mail.MailID, w.logger); err != nil {
+			w.logger.Error("Failed to mark mail sent", zap.String("mail_id", mail.MailID), zap.Error(err))
+		}
+		return
+	}
+
+	// A missing template will not appear on a retry.
+	dead := errors.Is(err, ErrUnknownTemplate) || mail.Attempts >= int64(w.cfg.MaxAttempts)
+	next := time.Now().Add(w.backoff(mail.Attempts)).UnixMilli()
+
+	w.logger.Warn("Failed to send mail",
+		zap.String("mail_id", mail.MailID),
+		zap.String("template", mail.Template),
+		zap.Int64("attempts", mail.Attempts),
+		zap.Bool("dead", dead),
+		zap.Error(err),
+	)
+
+	if err := w.outbox.MarkMailFailed(ctx, mail.MailID, err.Error(), next, dead, w.logger); err != nil {
+		w.logger.Error("Failed to mark mail failed", zap.String("mail_id", mail.MailID), zap.Error(err))
+	}
+}
+
+// backoff is the wait after the given number of attempts: BaseBackoff,
+// doubled for every attempt after the first, up to MaxBackoff.
+func (w *Worker) backoff(attempts int64) time.Duration {
+	delay := w.cfg.BaseBackoff
+	for i := int64(1); i < attempts && delay < w.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	return min(delay, w.cfg.MaxBackoff)
+}
@@ -3,12 +3,15 @@ package middlewares
 import (
 	"alumni_api/internal/auth"
 	"alumni_api/internal/controllers"
+	"alumni_api/internal/repositories"
 
 	"github.com/gofiber/fiber/v2"
 	"go.uber.org/zap"
 )
 
-func JWTMiddleware(logger *zap.Logger) fiber.Handler {
+// JWTMiddleware accepts an access token only while the session it was issued
+// for is active, so logging out or revoking a device takes effect at once.
+func JWTMiddleware(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// tokenString, ok := auth.ExtractJWT(c)
 		tokenString, ok := auth.ExtractJWT_Cookie(c)
@@ -16,9 +19,9 @@ func JWTMiddleware(logger *zap.Logger) fiber.Handler {
 			return controllers.HandleFail(c, fiber.StatusUnauthorized, "Missing Token", logger, nil)
 		}
 
-		claims, err := auth.ParseJWT(tokenString)
+		claims, err := controllers.SessionClaims(c, store, tokenString, logger)
 		if err != nil {
-			return controllers.HandleFail(c, fiber.StatusUnauthorized, "Invalid or expired token", logger, nil)
+			return controllers.HandleFailWithStatus(c, err, logger)
 		}
 
 		// Store claims in the context
@@ -28,7 +31,7 @@ func JWTMiddleware(logger *zap.Logger) fiber.Handler {
 }
 
 // Authenticated WebSocket Upgrade Middleware
-func WebSocketMiddleware(logger *zap.Logger) fiber.Handler {
+func WebSocketMiddleware(store *repositories.Store, logger *zap.Logger) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		// Extract token from headers
 		token := c.Get("Sec-WebSocket-Protocol") // WebSocket can't send Authorization header
@@ -37,9 +40,9 @@ func WebSocketMiddleware(logger *zap.Logger) fiber.Handler {
 		}
 
 		// Validate JWT
-		claims, err := auth.ParseJWT(token)
+		claims, err := controllers.SessionClaims(c, store, token, logger)
 		if err != nil {
-			return controllers.HandleFail(c, fiber.StatusUnauthorized, "Invalid or expired token", logger, nil)
+			return controllers.HandleFailWithStatus(c, err, logger)
 		}
 
 		c.Locals("claims", claims)
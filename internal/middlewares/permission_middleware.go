@@ -0,0 +1,21 @@
+package middlewares
+
+import (
+	"alumni_api/internal/controllers"
+	"alumni_api/internal/repositories"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// RequirePermission lets a request through only when the caller holds every
+// one of permissions. It goes after JWTMiddleware.
+func RequirePermission(store *repositories.Store, logger *zap.Logger, permissions ...string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := controllers.RequirePermissions(c, store, logger, permissions...); err != nil {
+			return controllers.HandleFailWithStatus(c, err, logger)
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import (
+	"alumni_api/pkg/customtypes"
+)
+
+// Attachment describes a file uploaded for a message. The file itself lives
+// under StorageKey in the private attachment directory; the node only holds
+// its metadata and who uploaded it.
+type Attachment struct {
+	AttachmentID string                        `json:"attachment_id,omitempty" mapstructure:"attachment_id"`
+	UploaderID   string                        `json:"uploader_id,omitempty" mapstructure:"uploader_id"`
+	FileName     customtypes.Encrypted[string] `json:"file_name,omitempty" mapstructure:"file_name" encrypt:"file_name"`
+	ContentType  string                        `json:"content_type,omitempty" mapstructure:"content_type"`
+	Size         int64                         `json:"size,omitempty" mapstructure:"size"`
+	StorageKey   string                        `json:"-" mapstructure:"storage_key"`
+}
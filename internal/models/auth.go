@@ -1,12 +1,18 @@
 package models
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"alumni_api/pkg/customtypes"
+
+	"github.com/golang-jwt/jwt/v5"
+)
 
 type Claims struct {
 	UserID       string `json:"user_id"`
 	Role         string `json:"role"`
 	DepartmentID string `json:"department_id,omitempty"`
+	Language     string `json:"lang,omitempty"`
 	AdmitYear    int    `json:"admit_year,omitempty"`
+	SessionID    string `json:"sid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -15,6 +21,15 @@ type OneTimeRegistryJWT struct {
 	jwt.RegisteredClaims
 }
 
+// MFAChallenge is handed out after a correct password on an account with
+// two-factor authentication. It carries the user in Subject so it can never
+// pass for an access token. Purpose is "mfa", or "mfa_setup" when the account
+// is required to enroll first.
+type MFAChallenge struct {
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
 type Verify struct {
 	UserID            string `json:"user_id,omitempty" mapstructure:"user_id" validate:"required,uuid4"`
 	Email             string `json:"email,omitempty" mapstructure:"email" validate:"omitempty,email"`
@@ -27,6 +42,12 @@ type ResetPassword struct {
 	ResetJWT string `json:"token,omitempty" mapstructure:"token"`
 }
 
+// LanguageRequest sets the language a user is answered and mailed in. An
+// empty language goes back to following Accept-Language.
+type LanguageRequest struct {
+	Language string `json:"language" validate:"omitempty,oneof=en th"`
+}
+
 type EmailRequest struct {
 	Email string `json:"email,omitempty" mapstructure:"email" validate:"required,email"`
 }
@@ -38,8 +59,19 @@ type TokenVerify struct {
 type LoginResponse struct {
 	UserID    string `json:"user_id,omitempty" mapstructure:"user_id" validate:"required,uuid4"`
 	Password  string `json:"user_password,omitempty" mapstructure:"user_password" validate:"required,min=8"`
-	Role      string `json:"role,omitempty" mapstructure:"role" validate:"required,oneof=student alumni staff visitor"`
+	Role      string `json:"role,omitempty" mapstructure:"role" validate:"required,oneof=user alumnus admin"`
 	AdmitYear int16  `json:"admit_year,omitempty" mapstructure:"admit_year" validate:"gte=1950,lte=2100"`
+	Language  string `json:"language,omitempty" mapstructure:"language"`
+
+	MFAEnabled  bool `json:"mfa_enabled,omitempty" mapstructure:"mfa_enabled"`
+	MFARequired bool `json:"mfa_required,omitempty" mapstructure:"mfa_required"`
+}
+
+// MFASecrets are the two-factor secrets stored on a UserProfile: the one in
+// use and the one waiting for its first code during enrollment.
+type MFASecrets struct {
+	Secret        customtypes.Encrypted[string] `json:"-" mapstructure:"mfa_secret" encrypt:"mfa_secret"`
+	PendingSecret customtypes.Encrypted[string] `json:"-" mapstructure:"mfa_pending_secret" encrypt:"mfa_pending_secret"`
 }
 
 type LoginRequest struct {
@@ -58,3 +90,20 @@ type RegistryOneTimeRequest struct {
 	Password string `json:"password,omitempty" mapstructure:"password" validate:"required,min=8"`
 	Token    string `json:"token,omitempty" mapstructure:"token" validate:"required"`
 }
+
+type MFACodeRequest struct {
+	Code string `json:"code,omitempty" mapstructure:"code" validate:"required"`
+}
+
+type MFAChallengeRequest struct {
+	ChallengeToken string `json:"challenge_token,omitempty" mapstructure:"challenge_token" validate:"required"`
+}
+
+type MFALoginRequest struct {
+	ChallengeToken string `json:"challenge_token,omitempty" mapstructure:"challenge_token" validate:"required"`
+	Code           string `json:"code,omitempty" mapstructure:"code" validate:"required"`
+}
+
+type MFARequiredRequest struct {
+	Required *bool `json:"required" mapstructure:"required" validate:"required"`
+}
@@ -5,4 +5,5 @@ const (
 	ErrInvalidIDFormat = "Invalid ID format"
 	ErrUserNotFound    = "No user found with that ID"
 	ErrRetrievalFailed = "Failed to retrieve user from Neo4j"
+	ErrInvalidCursor   = "Invalid pagination cursor"
 )
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"alumni_api/pkg/customtypes"
+)
+
+// Roles a member can hold in a group conversation.
+const (
+	ConversationOwner  = "owner"
+	ConversationAdmin  = "admin"
+	ConversationMember = "member"
+)
+
+type Conversation struct {
+	Title     string   `json:"title,omitempty" mapstructure:"title" validate:"required,min=1,max=100"`
+	MemberIDs []string `json:"member_ids,omitempty" mapstructure:"member_ids" validate:"omitempty,max=256,dive,uuid4"`
+	PostID    string   `json:"-" mapstructure:"post_id"`
+}
+
+type UpdateConversationRequest struct {
+	Title string `json:"title,omitempty" mapstructure:"title" validate:"required,min=1,max=100"`
+}
+
+type ConversationInviteRequest struct {
+	UserIDs []string `json:"user_ids,omitempty" mapstructure:"user_ids" validate:"required,min=1,max=256,dive,uuid4"`
+}
+
+type ConversationRoleRequest struct {
+	Role string `json:"role,omitempty" mapstructure:"role" validate:"required,oneof=admin member"`
+}
+
+type GroupMessage struct {
+	MessageID      string                        `json:"message_id,omitempty" mapstructure:"message_id" validate:"omitempty,uuid4"`
+	ConversationID string                        `json:"conversation_id,omitempty" mapstructure:"conversation_id"`
+	SenderID       string                        `json:"sender_id,omitempty" mapstructure:"sender_id"`
+	ReplyID        string                        `json:"reply_id,omitempty" mapstructure:"reply_id" validate:"omitempty,uuid4"`
+	AttachmentID   string                        `json:"attachment_id,omitempty" mapstructure:"attachment_id" validate:"omitempty,uuid4"`
+	Content        customtypes.Encrypted[string] `json:"content,omitempty" mapstructure:"content" validate:"required" encrypt:"content,reply_content,reply_message_content"`
+}
@@ -1,67 +1,110 @@
 package models
 
-var MessageEncryptField = []string{
-	"Content",
-}
+import (
+	"alumni_api/internal/encrypt"
+	"fmt"
+	"log"
+)
+
+// Blind indexes of the encrypted fields users can be searched by. Years are
+// indexed exactly; GPAX only by quarter point, so searches filter the
+// decrypted values for the exact range.
+var (
+	AdmitYearIndex    = encrypt.BlindIndex{Name: "admit_year", Width: 1, Min: 2510, Max: 2600}
+	GraduateYearIndex = encrypt.BlindIndex{Name: "graduate_year", Width: 1, Min: 2530, Max: 2700}
+	GPAXIndex         = encrypt.BlindIndex{Name: "gpax", Width: 0.25, Min: 0, Max: 4}
+	PositionIndex     = encrypt.BlindIndex{Name: "position"}
+)
+
+// EncryptedProperty is a property stored encrypted on the nodes with label
+// Kind, or on the relationships of type Kind.
+type EncryptedProperty struct {
+	Kind     string
+	Property string
 
-var MessageDecryptField = []string{
-	"reply_content",
+	// Index, if it has a Name, is the blind index kept next to the value.
+	Index encrypt.BlindIndex
 }
 
-var ChatMessageDecryptField = []string{
-	"me.message.content",
-	"me.message.reply_message_content",
-	"other.message.content",
-	"other.message.reply_message_content",
+// EncryptedProperties lists every property stored encrypted, for the
+// re-encryption job to walk when keys are rotated or indexes are added.
+var EncryptedProperties = []EncryptedProperty{
+	{Kind: "UserProfile", Property: "gpax", Index: GPAXIndex},
+	{Kind: "UserProfile", Property: "admit_year", Index: AdmitYearIndex},
+	{Kind: "UserProfile", Property: "graduate_year", Index: GraduateYearIndex},
+	{Kind: "UserProfile", Property: "mfa_secret"},
+	{Kind: "UserProfile", Property: "mfa_pending_secret"},
+	{Kind: "HAS_WORK_WITH", Property: "position", Index: PositionIndex},
+	{Kind: "HAS_WORK_WITH", Property: "salary_min"},
+	{Kind: "HAS_WORK_WITH", Property: "salary_max"},
+	{Kind: "Message", Property: "content"},
+	{Kind: "Attachment", Property: "file_name"},
 }
 
-var CompanyEncryptField = []string{
-	"Companies.Position",
-	"Companies.SalaryMin",
-	"Companies.SalaryMax",
+// EncryptedValue is the ciphertext of an EncryptedProperty on the node or
+// relationship with the given ID.
+type EncryptedValue struct {
+	ID    string
+	Raw   []byte
+	Index string
 }
 
-var CompanyDecryptField = []string{
-	"position",
-	"salary_min",
-	"salary_max",
+// EncryptedChange replaces Old with New and sets the blind index, if the
+// property has one, unless the value has changed since Old was read.
+type EncryptedChange struct {
+	ID    string
+	Old   []byte
+	New   []byte
+	Index string
 }
 
-var UserEncryptField = []string{
-	"StudentInfo.GPAX",
-	"StudentInfo.AdmitYear",
-	"StudentInfo.GraduateYear",
-	"StudentInfo.EducationLevel",
-	"StudentInfo.Email",
-	"StudentInfo.Github",
-	"StudentInfo.Linkedin",
-	"StudentInfo.Facebook",
-	"StudentInfo.Phone",
-	"Companies.Company",
-	"Companies.Address",
-	"Companies.Position",
+// encryptedModels are the structs whose encrypt tags declare what is stored
+// encrypted, together with the structs they hold.
+var encryptedModels = []interface{}{
+	UserProfile{},
+	CreateProfileRequest{},
+	UpdateUserProfileRequest{},
+	UserRequestCompany{},
+	UserCompanyUpdateRequest{},
+	Message{},
+	ReplyMessage{},
+	EditMessage{},
+	GroupMessage{},
+	Attachment{},
+	MFASecrets{},
 }
 
-var StudentInfoDecryptField = []string{
-	"gpax",
-	"admit_year",
-	"graduate_year",
-	"education_level",
+// checkEncryptedFields registers the encrypted fields and checks them
+// against EncryptedProperties, so that a tag and the re-encryption job can't
+// disagree about what is encrypted.
+func checkEncryptedFields() error {
+	indexes := []encrypt.BlindIndex{AdmitYearIndex, GraduateYearIndex, GPAXIndex, PositionIndex}
+	if err := encrypt.Register(indexes, encryptedModels...); err != nil {
+		return err
+	}
+
+	declared := make(map[string]bool)
+	for _, property := range encrypt.EncryptedProperties() {
+		declared[property] = true
+	}
+
+	listed := make(map[string]bool)
+	for _, prop := range EncryptedProperties {
+		if !declared[prop.Property] {
+			return fmt.Errorf("%s.%s is not declared by any encrypt tag", prop.Kind, prop.Property)
+		}
+		listed[prop.Property] = true
+	}
+	for property := range declared {
+		if !listed[property] {
+			return fmt.Errorf("%s is declared encrypted but missing from EncryptedProperties", property)
+		}
+	}
+	return nil
 }
 
-var UserDecryptField = []string{
-	"student_info.gpax",
-	"student_info.admit_year",
-	"student_info.graduate_year",
-	"student_info.education_level",
-	"contact_info.email",
-	"contact_info.github",
-	"contact_info.linkedin",
-	"contact_info.facebook",
-	"contact_info.phone",
-	"companies.company",
-	"companies.address",
-	"companies.position",
-	"companies.salary_min",
-	"companies.salary_max",
+func init() {
+	if err := checkEncryptedFields(); err != nil {
+		log.Fatalf("Invalid encrypted fields: %v", err)
+	}
 }
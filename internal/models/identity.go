@@ -0,0 +1,29 @@
+package models
+
+import "github.com/golang-jwt/jwt/v5"
+
+// Identity is a user as vouched for by an OpenID Connect provider. Provider
+// and Subject identify it for good; Email is only trusted to link accounts
+// when the provider says it verified it.
+type Identity struct {
+	Provider      string `json:"provider" mapstructure:"provider"`
+	Subject       string `json:"subject" mapstructure:"subject"`
+	Email         string `json:"email,omitempty" mapstructure:"email"`
+	EmailVerified bool   `json:"email_verified" mapstructure:"email_verified"`
+}
+
+// OIDCState travels in a cookie from the start of a provider sign-in to its
+// callback. It binds the callback to the browser that started it and holds
+// the PKCE verifier and nonce the code exchange is checked against.
+type OIDCState struct {
+	Provider string `json:"provider"`
+	State    string `json:"state"`
+	Nonce    string `json:"nonce"`
+	Verifier string `json:"verifier"`
+	jwt.RegisteredClaims
+}
+
+type OIDCCallbackRequest struct {
+	Code  string `json:"code,omitempty" mapstructure:"code" validate:"required"`
+	State string `json:"state,omitempty" mapstructure:"state" validate:"required"`
+}
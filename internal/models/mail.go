@@ -0,0 +1,36 @@
+package models
+
+// Mail templates, named after the mail_format templates they render.
+const (
+	MailVerifyEmail         = "verify_email"
+	MailVerifyChangeEmail   = "verify_change_email"
+	MailResetPassword       = "reset_password"
+	MailOneTimeRegistry     = "one_time_registry"
+	MailOneTimeRegistryFail = "one_time_registry_fail"
+	MailAccountLocked       = "account_locked"
+)
+
+// Outbox states of a Mail. A mail is dead once it has failed too many times
+// to be retried on its own; an admin can put it back in the queue.
+const (
+	MailPending = "pending"
+	MailSent    = "sent"
+	MailDead    = "dead"
+)
+
+// Mail is an email waiting in the outbox to be rendered and sent by the mail
+// worker. Data fills the template and may hold tokens, so it is never
+// returned to clients and is dropped once the mail is sent. Timestamps are
+// in milliseconds.
+type Mail struct {
+	MailID               string            `json:"mail_id" mapstructure:"mail_id"`
+	To                   string            `json:"to" mapstructure:"to"`
+	Template             string            `json:"template" mapstructure:"template"`
+	Language             string            `json:"language" mapstructure:"language"`
+	Data                 map[string]string `json:"-" mapstructure:"-"`
+	Status               string            `json:"status" mapstructure:"status"`
+	Attempts             int64             `json:"attempts" mapstructure:"attempts"`
+	NextAttemptTimestamp int64             `json:"next_attempt_timestamp" mapstructure:"next_attempt_timestamp"`
+	LastError            string            `json:"last_error,omitempty" mapstructure:"last_error"`
+	CreatedTimestamp     int64             `json:"created_timestamp" mapstructure:"created_timestamp"`
+}
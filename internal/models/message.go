@@ -8,8 +8,8 @@ type Message struct {
 	MessageID       string                        `json:"message_id,omitempty" mapstructure:"message_id" validate:"omitempty,uuid4"`
 	SenderID        string                        `json:"sender_id,omitempty" mapstructure:"sender_id" validate:"required,uuid4"`
 	ReceiverID      string                        `json:"receiver_id,omitempty" mapstructure:"receiver_id" validate:"required,uuid4"`
-	Content         customtypes.Encrypted[string] `json:"content,omitempty" mapstructure:"content" validate:"required"`
-	Attachment      customtypes.Encrypted[string] `json:"attachment,omitempty" mapstructure:"attachment,squash" validate:"omitempty,url"`
+	Content         customtypes.Encrypted[string] `json:"content,omitempty" mapstructure:"content" validate:"required" encrypt:"content,reply_content,reply_message_content"`
+	AttachmentID    string                        `json:"attachment_id,omitempty" mapstructure:"attachment_id" validate:"omitempty,uuid4"`
 	CreatedDatetime string                        `json:"created_datetime,omitempty" mapstructure:"created_datetime" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 	UpdatedDatetime string                        `json:"updated_datetime,omitempty" mapstructure:"updated_datetime" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 }
@@ -19,18 +19,27 @@ type ReplyMessage struct {
 	SenderID        string                        `json:"sender_id,omitempty" mapstructure:"sender_id" validate:"required,uuid4"`
 	ReceiverID      string                        `json:"receiver_id,omitempty" mapstructure:"receiver_id" validate:"required,uuid4"`
 	ReplyID         string                        `json:"reply_id,omitempty" mapstructure:"reply_id" validate:"required,uuid4"`
-	Content         customtypes.Encrypted[string] `json:"content,omitempty" mapstructure:"content" validate:"required"`
-	Attachment      customtypes.Encrypted[string] `json:"attachment,omitempty" mapstructure:"attachment,squash" validate:"omitempty,url"`
+	Content         customtypes.Encrypted[string] `json:"content,omitempty" mapstructure:"content" validate:"required" encrypt:"content,reply_content,reply_message_content"`
+	AttachmentID    string                        `json:"attachment_id,omitempty" mapstructure:"attachment_id" validate:"omitempty,uuid4"`
 	CreatedDatetime string                        `json:"created_datetime,omitempty" mapstructure:"created_datetime" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 	UpdatedDatetime string                        `json:"updated_datetime,omitempty" mapstructure:"updated_datetime" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
 }
 
 type EditMessage struct {
-	MessageID  string                        `json:"message_id,omitempty" mapstructure:"message_id" validate:"required,uuid4"`
-	Content    customtypes.Encrypted[string] `json:"content,omitempty" mapstructure:"content" validate:"required"`
-	Attachment customtypes.Encrypted[string] `json:"attachment,omitempty" mapstructure:"attachment,squash" validate:"omitempty,url"`
+	MessageID string                        `json:"message_id,omitempty" mapstructure:"message_id" validate:"required,uuid4"`
+	Content   customtypes.Encrypted[string] `json:"content,omitempty" mapstructure:"content" validate:"required" encrypt:"content,reply_content,reply_message_content"`
 }
 
 type DeleteMessage struct {
 	MessageID string `json:"message_id,omitempty" mapstructure:"message_id" validate:"required,uuid4"`
 }
+
+// ReadReceipt records that ReaderID has read every message SenderID sent them
+// up to and including MessageID. MessageIDs lists the messages newly marked.
+type ReadReceipt struct {
+	MessageID  string   `json:"message_id"`
+	ReaderID   string   `json:"reader_id"`
+	SenderID   string   `json:"sender_id"`
+	ReadAt     int64    `json:"read_at"`
+	MessageIDs []string `json:"message_ids"`
+}
@@ -0,0 +1,81 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+const (
+	DefaultPageLimit = 20
+	MaxPageLimit     = 100
+)
+
+// PageRequest is the cursor/limit pair list endpoints accept as query parameters.
+type PageRequest struct {
+	Cursor string `query:"cursor" json:"cursor,omitempty"`
+	Limit  int    `query:"limit" json:"limit,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// Cursor identifies the last row of a page by its created_timestamp and ID.
+// Ranked lists put their score in Timestamp and the time they ranked at, in
+// milliseconds, in RankedAt.
+type Cursor struct {
+	Timestamp int64  `json:"ts"`
+	ID        string `json:"id"`
+	RankedAt  int64  `json:"at,omitempty"`
+}
+
+// Page is a decoded PageRequest handed to the repositories. After is nil for
+// the first page.
+type Page struct {
+	After *Cursor
+	Limit int
+}
+
+// EncodeCursor turns a cursor into the opaque string handed to clients.
+func EncodeCursor(cursor Cursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses a string produced by EncodeCursor.
+func DecodeCursor(value string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.New(ErrInvalidCursor)
+	}
+
+	var cursor Cursor
+	if err := json.Unmarshal(data, &cursor); err != nil || cursor.ID == "" {
+		return nil, errors.New(ErrInvalidCursor)
+	}
+
+	return &cursor, nil
+}
+
+// Params adds the cursor_ts, cursor_id and limit parameters used by paginated
+// queries. One extra row is requested so NextPage can tell whether another
+// page follows.
+func (p Page) Params(params map[string]interface{}) map[string]interface{} {
+	params["cursor_ts"] = nil
+	params["cursor_id"] = nil
+	if p.After != nil {
+		params["cursor_ts"] = p.After.Timestamp
+		params["cursor_id"] = p.After.ID
+	}
+	params["limit"] = p.Limit + 1
+
+	return params
+}
+
+// NextPage trims rows fetched with Page.Params down to the page size and
+// returns the cursor of the following page, or "" when there is none.
+func NextPage[T any](rows []T, page Page, cursorOf func(T) Cursor) ([]T, string) {
+	if len(rows) <= page.Limit {
+		return rows, ""
+	}
+
+	rows = rows[:page.Limit]
+	return rows, EncodeCursor(cursorOf(rows[len(rows)-1]))
+}
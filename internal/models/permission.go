@@ -0,0 +1,85 @@
+package models
+
+import "slices"
+
+// Permissions are what roles grant. "any" permissions extend an action users
+// may take on their own content to everyone's.
+const (
+	PermissionPostReadAlumnus   = "post:read:alumnus"
+	PermissionPostReadAdmin     = "post:read:admin"
+	PermissionPostDeleteAny     = "post:delete:any"
+	PermissionCommentDeleteAny  = "comment:delete:any"
+	PermissionProfileCreate     = "profile:create"
+	PermissionProfileEditAny    = "profile:edit:any"
+	PermissionProfileAudit      = "profile:audit"
+	PermissionProfileReadAlumni = "profile:read:alumni"
+	PermissionReportReview      = "report:review"
+	PermissionRoleRequestReview = "role_request:review"
+	PermissionAccountManage     = "account:manage"
+	PermissionStatRead          = "stat:read"
+	PermissionRoleManage        = "role:manage"
+	PermissionMailManage        = "mail:manage"
+
+	// PermissionDepartmentAny lifts the limit of department admins to the
+	// users of their own department.
+	PermissionDepartmentAny = "department:any"
+)
+
+// Permissions lists every permission a role may grant.
+var Permissions = []string{
+	PermissionPostReadAlumnus,
+	PermissionPostReadAdmin,
+	PermissionPostDeleteAny,
+	PermissionCommentDeleteAny,
+	PermissionProfileCreate,
+	PermissionProfileEditAny,
+	PermissionProfileAudit,
+	PermissionProfileReadAlumni,
+	PermissionReportReview,
+	PermissionRoleRequestReview,
+	PermissionAccountManage,
+	PermissionStatRead,
+	PermissionRoleManage,
+	PermissionMailManage,
+	PermissionDepartmentAny,
+}
+
+// Role is a named set of permissions. Every user has the role named by their
+// UserProfile.role and may be assigned more. Built-in roles are defined here
+// and kept in sync at startup; other roles are managed through the API.
+type Role struct {
+	Name        string   `json:"name" mapstructure:"name" validate:"required,min=2,max=32"`
+	Permissions []string `json:"permissions" mapstructure:"permissions" validate:"dive,permission"`
+	BuiltIn     bool     `json:"built_in" mapstructure:"built_in"`
+}
+
+// BaseRoles are the UserProfile.role values. They come with the profile and
+// are never assigned on top of it.
+var BaseRoles = []string{"user", "alumnus", "admin"}
+
+// DepartmentAdminRole is granted together with the Department it applies to
+// and never through the role API. Its permissions reach only the users of
+// that department.
+const DepartmentAdminRole = "department_admin"
+
+var BuiltInRoles = []Role{
+	{Name: "user", Permissions: []string{}, BuiltIn: true},
+	{Name: "alumnus", Permissions: []string{PermissionPostReadAlumnus, PermissionProfileReadAlumni}, BuiltIn: true},
+	{Name: "moderator", Permissions: []string{
+		PermissionPostReadAlumnus,
+		PermissionPostDeleteAny,
+		PermissionCommentDeleteAny,
+		PermissionReportReview,
+	}, BuiltIn: true},
+	{Name: DepartmentAdminRole, Permissions: []string{
+		PermissionPostReadAlumnus,
+		PermissionReportReview,
+		PermissionRoleRequestReview,
+		PermissionStatRead,
+	}, BuiltIn: true},
+	{Name: "admin", Permissions: slices.Clone(Permissions), BuiltIn: true},
+}
+
+type RoleRequest struct {
+	Permissions []string `json:"permissions" mapstructure:"permissions" validate:"required,dive,permission"`
+}
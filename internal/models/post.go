@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"slices"
+	"time"
+)
 
 var AllowRangeType = []string{
 	"event",
@@ -17,6 +20,20 @@ type Post struct {
 	MediaURL     []string  `json:"media_urls,omitempty" mapstructure:"media_urls" validate:"omitempty,dive,url"`
 	RedirectLink string    `json:"redirect_link,omitempty" mapstructure:"redirect_link" validate:"omitempty,url"`
 	Visibility   string    `json:"visibility,omitempty" mapstructure:"visibility" validate:"required,oneof=alumnus admin all"`
+	CreateChat   bool      `json:"create_chat,omitempty" mapstructure:"create_chat"`
+}
+
+// PostVisibility lists the Post.Visibility values a caller holding the
+// given permissions may read. Anonymous callers hold none.
+func PostVisibility(permissions []string) []string {
+	visibility := []string{"all"}
+	if slices.Contains(permissions, PermissionPostReadAlumnus) {
+		visibility = append(visibility, "alumnus")
+	}
+	if slices.Contains(permissions, PermissionPostReadAdmin) {
+		visibility = append(visibility, "admin")
+	}
+	return visibility
 }
 
 type UpdatePostRequest struct {
@@ -45,6 +62,20 @@ type Comment struct {
 	Replies         []Comment `json:"replies,omitempty"`
 }
 
+// FeedCandidate is a post together with the graph signals used to rank it
+// for one viewer on /v1/post/feed.
+type FeedCandidate struct {
+	Post            map[string]interface{}
+	CreatedAt       int64
+	IsFriend        bool
+	SameGeneration  bool
+	SameStudentType bool
+	SharedCompanies int64
+	Likes           int64
+	Views           int64
+	Comments        int64
+}
+
 type CommentRequest struct {
 	Comment string `json:"comment,omitempty" mapstructure:"comment" validate:"required,max=200"`
 }
@@ -0,0 +1,132 @@
+package models
+
+import "slices"
+
+// Visibility values a user picks for each field group of their profile,
+// from widest to narrowest.
+const (
+	VisibilityPublic  = "public"
+	VisibilityAlumni  = "alumni"
+	VisibilityFriends = "friends"
+	VisibilityPrivate = "private"
+)
+
+// Field groups of a profile that have their own visibility.
+const (
+	PrivacyPersonal  = "personal"
+	PrivacyContact   = "contact"
+	PrivacyEducation = "education"
+	PrivacyCareer    = "career"
+	PrivacySalary    = "salary"
+)
+
+// PrivacyGroups lists the field groups and the keys each one covers in a
+// serialized profile, whether nested like FetchUserByID's or flat like the
+// stored properties. Salaries are covered inside each of the companies.
+var PrivacyGroups = map[string][]string{
+	PrivacyPersonal:  {"dob", "gender"},
+	PrivacyContact:   {"contact_info", "contact", "email", "github", "linkedin", "linkdin", "facebook", "phone"},
+	PrivacyEducation: {"student_info", "student_id", "admit_year", "graduate_year", "gpax", "college_info", "faculty", "department", "field", "student_type"},
+	PrivacyCareer:    {"companies"},
+	PrivacySalary:    {"salary_min", "salary_max"},
+}
+
+// PrivacySettings is who may see each field group of a profile. The owner
+// always sees everything. It is stored on UserProfile as privacy_<group>.
+type PrivacySettings struct {
+	Personal  string `json:"personal,omitempty" mapstructure:"privacy_personal" validate:"omitempty,oneof=public alumni friends private"`
+	Contact   string `json:"contact,omitempty" mapstructure:"privacy_contact" validate:"omitempty,oneof=public alumni friends private"`
+	Education string `json:"education,omitempty" mapstructure:"privacy_education" validate:"omitempty,oneof=public alumni friends private"`
+	Career    string `json:"career,omitempty" mapstructure:"privacy_career" validate:"omitempty,oneof=public alumni friends private"`
+	Salary    string `json:"salary,omitempty" mapstructure:"privacy_salary" validate:"omitempty,oneof=public alumni friends private"`
+}
+
+// DefaultPrivacy applies to the groups a user has not set: personal and
+// contact details go to friends, education and career to alumni, and
+// salaries nowhere.
+var DefaultPrivacy = PrivacySettings{
+	Personal:  VisibilityFriends,
+	Contact:   VisibilityFriends,
+	Education: VisibilityAlumni,
+	Career:    VisibilityAlumni,
+	Salary:    VisibilityPrivate,
+}
+
+// PrivacyProperty is the UserProfile property a group's visibility is
+// stored in.
+func PrivacyProperty(group string) string {
+	return "privacy_" + group
+}
+
+// PrivacyOf reads the settings stored in the privacy_<group> keys of a
+// serialized profile, filling in the defaults.
+func PrivacyOf(profile map[string]interface{}) PrivacySettings {
+	setting := func(group string) string {
+		visibility, _ := profile[PrivacyProperty(group)].(string)
+		return visibility
+	}
+
+	return PrivacySettings{
+		Personal:  setting(PrivacyPersonal),
+		Contact:   setting(PrivacyContact),
+		Education: setting(PrivacyEducation),
+		Career:    setting(PrivacyCareer),
+		Salary:    setting(PrivacySalary),
+	}.OrDefault()
+}
+
+// OrDefault fills in the groups left unset with DefaultPrivacy.
+func (s PrivacySettings) OrDefault() PrivacySettings {
+	or := func(visibility, fallback string) string {
+		if visibility == "" {
+			return fallback
+		}
+		return visibility
+	}
+
+	return PrivacySettings{
+		Personal:  or(s.Personal, DefaultPrivacy.Personal),
+		Contact:   or(s.Contact, DefaultPrivacy.Contact),
+		Education: or(s.Education, DefaultPrivacy.Education),
+		Career:    or(s.Career, DefaultPrivacy.Career),
+		Salary:    or(s.Salary, DefaultPrivacy.Salary),
+	}
+}
+
+// Group returns the visibility of a field group.
+func (s PrivacySettings) Group(group string) string {
+	switch group {
+	case PrivacyPersonal:
+		return s.Personal
+	case PrivacyContact:
+		return s.Contact
+	case PrivacyEducation:
+		return s.Education
+	case PrivacyCareer:
+		return s.Career
+	case PrivacySalary:
+		return s.Salary
+	}
+	return VisibilityPrivate
+}
+
+// PrivacyVisibility lists the visibility values whose fields a caller
+// holding the given permissions may see on someone else's profile. Friends
+// see what alumni see, so a narrower setting never shows more than a wider
+// one. Anonymous callers hold no permissions.
+func PrivacyVisibility(permissions []string, friend bool) []string {
+	visibility := []string{VisibilityPublic}
+	if friend || slices.Contains(permissions, PermissionProfileReadAlumni) {
+		visibility = append(visibility, VisibilityAlumni)
+	}
+	if friend {
+		visibility = append(visibility, VisibilityFriends)
+	}
+	return visibility
+}
+
+// PrivacyVisible reports whether a group set to visibility may be shown to
+// a caller holding the given permissions.
+func PrivacyVisible(visibility string, permissions []string, friend bool) bool {
+	return slices.Contains(PrivacyVisibility(permissions, friend), visibility)
+}
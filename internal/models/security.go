@@ -0,0 +1,32 @@
+package models
+
+// Security event types recorded on a UserProfile.
+const (
+	SecurityEventLoginFailed = "login_failed"
+	SecurityEventLocked      = "locked"
+	SecurityEventUnlocked    = "unlocked"
+	SecurityEventAudited     = "profile_audited"
+)
+
+// SecurityEvent is something that happened to an account's credentials or
+// its private fields, kept so the owner and admins can see it. ActorID is set
+// when someone other than the owner caused it, such as an admin unlocking the
+// account, and Reason when they had to give one.
+type SecurityEvent struct {
+	EventID          string `json:"event_id,omitempty" mapstructure:"event_id"`
+	Type             string `json:"type" mapstructure:"type"`
+	IP               string `json:"ip,omitempty" mapstructure:"ip"`
+	UserAgent        string `json:"user_agent,omitempty" mapstructure:"user_agent"`
+	ActorID          string `json:"actor_id,omitempty" mapstructure:"actor_id"`
+	Reason           string `json:"reason,omitempty" mapstructure:"reason"`
+	CreatedTimestamp int64  `json:"created_timestamp,omitempty" mapstructure:"created_timestamp"`
+}
+
+// LoginThrottle is the failed-login state of an account. Timestamps are in
+// milliseconds, like Cypher's timestamp(); LockedUntil is 0 when the account
+// was never locked.
+type LoginThrottle struct {
+	Failures        int64 `json:"login_failures" mapstructure:"login_failures"`
+	FailedTimestamp int64 `json:"login_failed_timestamp" mapstructure:"login_failed_timestamp"`
+	LockedUntil     int64 `json:"login_locked_until" mapstructure:"login_locked_until"`
+}
@@ -0,0 +1,13 @@
+package models
+
+// Session is one signed-in device. The refresh token itself is never stored,
+// only its hash; the hashes it replaced are kept so a replayed token can be
+// recognised and the session revoked.
+type Session struct {
+	SessionID        string `json:"session_id,omitempty" mapstructure:"session_id"`
+	UserID           string `json:"user_id,omitempty" mapstructure:"user_id"`
+	RefreshHash      string `json:"-" mapstructure:"refresh_hash"`
+	UserAgent        string `json:"user_agent,omitempty" mapstructure:"user_agent"`
+	IP               string `json:"ip,omitempty" mapstructure:"ip"`
+	ExpiresTimestamp int64  `json:"expires_timestamp,omitempty" mapstructure:"expires_timestamp"`
+}
@@ -26,9 +26,13 @@ type UserProfile struct {
 type StudentInfo struct {
 	StudentID    string                         `json:"student_id,omitempty" mapstructure:"student_id,omitempty" validate:"omitempty"`
 	Generation   string                         `json:"generation,omitempty" mapstructure:"generation,omitempty" validate:"omitempty"`
-	AdmitYear    customtypes.Encrypted[int16]   `json:"admit_year,omitempty" mapstructure:"admit_year,omitempty" validate:"omitempty,gte=2510,lte=2600"`
-	GraduateYear customtypes.Encrypted[int16]   `json:"graduate_year,omitempty" mapstructure:"graduate_year,omitempty" validate:"omitempty,gte=2530"`
-	GPAX         customtypes.Encrypted[float32] `json:"gpax,omitempty" mapstructure:"gpax,omitempty" validate:"omitempty,gte=0.0,lte=4.0"`
+	AdmitYear    customtypes.Encrypted[int16]   `json:"admit_year,omitempty" mapstructure:"admit_year,omitempty" validate:"omitempty,gte=2510,lte=2600" encrypt:"admit_year" index:"AdmitYearIndex"`
+	GraduateYear customtypes.Encrypted[int16]   `json:"graduate_year,omitempty" mapstructure:"graduate_year,omitempty" validate:"omitempty,gte=2530" encrypt:"graduate_year" index:"GraduateYearIndex"`
+	GPAX         customtypes.Encrypted[float32] `json:"gpax,omitempty" mapstructure:"gpax,omitempty" validate:"omitempty,gte=0.0,lte=4.0" encrypt:"gpax" index:"GPAXIndex"`
+
+	AdmitYearIndex    string `json:"-" mapstructure:"admit_year_index,omitempty"`
+	GraduateYearIndex string `json:"-" mapstructure:"graduate_year_index,omitempty"`
+	GPAXIndex         string `json:"-" mapstructure:"gpax_index,omitempty"`
 }
 
 type CollegeInfo struct {
@@ -41,9 +45,11 @@ type CollegeInfo struct {
 type Company struct {
 	Company   string                         `json:"company,omitempty" mapstructure:"company,omitempty" validate:"required,min=2,max=100"`
 	Address   string                         `json:"address,omitempty" mapstructure:"address,omitempty" validate:"omitempty,max=200"`
-	Position  customtypes.Encrypted[string]  `json:"position,omitempty" mapstructure:"position,omitempty" validate:"omitempty,max=100"`
-	SalaryMin customtypes.Encrypted[float32] `json:"salary_min,omitempty" mapstructure:"salary_min,omitempty" validate:"omitempty"`
-	SalaryMax customtypes.Encrypted[float32] `json:"salary_max,omitempty" mapstructure:"salary_max,omitempty" validate:"omitempty"`
+	Position  customtypes.Encrypted[string]  `json:"position,omitempty" mapstructure:"position,omitempty" validate:"omitempty,max=100" encrypt:"position" index:"PositionIndex"`
+	SalaryMin customtypes.Encrypted[float32] `json:"salary_min,omitempty" mapstructure:"salary_min,omitempty" validate:"omitempty" encrypt:"salary_min"`
+	SalaryMax customtypes.Encrypted[float32] `json:"salary_max,omitempty" mapstructure:"salary_max,omitempty" validate:"omitempty" encrypt:"salary_max"`
+
+	PositionIndex string `json:"-" mapstructure:"position_index,omitempty"`
 }
 
 type Contact struct {
@@ -47,9 +47,26 @@ type UserFriendRequest struct {
 	UserID string `json:"user_id,omitempty" mapstructure:"user_id" validate:"required,uuid4"`
 }
 
+// UserRequestFilter narrows down users by their college info, company and
+// encrypted profile fields. Ranges are inclusive and either bound may be left
+// out. The encrypted fields are matched through their blind indexes, whose
+// tokens the controller fills in.
 type UserRequestFilter struct {
-	StudentType string `json:"student_type,omitempty" mapstructure:"student_type" validate:"omitempty"`
-	Field       string `json:"field,omitempty" mapstructure:"field" validate:"omitempty"`
+	StudentType      string  `json:"student_type,omitempty" query:"student_type" mapstructure:"student_type" validate:"omitempty"`
+	Field            string  `json:"field,omitempty" query:"field" mapstructure:"field" validate:"omitempty"`
+	Company          string  `json:"company,omitempty" query:"company" mapstructure:"company" validate:"omitempty,max=100"`
+	Position         string  `json:"position,omitempty" query:"position" mapstructure:"position" validate:"omitempty,max=100"`
+	AdmitYearFrom    int16   `json:"admit_year_from,omitempty" query:"admit_year_from" mapstructure:"admit_year_from" validate:"omitempty,gte=2510,lte=2600"`
+	AdmitYearTo      int16   `json:"admit_year_to,omitempty" query:"admit_year_to" mapstructure:"admit_year_to" validate:"omitempty,gte=2510,lte=2600"`
+	GraduateYearFrom int16   `json:"graduate_year_from,omitempty" query:"graduate_year_from" mapstructure:"graduate_year_from" validate:"omitempty,gte=2530"`
+	GraduateYearTo   int16   `json:"graduate_year_to,omitempty" query:"graduate_year_to" mapstructure:"graduate_year_to" validate:"omitempty,gte=2530"`
+	GPAXMin          float32 `json:"gpax_min,omitempty" query:"gpax_min" mapstructure:"gpax_min" validate:"omitempty,gte=0.0,lte=4.0"`
+	GPAXMax          float32 `json:"gpax_max,omitempty" query:"gpax_max" mapstructure:"gpax_max" validate:"omitempty,gte=0.0,lte=4.0"`
+
+	PositionIndex       string   `json:"-" query:"-"`
+	AdmitYearIndexes    []string `json:"-" query:"-"`
+	GraduateYearIndexes []string `json:"-" query:"-"`
+	GPAXIndexes         []string `json:"-" query:"-"`
 }
 
 type UserRequestCompany struct {
@@ -57,7 +74,9 @@ type UserRequestCompany struct {
 }
 
 type UserCompanyUpdateRequest struct {
-	Position customtypes.Encrypted[string] `json:"position,omitempty" mapstructure:"position,omitempty" validate:"omitempty,max=100"`
+	Position customtypes.Encrypted[string] `json:"position,omitempty" mapstructure:"position,omitempty" validate:"omitempty,max=100" encrypt:"position" index:"PositionIndex"`
+
+	PositionIndex string `json:"-" mapstructure:"position_index,omitempty"`
 }
 
 type UserFOAFRequest struct {
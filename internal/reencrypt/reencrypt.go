@@ -0,0 +1,124 @@
+// Package reencrypt brings encrypted data up to date with the current keys,
+// so an old master key can be retired without downtime: both keys are
+// accepted while the job runs, and once it has finished nothing needs the old
+// one any more. Values written before envelope encryption are moved into
+// envelopes on the way, and missing or stale blind indexes are rebuilt.
+package reencrypt
+
+import (
+	"alumni_api/internal/encrypt"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"context"
+
+	"go.uber.org/zap"
+)
+
+const batchSize = 200
+
+// Result counts the values one run looked at.
+type Result struct {
+	Scanned int
+
+	// Updated were re-encrypted, had their blind index rebuilt, or both.
+	Updated int
+
+	// Changed were written to while the job ran and left as they were,
+	// already under the current key.
+	Changed int
+
+	// Failed could not be decrypted with any known key.
+	Failed int
+}
+
+// Job walks every property in models.EncryptedProperties.
+type Job struct {
+	store  repositories.EncryptedRepository
+	logger *zap.Logger
+}
+
+func NewJob(store repositories.EncryptedRepository, logger *zap.Logger) *Job {
+	return &Job{store: store, logger: logger}
+}
+
+// Run re-encrypts every value not yet under the current keys and indexes
+// every value whose blind index is missing or out of date. It is safe to
+// run again, or alongside the API, and stops early when ctx is done.
+func (j *Job) Run(ctx context.Context) (Result, error) {
+	var result Result
+
+	for _, prop := range models.EncryptedProperties {
+		if err := j.runProperty(ctx, prop, &result); err != nil {
+			return result, err
+		}
+	}
+
+	j.logger.Info("Re-encryption finished",
+		zap.Int("scanned", result.Scanned),
+		zap.Int("updated", result.Updated),
+		zap.Int("changed", result.Changed),
+		zap.Int("failed", result.Failed))
+	return result, nil
+}
+
+func (j *Job) runProperty(ctx context.Context, prop models.EncryptedProperty, result *Result) error {
+	after := ""
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		values, err := j.store.GetEncryptedValues(ctx, prop, after, batchSize, j.logger)
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return nil
+		}
+		after = values[len(values)-1].ID
+
+		var changes []models.EncryptedChange
+		for _, value := range values {
+			result.Scanned++
+
+			raw, changed, err := encrypt.Reencrypt(ctx, value.Raw)
+			if err != nil {
+				result.Failed++
+				j.logger.Warn("Failed to re-encrypt value",
+					zap.String("kind", prop.Kind),
+					zap.String("property", prop.Property),
+					zap.String("id", value.ID),
+					zap.Error(err))
+				continue
+			}
+
+			index := value.Index
+			if prop.Index.Name != "" {
+				if index, err = encrypt.IndexRaw(ctx, value.Raw, prop.Index); err != nil {
+					result.Failed++
+					j.logger.Warn("Failed to index value",
+						zap.String("kind", prop.Kind),
+						zap.String("property", prop.Property),
+						zap.String("id", value.ID),
+						zap.Error(err))
+					continue
+				}
+			}
+
+			if changed || index != value.Index {
+				changes = append(changes, models.EncryptedChange{ID: value.ID, Old: value.Raw, New: raw, Index: index})
+			}
+		}
+
+		if len(changes) == 0 {
+			continue
+		}
+
+		replaced, err := j.store.ReplaceEncryptedValues(ctx, prop, changes, j.logger)
+		if err != nil {
+			return err
+		}
+		result.Updated += replaced
+		result.Changed += len(changes) - replaced
+	}
+}
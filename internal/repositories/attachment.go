@@ -0,0 +1,161 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// attachmentProjection is the public view of an Attachment node, shared by
+// the message queries that return it alongside a message.
+const attachmentProjection = `CASE WHEN a IS NULL THEN null ELSE {
+        attachment_id: a.attachment_id,
+        file_name: a.file_name,
+        content_type: a.content_type,
+        size: a.size
+      } END`
+
+// linkAttachment attaches the sender's upload to the message created earlier
+// in the same query. Uploads that belong to someone else or already hang off
+// another message are ignored; controllers reject those before sending.
+const linkAttachment = `
+    OPTIONAL MATCH (s)-[:UPLOADED]->(a:Attachment {attachment_id: $attachment_id})
+    WHERE NOT EXISTS { (a)<-[:HAS_ATTACHMENT]-(:Message) }
+    FOREACH (_ IN CASE WHEN a IS NULL THEN [] ELSE [1] END | CREATE (m)-[:HAS_ATTACHMENT]->(a))
+    `
+
+func CreateAttachment(ctx context.Context, driver neo4j.DriverWithContext, attachment models.Attachment, logger *zap.Logger) (map[string]interface{}, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	attachment.AttachmentID = uuid.New().String()
+
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    CREATE (u)-[:UPLOADED]->(a:Attachment {
+      attachment_id: $attachment_id,
+      file_name: $file_name,
+      content_type: $content_type,
+      size: $size,
+      storage_key: $storage_key,
+      created_timestamp: timestamp()
+    })
+    RETURN a.created_timestamp AS created_timestamp
+    `
+
+	params := map[string]interface{}{
+		"user_id":       attachment.UploaderID,
+		"attachment_id": attachment.AttachmentID,
+		"file_name":     attachment.FileName.Raw,
+		"content_type":  attachment.ContentType,
+		"size":          attachment.Size,
+		"storage_key":   attachment.StorageKey,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to create attachment", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to create attachment")
+	}
+
+	if !result.Next(ctx) {
+		return nil, fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s not found", attachment.UploaderID))
+	}
+	created, _ := result.Record().Get("created_timestamp")
+
+	return map[string]interface{}{
+		"attachment_id":     attachment.AttachmentID,
+		"file_name":         attachment.FileName.Value,
+		"content_type":      attachment.ContentType,
+		"size":              attachment.Size,
+		"created_timestamp": created,
+	}, nil
+}
+
+// GetAttachment returns the attachment with its uploader, storage key and
+// the message it was sent with, if any.
+func GetAttachment(ctx context.Context, driver neo4j.DriverWithContext, attachmentID string, logger *zap.Logger) (map[string]interface{}, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (u:UserProfile)-[:UPLOADED]->(a:Attachment {attachment_id: $attachment_id})
+    OPTIONAL MATCH (m:Message)-[:HAS_ATTACHMENT]->(a)
+    RETURN
+      a.attachment_id AS attachment_id,
+      u.user_id AS uploader_id,
+      a.file_name AS file_name,
+      a.content_type AS content_type,
+      a.size AS size,
+      a.storage_key AS storage_key,
+      a.created_timestamp AS created_timestamp,
+      m.message_id AS message_id
+    `
+
+	params := map[string]interface{}{
+		"attachment_id": attachmentID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to retrieve attachment", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve attachment")
+	}
+
+	if !result.Next(ctx) {
+		return nil, fiber.NewError(http.StatusNotFound, fmt.Sprintf("Attachment: %s not found", attachmentID))
+	}
+
+	return result.Record().AsMap(), nil
+}
+
+// CanAccessAttachment reports whether the user uploaded the attachment or
+// takes part in the conversation of the message it was sent with: either end
+// of a direct message, or a member of the group conversation.
+func CanAccessAttachment(ctx context.Context, driver neo4j.DriverWithContext, attachmentID, userID string, logger *zap.Logger) (bool, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (a:Attachment {attachment_id: $attachment_id}), (u:UserProfile {user_id: $user_id})
+    OPTIONAL MATCH (m:Message)-[:HAS_ATTACHMENT]->(a)
+    RETURN EXISTS { (u)-[:UPLOADED]->(a) }
+      OR (m IS NOT NULL AND (
+        EXISTS { (u)-[:SENT|RECEIVED]->(m) }
+        OR EXISTS { (u)-[:MEMBER_OF]->(:Conversation)<-[:POSTED_IN]-(m) }
+      )) AS allowed
+    `
+
+	params := map[string]interface{}{
+		"attachment_id": attachmentID,
+		"user_id":       userID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to check attachment access", zap.Error(err))
+		return false, fiber.NewError(http.StatusInternalServerError, "Failed to check attachment access")
+	}
+
+	if !result.Next(ctx) {
+		return false, nil
+	}
+
+	allowed, _, _ := neo4j.GetRecordValue[bool](result.Record(), "allowed")
+	return allowed, nil
+}
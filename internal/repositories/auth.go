@@ -23,7 +23,9 @@ func Login(ctx context.Context, driver neo4j.DriverWithContext, username string,
     MATCH (u:UserProfile)
     WHERE (u.username = $username OR u.email = $username) AND u.is_verify = true
     RETURN u.user_id AS user_id, u.user_password AS user_password, u.role AS role,
-      u.admit_year AS admit_year
+      u.admit_year AS admit_year, u.language AS language,
+      coalesce(u.mfa_enabled, false) AS mfa_enabled,
+      coalesce(u.mfa_required, false) AS mfa_required
   `
 	params := map[string]interface{}{
 		"username": username,
@@ -158,7 +160,7 @@ func RegistryAlumnus(ctx context.Context, driver neo4j.DriverWithContext, user m
 	return ret, nil
 }
 
-func RegistryUser(ctx context.Context, driver neo4j.DriverWithContext, user models.RegistryRequest, logger *zap.Logger) (map[string]interface{}, error) {
+func RegistryUser(ctx context.Context, driver neo4j.DriverWithContext, user models.RegistryRequest, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeWrite,
@@ -169,7 +171,7 @@ func RegistryUser(ctx context.Context, driver neo4j.DriverWithContext, user mode
 	tx, err := session.BeginTransaction(ctx)
 	if err != nil {
 		logger.Error("Failed to start transaction", zap.Error(err))
-		return nil, fmt.Errorf("error starting transaction: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error starting transaction: %w", err)
 	}
 
 	// Defer a function to handle transaction rollback in case of failure
@@ -197,14 +199,14 @@ func RegistryUser(ctx context.Context, driver neo4j.DriverWithContext, user mode
 	checkResult, err := tx.Run(ctx, checkQuery, checkParams)
 	if err != nil {
 		logger.Error("Failed to check username uniqueness", zap.Error(err))
-		return nil, fmt.Errorf("error checking username uniqueness: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error checking username uniqueness: %w", err)
 	}
 
 	// Hash the password
 	hashedPass, err := auth.HashPassword(user.Password)
 	if err != nil {
 		logger.Error("Failed to hash password", zap.Error(err))
-		return nil, fmt.Errorf("error hashing password: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error hashing password: %w", err)
 	}
 
 	// Generate a verification token
@@ -213,19 +215,19 @@ func RegistryUser(ctx context.Context, driver neo4j.DriverWithContext, user mode
 	record, err := checkResult.Single(ctx)
 	if err != nil {
 		logger.Error("Failed to collect query results", zap.Error(err))
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Error retrieving data")
+		return nil, models.Mail{}, fiber.NewError(fiber.StatusInternalServerError, "Error retrieving data")
 	}
 
 	usernameExist, _ := record.Get("usernameExist")
 	if usernameExist.(bool) {
 		logger.Error("User already exist", zap.Error(err))
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "User already exist")
+		return nil, models.Mail{}, fiber.NewError(fiber.StatusInternalServerError, "User already exist")
 	}
 
 	emailExist, _ := record.Get("emailExist")
 	if emailExist.(bool) {
 		logger.Error("Email already used", zap.Error(err))
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Email already used")
+		return nil, models.Mail{}, fiber.NewError(fiber.StatusInternalServerError, "Email already used")
 	}
 
 	userID := uuid.New().String()
@@ -233,7 +235,7 @@ func RegistryUser(ctx context.Context, driver neo4j.DriverWithContext, user mode
 	jwtToken, err := auth.GenerateVerificationJWT(userID, token)
 	if err != nil {
 		logger.Error("Failed to create verify jwt", zap.Error(err))
-		return nil, fmt.Errorf("failed to create verify jwt: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("failed to create verify jwt: %w", err)
 	}
 
 	createQuery := `
@@ -244,7 +246,8 @@ func RegistryUser(ctx context.Context, driver neo4j.DriverWithContext, user mode
         email: $email,
         is_verify: false,
         verification_token: $token,
-        role: $role
+        role: $role,
+        created_timestamp: timestamp()
     })
     RETURN u.user_id AS user_id
   `
@@ -264,40 +267,34 @@ func RegistryUser(ctx context.Context, driver neo4j.DriverWithContext, user mode
 	createResult, err := tx.Run(ctx, createQuery, createParams)
 	if err != nil {
 		logger.Error("Failed to create user", zap.Error(err))
-		return nil, fmt.Errorf("error creating user: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error creating user: %w", err)
 	}
 
 	createRecord, err := createResult.Single(ctx)
 	if err != nil {
 		logger.Error("Failed to fetch created user ID", zap.Error(err))
-		return nil, fmt.Errorf("error fetching created user ID: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error fetching created user ID: %w", err)
 	}
 
 	createdUserID, _ := createRecord.Get("user_id")
 
-	// Commit the transaction before sending the email
 	if err = tx.Commit(ctx); err != nil {
 		logger.Error("Failed to commit transaction", zap.Error(err))
-		return nil, fmt.Errorf("error committing transaction: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error committing transaction: %w", err)
 	}
 
 	ref := auth.GenerateRefNum()
-
-	// Send verification email
-	if err = utils.SendVerificationEmail(user.Email, jwtToken, ref); err != nil {
-		logger.Error("Failed to send verification email", zap.Error(err))
-		// Rollback the transaction if email sending fails
-		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-			logger.Error("Failed to rollback transaction", zap.Error(rollbackErr))
-		}
-		return nil, fmt.Errorf("error sending verification email: %w", err)
+	mail := models.Mail{
+		To:       user.Email,
+		Template: models.MailVerifyEmail,
+		Data:     map[string]string{"Token": jwtToken, "Ref": ref},
 	}
 
 	ret := map[string]interface{}{
 		"user_id":          createdUserID,
 		"reference_number": ref,
 	}
-	return ret, nil
+	return ret, mail, nil
 }
 
 func VerifyAccount(ctx context.Context, driver neo4j.DriverWithContext, user_id, token string, logger *zap.Logger) (map[string]interface{}, error) {
@@ -376,7 +373,7 @@ func VerifyAccount(ctx context.Context, driver neo4j.DriverWithContext, user_id,
 	return ret, nil
 }
 
-func RequestChangePassword(ctx context.Context, driver neo4j.DriverWithContext, email string, logger *zap.Logger) (map[string]interface{}, error) {
+func RequestChangePassword(ctx context.Context, driver neo4j.DriverWithContext, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeWrite,
@@ -387,7 +384,7 @@ func RequestChangePassword(ctx context.Context, driver neo4j.DriverWithContext,
 	tx, err := session.BeginTransaction(ctx)
 	if err != nil {
 		logger.Error("Failed to start transaction", zap.Error(err))
-		return nil, fmt.Errorf("error starting transaction: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error starting transaction: %w", err)
 	}
 
 	// Defer a function to handle transaction rollback in case of failure
@@ -405,7 +402,8 @@ func RequestChangePassword(ctx context.Context, driver neo4j.DriverWithContext,
         email: $email
     })
     RETURN
-      u.user_id AS user_id
+      u.user_id AS user_id,
+      u.language AS language
   `
 	params := map[string]interface{}{
 		"email": email,
@@ -414,27 +412,27 @@ func RequestChangePassword(ctx context.Context, driver neo4j.DriverWithContext,
 	result, err := tx.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to update user", zap.Error(err))
-		return nil, fmt.Errorf("error updating user: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error updating user: %w", err)
 	}
 
 	record, err := result.Single(ctx)
 	if err != nil {
 		logger.Warn("User not found", zap.Error(err))
-		return nil, fmt.Errorf("user not found: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("user not found: %w", err)
 	}
 
 	user_id, ok := record.Get("user_id")
 	if !ok {
 		logger.Warn("user_id not found", zap.Error(err))
-		return nil, fmt.Errorf("user_id not found: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("user_id not found: %w", err)
 	}
 
 	token := auth.GenerateVerificationToken()
 
-	jwtToken, err := auth.GenerateVerificationJWT(user_id.(string), token)
+	jwtToken, err := auth.GenerateResetPasswordJWT(user_id.(string), token)
 	if err != nil {
 		logger.Error("Failed to create verify jwt", zap.Error(err))
-		return nil, fmt.Errorf("failed to create verify jwt: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("failed to create verify jwt: %w", err)
 	}
 
 	query = `
@@ -451,32 +449,28 @@ func RequestChangePassword(ctx context.Context, driver neo4j.DriverWithContext,
 	_, err = tx.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to update user", zap.Error(err))
-		return nil, fmt.Errorf("error updating user: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error updating user: %w", err)
 	}
 
-	ref := auth.GenerateRefNum()
-
-	// Send verification email
-	if err = utils.SendResetMail(email, jwtToken, ref); err != nil {
-		logger.Error("Failed to send verification email", zap.Error(err))
-		// Rollback the transaction if email sending fails
-		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-			logger.Error("Failed to rollback transaction", zap.Error(rollbackErr))
-		}
-		return nil, fmt.Errorf("error sending verification email: %w", err)
-	}
-
-	// Commit the transaction after sending the email
 	if err = tx.Commit(ctx); err != nil {
 		logger.Error("Failed to commit transaction", zap.Error(err))
-		return nil, fmt.Errorf("error committing transaction: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error committing transaction: %w", err)
 	}
 
+	ref := auth.GenerateRefNum()
+	language, _ := record.Get("language")
+	mail := models.Mail{
+		To:       email,
+		Template: models.MailResetPassword,
+		Data:     map[string]string{"Token": jwtToken, "Ref": ref},
+	}
+	mail.Language, _ = language.(string)
+
 	ret := map[string]interface{}{
 		"reference_number": ref,
 	}
 
-	return ret, nil
+	return ret, mail, nil
 }
 
 func ChangePassword(ctx context.Context, driver neo4j.DriverWithContext, user_id, password, token string, logger *zap.Logger) error {
@@ -541,7 +535,7 @@ func ChangePassword(ctx context.Context, driver neo4j.DriverWithContext, user_id
 	return nil
 }
 
-func RequestChangeMail(ctx context.Context, driver neo4j.DriverWithContext, user_id, email string, logger *zap.Logger) (map[string]interface{}, error) {
+func RequestChangeMail(ctx context.Context, driver neo4j.DriverWithContext, user_id, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeWrite,
@@ -552,7 +546,7 @@ func RequestChangeMail(ctx context.Context, driver neo4j.DriverWithContext, user
 	tx, err := session.BeginTransaction(ctx)
 	if err != nil {
 		logger.Error("Failed to start transaction", zap.Error(err))
-		return nil, fmt.Errorf("error starting transaction: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error starting transaction: %w", err)
 	}
 
 	// Defer a function to handle transaction rollback in case of failure
@@ -570,7 +564,7 @@ func RequestChangeMail(ctx context.Context, driver neo4j.DriverWithContext, user
 	jwtToken, err := auth.GenerateVerifyEmailJWT(user_id, email, token)
 	if err != nil {
 		logger.Error("Failed to create verify jwt", zap.Error(err))
-		return nil, fmt.Errorf("failed to create verify jwt: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("failed to create verify jwt: %w", err)
 	}
 
 	query := `
@@ -587,32 +581,26 @@ func RequestChangeMail(ctx context.Context, driver neo4j.DriverWithContext, user
 	_, err = tx.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to update user", zap.Error(err))
-		return nil, fmt.Errorf("error updating user: %w", err)
-	}
-
-	ref := auth.GenerateRefNum()
-
-	// Send verification email
-	if err = utils.SendVerificationChangeEmail(email, jwtToken, ref); err != nil {
-		logger.Error("Failed to send verification email", zap.Error(err))
-		// Rollback the transaction if email sending fails
-		if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-			logger.Error("Failed to rollback transaction", zap.Error(rollbackErr))
-		}
-		return nil, fmt.Errorf("error sending verification email: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error updating user: %w", err)
 	}
 
-	// Commit the transaction after sending the email
 	if err = tx.Commit(ctx); err != nil {
 		logger.Error("Failed to commit transaction", zap.Error(err))
-		return nil, fmt.Errorf("error committing transaction: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error committing transaction: %w", err)
+	}
+
+	ref := auth.GenerateRefNum()
+	mail := models.Mail{
+		To:       email,
+		Template: models.MailVerifyChangeEmail,
+		Data:     map[string]string{"Token": jwtToken, "Ref": ref},
 	}
 
 	ret := map[string]interface{}{
 		"reference_number": ref,
 	}
 
-	return ret, nil
+	return ret, mail, nil
 }
 
 func VerifyEmail(ctx context.Context, driver neo4j.DriverWithContext, user_id, email, token string, logger *zap.Logger) error {
@@ -668,7 +656,7 @@ func VerifyEmail(ctx context.Context, driver neo4j.DriverWithContext, user_id, e
 	return nil
 }
 
-func RequestAlumniOneTimeRegistry(ctx context.Context, driver neo4j.DriverWithContext, email string, logger *zap.Logger) (map[string]interface{}, error) {
+func RequestAlumniOneTimeRegistry(ctx context.Context, driver neo4j.DriverWithContext, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeRead,
@@ -679,7 +667,7 @@ func RequestAlumniOneTimeRegistry(ctx context.Context, driver neo4j.DriverWithCo
 	tx, err := session.BeginTransaction(ctx)
 	if err != nil {
 		logger.Error("Failed to start transaction", zap.Error(err))
-		return nil, fmt.Errorf("error starting transaction: %w", err)
+		return nil, models.Mail{}, fmt.Errorf("error starting transaction: %w", err)
 	}
 
 	// Defer a function to handle transaction rollback in case of failure
@@ -704,58 +692,47 @@ func RequestAlumniOneTimeRegistry(ctx context.Context, driver neo4j.DriverWithCo
 	result, err := tx.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to query user", zap.Error(err))
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Error querying user")
+		return nil, models.Mail{}, fiber.NewError(fiber.StatusInternalServerError, "Error querying user")
 	}
 
 	record, err := result.Single(ctx)
 	if err != nil {
 		logger.Warn("User not found", zap.Error(err))
-		return nil, fiber.NewError(fiber.StatusUnauthorized, "User not found")
+		return nil, models.Mail{}, fiber.NewError(fiber.StatusUnauthorized, "User not found")
 	}
 
 	userExists, ok := record.Get("userExist")
 	if !ok {
 		logger.Warn("User not found")
-		return nil, fiber.NewError(fiber.StatusInternalServerError, "Error Using the Query")
+		return nil, models.Mail{}, fiber.NewError(fiber.StatusInternalServerError, "Error Using the Query")
 	}
 
 	ref := auth.GenerateRefNum()
+	mail := models.Mail{
+		To:       email,
+		Template: models.MailOneTimeRegistryFail,
+		Data:     map[string]string{"Ref": ref},
+	}
 
 	if userExists.(bool) {
 		jwtToken, err := auth.GenerateOneTimeRegistryJWT(email)
 		if err != nil {
 			logger.Error("Failed to create verify jwt", zap.Error(err))
-			return nil, fmt.Errorf("failed to create verify jwt: %w", err)
-		}
-
-		if err = utils.SendOneTimeRegistryEmailSucc(email, jwtToken, ref); err != nil {
-			logger.Error("Failed to send verification email", zap.Error(err))
-			// Rollback the transaction if email sending fails
-			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-				logger.Error("Failed to rollback transaction", zap.Error(rollbackErr))
-			}
-			return nil, fmt.Errorf("error sending verification email: %w", err)
-		}
-	} else {
-		if err = utils.SendOneTimeRegistryEmailFail(email, ref); err != nil {
-			logger.Error("Failed to send verification email", zap.Error(err))
-			// Rollback the transaction if email sending fails
-			if rollbackErr := tx.Rollback(ctx); rollbackErr != nil {
-				logger.Error("Failed to rollback transaction", zap.Error(rollbackErr))
-			}
-			return nil, fmt.Errorf("error sending verification email: %w", err)
+			return nil, models.Mail{}, fmt.Errorf("failed to create verify jwt: %w", err)
 		}
 
+		mail.Template = models.MailOneTimeRegistry
+		mail.Data["Token"] = jwtToken
 	}
 
 	ret := map[string]interface{}{
 		"reference_number": ref,
 	}
 
-	return ret, nil
+	return ret, mail, nil
 }
 
-func ApproveAlumnusRole(ctx context.Context, driver neo4j.DriverWithContext, request_id string, logger *zap.Logger) error {
+func ApproveAlumnusRole(ctx context.Context, driver neo4j.DriverWithContext, request_id, departmentID string, logger *zap.Logger) error {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeWrite,
@@ -764,25 +741,39 @@ func ApproveAlumnusRole(ctx context.Context, driver neo4j.DriverWithContext, req
 
 	query := `
     MATCH (u:UserProfile)-[:HAS_REQUEST]->(r:Request {request_id: $request_id})
-    WHERE r.status = "pending"
+    WHERE r.status = "pending" AND ` + inDepartment("u") + `
     SET
       u.role = "alumnus",
       r.status = "approve"
+    RETURN count(r) AS updated
   `
 	params := map[string]interface{}{
-		"request_id": request_id,
+		"request_id":    request_id,
+		"department_id": departmentParam(departmentID),
 	}
 
-	_, err := session.Run(ctx, query, params)
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to query user", zap.Error(err))
+		return fmt.Errorf("error querying user: %w", err)
+	}
+
+	record, err := result.Single(ctx)
 	if err != nil {
 		logger.Error("Failed to query user", zap.Error(err))
 		return fmt.Errorf("error querying user: %w", err)
 	}
 
+	// Requests outside the caller's department are not found, the same as
+	// ones already decided.
+	if updated, _ := record.Get("updated"); updated == int64(0) {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Request: %s not found", request_id))
+	}
+
 	return nil
 }
 
-func RejectAlumnusRole(ctx context.Context, driver neo4j.DriverWithContext, request_id string, logger *zap.Logger) error {
+func RejectAlumnusRole(ctx context.Context, driver neo4j.DriverWithContext, request_id, departmentID string, logger *zap.Logger) error {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeWrite,
@@ -791,20 +782,32 @@ func RejectAlumnusRole(ctx context.Context, driver neo4j.DriverWithContext, requ
 
 	query := `
     MATCH (u:UserProfile)-[:HAS_REQUEST]->(r:Request {request_id: $request_id})
-    WHERE r.status = "pending"
+    WHERE r.status = "pending" AND ` + inDepartment("u") + `
     SET
       r.status = "reject"
+    RETURN count(r) AS updated
   `
 	params := map[string]interface{}{
-		"request_id": request_id,
+		"request_id":    request_id,
+		"department_id": departmentParam(departmentID),
 	}
 
-	_, err := session.Run(ctx, query, params)
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to query user", zap.Error(err))
+		return fmt.Errorf("error querying user: %w", err)
+	}
+
+	record, err := result.Single(ctx)
 	if err != nil {
 		logger.Error("Failed to query user", zap.Error(err))
 		return fmt.Errorf("error querying user: %w", err)
 	}
 
+	if updated, _ := record.Get("updated"); updated == int64(0) {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Request: %s not found", request_id))
+	}
+
 	return nil
 }
 
@@ -838,7 +841,7 @@ func RequestAlumnusRole(ctx context.Context, driver neo4j.DriverWithContext, use
 	return nil
 }
 
-func GetAllRequest(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) ([]map[string]interface{}, error) {
+func GetAllRequest(ctx context.Context, driver neo4j.DriverWithContext, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeRead,
@@ -847,6 +850,7 @@ func GetAllRequest(ctx context.Context, driver neo4j.DriverWithContext, logger *
 
 	query := `
     MATCH (u:UserProfile)-[:HAS_REQUEST]->(r:Request)
+    WHERE ` + inDepartment("u") + `
     OPTIONAL MATCH (u)-[workRel:HAS_WORK_WITH]->(company:Company)
     OPTIONAL MATCH (u)-->(st:StudentType)<--(fld:Field)<--(d:Department)<--(f:Faculty)
 
@@ -899,7 +903,11 @@ func GetAllRequest(ctx context.Context, driver neo4j.DriverWithContext, logger *
     } AS result
   `
 
-	result, err := session.Run(ctx, query, nil)
+	params := map[string]interface{}{
+		"department_id": departmentParam(departmentID),
+	}
+
+	result, err := session.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to query user", zap.Error(err))
 		return nil, fiber.NewError(fiber.StatusInternalServerError, fmt.Sprintf("error querying user: %s", err))
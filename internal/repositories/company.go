@@ -89,8 +89,9 @@ func AddUserCompany(ctx context.Context, driver neo4j.DriverWithContext, id stri
 		}
 
 		if len(company.Position.Raw) != 0 {
-			query += `,r.position = $position`
+			query += `,r.position = $position, r.position_index = $position_index`
 			params["position"] = company.Position.Raw
+			params["position_index"] = company.PositionIndex
 		}
 		if len(company.SalaryMax.Raw) != 0 {
 			query += `,r.salary_max = $salary_max`
@@ -184,12 +185,14 @@ func UpdateUserCompany(ctx context.Context, driver neo4j.DriverWithContext, user
 			updateQuery := `
 			MATCH (a:Company {company_id: $companyID})<-[r:HAS_WORK_WITH]-(u:UserProfile {user_id: $userID})
 			SET r.position = $position,
+				r.position_index = $positionIndex,
 				r.updated_timestamp = timestamp()
 		`
 			_, err := tx.Run(ctx, updateQuery, map[string]interface{}{
-				"companyID": companyID,
-				"userID":    userID,
-				"position":  company.Position,
+				"companyID":     companyID,
+				"userID":        userID,
+				"position":      company.Position.Raw,
+				"positionIndex": company.PositionIndex,
 			})
 			if err != nil {
 				logger.Error("Failed to update user company info", zap.Error(err))
@@ -268,7 +271,8 @@ func FindCompanyAssociate(ctx context.Context, driver neo4j.DriverWithContext, c
     RETURN
       u.user_id AS user_id,
       u.first_name + ' ' + u.last_name AS fullname,
-      u.first_name_eng + ' ' + u.last_name_eng AS fullname_eng
+      u.first_name_eng + ' ' + u.last_name_eng AS fullname_eng,
+      u.privacy_career AS privacy_career
   `
 
 	params := map[string]interface{}{
@@ -0,0 +1,517 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+func CreateConversation(ctx context.Context, driver neo4j.DriverWithContext, ownerID string, conv models.Conversation, logger *zap.Logger) (map[string]interface{}, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	conversationID := uuid.New().String()
+
+	query := `
+    MATCH (o:UserProfile {user_id: $owner_id})
+    CREATE (o)-[:MEMBER_OF {role: "owner", joined_timestamp: timestamp()}]->(c:Conversation {
+      conversation_id: $conversation_id,
+      title: $title,
+      created_timestamp: timestamp()
+    })
+    WITH o, c
+    OPTIONAL MATCH (p:Post {post_id: $post_id})
+    FOREACH (_ IN CASE WHEN p IS NULL THEN [] ELSE [1] END | CREATE (p)-[:HAS_CHAT]->(c))
+    WITH o, c
+    UNWIND CASE WHEN size($member_ids) = 0 THEN [null] ELSE $member_ids END AS member_id
+    OPTIONAL MATCH (u:UserProfile {user_id: member_id})
+    WHERE u <> o
+    FOREACH (_ IN CASE WHEN u IS NULL THEN [] ELSE [1] END |
+      MERGE (u)-[m:MEMBER_OF]->(c)
+      ON CREATE SET m.role = "member", m.joined_timestamp = timestamp()
+    )
+    RETURN DISTINCT c.conversation_id AS conversation_id
+    `
+
+	memberIDs := conv.MemberIDs
+	if memberIDs == nil {
+		memberIDs = []string{}
+	}
+
+	params := map[string]interface{}{
+		"owner_id":        ownerID,
+		"conversation_id": conversationID,
+		"title":           conv.Title,
+		"post_id":         conv.PostID,
+		"member_ids":      memberIDs,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to create conversation", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to create conversation")
+	}
+
+	if !result.Next(ctx) {
+		return nil, fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s not found", ownerID))
+	}
+
+	return map[string]interface{}{
+		"conversation_id": conversationID,
+	}, nil
+}
+
+func GetConversationByID(ctx context.Context, driver neo4j.DriverWithContext, conversationID string, logger *zap.Logger) (map[string]interface{}, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (c:Conversation {conversation_id: $conversation_id})
+    OPTIONAL MATCH (p:Post)-[:HAS_CHAT]->(c)
+    OPTIONAL MATCH (u:UserProfile)-[m:MEMBER_OF]->(c)
+    WITH c, p, u, m
+    ORDER BY m.joined_timestamp, u.user_id
+    RETURN
+      c.conversation_id AS conversation_id,
+      c.title AS title,
+      p.post_id AS post_id,
+      c.created_timestamp AS created_timestamp,
+      c.updated_timestamp AS updated_timestamp,
+      collect({
+        user_id: u.user_id,
+        username: u.username,
+        name: u.first_name + " " + u.last_name,
+        picture: u.profile_picture,
+        role: m.role,
+        joined_timestamp: m.joined_timestamp
+      }) AS members
+    `
+
+	result, err := session.Run(ctx, query, map[string]interface{}{"conversation_id": conversationID})
+	if err != nil {
+		logger.Error("Failed to retrieve conversation", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve conversation")
+	}
+
+	if !result.Next(ctx) {
+		return nil, fiber.NewError(http.StatusNotFound, fmt.Sprintf("Conversation: %s not found", conversationID))
+	}
+
+	return result.Record().AsMap(), nil
+}
+
+func UpdateConversation(ctx context.Context, driver neo4j.DriverWithContext, conversationID, title string, logger *zap.Logger) error {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (c:Conversation {conversation_id: $conversation_id})
+    SET c.title = $title, c.updated_timestamp = timestamp()
+    `
+
+	params := map[string]interface{}{
+		"conversation_id": conversationID,
+		"title":           title,
+	}
+
+	if _, err := session.Run(ctx, query, params); err != nil {
+		logger.Error("Failed to update conversation", zap.Error(err))
+		return fiber.NewError(http.StatusInternalServerError, "Failed to update conversation")
+	}
+
+	return nil
+}
+
+// GetMemberRole returns the user's role in the conversation, or an empty
+// string when they are not a member.
+func GetMemberRole(ctx context.Context, driver neo4j.DriverWithContext, conversationID, userID string, logger *zap.Logger) (string, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (:UserProfile {user_id: $user_id})-[m:MEMBER_OF]->(:Conversation {conversation_id: $conversation_id})
+    RETURN m.role AS role
+    `
+
+	params := map[string]interface{}{
+		"conversation_id": conversationID,
+		"user_id":         userID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to retrieve conversation role", zap.Error(err))
+		return "", fiber.NewError(http.StatusInternalServerError, "Failed to retrieve conversation role")
+	}
+
+	if !result.Next(ctx) {
+		return "", nil
+	}
+
+	role, _, _ := neo4j.GetRecordValue[string](result.Record(), "role")
+	return role, nil
+}
+
+func GetMemberIDs(ctx context.Context, driver neo4j.DriverWithContext, conversationID string, logger *zap.Logger) ([]string, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (u:UserProfile)-[:MEMBER_OF]->(:Conversation {conversation_id: $conversation_id})
+    RETURN u.user_id AS user_id
+    `
+
+	result, err := session.Run(ctx, query, map[string]interface{}{"conversation_id": conversationID})
+	if err != nil {
+		logger.Error("Failed to retrieve conversation members", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve conversation members")
+	}
+
+	records, err := result.Collect(ctx)
+	if err != nil {
+		logger.Error("Failed to collect results", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
+	}
+
+	memberIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		if userID, _, err := neo4j.GetRecordValue[string](record, "user_id"); err == nil {
+			memberIDs = append(memberIDs, userID)
+		}
+	}
+
+	return memberIDs, nil
+}
+
+// ShareConversation reports whether the two users have exchanged direct
+// messages or are members of the same group conversation.
+func ShareConversation(ctx context.Context, driver neo4j.DriverWithContext, userID1, userID2 string, logger *zap.Logger) (bool, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (a:UserProfile {user_id: $user_id1}), (b:UserProfile {user_id: $user_id2})
+    RETURN EXISTS { (a)-[:SENT|RECEIVED]->(:Message)<-[:SENT|RECEIVED]-(b) }
+      OR EXISTS { (a)-[:MEMBER_OF]->(:Conversation)<-[:MEMBER_OF]-(b) } AS shared
+    `
+
+	params := map[string]interface{}{
+		"user_id1": userID1,
+		"user_id2": userID2,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to check shared conversations", zap.Error(err))
+		return false, fiber.NewError(http.StatusInternalServerError, "Failed to check shared conversations")
+	}
+
+	if !result.Next(ctx) {
+		return false, nil
+	}
+
+	shared, _, _ := neo4j.GetRecordValue[bool](result.Record(), "shared")
+	return shared, nil
+}
+
+// AddMembers adds the users as plain members. Existing members keep their
+// role and unknown users are skipped.
+func AddMembers(ctx context.Context, driver neo4j.DriverWithContext, conversationID string, userIDs []string, logger *zap.Logger) error {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (c:Conversation {conversation_id: $conversation_id})
+    UNWIND $user_ids AS user_id
+    MATCH (u:UserProfile {user_id: user_id})
+    MERGE (u)-[m:MEMBER_OF]->(c)
+    ON CREATE SET m.role = "member", m.joined_timestamp = timestamp()
+    `
+
+	params := map[string]interface{}{
+		"conversation_id": conversationID,
+		"user_ids":        userIDs,
+	}
+
+	if _, err := session.Run(ctx, query, params); err != nil {
+		logger.Error("Failed to add conversation members", zap.Error(err))
+		return fiber.NewError(http.StatusInternalServerError, "Failed to add conversation members")
+	}
+
+	return nil
+}
+
+func SetMemberRole(ctx context.Context, driver neo4j.DriverWithContext, conversationID, userID, role string, logger *zap.Logger) error {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (:UserProfile {user_id: $user_id})-[m:MEMBER_OF]->(:Conversation {conversation_id: $conversation_id})
+    SET m.role = $role
+    `
+
+	params := map[string]interface{}{
+		"conversation_id": conversationID,
+		"user_id":         userID,
+		"role":            role,
+	}
+
+	if _, err := session.Run(ctx, query, params); err != nil {
+		logger.Error("Failed to update conversation role", zap.Error(err))
+		return fiber.NewError(http.StatusInternalServerError, "Failed to update conversation role")
+	}
+
+	return nil
+}
+
+// RemoveMember drops the user from the conversation. When the owner leaves,
+// the longest-standing admin, or failing that member, takes over; the last
+// member leaving deletes the conversation together with its messages.
+func RemoveMember(ctx context.Context, driver neo4j.DriverWithContext, conversationID, userID string, logger *zap.Logger) error {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (:UserProfile {user_id: $user_id})-[m:MEMBER_OF]->(c:Conversation {conversation_id: $conversation_id})
+    WITH c, m, m.role = "owner" AS was_owner
+    DELETE m
+    WITH c, was_owner
+    OPTIONAL MATCH (:UserProfile)-[rest:MEMBER_OF]->(c)
+    WITH c, was_owner, rest
+    ORDER BY CASE rest.role WHEN "admin" THEN 0 ELSE 1 END, rest.joined_timestamp
+    WITH c, was_owner, collect(rest) AS remaining
+    FOREACH (next IN CASE WHEN was_owner AND size(remaining) > 0 THEN [remaining[0]] ELSE [] END |
+      SET next.role = "owner"
+    )
+    WITH c, remaining
+    WHERE size(remaining) = 0
+    OPTIONAL MATCH (msg:Message)-[:POSTED_IN]->(c)
+    WITH c, collect(msg) AS messages
+    FOREACH (msg IN messages | DETACH DELETE msg)
+    DETACH DELETE c
+    `
+
+	params := map[string]interface{}{
+		"conversation_id": conversationID,
+		"user_id":         userID,
+	}
+
+	if _, err := session.Run(ctx, query, params); err != nil {
+		logger.Error("Failed to remove conversation member", zap.Error(err))
+		return fiber.NewError(http.StatusInternalServerError, "Failed to remove conversation member")
+	}
+
+	return nil
+}
+
+// GetEventConversationID returns the group chat linked to an event post, or
+// an empty string when it has none.
+func GetEventConversationID(ctx context.Context, driver neo4j.DriverWithContext, postID string, logger *zap.Logger) (string, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (:Post {post_id: $post_id, post_type: "event"})-[:HAS_CHAT]->(c:Conversation)
+    RETURN c.conversation_id AS conversation_id
+    `
+
+	result, err := session.Run(ctx, query, map[string]interface{}{"post_id": postID})
+	if err != nil {
+		logger.Error("Failed to retrieve event conversation", zap.Error(err))
+		return "", fiber.NewError(http.StatusInternalServerError, "Failed to retrieve event conversation")
+	}
+
+	if !result.Next(ctx) {
+		return "", nil
+	}
+
+	conversationID, _, _ := neo4j.GetRecordValue[string](result.Record(), "conversation_id")
+	return conversationID, nil
+}
+
+func SendGroupMessage(ctx context.Context, driver neo4j.DriverWithContext, msg models.GroupMessage, logger *zap.Logger) (map[string]interface{}, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	msg.MessageID = uuid.New().String()
+
+	query := `
+    MATCH (s:UserProfile {user_id: $sender})-[:MEMBER_OF]->(c:Conversation {conversation_id: $conversation_id})
+    OPTIONAL MATCH (rm:Message {message_id: $reply_id})-[:POSTED_IN]->(c)
+    WITH s, c, rm
+    WHERE $reply_id IS NULL OR rm IS NOT NULL
+    CREATE (s)-[:SENT]->(m:Message {
+      message_id: $message_id,
+      content: $content,
+      created_timestamp: timestamp()
+    })-[:POSTED_IN]->(c)
+    FOREACH (_ IN CASE WHEN rm IS NULL THEN [] ELSE [1] END | CREATE (m)-[:REPLIED]->(rm))
+    WITH s, m, rm` + linkAttachment + `
+    RETURN
+      rm.content AS reply_content,
+      s.username AS sender_username,
+      s.first_name + " " + s.last_name AS sender_fullname,
+      s.profile_picture AS sender_picture,
+      m.created_timestamp AS timestamp,
+      ` + attachmentProjection + ` AS attachment
+    `
+
+	var replyID interface{}
+	if msg.ReplyID != "" {
+		replyID = msg.ReplyID
+	}
+
+	params := map[string]interface{}{
+		"message_id":      msg.MessageID,
+		"conversation_id": msg.ConversationID,
+		"reply_id":        replyID,
+		"sender":          msg.SenderID,
+		"content":         msg.Content.Raw,
+		"attachment_id":   msg.AttachmentID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to send message", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to send message")
+	}
+
+	if !result.Next(ctx) {
+		return nil, fiber.NewError(http.StatusNotFound, fmt.Sprintf("Reply message: %s not found", msg.ReplyID))
+	}
+	record := result.Record()
+
+	messageData := map[string]interface{}{
+		"message_id":      msg.MessageID,
+		"conversation_id": msg.ConversationID,
+		"content":         msg.Content.Value,
+		"sender_id":       msg.SenderID,
+	}
+
+	if msg.ReplyID != "" {
+		messageData["reply_message_id"] = msg.ReplyID
+	}
+
+	if replyContent, ok := record.Get("reply_content"); ok && replyContent != nil {
+		messageData["reply_content"] = replyContent
+	}
+
+	if senderUsername, ok := record.Get("sender_username"); ok {
+		messageData["sender_username"] = senderUsername
+	}
+
+	if senderFullname, ok := record.Get("sender_fullname"); ok && senderFullname != nil {
+		messageData["sender_fullname"] = senderFullname
+	}
+
+	if senderPicture, ok := record.Get("sender_picture"); ok && senderPicture != nil {
+		messageData["sender_picture"] = senderPicture
+	}
+
+	if timestamp, ok := record.Get("timestamp"); ok {
+		messageData["timestamp"] = timestamp
+	}
+
+	if attachment, ok := record.Get("attachment"); ok && attachment != nil {
+		messageData["attachment"] = attachment
+	}
+
+	return messageData, nil
+}
+
+func GetGroupMessages(ctx context.Context, driver neo4j.DriverWithContext, conversationID string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (s:UserProfile)-[:SENT]->(m:Message)-[:POSTED_IN]->(:Conversation {conversation_id: $conversation_id})
+    WHERE $cursor_ts IS NULL
+      OR m.created_timestamp < $cursor_ts
+      OR (m.created_timestamp = $cursor_ts AND m.message_id < $cursor_id)
+    OPTIONAL MATCH (m)-[:REPLIED]->(rm:Message)
+    OPTIONAL MATCH (m)-[:HAS_ATTACHMENT]->(a:Attachment)
+    RETURN
+      m.message_id AS message_id,
+      m.content AS content,
+      m.created_timestamp AS created_timestamp,
+      m.updated_timestamp AS update_timestamp,
+      rm.message_id AS reply_message_id,
+      rm.content AS reply_message_content,
+      ` + attachmentProjection + ` AS attachment,
+      {
+        id: s.user_id,
+        username: s.username,
+        name: s.first_name + " " + s.last_name,
+        picture: s.profile_picture
+      } AS sender
+    ORDER BY created_timestamp DESC, message_id DESC
+    LIMIT $limit
+    `
+
+	params := page.Params(map[string]interface{}{
+		"conversation_id": conversationID,
+	})
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to retrieve messages", zap.Error(err))
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to retrieve messages")
+	}
+
+	records, err := result.Collect(ctx)
+	if err != nil {
+		logger.Error("Failed to collect results", zap.Error(err))
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
+	}
+
+	records, nextCursor := models.NextPage(records, page, recordCursor("created_timestamp", "message_id"))
+
+	messages := []map[string]interface{}{}
+	for _, record := range records {
+		messages = append(messages, record.AsMap())
+	}
+
+	return messages, nextCursor, nil
+}
@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// inDepartment is a WHERE condition on the UserProfile bound to variable. It
+// holds when $department_id is null, for global scope, or when the user
+// belongs to a field of that department.
+func inDepartment(variable string) string {
+	return fmt.Sprintf(`($department_id IS NULL OR EXISTS {
+      MATCH (%s)-[:BELONGS_TO_FIELD]->(:Field)<-[:HAS_FIELD]-(:Department {department_id: $department_id})
+    })`, variable)
+}
+
+// departmentParam maps the empty department of global scope to null.
+func departmentParam(departmentID string) interface{} {
+	if departmentID == "" {
+		return nil
+	}
+	return departmentID
+}
+
+// AssignDepartmentIDs gives a department_id to the departments created
+// before departments had one.
+func AssignDepartmentIDs(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) error {
+	query := `
+    MATCH (d:Department)
+    WHERE d.department_id IS NULL
+    SET d.department_id = randomUUID()
+    RETURN count(d) AS assigned
+    `
+
+	_, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, nil, "assign department ids", logger)
+	return err
+}
+
+func GetDepartments(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) ([]map[string]interface{}, error) {
+	query := `
+    MATCH (f:Faculty)-[:HAS_DEPARTMENT]->(d:Department)
+    OPTIONAL MATCH (admin:UserProfile)-[:ADMINISTERS]->(d)
+    RETURN
+      d.department_id AS department_id,
+      f.name AS faculty,
+      d.name AS department,
+      collect(admin.user_id) AS admins
+    ORDER BY faculty, department
+    `
+
+	return runQuery(ctx, driver, neo4j.AccessModeRead, query, nil, "retrieve departments", logger)
+}
+
+// GetAdministeredDepartment returns the department_id of the department the
+// user administers, or "" when they administer none.
+func GetAdministeredDepartment(ctx context.Context, driver neo4j.DriverWithContext, userID string, logger *zap.Logger) (string, error) {
+	query := `
+    MATCH (:UserProfile {user_id: $user_id})-[:ADMINISTERS]->(d:Department)
+    RETURN d.department_id AS department_id
+    LIMIT 1
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeRead, query, params, "retrieve administered department", logger)
+	if err != nil || len(records) == 0 {
+		return "", err
+	}
+
+	departmentID, _ := records[0]["department_id"].(string)
+	return departmentID, nil
+}
+
+// AppointDepartmentAdmin makes the user the admin of one department, in place
+// of any other they administered, and grants them the department admin role.
+func AppointDepartmentAdmin(ctx context.Context, driver neo4j.DriverWithContext, departmentID, userID string, logger *zap.Logger) error {
+	query := `
+    OPTIONAL MATCH (u:UserProfile {user_id: $user_id})
+    OPTIONAL MATCH (d:Department {department_id: $department_id})
+    OPTIONAL MATCH (r:Role {name: $role})
+    OPTIONAL MATCH (u)-[old:ADMINISTERS]->(other:Department)
+    WHERE other <> d
+    WITH u, d, r, collect(old) AS olds,
+      u IS NOT NULL AND d IS NOT NULL AND r IS NOT NULL AS found
+    FOREACH (o IN CASE WHEN found THEN olds ELSE [] END | DELETE o)
+    FOREACH (_ IN CASE WHEN found THEN [1] ELSE [] END |
+      MERGE (u)-[a:ADMINISTERS]->(d)
+      ON CREATE SET a.assigned_timestamp = timestamp()
+      MERGE (u)-[h:HAS_ROLE]->(r)
+      ON CREATE SET h.assigned_timestamp = timestamp()
+    )
+    RETURN u IS NOT NULL AS user_exists, d IS NOT NULL AS department_exists, r IS NOT NULL AS role_exists
+    `
+
+	params := map[string]interface{}{
+		"user_id":       userID,
+		"department_id": departmentID,
+		"role":          models.DepartmentAdminRole,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "appoint department admin", logger)
+	if err != nil {
+		return err
+	}
+
+	if records[0]["user_exists"] != true {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+
+	if records[0]["department_exists"] != true {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("Department: %s not found", departmentID))
+	}
+
+	if records[0]["role_exists"] != true {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("Role: %s not found", models.DepartmentAdminRole))
+	}
+
+	return nil
+}
+
+// RemoveDepartmentAdmin takes the department and the department admin role
+// away from the user.
+func RemoveDepartmentAdmin(ctx context.Context, driver neo4j.DriverWithContext, departmentID, userID string, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})-[a:ADMINISTERS]->(:Department {department_id: $department_id})
+    OPTIONAL MATCH (u)-[h:HAS_ROLE]->(:Role {name: $role})
+    DELETE a, h
+    RETURN count(a) AS removed
+    `
+
+	params := map[string]interface{}{
+		"user_id":       userID,
+		"department_id": departmentID,
+		"role":          models.DepartmentAdminRole,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "remove department admin", logger)
+	if err != nil {
+		return err
+	}
+
+	if removed, _ := records[0]["removed"].(int64); removed == 0 {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s does not administer department %s", userID, departmentID))
+	}
+
+	return nil
+}
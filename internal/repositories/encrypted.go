@@ -0,0 +1,121 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// encryptedPattern matches the node or relationship holding prop as n. The
+// label and property are spliced into the query, so only the ones listed in
+// models.EncryptedProperties are accepted.
+func encryptedPattern(prop models.EncryptedProperty, logger *zap.Logger) (string, error) {
+	if !slices.Contains(models.EncryptedProperties, prop) {
+		logger.Error("Unknown encrypted property", zap.String("kind", prop.Kind), zap.String("property", prop.Property))
+		return "", fiber.NewError(http.StatusInternalServerError, "Unknown encrypted property")
+	}
+
+	if prop.Kind == "HAS_WORK_WITH" {
+		return "()-[n:HAS_WORK_WITH]->()", nil
+	}
+	return fmt.Sprintf("(n:%s)", prop.Kind), nil
+}
+
+// GetEncryptedValues pages through the values of prop, with their blind
+// index if it has one, by element id, starting after the given id.
+func GetEncryptedValues(ctx context.Context, driver neo4j.DriverWithContext, prop models.EncryptedProperty, after string, limit int, logger *zap.Logger) ([]models.EncryptedValue, error) {
+	pattern, err := encryptedPattern(prop, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	index := "null"
+	if prop.Index.Name != "" {
+		index = "n." + prop.Index.Property()
+	}
+
+	query := fmt.Sprintf(`
+    MATCH %s
+    WHERE n.%s IS NOT NULL AND elementId(n) > $after
+    RETURN elementId(n) AS id, n.%s AS raw, %s AS index
+    ORDER BY id
+    LIMIT $limit
+    `, pattern, prop.Property, prop.Property, index)
+
+	params := map[string]interface{}{
+		"after": after,
+		"limit": limit,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeRead, query, params, "retrieve encrypted values", logger)
+	if err != nil {
+		return nil, err
+	}
+
+	values := []models.EncryptedValue{}
+	for _, record := range records {
+		id, _ := record["id"].(string)
+		raw, ok := record["raw"].([]byte)
+		if !ok {
+			logger.Warn("Encrypted property is not a byte array", zap.String("property", prop.Property), zap.String("id", id))
+		}
+		index, _ := record["index"].(string)
+		values = append(values, models.EncryptedValue{ID: id, Raw: raw, Index: index})
+	}
+
+	return values, nil
+}
+
+// ReplaceEncryptedValues writes the new ciphertexts of prop, and their blind
+// indexes, and returns how many were replaced. A value changed since it was read is left alone, so
+// writes made while the job runs are never overwritten.
+func ReplaceEncryptedValues(ctx context.Context, driver neo4j.DriverWithContext, prop models.EncryptedProperty, changes []models.EncryptedChange, logger *zap.Logger) (int, error) {
+	pattern, err := encryptedPattern(prop, logger)
+	if err != nil {
+		return 0, err
+	}
+
+	set := fmt.Sprintf("n.%s = change.new", prop.Property)
+	if prop.Index.Name != "" {
+		set += fmt.Sprintf(", n.%s = change.index", prop.Index.Property())
+	}
+
+	query := fmt.Sprintf(`
+    UNWIND $changes AS change
+    MATCH %s
+    WHERE elementId(n) = change.id AND n.%s = change.old
+    SET %s
+    RETURN count(n) AS replaced
+    `, pattern, prop.Property, set)
+
+	rows := make([]map[string]interface{}, 0, len(changes))
+	for _, change := range changes {
+		rows = append(rows, map[string]interface{}{
+			"id":    change.ID,
+			"old":   change.Old,
+			"new":   change.New,
+			"index": change.Index,
+		})
+	}
+
+	params := map[string]interface{}{
+		"changes": rows,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "replace encrypted values", logger)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(records) == 0 {
+		return 0, nil
+	}
+	replaced, _ := records[0]["replaced"].(int64)
+	return int(replaced), nil
+}
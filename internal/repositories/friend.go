@@ -175,6 +175,7 @@ func GetFOAF(ctx context.Context, driver neo4j.DriverWithContext, user_id, other
       profile_picture: n.profile_picture,
       fullname: n.first_name + ' ' + n.last_name,
       fullname_eng: n.first_name_eng + ' ' + n.last_name_eng,
+      privacy_contact: n.privacy_contact,
       depth: idx
     }) AS nodeInfoList
     WITH DISTINCT nodeInfoList
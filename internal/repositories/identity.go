@@ -0,0 +1,141 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/utils"
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// identityLoginReturn is the RETURN clause shared by the identity queries,
+// the same columns Login returns.
+const identityLoginReturn = `
+    RETURN u.user_id AS user_id, u.role AS role, u.admit_year AS admit_year,
+      u.language AS language,
+      coalesce(u.mfa_enabled, false) AS mfa_enabled,
+      coalesce(u.mfa_required, false) AS mfa_required
+`
+
+// SignInWithIdentity resolves an OpenID Connect identity to a UserProfile.
+// An identity seen before signs in as the profile it HAS_IDENTITY from. A new
+// one is linked to the verified profile with the same email, but only when
+// the provider verified that email; otherwise a pending profile with the
+// "user" role is created for it.
+func SignInWithIdentity(ctx context.Context, driver neo4j.DriverWithContext, identity models.Identity, logger *zap.Logger) (models.LoginResponse, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	tx, err := session.BeginTransaction(ctx)
+	if err != nil {
+		logger.Error("Failed to start transaction", zap.Error(err))
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error starting transaction")
+	}
+	defer tx.Close(ctx)
+
+	params := map[string]interface{}{
+		"provider": identity.Provider,
+		"subject":  identity.Subject,
+		"email":    identity.Email,
+		"verified": identity.EmailVerified,
+	}
+
+	queries := []struct {
+		action string
+		query  string
+	}{
+		{"find linked identity", `
+    MATCH (u:UserProfile)-[:HAS_IDENTITY]->(i:Identity {provider: $provider, subject: $subject})
+    SET i.email = $email, i.email_verified = $verified, i.last_login_timestamp = timestamp()
+    ` + identityLoginReturn},
+		{"link identity", `
+    MATCH (u:UserProfile {email: $email, is_verify: true})
+    WHERE $verified AND $email <> ""
+    WITH u ORDER BY u.created_timestamp LIMIT 1
+    CREATE (u)-[:HAS_IDENTITY]->(:Identity {
+        provider: $provider,
+        subject: $subject,
+        email: $email,
+        email_verified: $verified,
+        created_timestamp: timestamp(),
+        last_login_timestamp: timestamp()
+    })
+    ` + identityLoginReturn},
+	}
+
+	for _, step := range queries {
+		result, err := tx.Run(ctx, step.query, params)
+		if err != nil {
+			logger.Error("Failed to "+step.action, zap.Error(err))
+			return models.LoginResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to "+step.action)
+		}
+
+		if result.Next(ctx) {
+			return commitIdentityLogin(ctx, tx, result.Record().AsMap(), logger)
+		}
+	}
+
+	username := fmt.Sprintf("%s:%s", identity.Provider, identity.Subject)
+	email := interface{}(nil)
+	if identity.EmailVerified {
+		username, email = identity.Email, identity.Email
+	}
+
+	createQuery := `
+    CREATE (u:UserProfile {
+        user_id: $user_id,
+        username: $username,
+        email: $profile_email,
+        is_verify: true,
+        role: "user",
+        created_timestamp: timestamp()
+    })-[:HAS_IDENTITY]->(:Identity {
+        provider: $provider,
+        subject: $subject,
+        email: $email,
+        email_verified: $verified,
+        created_timestamp: timestamp(),
+        last_login_timestamp: timestamp()
+    })
+    ` + identityLoginReturn
+
+	params["user_id"] = uuid.New().String()
+	params["username"] = username
+	params["profile_email"] = email
+
+	result, err := tx.Run(ctx, createQuery, params)
+	if err != nil {
+		logger.Error("Failed to create profile for identity", zap.Error(err))
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to create profile")
+	}
+
+	record, err := result.Single(ctx)
+	if err != nil {
+		logger.Error("Failed to fetch created profile", zap.Error(err))
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to create profile")
+	}
+
+	return commitIdentityLogin(ctx, tx, record.AsMap(), logger)
+}
+
+func commitIdentityLogin(ctx context.Context, tx neo4j.ExplicitTransaction, record map[string]interface{}, logger *zap.Logger) (models.LoginResponse, error) {
+	var res models.LoginResponse
+	if err := utils.MapToStruct(record, &res); err != nil {
+		logger.Error("Error decoding user properties", zap.Error(err))
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error decoding user properties")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		logger.Error("Failed to commit transaction", zap.Error(err))
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error committing transaction")
+	}
+
+	return res, nil
+}
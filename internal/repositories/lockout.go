@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"alumni_api/internal/auth"
+	"alumni_api/internal/models"
+	"alumni_api/internal/utils"
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// createSecurityEvent is the Cypher that records event on the UserProfile u.
+const createSecurityEvent = `
+    CREATE (u)-[:HAS_SECURITY_EVENT]->(:SecurityEvent {
+      event_id: $event_id,
+      type: $type,
+      ip: $ip,
+      user_agent: $user_agent,
+      actor_id: $actor_id,
+      reason: $reason,
+      created_timestamp: timestamp()
+    })
+`
+
+// loginThrottleReturn is the RETURN clause of the queries that report the
+// failed-login state.
+const loginThrottleReturn = `
+    RETURN
+      coalesce(u.login_failures, 0) AS login_failures,
+      coalesce(u.login_failed_timestamp, 0) AS login_failed_timestamp,
+      coalesce(u.login_locked_until, 0) AS login_locked_until
+`
+
+func securityEventParams(userID string, event models.SecurityEvent) map[string]interface{} {
+	return map[string]interface{}{
+		"user_id":    userID,
+		"event_id":   uuid.New().String(),
+		"type":       event.Type,
+		"ip":         event.IP,
+		"user_agent": event.UserAgent,
+		"actor_id":   event.ActorID,
+		"reason":     event.Reason,
+	}
+}
+
+func decodeLoginThrottle(record map[string]interface{}, logger *zap.Logger) (models.LoginThrottle, error) {
+	var throttle models.LoginThrottle
+	if err := utils.MapToStruct(record, &throttle); err != nil {
+		logger.Error("Error decoding login throttle", zap.Error(err))
+		return models.LoginThrottle{}, fiber.NewError(http.StatusInternalServerError, "Error decoding login throttle")
+	}
+	return throttle, nil
+}
+
+func GetLoginThrottle(ctx context.Context, driver neo4j.DriverWithContext, userID string, logger *zap.Logger) (models.LoginThrottle, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    ` + loginThrottleReturn
+
+	params := map[string]interface{}{
+		"user_id": userID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to retrieve login throttle", zap.Error(err))
+		return models.LoginThrottle{}, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve login throttle")
+	}
+
+	if !result.Next(ctx) {
+		return models.LoginThrottle{}, fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+
+	return decodeLoginThrottle(result.Record().AsMap(), logger)
+}
+
+// RecordLoginFailure counts a wrong password and records it as a
+// login_failed event. Failures older than window no longer count. The first
+// SET takes the write lock, so concurrent failures are all counted.
+func RecordLoginFailure(ctx context.Context, driver neo4j.DriverWithContext, userID string, window time.Duration, event models.SecurityEvent, logger *zap.Logger) (models.LoginThrottle, error) {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u._lock = true
+    SET u.login_failures = CASE
+          WHEN coalesce(u.login_failed_timestamp, 0) < timestamp() - $window THEN 1
+          ELSE coalesce(u.login_failures, 0) + 1
+        END,
+        u.login_failed_timestamp = timestamp()
+    REMOVE u._lock
+    ` + createSecurityEvent + `
+    WITH u
+    ` + loginThrottleReturn
+
+	event.Type = models.SecurityEventLoginFailed
+	params := securityEventParams(userID, event)
+	params["window"] = window.Milliseconds()
+
+	record, err := runUserUpdate(ctx, driver, query, params, "record login failure", logger)
+	if err != nil {
+		return models.LoginThrottle{}, err
+	}
+
+	return decodeLoginThrottle(record, logger)
+}
+
+// LockAccount refuses logins until the given time, in milliseconds, and
+// starts the failure count over for when it expires. It returns the mail
+// telling the owner, which has no recipient when they have no email.
+func LockAccount(ctx context.Context, driver neo4j.DriverWithContext, userID string, until int64, event models.SecurityEvent, logger *zap.Logger) (models.Mail, error) {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u.login_locked_until = $until, u.login_failures = 0
+    ` + createSecurityEvent + `
+    WITH u
+    RETURN u.email AS email, u.language AS language
+    `
+
+	event.Type = models.SecurityEventLocked
+	params := securityEventParams(userID, event)
+	params["until"] = until
+
+	record, err := runUserUpdate(ctx, driver, query, params, "lock account", logger)
+	if err != nil {
+		return models.Mail{}, err
+	}
+
+	email, _ := record["email"].(string)
+	language, _ := record["language"].(string)
+	return lockedMail(email, language, until), nil
+}
+
+// lockedMail is the mail telling the owner of an account it was locked until
+// the given time.
+func lockedMail(email, language string, until int64) models.Mail {
+	return models.Mail{
+		To:       email,
+		Template: models.MailAccountLocked,
+		Language: language,
+		Data: map[string]string{
+			"Until": time.UnixMilli(until).Format("2 Jan 2006 15:04 MST"),
+			"Ref":   auth.GenerateRefNum(),
+		},
+	}
+}
+
+// UnlockAccount lifts a lock and clears the failure count, as done by an
+// admin, who is recorded as the event's actor.
+func UnlockAccount(ctx context.Context, driver neo4j.DriverWithContext, userID string, event models.SecurityEvent, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u.login_failures = 0
+    REMOVE u.login_locked_until, u.login_failed_timestamp
+    ` + createSecurityEvent + `
+    WITH u
+    RETURN u.user_id AS user_id
+    `
+
+	event.Type = models.SecurityEventUnlocked
+	_, err := runUserUpdate(ctx, driver, query, securityEventParams(userID, event), "unlock account", logger)
+	return err
+}
+
+// RecordSecurityEvent records an event that comes with no change to the
+// account, such as an admin reading fields its owner has hidden.
+func RecordSecurityEvent(ctx context.Context, driver neo4j.DriverWithContext, userID string, event models.SecurityEvent, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    ` + createSecurityEvent + `
+    WITH u
+    RETURN u.user_id AS user_id
+    `
+
+	_, err := runUserUpdate(ctx, driver, query, securityEventParams(userID, event), "record security event", logger)
+	return err
+}
+
+// ResetLoginFailures clears the failure count after a successful login.
+func ResetLoginFailures(ctx context.Context, driver neo4j.DriverWithContext, userID string, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u.login_failures = 0
+    RETURN u.user_id AS user_id
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+	}
+
+	_, err := runUserUpdate(ctx, driver, query, params, "reset login failures", logger)
+	return err
+}
+
+// GetSecurityEvents returns the latest security events of a user, newest
+// first.
+func GetSecurityEvents(ctx context.Context, driver neo4j.DriverWithContext, userID string, limit int, logger *zap.Logger) ([]map[string]interface{}, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})-[:HAS_SECURITY_EVENT]->(e:SecurityEvent)
+    RETURN
+      e.event_id AS event_id,
+      e.type AS type,
+      e.ip AS ip,
+      e.user_agent AS user_agent,
+      e.actor_id AS actor_id,
+      e.reason AS reason,
+      e.created_timestamp AS created_timestamp
+    ORDER BY e.created_timestamp DESC, e.event_id
+    LIMIT $limit
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+		"limit":   limit,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to retrieve security events", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve security events")
+	}
+
+	events := []map[string]interface{}{}
+	for result.Next(ctx) {
+		events = append(events, result.Record().AsMap())
+	}
+
+	return events, nil
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type attachmentRepository struct {
+	db *DB
+}
+
+// attachedTo returns the message the attachment was sent with.
+func (db *DB) attachedTo(attachmentID string) *messageNode {
+	for _, node := range db.messages {
+		if node.attachmentID == attachmentID {
+			return node
+		}
+	}
+	return nil
+}
+
+// linkAttachment mirrors the linkAttachment Cypher fragment: the upload is
+// attached only when the sender owns it and it is not attached elsewhere.
+func (db *DB) linkAttachment(node *messageNode, attachmentID string) {
+	attachment, ok := db.attachments[attachmentID]
+	if !ok || attachment.uploaderID != node.senderID || db.attachedTo(attachmentID) != nil {
+		return
+	}
+	node.attachmentID = attachmentID
+}
+
+// attachmentData mirrors attachmentProjection, nil when the message has none.
+func (db *DB) attachmentData(attachmentID string) interface{} {
+	attachment, ok := db.attachments[attachmentID]
+	if !ok {
+		return nil
+	}
+
+	return map[string]interface{}{
+		"attachment_id": attachment.id,
+		"file_name":     attachment.fileName,
+		"content_type":  attachment.contentType,
+		"size":          attachment.size,
+	}
+}
+
+func (r *attachmentRepository) CreateAttachment(ctx context.Context, attachment models.Attachment, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[attachment.UploaderID]; !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("User: %s not found", attachment.UploaderID))
+	}
+
+	node := &attachmentNode{
+		id:          uuid.New().String(),
+		uploaderID:  attachment.UploaderID,
+		fileName:    attachment.FileName.Raw,
+		contentType: attachment.ContentType,
+		size:        attachment.Size,
+		storageKey:  attachment.StorageKey,
+		created:     r.db.timestamp(),
+	}
+	r.db.attachments[node.id] = node
+
+	return map[string]interface{}{
+		"attachment_id":     node.id,
+		"file_name":         attachment.FileName.Value,
+		"content_type":      node.contentType,
+		"size":              node.size,
+		"created_timestamp": node.created,
+	}, nil
+}
+
+func (r *attachmentRepository) GetAttachment(ctx context.Context, attachmentID string, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	attachment, ok := r.db.attachments[attachmentID]
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Attachment: %s not found", attachmentID))
+	}
+	if _, ok := r.db.users[attachment.uploaderID]; !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Attachment: %s not found", attachmentID))
+	}
+
+	data := map[string]interface{}{
+		"attachment_id":     attachment.id,
+		"uploader_id":       attachment.uploaderID,
+		"file_name":         attachment.fileName,
+		"content_type":      attachment.contentType,
+		"size":              attachment.size,
+		"storage_key":       attachment.storageKey,
+		"created_timestamp": attachment.created,
+		"message_id":        nil,
+	}
+	if message := r.db.attachedTo(attachmentID); message != nil {
+		data["message_id"] = message.id
+	}
+
+	return data, nil
+}
+
+func (r *attachmentRepository) CanAccessAttachment(ctx context.Context, attachmentID, userID string, logger *zap.Logger) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	attachment, ok := r.db.attachments[attachmentID]
+	if !ok {
+		return false, nil
+	}
+	if _, ok := r.db.users[userID]; !ok {
+		return false, nil
+	}
+	if attachment.uploaderID == userID {
+		return true, nil
+	}
+
+	message := r.db.attachedTo(attachmentID)
+	if message == nil {
+		return false, nil
+	}
+	if message.conversationID == "" {
+		return message.senderID == userID || message.receiverID == userID, nil
+	}
+
+	conv, ok := r.db.conversations[message.conversationID]
+	return ok && conv.members[userID] != nil, nil
+}
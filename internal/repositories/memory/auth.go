@@ -0,0 +1,514 @@
+package memory
+
+import (
+	"alumni_api/internal/auth"
+	"alumni_api/internal/models"
+	"alumni_api/internal/utils"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// authRepository keeps the same token bookkeeping as the Neo4j version and
+// returns the same mails.
+type authRepository struct {
+	db *DB
+}
+
+func (db *DB) findUser(match func(props map[string]interface{}) bool) *userNode {
+	for _, user := range db.sortedUsers() {
+		if match(user.props) {
+			return user
+		}
+	}
+	return nil
+}
+
+func (r *authRepository) Login(ctx context.Context, username string, logger *zap.Logger) (models.LoginResponse, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	user := r.db.findUser(func(p map[string]interface{}) bool {
+		return (p["username"] == username || p["email"] == username) && p["is_verify"] == true
+	})
+	if user == nil {
+		logger.Warn("User not found", zap.String("username", username))
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusUnauthorized, "User not found")
+	}
+
+	record := map[string]interface{}{
+		"user_id":       user.props["user_id"],
+		"user_password": user.props["user_password"],
+		"role":          user.props["role"],
+		"admit_year":    user.props["admit_year"],
+		"language":      user.props["language"],
+		"mfa_enabled":   user.props["mfa_enabled"] == true,
+		"mfa_required":  user.props["mfa_required"] == true,
+	}
+
+	var res models.LoginResponse
+	if err := utils.MapToStruct(record, &res); err != nil {
+		logger.Error("Error decoding user properties", zap.Error(err))
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error decoding user properties")
+	}
+
+	return res, nil
+}
+
+func (r *authRepository) RegistryUser(ctx context.Context, user models.RegistryRequest, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
+	hashedPass, err := auth.HashPassword(user.Password)
+	if err != nil {
+		logger.Error("Failed to hash password", zap.Error(err))
+		return nil, models.Mail{}, fmt.Errorf("error hashing password: %w", err)
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if r.db.findUser(func(p map[string]interface{}) bool {
+		return p["username"] == user.Username && p["is_verify"] == true
+	}) != nil {
+		logger.Error("User already exist")
+		return nil, models.Mail{}, fiber.NewError(fiber.StatusInternalServerError, "User already exist")
+	}
+	if r.db.findUser(func(p map[string]interface{}) bool {
+		return p["email"] == user.Email && p["is_verify"] == true
+	}) != nil {
+		logger.Error("Email already used")
+		return nil, models.Mail{}, fiber.NewError(fiber.StatusInternalServerError, "Email already used")
+	}
+
+	username := user.Username
+	if username == "" {
+		username = user.Email
+	}
+
+	userID := uuid.New().String()
+	token := auth.GenerateVerificationToken()
+	jwtToken, err := auth.GenerateVerificationJWT(userID, token)
+	if err != nil {
+		logger.Error("Failed to create verify jwt", zap.Error(err))
+		return nil, models.Mail{}, fmt.Errorf("failed to create verify jwt: %w", err)
+	}
+
+	node := r.db.newUser()
+	node.props = map[string]interface{}{
+		"user_id":            userID,
+		"username":           username,
+		"user_password":      hashedPass,
+		"email":              user.Email,
+		"is_verify":          false,
+		"verification_token": token,
+		"role":               "user",
+		"created_timestamp":  r.db.timestamp(),
+	}
+	r.db.users[userID] = node
+
+	ref := auth.GenerateRefNum()
+	mail := models.Mail{
+		To:       user.Email,
+		Template: models.MailVerifyEmail,
+		Data:     map[string]string{"Token": jwtToken, "Ref": ref},
+	}
+
+	return map[string]interface{}{
+		"user_id":          userID,
+		"reference_number": ref,
+	}, mail, nil
+}
+
+func (r *authRepository) RegistryAlumnus(ctx context.Context, user models.RegistryOneTimeRequest, email string, logger *zap.Logger) (map[string]interface{}, error) {
+	if user.Username == "" {
+		user.Username = email
+	}
+
+	hashedPass, err := auth.HashPassword(user.Password)
+	if err != nil {
+		logger.Error("Failed to hash password", zap.Error(err))
+		return nil, fmt.Errorf("error hashing password: %w", err)
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if r.db.findUser(func(p map[string]interface{}) bool {
+		return p["username"] == user.Username && p["is_verify"] == true
+	}) != nil {
+		logger.Error("User already used")
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "User already exist")
+	}
+
+	node := r.db.findUser(func(p map[string]interface{}) bool {
+		return p["email"] == email
+	})
+	if node == nil {
+		logger.Error("Failed to collect query results", zap.String("email", email))
+		return nil, fiber.NewError(fiber.StatusInternalServerError, "Error retrieving data")
+	}
+
+	node.props["username"] = user.Username
+	node.props["user_password"] = hashedPass
+	node.props["is_verify"] = true
+	node.props["role"] = "alumnus"
+
+	return map[string]interface{}{
+		"user_id": node.props["user_id"].(string),
+	}, nil
+}
+
+func (r *authRepository) VerifyAccount(ctx context.Context, userID, token string, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		logger.Warn("User not found", zap.String("user_id", userID))
+		return nil, fmt.Errorf("user not found: %s", userID)
+	}
+
+	if verificationToken, ok := user.props["verification_token"].(string); !ok || verificationToken != token {
+		logger.Warn("Incorrect verification token", zap.String("user_id", userID))
+		return nil, fmt.Errorf("incorrect verification token")
+	}
+
+	delete(user.props, "verification_token")
+	user.props["is_verify"] = true
+
+	for id, dup := range r.db.users {
+		if id != userID && dup.props["username"] == user.props["username"] {
+			delete(r.db.users, id)
+		}
+	}
+
+	return map[string]interface{}{
+		"username": user.props["username"],
+		"role":     user.props["role"],
+	}, nil
+}
+
+func (r *authRepository) RequestChangePassword(ctx context.Context, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user := r.db.findUser(func(p map[string]interface{}) bool {
+		return p["email"] == email
+	})
+	if user == nil {
+		logger.Warn("User not found", zap.String("email", email))
+		return nil, models.Mail{}, fmt.Errorf("user not found: %s", email)
+	}
+
+	token := auth.GenerateVerificationToken()
+	jwtToken, err := auth.GenerateResetPasswordJWT(user.props["user_id"].(string), token)
+	if err != nil {
+		logger.Error("Failed to create verify jwt", zap.Error(err))
+		return nil, models.Mail{}, fmt.Errorf("failed to create verify jwt: %w", err)
+	}
+
+	user.props["reset_password_token"] = token
+
+	ref := auth.GenerateRefNum()
+	mail := models.Mail{
+		To:       email,
+		Template: models.MailResetPassword,
+		Data:     map[string]string{"Token": jwtToken, "Ref": ref},
+	}
+	mail.Language, _ = user.props["language"].(string)
+
+	return map[string]interface{}{
+		"reference_number": ref,
+	}, mail, nil
+}
+
+func (r *authRepository) ChangePassword(ctx context.Context, userID, password, token string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		logger.Warn("User not found", zap.String("user_id", userID))
+		return fmt.Errorf("user not found: %s", userID)
+	}
+
+	if resetToken, ok := user.props["reset_password_token"].(string); !ok || resetToken != token {
+		logger.Warn("Incorrect Reset token", zap.String("user_id", userID))
+		return fmt.Errorf("incorrect Reset token")
+	}
+
+	hashedPass, err := auth.HashPassword(password)
+	if err != nil {
+		logger.Error("Failed to hash password", zap.Error(err))
+		return fmt.Errorf("error hashing password: %w", err)
+	}
+
+	delete(user.props, "reset_password_token")
+	user.props["user_password"] = hashedPass
+
+	return nil
+}
+
+func (r *authRepository) RequestChangeMail(ctx context.Context, userID, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
+	token := auth.GenerateVerificationToken()
+	jwtToken, err := auth.GenerateVerifyEmailJWT(userID, email, token)
+	if err != nil {
+		logger.Error("Failed to create verify jwt", zap.Error(err))
+		return nil, models.Mail{}, fmt.Errorf("failed to create verify jwt: %w", err)
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if user, ok := r.db.users[userID]; ok {
+		user.props["change_email_token"] = token
+	}
+
+	ref := auth.GenerateRefNum()
+	mail := models.Mail{
+		To:       email,
+		Template: models.MailVerifyChangeEmail,
+		Data:     map[string]string{"Token": jwtToken, "Ref": ref},
+	}
+
+	return map[string]interface{}{
+		"reference_number": ref,
+	}, mail, nil
+}
+
+func (r *authRepository) VerifyEmail(ctx context.Context, userID, email, token string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		logger.Warn("User not found", zap.String("user_id", userID))
+		return fmt.Errorf("user not found: %s", userID)
+	}
+
+	if mailToken, ok := user.props["change_email_token"].(string); !ok || mailToken != token {
+		logger.Warn("Incorrect verification token", zap.String("user_id", userID))
+		return fmt.Errorf("incorrect verification token")
+	}
+
+	user.props["email"] = email
+	delete(user.props, "change_email_token")
+
+	return nil
+}
+
+func (r *authRepository) RequestAlumniOneTimeRegistry(ctx context.Context, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
+	r.db.mu.RLock()
+	user := r.db.findUser(func(p map[string]interface{}) bool {
+		return p["email"] == email && p["is_verify"] != true && p["role"] == "alumnus"
+	})
+	r.db.mu.RUnlock()
+
+	ref := auth.GenerateRefNum()
+	mail := models.Mail{
+		To:       email,
+		Template: models.MailOneTimeRegistryFail,
+		Data:     map[string]string{"Ref": ref},
+	}
+
+	if user != nil {
+		jwtToken, err := auth.GenerateOneTimeRegistryJWT(email)
+		if err != nil {
+			logger.Error("Failed to create verify jwt", zap.Error(err))
+			return nil, models.Mail{}, fmt.Errorf("failed to create verify jwt: %w", err)
+		}
+
+		mail.Template = models.MailOneTimeRegistry
+		mail.Data["Token"] = jwtToken
+	}
+
+	return map[string]interface{}{
+		"reference_number": ref,
+	}, mail, nil
+}
+
+func (r *authRepository) ApproveAlumnusRole(ctx context.Context, requestID, departmentID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	request, user := r.db.pendingRequest(requestID, departmentID)
+	if request == nil {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Request: %s not found", requestID))
+	}
+
+	user.props["role"] = "alumnus"
+	request.status = "approve"
+
+	return nil
+}
+
+func (r *authRepository) RejectAlumnusRole(ctx context.Context, requestID, departmentID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	request, _ := r.db.pendingRequest(requestID, departmentID)
+	if request == nil {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Request: %s not found", requestID))
+	}
+
+	request.status = "reject"
+	return nil
+}
+
+func (db *DB) pendingRequest(requestID, departmentID string) (*requestNode, *userNode) {
+	request, ok := db.requests[requestID]
+	if !ok || request.status != "pending" {
+		return nil, nil
+	}
+	user, ok := db.users[request.userID]
+	if !ok || !db.inDepartment(user, departmentID) {
+		return nil, nil
+	}
+	return request, user
+}
+
+func (r *authRepository) RequestAlumnusRole(ctx context.Context, userID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[userID]; !ok {
+		return nil
+	}
+
+	for _, request := range r.db.requests {
+		if request.userID == userID && request.reqType == "role_request" {
+			request.updated = r.db.timestamp()
+			return nil
+		}
+	}
+
+	request := &requestNode{
+		id:      uuid.New().String(),
+		userID:  userID,
+		reqType: "role_request",
+		status:  "pending",
+		created: r.db.timestamp(),
+	}
+	r.db.requests[request.id] = request
+
+	return nil
+}
+
+func (r *authRepository) GetAllRequest(ctx context.Context, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	requests := make([]*requestNode, 0, len(r.db.requests))
+	for _, request := range r.db.requests {
+		if user, ok := r.db.users[request.userID]; ok && r.db.inDepartment(user, departmentID) {
+			requests = append(requests, request)
+		}
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		return requests[i].created < requests[j].created
+	})
+
+	var ret []map[string]interface{}
+
+	for _, request := range requests {
+		user := r.db.users[request.userID]
+		p := user.props
+
+		companies := []interface{}{}
+		for _, companyID := range r.db.sortedWorks(user) {
+			company := r.db.companies[companyID]
+			companies = append(companies, map[string]interface{}{
+				"company":  company.name,
+				"address":  company.address,
+				"position": user.works[companyID].position,
+			})
+		}
+
+		var updated interface{}
+		if request.updated != 0 {
+			updated = request.updated
+		}
+
+		result := map[string]interface{}{
+			"user": map[string]interface{}{
+				"user_id":         p["user_id"],
+				"username":        p["username"],
+				"first_name":      p["first_name"],
+				"last_name":       p["last_name"],
+				"first_name_eng":  p["first_name_eng"],
+				"last_name_eng":   p["last_name_eng"],
+				"name":            concat(p, "first_name", "last_name"),
+				"name_eng":        concat(p, "first_name_eng", "last_name_eng"),
+				"profile_picture": p["profile_picture"],
+				"role":            p["role"],
+				"student_id":      p["student_id"],
+				"generation":      p["generation"],
+				"admit_year":      p["admit_year"],
+				"graduate_year":   p["graduate_year"],
+				"gpax":            p["gpax"],
+				"faculty":         collegeValue(user.college.Faculty),
+				"department":      collegeValue(user.college.Department),
+				"field":           collegeValue(user.college.Field),
+				"student_type":    collegeValue(user.college.StudentType),
+				"email":           p["email"],
+				"github":          p["github"],
+				"linkedin":        p["linkedin"],
+				"facebook":        p["facebook"],
+				"phone":           p["phone"],
+				"companies":       companies,
+			},
+			"request": map[string]interface{}{
+				"type":              request.reqType,
+				"status":            request.status,
+				"request_id":        request.id,
+				"created_timestamp": request.created,
+				"updated_timestamp": updated,
+			},
+		}
+
+		utils.CleanNullValues(result)
+		ret = append(ret, result)
+	}
+
+	return ret, nil
+}
+
+func (r *authRepository) UsernameVerify(ctx context.Context, username string, logger *zap.Logger) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	user := r.db.findUser(func(p map[string]interface{}) bool {
+		return p["username"] == username || p["email"] == username
+	})
+	if user == nil {
+		return false, nil
+	}
+
+	verified, _ := user.props["is_verify"].(bool)
+	return verified, nil
+}
+
+func (r *authRepository) EmailExist(ctx context.Context, email string, logger *zap.Logger) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	user := r.db.findUser(func(p map[string]interface{}) bool {
+		return p["email"] == email
+	})
+	return user != nil, nil
+}
+
+func (r *authRepository) IsRequestApproved(ctx context.Context, userID string, logger *zap.Logger) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	for _, request := range r.db.requests {
+		if request.userID == userID {
+			return request.status == "approve", nil
+		}
+	}
+
+	return false, nil
+}
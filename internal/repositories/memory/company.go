@@ -0,0 +1,182 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type companyRepository struct {
+	db *DB
+}
+
+func rawOrNil(raw []byte) interface{} {
+	if len(raw) == 0 {
+		return nil
+	}
+	return raw
+}
+
+func (r *companyRepository) CompanyFullTextSearch(ctx context.Context, queryTerm string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	term := strings.ToLower(queryTerm)
+	companies := []map[string]interface{}{}
+
+	for _, company := range r.db.companies {
+		name := strings.ToLower(company.name)
+		if !strings.Contains(name, term) {
+			continue
+		}
+		score := 1.0
+		if name == term {
+			score = 2.0
+		}
+		companies = append(companies, map[string]interface{}{
+			"name":  company.name,
+			"score": score,
+		})
+	}
+
+	sort.Slice(companies, func(i, j int) bool {
+		if companies[i]["score"] != companies[j]["score"] {
+			return companies[i]["score"].(float64) > companies[j]["score"].(float64)
+		}
+		return companies[i]["name"].(string) < companies[j]["name"].(string)
+	})
+	if len(companies) > 10 {
+		companies = companies[:10]
+	}
+
+	return companies, nil
+}
+
+func (r *companyRepository) AddUserCompany(ctx context.Context, id string, companies models.UserRequestCompany, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, userExists := r.db.users[id]
+
+	for _, company := range companies.Companies {
+		node := r.db.companyByName(company.Company)
+		if node == nil {
+			node = &companyNode{
+				id:      uuid.New().String(),
+				name:    company.Company,
+				address: company.Address,
+			}
+			r.db.companies[node.id] = node
+		}
+
+		if !userExists {
+			continue
+		}
+
+		work, ok := user.works[node.id]
+		if !ok {
+			work = &workEdge{}
+			user.works[node.id] = work
+		}
+		work.created = r.db.timestamp()
+		if raw := rawOrNil(company.Position.Raw); raw != nil {
+			work.position = raw
+			work.positionIndex = company.PositionIndex
+		}
+		if raw := rawOrNil(company.SalaryMax.Raw); raw != nil {
+			work.salaryMax = raw
+		}
+		if raw := rawOrNil(company.SalaryMin.Raw); raw != nil {
+			work.salaryMin = raw
+		}
+	}
+
+	if !userExists {
+		return nil, nil
+	}
+
+	var userCompanies []map[string]interface{}
+
+	for _, companyID := range r.db.sortedWorks(user) {
+		work := user.works[companyID]
+		userCompanies = append(userCompanies, map[string]interface{}{
+			"company":    r.db.companies[companyID].name,
+			"position":   work.position,
+			"salary_min": work.salaryMin,
+			"salary_max": work.salaryMax,
+		})
+	}
+
+	return userCompanies, nil
+}
+
+func (r *companyRepository) lookup(userID, companyID string, logger *zap.Logger) (*userNode, error) {
+	user, ok := r.db.users[userID]
+	if _, exists := r.db.companies[companyID]; !ok || !exists {
+		logger.Warn("User or Company not found", zap.String("userID", userID), zap.String("companyID", companyID))
+		return nil, fiber.NewError(fiber.StatusNotFound, "User or Company not found")
+	}
+	return user, nil
+}
+
+func (r *companyRepository) UpdateUserCompany(ctx context.Context, userID, companyID string, company models.UserCompanyUpdateRequest, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.lookup(userID, companyID, logger)
+	if err != nil {
+		return err
+	}
+
+	if work, ok := user.works[companyID]; ok {
+		work.position = rawOrNil(company.Position.Raw)
+		work.positionIndex = company.PositionIndex
+		work.updated = r.db.timestamp()
+	}
+
+	return nil
+}
+
+func (r *companyRepository) DeleteUserCompany(ctx context.Context, userID, companyID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.lookup(userID, companyID, logger)
+	if err != nil {
+		return err
+	}
+
+	delete(user.works, companyID)
+	return nil
+}
+
+func (r *companyRepository) FindCompanyAssociate(ctx context.Context, company models.Company, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	node := r.db.companyByName(company.Company)
+	if node == nil {
+		return nil, nil
+	}
+
+	var associate []map[string]interface{}
+
+	for _, user := range r.db.sortedUsers() {
+		if _, ok := user.works[node.id]; !ok {
+			continue
+		}
+		associate = append(associate, map[string]interface{}{
+			"user_id":        user.props["user_id"],
+			"fullname":       concat(user.props, "first_name", "last_name"),
+			"fullname_eng":   concat(user.props, "first_name_eng", "last_name_eng"),
+			"privacy_career": user.props["privacy_career"],
+		})
+	}
+
+	return associate, nil
+}
@@ -0,0 +1,369 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type conversationRepository struct {
+	db *DB
+}
+
+// liveMembers returns the IDs of members whose UserProfile still exists,
+// longest-standing first.
+func (db *DB) liveMembers(conv *conversationNode) []string {
+	ids := make([]string, 0, len(conv.members))
+	for id := range conv.members {
+		if _, ok := db.users[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		a, b := conv.members[ids[i]], conv.members[ids[j]]
+		if a.joined != b.joined {
+			return a.joined < b.joined
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+func (r *conversationRepository) CreateConversation(ctx context.Context, ownerID string, conv models.Conversation, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[ownerID]; !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("User: %s not found", ownerID))
+	}
+
+	node := &conversationNode{
+		id:      uuid.New().String(),
+		title:   conv.Title,
+		created: r.db.timestamp(),
+		members: map[string]*memberEdge{
+			ownerID: {role: models.ConversationOwner, joined: r.db.timestamp()},
+		},
+	}
+	if _, ok := r.db.posts[conv.PostID]; ok {
+		node.postID = conv.PostID
+	}
+	for _, userID := range conv.MemberIDs {
+		if _, ok := r.db.users[userID]; !ok {
+			continue
+		}
+		if _, ok := node.members[userID]; !ok {
+			node.members[userID] = &memberEdge{role: models.ConversationMember, joined: r.db.timestamp()}
+		}
+	}
+	r.db.conversations[node.id] = node
+
+	return map[string]interface{}{
+		"conversation_id": node.id,
+	}, nil
+}
+
+func (r *conversationRepository) GetConversationByID(ctx context.Context, conversationID string, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	conv, ok := r.db.conversations[conversationID]
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Conversation: %s not found", conversationID))
+	}
+
+	members := []interface{}{}
+	for _, userID := range r.db.liveMembers(conv) {
+		p := r.db.users[userID].props
+		members = append(members, map[string]interface{}{
+			"user_id":          p["user_id"],
+			"username":         p["username"],
+			"name":             concat(p, "first_name", "last_name"),
+			"picture":          p["profile_picture"],
+			"role":             conv.members[userID].role,
+			"joined_timestamp": conv.members[userID].joined,
+		})
+	}
+
+	data := map[string]interface{}{
+		"conversation_id":   conv.id,
+		"title":             conv.title,
+		"post_id":           nil,
+		"created_timestamp": conv.created,
+		"updated_timestamp": nil,
+		"members":           members,
+	}
+	if _, ok := r.db.posts[conv.postID]; ok {
+		data["post_id"] = conv.postID
+	}
+	if conv.updated != 0 {
+		data["updated_timestamp"] = conv.updated
+	}
+
+	return data, nil
+}
+
+func (r *conversationRepository) UpdateConversation(ctx context.Context, conversationID, title string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if conv, ok := r.db.conversations[conversationID]; ok {
+		conv.title = title
+		conv.updated = r.db.timestamp()
+	}
+
+	return nil
+}
+
+func (r *conversationRepository) GetMemberRole(ctx context.Context, conversationID, userID string, logger *zap.Logger) (string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	conv, ok := r.db.conversations[conversationID]
+	if !ok {
+		return "", nil
+	}
+	if _, ok := r.db.users[userID]; !ok {
+		return "", nil
+	}
+	if member, ok := conv.members[userID]; ok {
+		return member.role, nil
+	}
+
+	return "", nil
+}
+
+func (r *conversationRepository) GetMemberIDs(ctx context.Context, conversationID string, logger *zap.Logger) ([]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	conv, ok := r.db.conversations[conversationID]
+	if !ok {
+		return []string{}, nil
+	}
+
+	return r.db.liveMembers(conv), nil
+}
+
+func (r *conversationRepository) ShareConversation(ctx context.Context, userID1, userID2 string, logger *zap.Logger) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if r.db.users[userID1] == nil || r.db.users[userID2] == nil {
+		return false, nil
+	}
+
+	for _, message := range r.db.messages {
+		if message.conversationID == "" &&
+			(message.senderID == userID1 && message.receiverID == userID2 ||
+				message.senderID == userID2 && message.receiverID == userID1) {
+			return true, nil
+		}
+	}
+	for _, conv := range r.db.conversations {
+		if conv.members[userID1] != nil && conv.members[userID2] != nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (r *conversationRepository) AddMembers(ctx context.Context, conversationID string, userIDs []string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	conv, ok := r.db.conversations[conversationID]
+	if !ok {
+		return nil
+	}
+
+	for _, userID := range userIDs {
+		if _, ok := r.db.users[userID]; !ok {
+			continue
+		}
+		if _, ok := conv.members[userID]; !ok {
+			conv.members[userID] = &memberEdge{role: models.ConversationMember, joined: r.db.timestamp()}
+		}
+	}
+
+	return nil
+}
+
+func (r *conversationRepository) SetMemberRole(ctx context.Context, conversationID, userID, role string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if conv, ok := r.db.conversations[conversationID]; ok {
+		if member, ok := conv.members[userID]; ok {
+			member.role = role
+		}
+	}
+
+	return nil
+}
+
+func (r *conversationRepository) RemoveMember(ctx context.Context, conversationID, userID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	conv, ok := r.db.conversations[conversationID]
+	if !ok {
+		return nil
+	}
+	member, ok := conv.members[userID]
+	if !ok {
+		return nil
+	}
+	delete(conv.members, userID)
+
+	remaining := r.db.liveMembers(conv)
+	if len(remaining) == 0 {
+		for id, node := range r.db.messages {
+			if node.conversationID == conversationID {
+				delete(r.db.messages, id)
+			}
+		}
+		delete(r.db.conversations, conversationID)
+		return nil
+	}
+
+	if member.role == models.ConversationOwner {
+		sort.SliceStable(remaining, func(i, j int) bool {
+			return conv.members[remaining[i]].role == models.ConversationAdmin &&
+				conv.members[remaining[j]].role != models.ConversationAdmin
+		})
+		conv.members[remaining[0]].role = models.ConversationOwner
+	}
+
+	return nil
+}
+
+func (r *conversationRepository) GetEventConversationID(ctx context.Context, postID string, logger *zap.Logger) (string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	post, ok := r.db.posts[postID]
+	if !ok || post.props["post_type"] != "event" {
+		return "", nil
+	}
+
+	for _, conv := range r.db.conversations {
+		if conv.postID == postID {
+			return conv.id, nil
+		}
+	}
+
+	return "", nil
+}
+
+func (r *conversationRepository) SendGroupMessage(ctx context.Context, msg models.GroupMessage, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	messageData := map[string]interface{}{
+		"conversation_id": msg.ConversationID,
+		"content":         msg.Content.Value,
+		"sender_id":       msg.SenderID,
+	}
+
+	conv, ok := r.db.conversations[msg.ConversationID]
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Conversation: %s not found", msg.ConversationID))
+	}
+	sender, ok := r.db.users[msg.SenderID]
+	if !ok || conv.members[msg.SenderID] == nil {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Conversation: %s not found", msg.ConversationID))
+	}
+
+	var replied *messageNode
+	if msg.ReplyID != "" {
+		replied, ok = r.db.messages[msg.ReplyID]
+		if !ok || replied.conversationID != msg.ConversationID {
+			return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Reply message: %s not found", msg.ReplyID))
+		}
+		messageData["reply_message_id"] = replied.id
+		messageData["reply_content"] = replied.content
+	}
+
+	node := &messageNode{
+		id:             uuid.New().String(),
+		senderID:       msg.SenderID,
+		conversationID: msg.ConversationID,
+		replyID:        msg.ReplyID,
+		content:        msg.Content.Raw,
+		created:        r.db.timestamp(),
+	}
+	r.db.messages[node.id] = node
+
+	messageData["message_id"] = node.id
+	messageData["sender_username"] = sender.props["username"]
+	if fullname := concat(sender.props, "first_name", "last_name"); fullname != nil {
+		messageData["sender_fullname"] = fullname
+	}
+	if picture := sender.props["profile_picture"]; picture != nil {
+		messageData["sender_picture"] = picture
+	}
+	messageData["timestamp"] = node.created
+
+	r.db.linkAttachment(node, msg.AttachmentID)
+	if attachment := r.db.attachmentData(node.attachmentID); attachment != nil {
+		messageData["attachment"] = attachment
+	}
+
+	return messageData, nil
+}
+
+func (r *conversationRepository) GetGroupMessages(ctx context.Context, conversationID string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var posted []*messageNode
+	for _, node := range r.db.messages {
+		if node.conversationID != conversationID {
+			continue
+		}
+		if _, ok := r.db.users[node.senderID]; !ok {
+			continue
+		}
+		posted = append(posted, node)
+	}
+	posted, nextCursor := paginate(posted, page, messageCursor, newestFirst)
+
+	messages := []map[string]interface{}{}
+	for _, node := range posted {
+		sender := r.db.users[node.senderID].props
+
+		message := map[string]interface{}{
+			"message_id":            node.id,
+			"content":               node.content,
+			"created_timestamp":     node.created,
+			"update_timestamp":      nil,
+			"reply_message_id":      nil,
+			"reply_message_content": nil,
+			"attachment":            r.db.attachmentData(node.attachmentID),
+			"sender": map[string]interface{}{
+				"id":       sender["user_id"],
+				"username": sender["username"],
+				"name":     concat(sender, "first_name", "last_name"),
+				"picture":  sender["profile_picture"],
+			},
+		}
+		if node.updated != 0 {
+			message["update_timestamp"] = node.updated
+		}
+		if replied, ok := r.db.messages[node.replyID]; ok {
+			message["reply_message_id"] = replied.id
+			message["reply_message_content"] = replied.content
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nextCursor, nil
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type departmentRepository struct {
+	db *DB
+}
+
+// department mimics the MERGE of a Faculty and its Department, returning the
+// existing department when there is one.
+func (db *DB) department(faculty, name string) *departmentNode {
+	for _, department := range db.departments {
+		if department.faculty == faculty && department.name == name {
+			return department
+		}
+	}
+
+	department := &departmentNode{id: uuid.New().String(), faculty: faculty, name: name}
+	db.departments[department.id] = department
+	return department
+}
+
+// inDepartment reports whether user belongs to the department, or true for
+// the empty department of global scope.
+func (db *DB) inDepartment(user *userNode, departmentID string) bool {
+	if departmentID == "" {
+		return true
+	}
+
+	department, ok := db.departments[departmentID]
+	if !ok || user.college.Field == "" {
+		return false
+	}
+
+	return user.college.Faculty == department.faculty && user.college.Department == department.name
+}
+
+func (r *departmentRepository) AssignDepartmentIDs(ctx context.Context, logger *zap.Logger) error {
+	return nil
+}
+
+func (r *departmentRepository) GetDepartments(ctx context.Context, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	ret := []map[string]interface{}{}
+	for _, department := range r.db.departments {
+		admins := []interface{}{}
+		for _, user := range r.db.sortedUsers() {
+			if user.administers == department.id {
+				admins = append(admins, user.props["user_id"])
+			}
+		}
+
+		ret = append(ret, map[string]interface{}{
+			"department_id": department.id,
+			"faculty":       department.faculty,
+			"department":    department.name,
+			"admins":        admins,
+		})
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i]["faculty"] != ret[j]["faculty"] {
+			return ret[i]["faculty"].(string) < ret[j]["faculty"].(string)
+		}
+		return ret[i]["department"].(string) < ret[j]["department"].(string)
+	})
+	return ret, nil
+}
+
+func (r *departmentRepository) GetAdministeredDepartment(ctx context.Context, userID string, logger *zap.Logger) (string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if user, ok := r.db.users[userID]; ok {
+		return user.administers, nil
+	}
+	return "", nil
+}
+
+func (r *departmentRepository) AppointDepartmentAdmin(ctx context.Context, departmentID, userID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+	if _, ok := r.db.departments[departmentID]; !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Department: %s not found", departmentID))
+	}
+	if _, ok := r.db.roles[models.DepartmentAdminRole]; !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Role: %s not found", models.DepartmentAdminRole))
+	}
+
+	user.administers = departmentID
+	if _, ok := user.roles[models.DepartmentAdminRole]; !ok {
+		user.roles[models.DepartmentAdminRole] = r.db.timestamp()
+	}
+	return nil
+}
+
+func (r *departmentRepository) RemoveDepartmentAdmin(ctx context.Context, departmentID, userID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[userID]
+	if !ok || user.administers != departmentID {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("User: %s does not administer department %s", userID, departmentID))
+	}
+
+	user.administers = ""
+	delete(user.roles, models.DepartmentAdminRole)
+	return nil
+}
@@ -0,0 +1,134 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"bytes"
+	"context"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+type encryptedRepository struct {
+	db *DB
+}
+
+// encryptedSlot is where the memory store keeps one value of an
+// EncryptedProperty, along with its blind index if it has one.
+type encryptedSlot struct {
+	id  string
+	get func(property string) interface{}
+	set func(property string, value interface{})
+}
+
+func propsSlot(id string, props map[string]interface{}) encryptedSlot {
+	return encryptedSlot{
+		id:  id,
+		get: func(property string) interface{} { return props[property] },
+		set: func(property string, value interface{}) { props[property] = value },
+	}
+}
+
+func fieldsSlot(id string, fields map[string]*interface{}) encryptedSlot {
+	return encryptedSlot{
+		id: id,
+		get: func(property string) interface{} {
+			if field, ok := fields[property]; ok {
+				return *field
+			}
+			return nil
+		},
+		set: func(property string, value interface{}) {
+			if field, ok := fields[property]; ok {
+				*field = value
+			}
+		},
+	}
+}
+
+// encryptedSlots returns the slots that may hold prop, ordered by id. A work
+// edge is identified by its user_id and company_id.
+func (db *DB) encryptedSlots(prop models.EncryptedProperty) []encryptedSlot {
+	var slots []encryptedSlot
+
+	switch prop.Kind {
+	case "UserProfile":
+		for id, user := range db.users {
+			slots = append(slots, propsSlot(id, user.props))
+		}
+	case "HAS_WORK_WITH":
+		for userID, user := range db.users {
+			for companyID, work := range user.works {
+				slots = append(slots, fieldsSlot(userID+":"+companyID, map[string]*interface{}{
+					"position":       &work.position,
+					"position_index": &work.positionIndex,
+					"salary_min":     &work.salaryMin,
+					"salary_max":     &work.salaryMax,
+				}))
+			}
+		}
+	case "Message":
+		for id, message := range db.messages {
+			slots = append(slots, fieldsSlot(id, map[string]*interface{}{"content": &message.content}))
+		}
+	case "Attachment":
+		for id, attachment := range db.attachments {
+			slots = append(slots, fieldsSlot(id, map[string]*interface{}{"file_name": &attachment.fileName}))
+		}
+	}
+
+	sort.Slice(slots, func(i, j int) bool { return slots[i].id < slots[j].id })
+	return slots
+}
+
+func (r *encryptedRepository) GetEncryptedValues(ctx context.Context, prop models.EncryptedProperty, after string, limit int, logger *zap.Logger) ([]models.EncryptedValue, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	values := []models.EncryptedValue{}
+	for _, slot := range r.db.encryptedSlots(prop) {
+		if len(values) == limit {
+			break
+		}
+		value := slot.get(prop.Property)
+		if slot.id <= after || value == nil {
+			continue
+		}
+
+		raw, _ := value.([]byte)
+		var index string
+		if prop.Index.Name != "" {
+			index, _ = slot.get(prop.Index.Property()).(string)
+		}
+		values = append(values, models.EncryptedValue{ID: slot.id, Raw: raw, Index: index})
+	}
+
+	return values, nil
+}
+
+func (r *encryptedRepository) ReplaceEncryptedValues(ctx context.Context, prop models.EncryptedProperty, changes []models.EncryptedChange, logger *zap.Logger) (int, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	slots := make(map[string]encryptedSlot)
+	for _, slot := range r.db.encryptedSlots(prop) {
+		slots[slot.id] = slot
+	}
+
+	replaced := 0
+	for _, change := range changes {
+		slot, ok := slots[change.ID]
+		if !ok {
+			continue
+		}
+		if raw, ok := slot.get(prop.Property).([]byte); ok && bytes.Equal(raw, change.Old) {
+			slot.set(prop.Property, change.New)
+			if prop.Index.Name != "" {
+				slot.set(prop.Index.Property(), change.Index)
+			}
+			replaced++
+		}
+	}
+
+	return replaced, nil
+}
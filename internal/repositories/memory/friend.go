@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"alumni_api/internal/utils"
+	"context"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type friendRepository struct {
+	db *DB
+}
+
+func (db *DB) sortedFriends(user *userNode) []string {
+	ids := make([]string, 0, len(user.friends))
+	for id := range user.friends {
+		if _, ok := db.users[id]; ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return user.friends[ids[i]] < user.friends[ids[j]]
+	})
+	return ids
+}
+
+func (r *friendRepository) GetUserFriendByID(ctx context.Context, id string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	user, ok := r.db.users[id]
+	if !ok {
+		return nil, nil
+	}
+
+	var friends []map[string]interface{}
+
+	for _, friendID := range r.db.sortedFriends(user) {
+		p := r.db.users[friendID].props
+		friendMap := map[string]interface{}{
+			"user_id":         p["user_id"],
+			"username":        p["username"],
+			"first_name":      p["first_name"],
+			"last_name":       p["last_name"],
+			"first_name_eng":  p["first_name_eng"],
+			"last_name_eng":   p["last_name_eng"],
+			"profile_picture": p["profile_picture"],
+		}
+		for key, value := range friendMap {
+			if utils.IsEmpty(value) {
+				delete(friendMap, key)
+			}
+		}
+		friends = append(friends, friendMap)
+	}
+
+	return friends, nil
+}
+
+func (r *friendRepository) AddFriend(ctx context.Context, userID1, userID2 string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	u1, ok1 := r.db.users[userID1]
+	u2, ok2 := r.db.users[userID2]
+	if !ok1 || !ok2 {
+		logger.Error("Failed to retrieve result", zap.String("userID1", userID1), zap.String("userID2", userID2))
+		return fiber.NewError(fiber.StatusInternalServerError, "Failed to retrieve result after creating relationship")
+	}
+
+	if _, ok := u1.friends[userID2]; !ok {
+		u1.friends[userID2] = r.db.timestamp()
+	}
+	if _, ok := u2.friends[userID1]; !ok {
+		u2.friends[userID1] = r.db.timestamp()
+	}
+
+	return nil
+}
+
+func (r *friendRepository) Unfriend(ctx context.Context, userID1, userID2 string, logger *zap.Logger) error {
+	if userID1 == userID2 {
+		return fiber.NewError(fiber.StatusBadRequest, "Cannot unfriend oneself")
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	u1, ok1 := r.db.users[userID1]
+	u2, ok2 := r.db.users[userID2]
+	if !ok1 || !ok2 {
+		return nil
+	}
+	if _, ok := u1.friends[userID2]; !ok {
+		return nil
+	}
+
+	delete(u1.friends, userID2)
+	delete(u2.friends, userID1)
+	return nil
+}
+
+// friendPaths enumerates the simple FRIEND paths from one user to another of
+// at most maxHops hops, returning the intermediate user IDs of each.
+func (db *DB) friendPaths(from, to string, maxHops int) [][]string {
+	var paths [][]string
+	visited := map[string]bool{from: true}
+	var walk func(current string, trail []string)
+	walk = func(current string, trail []string) {
+		if len(trail) >= maxHops {
+			return
+		}
+		for _, next := range db.sortedFriends(db.users[current]) {
+			if next == to {
+				paths = append(paths, append([]string(nil), trail...))
+				continue
+			}
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			walk(next, append(trail, next))
+			visited[next] = false
+		}
+	}
+	walk(from, nil)
+	return paths
+}
+
+func (r *friendRepository) GetFOAF(ctx context.Context, userID, otherID string, degree int, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	if _, ok := r.db.users[userID]; !ok {
+		return nil, nil
+	}
+	if _, ok := r.db.users[otherID]; !ok {
+		return nil, nil
+	}
+
+	var paths [][]string
+	for _, path := range r.db.friendPaths(userID, otherID, degree) {
+		if len(path) > 0 {
+			paths = append(paths, path)
+		}
+	}
+	sort.SliceStable(paths, func(i, j int) bool {
+		return len(paths[i]) < len(paths[j])
+	})
+	if len(paths) > 50 {
+		paths = paths[:50]
+	}
+
+	var foaf []map[string]interface{}
+
+	for _, path := range paths {
+		for idx, id := range path {
+			p := r.db.users[id].props
+			foaf = append(foaf, map[string]interface{}{
+				"user_id": p["user_id"],
+				"contact": map[string]interface{}{
+					"email":    p["email"],
+					"linkedin": p["linkedin"],
+					"phone":    p["phone"],
+					"facebook": p["facebook"],
+				},
+				"profile_picture": p["profile_picture"],
+				"fullname":        concat(p, "first_name", "last_name"),
+				"fullname_eng":    concat(p, "first_name_eng", "last_name_eng"),
+				"privacy_contact": p["privacy_contact"],
+				"depth":           int64(idx + 1),
+			})
+		}
+	}
+
+	return foaf, nil
+}
@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/utils"
+	"context"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type identityRepository struct {
+	db *DB
+}
+
+func (r *identityRepository) SignInWithIdentity(ctx context.Context, identity models.Identity, logger *zap.Logger) (models.LoginResponse, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	key := identity.Provider + "\x00" + identity.Subject
+	now := r.db.timestamp()
+
+	var user *userNode
+	if node, ok := r.db.identities[key]; ok {
+		node.email, node.emailVerified, node.lastLogin = identity.Email, identity.EmailVerified, now
+		user = r.db.users[node.userID]
+	}
+
+	if user == nil && identity.EmailVerified && identity.Email != "" {
+		user = r.db.findUser(func(p map[string]interface{}) bool {
+			return p["email"] == identity.Email && p["is_verify"] == true
+		})
+	}
+
+	if user == nil {
+		user = r.db.newUser()
+		user.props = map[string]interface{}{
+			"user_id":           uuid.New().String(),
+			"username":          fmt.Sprintf("%s:%s", identity.Provider, identity.Subject),
+			"is_verify":         true,
+			"role":              "user",
+			"created_timestamp": now,
+		}
+		if identity.EmailVerified {
+			user.props["username"] = identity.Email
+			user.props["email"] = identity.Email
+		}
+		r.db.users[user.props["user_id"].(string)] = user
+	}
+
+	if _, ok := r.db.identities[key]; !ok {
+		r.db.identities[key] = &identityNode{
+			provider:      identity.Provider,
+			subject:       identity.Subject,
+			userID:        user.props["user_id"].(string),
+			email:         identity.Email,
+			emailVerified: identity.EmailVerified,
+			created:       now,
+			lastLogin:     now,
+		}
+	}
+
+	record := map[string]interface{}{
+		"user_id":      user.props["user_id"],
+		"role":         user.props["role"],
+		"admit_year":   user.props["admit_year"],
+		"language":     user.props["language"],
+		"mfa_enabled":  user.props["mfa_enabled"] == true,
+		"mfa_required": user.props["mfa_required"] == true,
+	}
+
+	var res models.LoginResponse
+	if err := utils.MapToStruct(record, &res); err != nil {
+		logger.Error("Error decoding user properties", zap.Error(err))
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error decoding user properties")
+	}
+
+	return res, nil
+}
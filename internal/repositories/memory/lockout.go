@@ -0,0 +1,169 @@
+package memory
+
+import (
+	"alumni_api/internal/auth"
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// lockoutRepository keeps the failed-login state as UserProfile properties,
+// under the same names the Neo4j queries use.
+type lockoutRepository struct {
+	db *DB
+}
+
+func (r *lockoutRepository) user(userID string) (*userNode, error) {
+	user, ok := r.db.users[userID]
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+	return user, nil
+}
+
+func (r *lockoutRepository) record(user *userNode, eventType string, event models.SecurityEvent) {
+	event.EventID = uuid.New().String()
+	event.Type = eventType
+	event.CreatedTimestamp = r.db.timestamp()
+	user.events = append(user.events, event)
+}
+
+func throttleOf(user *userNode) models.LoginThrottle {
+	failures, _ := user.props["login_failures"].(int64)
+	failed, _ := user.props["login_failed_timestamp"].(int64)
+	locked, _ := user.props["login_locked_until"].(int64)
+	return models.LoginThrottle{Failures: failures, FailedTimestamp: failed, LockedUntil: locked}
+}
+
+func (r *lockoutRepository) GetLoginThrottle(ctx context.Context, userID string, logger *zap.Logger) (models.LoginThrottle, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return models.LoginThrottle{}, err
+	}
+
+	return throttleOf(user), nil
+}
+
+func (r *lockoutRepository) RecordLoginFailure(ctx context.Context, userID string, window time.Duration, event models.SecurityEvent, logger *zap.Logger) (models.LoginThrottle, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return models.LoginThrottle{}, err
+	}
+
+	now := time.Now().UnixMilli()
+	throttle := throttleOf(user)
+	if throttle.FailedTimestamp < now-window.Milliseconds() {
+		throttle.Failures = 0
+	}
+
+	user.props["login_failures"] = throttle.Failures + 1
+	user.props["login_failed_timestamp"] = now
+	r.record(user, models.SecurityEventLoginFailed, event)
+
+	return throttleOf(user), nil
+}
+
+func (r *lockoutRepository) LockAccount(ctx context.Context, userID string, until int64, event models.SecurityEvent, logger *zap.Logger) (models.Mail, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return models.Mail{}, err
+	}
+
+	user.props["login_locked_until"] = until
+	user.props["login_failures"] = int64(0)
+	r.record(user, models.SecurityEventLocked, event)
+
+	email, _ := user.props["email"].(string)
+	language, _ := user.props["language"].(string)
+	return models.Mail{
+		To:       email,
+		Template: models.MailAccountLocked,
+		Language: language,
+		Data: map[string]string{
+			"Until": time.UnixMilli(until).Format("2 Jan 2006 15:04 MST"),
+			"Ref":   auth.GenerateRefNum(),
+		},
+	}, nil
+}
+
+func (r *lockoutRepository) UnlockAccount(ctx context.Context, userID string, event models.SecurityEvent, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+
+	user.props["login_failures"] = int64(0)
+	delete(user.props, "login_locked_until")
+	delete(user.props, "login_failed_timestamp")
+	r.record(user, models.SecurityEventUnlocked, event)
+	return nil
+}
+
+func (r *lockoutRepository) RecordSecurityEvent(ctx context.Context, userID string, event models.SecurityEvent, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+
+	r.record(user, event.Type, event)
+	return nil
+}
+
+func (r *lockoutRepository) ResetLoginFailures(ctx context.Context, userID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+
+	user.props["login_failures"] = int64(0)
+	return nil
+}
+
+func (r *lockoutRepository) GetSecurityEvents(ctx context.Context, userID string, limit int, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	events := []map[string]interface{}{}
+	user, ok := r.db.users[userID]
+	if !ok {
+		return events, nil
+	}
+
+	for i := len(user.events) - 1; i >= 0 && len(events) < limit; i-- {
+		event := user.events[i]
+		events = append(events, map[string]interface{}{
+			"event_id":          event.EventID,
+			"type":              event.Type,
+			"ip":                event.IP,
+			"user_agent":        event.UserAgent,
+			"actor_id":          event.ActorID,
+			"reason":            event.Reason,
+			"created_timestamp": event.CreatedTimestamp,
+		})
+	}
+
+	return events, nil
+}
@@ -0,0 +1,326 @@
+// Package memory is an in-memory implementation of the repositories.Store
+// interfaces. It mirrors the graph model used by the Neo4j queries closely
+// enough that controllers can be exercised end to end with app.Test().
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type userNode struct {
+	props   map[string]interface{}
+	college models.CollegeInfo
+	works   map[string]*workEdge
+	friends map[string]int64
+	events  []models.SecurityEvent
+	roles   map[string]int64
+
+	// administers is the department_id of the Department the user
+	// ADMINISTERS, if any.
+	administers string
+}
+
+type workEdge struct {
+	position      interface{}
+	positionIndex interface{}
+	salaryMin     interface{}
+	salaryMax     interface{}
+	created       int64
+	updated       int64
+}
+
+type companyNode struct {
+	id      string
+	name    string
+	address string
+}
+
+type postNode struct {
+	props    map[string]interface{}
+	authorID string
+	likes    map[string]bool
+	views    map[string]bool
+}
+
+type commentNode struct {
+	id       string
+	comment  string
+	authorID string
+	postID   string
+	parentID string
+	created  int64
+	updated  int64
+	likes    map[string]bool
+}
+
+// messageNode is either a direct message, with a receiverID, or a group
+// message posted in conversationID.
+type messageNode struct {
+	id             string
+	senderID       string
+	receiverID     string
+	conversationID string
+	replyID        string
+	attachmentID   string
+	content        interface{}
+	created        int64
+	updated        int64
+	readAt         int64
+}
+
+type attachmentNode struct {
+	id          string
+	uploaderID  string
+	fileName    interface{}
+	contentType string
+	size        int64
+	storageKey  string
+	created     int64
+}
+
+type sessionNode struct {
+	id             string
+	userID         string
+	refreshHash    string
+	previousHashes []string
+	userAgent      string
+	ip             string
+	created        int64
+	lastUsed       int64
+	expires        int64
+	revoked        int64
+	revokedReason  string
+}
+
+type identityNode struct {
+	provider      string
+	subject       string
+	userID        string
+	email         string
+	emailVerified bool
+	created       int64
+	lastLogin     int64
+}
+
+type roleNode struct {
+	name        string
+	permissions []string
+	builtIn     bool
+}
+
+type departmentNode struct {
+	id      string
+	faculty string
+	name    string
+}
+
+type memberEdge struct {
+	role   string
+	joined int64
+}
+
+type conversationNode struct {
+	id      string
+	title   string
+	postID  string
+	created int64
+	updated int64
+	members map[string]*memberEdge
+}
+
+type requestNode struct {
+	id      string
+	userID  string
+	reqType string
+	status  string
+	created int64
+	updated int64
+}
+
+type reportNode struct {
+	id         string
+	reporterID string
+	targetID   string
+	reportType string
+	status     string
+	category   string
+	additional string
+	created    int64
+}
+
+// DB holds the nodes and edges shared by every repository of a Store.
+type DB struct {
+	mu            sync.RWMutex
+	lastTime      int64
+	users         map[string]*userNode
+	companies     map[string]*companyNode
+	posts         map[string]*postNode
+	comments      map[string]*commentNode
+	messages      map[string]*messageNode
+	conversations map[string]*conversationNode
+	attachments   map[string]*attachmentNode
+	sessions      map[string]*sessionNode
+	identities    map[string]*identityNode
+	roles         map[string]*roleNode
+	departments   map[string]*departmentNode
+	requests      map[string]*requestNode
+	reports       []*reportNode
+	mails         map[string]*models.Mail
+}
+
+// New returns an empty in-memory database.
+func New() *DB {
+	return &DB{
+		users:         make(map[string]*userNode),
+		companies:     make(map[string]*companyNode),
+		posts:         make(map[string]*postNode),
+		comments:      make(map[string]*commentNode),
+		messages:      make(map[string]*messageNode),
+		conversations: make(map[string]*conversationNode),
+		attachments:   make(map[string]*attachmentNode),
+		sessions:      make(map[string]*sessionNode),
+		identities:    make(map[string]*identityNode),
+		roles:         make(map[string]*roleNode),
+		departments:   make(map[string]*departmentNode),
+		requests:      make(map[string]*requestNode),
+		mails:         make(map[string]*models.Mail),
+	}
+}
+
+// NewStore returns a Store backed by a fresh in-memory database.
+func NewStore() *repositories.Store {
+	return New().Store()
+}
+
+// Store returns the repositories backed by db.
+func (db *DB) Store() *repositories.Store {
+	return &repositories.Store{
+		User:         &userRepository{db: db},
+		Post:         &postRepository{db: db},
+		Message:      &messageRepository{db: db},
+		Conversation: &conversationRepository{db: db},
+		Attachment:   &attachmentRepository{db: db},
+		Friend:       &friendRepository{db: db},
+		Company:      &companyRepository{db: db},
+		Auth:         &authRepository{db: db},
+		Session:      &sessionRepository{db: db},
+		MFA:          &mfaRepository{db: db},
+		Identity:     &identityRepository{db: db},
+		Lockout:      &lockoutRepository{db: db},
+		Role:         &roleRepository{db: db},
+		Department:   &departmentRepository{db: db},
+		Statistic:    &statisticRepository{db: db},
+		Report:       &reportRepository{db: db},
+		Outbox:       &outboxRepository{db: db},
+		Encrypted:    &encryptedRepository{db: db},
+	}
+}
+
+// PutUser inserts a UserProfile with the given properties, the equivalent of
+// a bare CREATE in Cypher, and returns its user_id. A user_id is generated
+// when props does not carry one.
+func (db *DB) PutUser(props map[string]interface{}) string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	node := db.newUser()
+	for key, value := range props {
+		node.props[key] = value
+	}
+
+	if _, ok := node.props["created_timestamp"]; !ok {
+		node.props["created_timestamp"] = db.timestamp()
+	}
+
+	userID, ok := node.props["user_id"].(string)
+	if !ok || userID == "" {
+		userID = uuid.New().String()
+		node.props["user_id"] = userID
+	}
+
+	db.users[userID] = node
+	return userID
+}
+
+func (db *DB) newUser() *userNode {
+	return &userNode{
+		props:   make(map[string]interface{}),
+		works:   make(map[string]*workEdge),
+		friends: make(map[string]int64),
+		roles:   make(map[string]int64),
+	}
+}
+
+// timestamp mimics Cypher's timestamp() but never returns the same value
+// twice, so ordering by creation time stays deterministic.
+func (db *DB) timestamp() int64 {
+	now := time.Now().UnixMilli()
+	if now <= db.lastTime {
+		now = db.lastTime + 1
+	}
+	db.lastTime = now
+	return now
+}
+
+// concat mimics Cypher string concatenation, where any null operand yields null.
+func concat(props map[string]interface{}, first, last string) interface{} {
+	a, ok1 := props[first].(string)
+	b, ok2 := props[last].(string)
+	if !ok1 || !ok2 {
+		return nil
+	}
+	return a + " " + b
+}
+
+func copyProps(props map[string]interface{}) map[string]interface{} {
+	ret := make(map[string]interface{}, len(props))
+	for key, value := range props {
+		ret[key] = value
+	}
+	return ret
+}
+
+func (db *DB) companyByName(name string) *companyNode {
+	for _, company := range db.companies {
+		if company.name == name {
+			return company
+		}
+	}
+	return nil
+}
+
+// newestFirst and oldestFirst order rows by (created, id), matching the
+// ORDER BY of the paginated Cypher queries.
+func newestFirst(a, b models.Cursor) bool {
+	if a.Timestamp != b.Timestamp {
+		return a.Timestamp > b.Timestamp
+	}
+	return a.ID > b.ID
+}
+
+func oldestFirst(a, b models.Cursor) bool {
+	return newestFirst(b, a)
+}
+
+// paginate sorts rows, drops everything up to and including the cursor and
+// cuts the rest down to one page.
+func paginate[T any](rows []T, page models.Page, cursorOf func(T) models.Cursor, less func(a, b models.Cursor) bool) ([]T, string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		return less(cursorOf(rows[i]), cursorOf(rows[j]))
+	})
+
+	var kept []T
+	for _, row := range rows {
+		if page.After == nil || less(*page.After, cursorOf(row)) {
+			kept = append(kept, row)
+		}
+	}
+
+	return models.NextPage(kept, page, cursorOf)
+}
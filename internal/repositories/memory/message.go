@@ -0,0 +1,327 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type messageRepository struct {
+	db *DB
+}
+
+func (r *messageRepository) create(senderID, receiverID, replyID string, content []byte) (*messageNode, *userNode) {
+	sender, ok := r.db.users[senderID]
+	if !ok {
+		return nil, nil
+	}
+	if _, ok := r.db.users[receiverID]; !ok {
+		return nil, nil
+	}
+
+	node := &messageNode{
+		id:         uuid.New().String(),
+		senderID:   senderID,
+		receiverID: receiverID,
+		replyID:    replyID,
+		content:    content,
+		created:    r.db.timestamp(),
+	}
+	r.db.messages[node.id] = node
+
+	return node, sender
+}
+
+func (r *messageRepository) SendMessage(ctx context.Context, msg models.Message, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	node, sender := r.create(msg.SenderID, msg.ReceiverID, "", msg.Content.Raw)
+
+	messageData := map[string]interface{}{
+		"content":   msg.Content.Value,
+		"sender_id": msg.SenderID,
+	}
+
+	if node == nil {
+		messageData["message_id"] = uuid.New().String()
+		return messageData, nil
+	}
+
+	messageData["message_id"] = node.id
+	messageData["sender_username"] = sender.props["username"]
+	if fullname := concat(sender.props, "first_name", "last_name"); fullname != nil {
+		messageData["sender_fullname"] = fullname
+	}
+	if picture := sender.props["profile_picture"]; picture != nil {
+		messageData["sender_picture"] = picture
+	}
+	messageData["timestamp"] = node.created
+
+	r.db.linkAttachment(node, msg.AttachmentID)
+	if attachment := r.db.attachmentData(node.attachmentID); attachment != nil {
+		messageData["attachment"] = attachment
+	}
+
+	return messageData, nil
+}
+
+func (r *messageRepository) ReplyMessage(ctx context.Context, msg models.ReplyMessage, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	messageData := map[string]interface{}{
+		"reply_message_id": msg.ReplyID,
+		"content":          msg.Content.Value,
+		"sender_id":        msg.SenderID,
+	}
+
+	replied, ok := r.db.messages[msg.ReplyID]
+	if !ok {
+		messageData["message_id"] = uuid.New().String()
+		return messageData, nil
+	}
+
+	node, sender := r.create(msg.SenderID, msg.ReceiverID, msg.ReplyID, msg.Content.Raw)
+	if node == nil {
+		messageData["message_id"] = uuid.New().String()
+		return messageData, nil
+	}
+
+	messageData["message_id"] = node.id
+	messageData["sender_username"] = sender.props["username"]
+	messageData["reply_content"] = replied.content
+	if fullname := concat(sender.props, "first_name", "last_name"); fullname != nil {
+		messageData["sender_fullname"] = fullname
+	}
+	if picture := sender.props["profile_picture"]; picture != nil {
+		messageData["sender_picture"] = picture
+	}
+	messageData["timestamp"] = node.created
+
+	r.db.linkAttachment(node, msg.AttachmentID)
+	if attachment := r.db.attachmentData(node.attachmentID); attachment != nil {
+		messageData["attachment"] = attachment
+	}
+
+	return messageData, nil
+}
+
+func (r *messageRepository) EditMessage(ctx context.Context, msg models.EditMessage, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if node, ok := r.db.messages[msg.MessageID]; ok {
+		node.content = msg.Content.Raw
+		node.updated = r.db.timestamp()
+	}
+
+	return nil
+}
+
+func (r *messageRepository) DeleteMessage(ctx context.Context, msg models.DeleteMessage, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.messages, msg.MessageID)
+	for _, node := range r.db.messages {
+		if node.replyID == msg.MessageID {
+			node.replyID = ""
+		}
+	}
+
+	return nil
+}
+
+func messageCursor(message *messageNode) models.Cursor {
+	return models.Cursor{Timestamp: message.created, ID: message.id}
+}
+
+func (r *messageRepository) GetMessage(ctx context.Context, senderID, receiverID string, page models.Page, logger *zap.Logger) (map[string]interface{}, string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var conversation []*messageNode
+	for _, node := range r.db.messages {
+		if (node.senderID == senderID && node.receiverID == receiverID) ||
+			(node.senderID == receiverID && node.receiverID == senderID) {
+			if _, ok := r.db.users[node.senderID]; !ok {
+				continue
+			}
+			if _, ok := r.db.users[node.receiverID]; !ok {
+				continue
+			}
+			conversation = append(conversation, node)
+		}
+	}
+	conversation, nextCursor := paginate(conversation, page, messageCursor, newestFirst)
+
+	me := []interface{}{}
+	other := []interface{}{}
+
+	for _, node := range conversation {
+		sender := r.db.users[node.senderID]
+
+		message := map[string]interface{}{
+			"message_id":            node.id,
+			"content":               node.content,
+			"created_timestamp":     node.created,
+			"update_timestamp":      nil,
+			"read_at":               nil,
+			"reply_message_id":      nil,
+			"reply_message_content": nil,
+			"attachment":            r.db.attachmentData(node.attachmentID),
+		}
+		if node.updated != 0 {
+			message["update_timestamp"] = node.updated
+		}
+		if node.readAt != 0 {
+			message["read_at"] = node.readAt
+		}
+		if replied, ok := r.db.messages[node.replyID]; ok {
+			message["reply_message_id"] = replied.id
+			message["reply_message_content"] = replied.content
+		}
+
+		entry := map[string]interface{}{
+			"id":       sender.props["user_id"],
+			"username": sender.props["username"],
+			"name":     concat(sender.props, "first_name", "last_name"),
+			"picture":  sender.props["profile_picture"],
+			"message":  message,
+		}
+
+		if node.senderID == senderID {
+			me = append(me, entry)
+		} else {
+			other = append(other, entry)
+		}
+	}
+
+	return map[string]interface{}{
+		"me":    me,
+		"other": other,
+	}, nextCursor, nil
+}
+
+func (r *messageRepository) MarkRead(ctx context.Context, readerID, messageID string, logger *zap.Logger) (models.ReadReceipt, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	target, ok := r.db.messages[messageID]
+	if !ok || target.receiverID != readerID {
+		return models.ReadReceipt{}, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Message: %s not found", messageID))
+	}
+	if _, ok := r.db.users[target.senderID]; !ok {
+		return models.ReadReceipt{}, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Message: %s not found", messageID))
+	}
+
+	var unread []*messageNode
+	for _, node := range r.db.messages {
+		if node.senderID == target.senderID && node.receiverID == readerID &&
+			node.readAt == 0 && node.created <= target.created {
+			unread = append(unread, node)
+		}
+	}
+	sort.Slice(unread, func(i, j int) bool {
+		return unread[i].created < unread[j].created
+	})
+
+	receipt := models.ReadReceipt{
+		MessageID:  messageID,
+		ReaderID:   readerID,
+		SenderID:   target.senderID,
+		ReadAt:     r.db.timestamp(),
+		MessageIDs: []string{},
+	}
+	for _, node := range unread {
+		node.readAt = receipt.ReadAt
+		receipt.MessageIDs = append(receipt.MessageIDs, node.id)
+	}
+
+	return receipt, nil
+}
+
+type inboxEntry struct {
+	other  *userNode
+	last   *messageNode
+	unread int64
+}
+
+func inboxCursor(c *inboxEntry) models.Cursor {
+	return models.Cursor{Timestamp: c.last.created, ID: c.other.props["user_id"].(string)}
+}
+
+func (r *messageRepository) GetConversations(ctx context.Context, userID string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	byUser := make(map[string]*inboxEntry)
+	for _, node := range r.db.messages {
+		otherID := node.receiverID
+		if node.receiverID == userID {
+			otherID = node.senderID
+		} else if node.senderID != userID {
+			continue
+		}
+		if otherID == userID {
+			continue
+		}
+
+		other, ok := r.db.users[otherID]
+		if !ok {
+			continue
+		}
+		if _, ok := r.db.users[userID]; !ok {
+			continue
+		}
+
+		c, ok := byUser[otherID]
+		if !ok {
+			c = &inboxEntry{other: other, last: node}
+			byUser[otherID] = c
+		}
+		if newestFirst(messageCursor(node), messageCursor(c.last)) {
+			c.last = node
+		}
+		if node.receiverID == userID && node.readAt == 0 {
+			c.unread++
+		}
+	}
+
+	rows := make([]*inboxEntry, 0, len(byUser))
+	for _, c := range byUser {
+		rows = append(rows, c)
+	}
+	rows, nextCursor := paginate(rows, page, inboxCursor, newestFirst)
+
+	conversations := []map[string]interface{}{}
+	for _, c := range rows {
+		lastMessage := map[string]interface{}{
+			"message_id": c.last.id,
+			"content":    c.last.content,
+			"sender_id":  c.last.senderID,
+			"read_at":    nil,
+		}
+		if c.last.readAt != 0 {
+			lastMessage["read_at"] = c.last.readAt
+		}
+
+		conversations = append(conversations, map[string]interface{}{
+			"user_id":      c.other.props["user_id"],
+			"username":     c.other.props["username"],
+			"name":         concat(c.other.props, "first_name", "last_name"),
+			"picture":      c.other.props["profile_picture"],
+			"last_message": lastMessage,
+			"timestamp":    c.last.created,
+			"unread_count": c.unread,
+		})
+	}
+
+	return conversations, nextCursor, nil
+}
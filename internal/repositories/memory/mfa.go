@@ -0,0 +1,190 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// mfaRepository keeps the two-factor settings as UserProfile properties,
+// under the same names the Neo4j queries use.
+type mfaRepository struct {
+	db *DB
+}
+
+func (r *mfaRepository) user(userID string) (*userNode, error) {
+	user, ok := r.db.users[userID]
+	if !ok {
+		return nil, fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+	return user, nil
+}
+
+func (r *mfaRepository) GetMFA(ctx context.Context, userID string, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	backupHashes, _ := user.props["mfa_backup_hashes"].([]string)
+	failures, _ := user.props["mfa_failures"].(int64)
+	failed, _ := user.props["mfa_failed_timestamp"].(int64)
+
+	return map[string]interface{}{
+		"user_id":                user.props["user_id"],
+		"username":               user.props["username"],
+		"role":                   user.props["role"],
+		"admit_year":             user.props["admit_year"],
+		"language":               user.props["language"],
+		"mfa_enabled":            user.props["mfa_enabled"] == true,
+		"mfa_required":           user.props["mfa_required"] == true,
+		"mfa_secret":             user.props["mfa_secret"],
+		"mfa_pending_secret":     user.props["mfa_pending_secret"],
+		"backup_codes_remaining": int64(len(backupHashes)),
+		"mfa_failures":           failures,
+		"mfa_failed_timestamp":   failed,
+	}, nil
+}
+
+func (r *mfaRepository) SetPendingMFASecret(ctx context.Context, userID string, secret []byte, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+
+	user.props["mfa_pending_secret"] = secret
+	return nil
+}
+
+func (r *mfaRepository) EnableMFA(ctx context.Context, userID string, backupHashes []string, step int64, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+	if user.props["mfa_pending_secret"] == nil {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+
+	user.props["mfa_secret"] = user.props["mfa_pending_secret"]
+	user.props["mfa_enabled"] = true
+	user.props["mfa_backup_hashes"] = slices.Clone(backupHashes)
+	user.props["mfa_last_step"] = step
+	user.props["mfa_failures"] = int64(0)
+	delete(user.props, "mfa_pending_secret")
+	return nil
+}
+
+func (r *mfaRepository) DisableMFA(ctx context.Context, userID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+
+	user.props["mfa_enabled"] = false
+	for _, key := range []string{"mfa_secret", "mfa_pending_secret", "mfa_backup_hashes", "mfa_last_step"} {
+		delete(user.props, key)
+	}
+	return nil
+}
+
+func (r *mfaRepository) SetBackupCodes(ctx context.Context, userID string, backupHashes []string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+
+	user.props["mfa_backup_hashes"] = slices.Clone(backupHashes)
+	return nil
+}
+
+func (r *mfaRepository) UseTOTPStep(ctx context.Context, userID string, step int64, logger *zap.Logger) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return false, err
+	}
+
+	last, ok := user.props["mfa_last_step"].(int64)
+	if ok && last >= step {
+		return false, nil
+	}
+
+	user.props["mfa_last_step"] = step
+	user.props["mfa_failures"] = int64(0)
+	return true, nil
+}
+
+func (r *mfaRepository) UseBackupCode(ctx context.Context, userID, hash string, logger *zap.Logger) (bool, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return false, err
+	}
+
+	backupHashes, _ := user.props["mfa_backup_hashes"].([]string)
+	i := slices.Index(backupHashes, hash)
+	if i < 0 {
+		return false, nil
+	}
+
+	user.props["mfa_backup_hashes"] = slices.Delete(slices.Clone(backupHashes), i, i+1)
+	user.props["mfa_failures"] = int64(0)
+	return true, nil
+}
+
+func (r *mfaRepository) RecordMFAFailure(ctx context.Context, userID string, window time.Duration, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UnixMilli()
+	failures, _ := user.props["mfa_failures"].(int64)
+	failed, _ := user.props["mfa_failed_timestamp"].(int64)
+	if failed < now-window.Milliseconds() {
+		failures = 0
+	}
+
+	user.props["mfa_failures"] = failures + 1
+	user.props["mfa_failed_timestamp"] = now
+	return nil
+}
+
+func (r *mfaRepository) SetMFARequired(ctx context.Context, userID string, required bool, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, err := r.user(userID)
+	if err != nil {
+		return err
+	}
+
+	user.props["mfa_required"] = required
+	return nil
+}
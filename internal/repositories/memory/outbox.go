@@ -0,0 +1,153 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type outboxRepository struct {
+	db *DB
+}
+
+// copyMail returns a mail that can be handed out without sharing its Data
+// with the outbox.
+func copyMail(mail *models.Mail) models.Mail {
+	ret := *mail
+	ret.Data = maps.Clone(mail.Data)
+	return ret
+}
+
+func (r *outboxRepository) EnqueueMail(ctx context.Context, mail models.Mail, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := r.db.timestamp()
+	mail.MailID = uuid.New().String()
+	mail.Data = maps.Clone(mail.Data)
+	mail.Status = models.MailPending
+	mail.Attempts = 0
+	mail.NextAttemptTimestamp = now
+	mail.LastError = ""
+	mail.CreatedTimestamp = now
+	r.db.mails[mail.MailID] = &mail
+	return nil
+}
+
+func (r *outboxRepository) ClaimMails(ctx context.Context, limit int, lease time.Duration, logger *zap.Logger) ([]models.Mail, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	now := r.db.timestamp()
+	var due []*models.Mail
+	for _, mail := range r.db.mails {
+		if mail.Status == models.MailPending && mail.NextAttemptTimestamp <= now {
+			due = append(due, mail)
+		}
+	}
+
+	sort.Slice(due, func(i, j int) bool {
+		if due[i].NextAttemptTimestamp != due[j].NextAttemptTimestamp {
+			return due[i].NextAttemptTimestamp < due[j].NextAttemptTimestamp
+		}
+		return due[i].MailID < due[j].MailID
+	})
+	if len(due) > limit {
+		due = due[:limit]
+	}
+
+	mails := []models.Mail{}
+	for _, mail := range due {
+		mail.Attempts++
+		mail.NextAttemptTimestamp = now + lease.Milliseconds()
+		mails = append(mails, copyMail(mail))
+	}
+	return mails, nil
+}
+
+func (r *outboxRepository) MarkMailSent(ctx context.Context, mailID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if mail, ok := r.db.mails[mailID]; ok {
+		mail.Status = models.MailSent
+		mail.Data = nil
+		mail.LastError = ""
+	}
+	return nil
+}
+
+func (r *outboxRepository) MarkMailFailed(ctx context.Context, mailID, lastError string, nextAttempt int64, dead bool, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if mail, ok := r.db.mails[mailID]; ok {
+		mail.Status = models.MailPending
+		if dead {
+			mail.Status = models.MailDead
+		}
+		mail.LastError = lastError
+		mail.NextAttemptTimestamp = nextAttempt
+	}
+	return nil
+}
+
+func (r *outboxRepository) GetDeadLetters(ctx context.Context, page models.Page, logger *zap.Logger) ([]models.Mail, string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var dead []models.Mail
+	for _, mail := range r.db.mails {
+		if mail.Status == models.MailDead {
+			dead = append(dead, copyMail(mail))
+		}
+	}
+
+	dead, nextCursor := paginate(dead, page, mailCursor, newestFirst)
+	if dead == nil {
+		dead = []models.Mail{}
+	}
+	return dead, nextCursor, nil
+}
+
+func mailCursor(mail models.Mail) models.Cursor {
+	return models.Cursor{Timestamp: mail.CreatedTimestamp, ID: mail.MailID}
+}
+
+func (r *outboxRepository) RetryDeadLetter(ctx context.Context, mailID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	mail, ok := r.db.mails[mailID]
+	if !ok || mail.Status != models.MailDead {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Dead letter: %s not found", mailID))
+	}
+
+	mail.Status = models.MailPending
+	mail.Attempts = 0
+	mail.NextAttemptTimestamp = r.db.timestamp()
+	return nil
+}
+
+// Mails returns every mail in the outbox, whatever its status, for tests to
+// inspect.
+func (db *DB) Mails() []models.Mail {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	mails := []models.Mail{}
+	for _, mail := range db.mails {
+		mails = append(mails, copyMail(mail))
+	}
+	sort.Slice(mails, func(i, j int) bool {
+		return newestFirst(mailCursor(mails[j]), mailCursor(mails[i]))
+	})
+	return mails
+}
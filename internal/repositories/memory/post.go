@@ -0,0 +1,555 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/services"
+	"alumni_api/internal/utils"
+	"context"
+	"net/http"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type postRepository struct {
+	db *DB
+}
+
+// sortedPosts returns the posts whose author still exists, oldest first.
+func (db *DB) sortedPosts() []*postNode {
+	posts := make([]*postNode, 0, len(db.posts))
+	for _, post := range db.posts {
+		if _, ok := db.users[post.authorID]; ok {
+			posts = append(posts, post)
+		}
+	}
+	sort.Slice(posts, func(i, j int) bool {
+		return posts[i].props["created_timestamp"].(int64) < posts[j].props["created_timestamp"].(int64)
+	})
+	return posts
+}
+
+// visibleTo mirrors the visibility filter of the post queries: posts without
+// a visibility count as "all" and authors always see their own posts.
+func (post *postNode) visibleTo(userID string, visibility []string) bool {
+	value, ok := post.props["visibility"].(string)
+	if !ok || value == "" {
+		value = "all"
+	}
+	return slices.Contains(visibility, value) || (userID != "" && post.authorID == userID)
+}
+
+func (db *DB) visiblePosts(userID string, visibility []string) []*postNode {
+	var posts []*postNode
+	for _, post := range db.sortedPosts() {
+		if post.visibleTo(userID, visibility) {
+			posts = append(posts, post)
+		}
+	}
+	return posts
+}
+
+// countLive counts the edges in set whose user still exists.
+func (db *DB) countLive(set map[string]bool) int64 {
+	var count int64
+	for userID := range set {
+		if _, ok := db.users[userID]; ok {
+			count++
+		}
+	}
+	return count
+}
+
+func (db *DB) directComments(postID string) int64 {
+	var count int64
+	for _, comment := range db.comments {
+		if comment.postID == postID {
+			count++
+		}
+	}
+	return count
+}
+
+func postCursor(post *postNode) models.Cursor {
+	return models.Cursor{
+		Timestamp: post.props["created_timestamp"].(int64),
+		ID:        post.props["post_id"].(string),
+	}
+}
+
+// postSummary builds the row GetAllPosts returns for one post.
+func (db *DB) postSummary(post *postNode, userID string) map[string]interface{} {
+	p := post.props
+	author := db.users[post.authorID].props
+
+	row := map[string]interface{}{
+		"post_id":           p["post_id"],
+		"title":             p["title"],
+		"post_type":         p["post_type"],
+		"media_urls":        p["media_urls"],
+		"redirect_link":     p["redirect_link"],
+		"start_date":        p["start_date"],
+		"end_date":          p["end_date"],
+		"name":              concat(author, "first_name", "last_name"),
+		"user_id":           author["user_id"],
+		"profile_picture":   author["profile_picture"],
+		"likes_count":       db.countLive(post.likes),
+		"views_count":       db.countLive(post.views),
+		"comments_count":    db.directComments(post.props["post_id"].(string)),
+		"has_liked":         post.likes[userID],
+		"created_timestamp": p["created_timestamp"],
+	}
+
+	for key, value := range row {
+		if value == nil || value == "" {
+			delete(row, key)
+		}
+	}
+
+	return row
+}
+
+func (r *postRepository) GetAllPosts(ctx context.Context, userID string, visibility []string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var posts []map[string]interface{}
+
+	nodes, nextCursor := paginate(r.db.visiblePosts(userID, visibility), page, postCursor, newestFirst)
+
+	for _, post := range nodes {
+		posts = append(posts, r.db.postSummary(post, userID))
+	}
+
+	return posts, nextCursor, nil
+}
+
+func (r *postRepository) GetFeedCandidates(ctx context.Context, userID string, visibility []string, window int, logger *zap.Logger) ([]models.FeedCandidate, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var candidates []models.FeedCandidate
+
+	posts := r.db.visiblePosts(userID, visibility)
+	slices.Reverse(posts)
+	if len(posts) > window {
+		posts = posts[:window]
+	}
+
+	viewer, hasViewer := r.db.users[userID]
+
+	for _, post := range posts {
+		author := r.db.users[post.authorID]
+		summary := r.db.postSummary(post, userID)
+
+		candidate := models.FeedCandidate{
+			Post:      summary,
+			CreatedAt: post.props["created_timestamp"].(int64),
+			Likes:     summary["likes_count"].(int64),
+			Views:     summary["views_count"].(int64),
+			Comments:  summary["comments_count"].(int64),
+		}
+
+		if hasViewer {
+			_, candidate.IsFriend = viewer.friends[post.authorID]
+			generation := viewer.props["generation"]
+			candidate.SameGeneration = generation != nil && generation == author.props["generation"]
+			candidate.SameStudentType = viewer.college.StudentType != "" && viewer.college == author.college
+			for companyID := range viewer.works {
+				if _, ok := author.works[companyID]; ok {
+					candidate.SharedCompanies++
+				}
+			}
+		}
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
+}
+
+func (r *postRepository) PostVisible(ctx context.Context, postID, userID string, visibility []string, logger *zap.Logger) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	post, ok := r.db.posts[postID]
+	if !ok {
+		return false, nil
+	}
+	if _, ok := r.db.users[post.authorID]; !ok {
+		return false, nil
+	}
+
+	return post.visibleTo(userID, visibility), nil
+}
+
+func (r *postRepository) GetPostByID(ctx context.Context, postID, userID string, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	post, ok := r.db.posts[postID]
+	if !ok {
+		logger.Error("Failed to collect results", zap.String("post_id", postID))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
+	}
+	author, ok := r.db.users[post.authorID]
+	if !ok {
+		logger.Error("Failed to collect results", zap.String("post_id", postID))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
+	}
+	p := post.props
+
+	return map[string]interface{}{
+		"post_id":                p["post_id"],
+		"title":                  p["title"],
+		"content":                p["content"],
+		"post_type":              p["post_type"],
+		"media_urls":             p["media_urls"],
+		"redirect_link":          p["redirect_link"],
+		"start_date":             p["start_date"],
+		"end_date":               p["end_date"],
+		"created_timestamp":      p["created_timestamp"],
+		"author_name":            concat(author.props, "first_name", "last_name"),
+		"author_user_id":         author.props["user_id"],
+		"author_profile_picture": author.props["profile_picture"],
+		"likes_count":            r.db.countLive(post.likes),
+		"views_count":            r.db.countLive(post.views),
+		"has_liked":              post.likes[userID],
+	}, nil
+}
+
+func (r *postRepository) CreatePost(ctx context.Context, userID string, post models.Post, logger *zap.Logger) (map[string]interface{}, error) {
+	postID := uuid.New().String()
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[userID]; ok {
+		props := map[string]interface{}{
+			"post_id":           postID,
+			"title":             post.Title,
+			"content":           post.Content,
+			"media_urls":        post.MediaURL,
+			"redirect_link":     post.RedirectLink,
+			"post_type":         post.PostType,
+			"visibility":        post.Visibility,
+			"created_timestamp": r.db.timestamp(),
+		}
+		if post.MediaURL == nil {
+			delete(props, "media_urls")
+		}
+		if slices.Contains(models.AllowRangeType, post.PostType) {
+			props["start_date"] = post.StartDate
+			props["end_date"] = post.EndDate
+		}
+
+		r.db.posts[postID] = &postNode{
+			props:    props,
+			authorID: userID,
+			likes:    make(map[string]bool),
+			views:    make(map[string]bool),
+		}
+	}
+
+	return map[string]interface{}{
+		"post_id": postID,
+	}, nil
+}
+
+func (r *postRepository) UpdatePostByID(ctx context.Context, postID string, updatedData models.UpdatePostRequest, logger *zap.Logger) error {
+	properties, err := utils.StructToMap(updatedData)
+	if err != nil {
+		logger.Error("Failed to convert struct to map", zap.Error(err))
+		return fiber.NewError(http.StatusInternalServerError, "Internal server error")
+	}
+
+	properties["updated_timestamp"] = time.Now().Unix()
+
+	if !slices.Contains(models.AllowRangeType, updatedData.PostType) {
+		delete(properties, "start_date")
+		delete(properties, "end_date")
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if post, ok := r.db.posts[postID]; ok {
+		for key, value := range properties {
+			post.props[key] = value
+		}
+	}
+
+	return nil
+}
+
+func (r *postRepository) DeletePostByID(ctx context.Context, postID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.posts[postID]; !ok {
+		logger.Warn("No post found with given post_id")
+		return fiber.NewError(http.StatusNotFound, "Post not found")
+	}
+
+	delete(r.db.posts, postID)
+	return nil
+}
+
+func (r *postRepository) LikePost(ctx context.Context, userID, postID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	post, ok := r.db.posts[postID]
+	if _, exists := r.db.users[userID]; ok && exists {
+		post.likes[userID] = true
+	}
+
+	return nil
+}
+
+func (r *postRepository) UnlikePost(ctx context.Context, userID, postID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if post, ok := r.db.posts[postID]; ok {
+		delete(post.likes, userID)
+	}
+
+	return nil
+}
+
+// threadOf returns the comments attached to postID, directly or through
+// replies, ordered by creation time.
+func (db *DB) threadOf(postID string) []*commentNode {
+	inThread := make(map[string]bool)
+	changed := true
+	for changed {
+		changed = false
+		for id, comment := range db.comments {
+			if inThread[id] {
+				continue
+			}
+			if comment.postID == postID || (comment.parentID != "" && inThread[comment.parentID]) {
+				inThread[id] = true
+				changed = true
+			}
+		}
+	}
+
+	var thread []*commentNode
+	for id := range inThread {
+		thread = append(thread, db.comments[id])
+	}
+	sort.Slice(thread, func(i, j int) bool {
+		return thread[i].created < thread[j].created
+	})
+	return thread
+}
+
+// rootOf walks up the reply chain to the comment made directly on the post.
+func (db *DB) rootOf(comment *commentNode) *commentNode {
+	for comment.parentID != "" {
+		parent, ok := db.comments[comment.parentID]
+		if !ok {
+			break
+		}
+		comment = parent
+	}
+	return comment
+}
+
+func commentCursor(comment *commentNode) models.Cursor {
+	return models.Cursor{Timestamp: comment.created, ID: comment.id}
+}
+
+func (r *postRepository) GetCommentByPostID(ctx context.Context, postID, userID string, page models.Page, logger *zap.Logger) ([]models.Comment, string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var comments []models.Comment
+
+	post, ok := r.db.posts[postID]
+	if !ok {
+		return services.BuildCommentTree(comments), "", nil
+	}
+	if _, ok := r.db.users[post.authorID]; !ok {
+		return services.BuildCommentTree(comments), "", nil
+	}
+
+	thread := r.db.threadOf(postID)
+
+	var roots []*commentNode
+	for _, node := range thread {
+		if node.postID == postID {
+			roots = append(roots, node)
+		}
+	}
+	roots, nextCursor := paginate(roots, page, commentCursor, oldestFirst)
+
+	inPage := make(map[string]bool)
+	for _, root := range roots {
+		inPage[root.id] = true
+	}
+
+	for _, node := range thread {
+		if !inPage[r.db.rootOf(node).id] {
+			continue
+		}
+		comment := models.Comment{
+			CommentID:  node.id,
+			Content:    node.comment,
+			CreatedAt:  node.created,
+			LikeCounts: r.db.countLive(node.likes),
+			HasLike:    node.likes[userID],
+		}
+		if node.parentID != "" {
+			parentID := node.parentID
+			comment.ParentCommentID = &parentID
+		}
+		if user, ok := r.db.users[node.authorID]; ok {
+			comment.UserID = utils.SafeString(user.props["user_id"])
+			comment.Username = utils.SafeString(user.props["username"])
+			comment.Fullname = utils.SafeString(concat(user.props, "first_name", "last_name"))
+			comment.FullnameEng = utils.SafeString(concat(user.props, "first_name_eng", "last_name_eng"))
+			comment.ProfilePicture = utils.SafeString(user.props["profile_picture"])
+		}
+		comments = append(comments, comment)
+	}
+
+	return services.BuildCommentTree(comments), nextCursor, nil
+}
+
+func (r *postRepository) addComment(userID, postID, parentID, comment string, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[userID]
+	_, targetExists := r.db.posts[postID]
+	if parentID != "" {
+		_, targetExists = r.db.comments[parentID]
+	}
+	if !ok || !targetExists {
+		logger.Error("Failed to collect results", zap.String("user_id", userID))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
+	}
+
+	node := &commentNode{
+		id:       uuid.New().String(),
+		comment:  comment,
+		authorID: userID,
+		postID:   postID,
+		parentID: parentID,
+		created:  r.db.timestamp(),
+		likes:    make(map[string]bool),
+	}
+	r.db.comments[node.id] = node
+
+	return map[string]interface{}{
+		"user_id":           user.props["user_id"],
+		"username":          user.props["username"],
+		"content":           node.comment,
+		"comment_id":        node.id,
+		"created_timestamp": node.created,
+	}, nil
+}
+
+func (r *postRepository) CommentPost(ctx context.Context, userID, postID, comment string, logger *zap.Logger) (map[string]interface{}, error) {
+	return r.addComment(userID, postID, "", comment, logger)
+}
+
+func (r *postRepository) ReplyComment(ctx context.Context, userID, commentID, comment string, logger *zap.Logger) (map[string]interface{}, error) {
+	return r.addComment(userID, "", commentID, comment, logger)
+}
+
+func (r *postRepository) UpdateCommentPost(ctx context.Context, commentID, comment string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if node, ok := r.db.comments[commentID]; ok {
+		node.comment = comment
+		node.updated = r.db.timestamp()
+	}
+
+	return nil
+}
+
+func (r *postRepository) DeleteCommentPost(ctx context.Context, commentID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.comments, commentID)
+	return nil
+}
+
+func (r *postRepository) LikeComment(ctx context.Context, userID, commentID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	node, ok := r.db.comments[commentID]
+	if _, exists := r.db.users[userID]; ok && exists {
+		node.likes[userID] = true
+	}
+
+	return nil
+}
+
+func (r *postRepository) UnlikeComment(ctx context.Context, userID, commentID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if node, ok := r.db.comments[commentID]; ok {
+		delete(node.likes, userID)
+	}
+
+	return nil
+}
+
+func (r *postRepository) AddView(ctx context.Context, userID, postID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	post, ok := r.db.posts[postID]
+	if _, exists := r.db.users[userID]; ok && exists {
+		post.views[userID] = true
+	}
+
+	return nil
+}
+
+func (r *postRepository) GetAuthorUserID(ctx context.Context, postID string, logger *zap.Logger) (string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	post, ok := r.db.posts[postID]
+	if !ok {
+		logger.Error("Error retrieving result", zap.String("post_id", postID))
+		return "", fiber.NewError(http.StatusInternalServerError, "Error retrieving result")
+	}
+	if _, ok := r.db.users[post.authorID]; !ok {
+		logger.Error("Error retrieving result", zap.String("post_id", postID))
+		return "", fiber.NewError(http.StatusInternalServerError, "Error retrieving result")
+	}
+
+	return post.authorID, nil
+}
+
+func (r *postRepository) GetCommentUserID(ctx context.Context, commentID string, logger *zap.Logger) (string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	node, ok := r.db.comments[commentID]
+	if !ok {
+		logger.Error("Error retrieving result", zap.String("comment_id", commentID))
+		return "", fiber.NewError(http.StatusInternalServerError, "Error retrieving result")
+	}
+	if _, ok := r.db.users[node.authorID]; !ok {
+		logger.Error("Error retrieving result", zap.String("comment_id", commentID))
+		return "", fiber.NewError(http.StatusInternalServerError, "Error retrieving result")
+	}
+
+	return node.authorID, nil
+}
@@ -0,0 +1,111 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type reportRepository struct {
+	db *DB
+}
+
+func reportCursor(report *reportNode) models.Cursor {
+	return models.Cursor{Timestamp: report.created, ID: report.id}
+}
+
+func (r *reportRepository) FetchReport(ctx context.Context, page models.Page, departmentID string, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var reports []map[string]interface{}
+
+	var nodes []*reportNode
+	for _, report := range r.db.reports {
+		if report.reportType != "post" {
+			continue
+		}
+		post, ok := r.db.posts[report.targetID]
+		if !ok {
+			continue
+		}
+		if author, ok := r.db.users[post.authorID]; !ok || !r.db.inDepartment(author, departmentID) {
+			continue
+		}
+		if _, ok := r.db.users[report.reporterID]; !ok {
+			continue
+		}
+		nodes = append(nodes, report)
+	}
+	nodes, nextCursor := paginate(nodes, page, reportCursor, newestFirst)
+
+	for _, report := range nodes {
+		post := r.db.posts[report.targetID]
+		author := r.db.users[post.authorID]
+		reporter := r.db.users[report.reporterID]
+
+		reports = append(reports, map[string]interface{}{
+			"post_id":    post.props["post_id"],
+			"title":      post.props["title"],
+			"content":    post.props["content"],
+			"post_type":  post.props["post_type"],
+			"media_urls": post.props["media_urls"],
+
+			"author_name":            concat(author.props, "first_name", "last_name"),
+			"author_username":        author.props["username"],
+			"author_user_id":         author.props["user_id"],
+			"author_profile_picture": author.props["profile_picture"],
+
+			"reporter_name":            concat(reporter.props, "first_name", "last_name"),
+			"reporter_username":        reporter.props["username"],
+			"reporter_user_id":         reporter.props["user_id"],
+			"reporter_profile_picture": reporter.props["profile_picture"],
+
+			"report_id":         report.id,
+			"additional":        report.additional,
+			"category":          report.category,
+			"status":            report.status,
+			"type":              report.reportType,
+			"created_timestamp": report.created,
+		})
+	}
+
+	return reports, nextCursor, nil
+}
+
+func (r *reportRepository) Report(ctx context.Context, report models.Report, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[report.UserID]; !ok {
+		return nil
+	}
+
+	var targetExists bool
+	switch report.Type {
+	case "post":
+		_, targetExists = r.db.posts[report.ID]
+	case "comment":
+		_, targetExists = r.db.comments[report.ID]
+	default:
+		_, targetExists = r.db.users[report.ID]
+	}
+	if !targetExists {
+		return nil
+	}
+
+	r.db.reports = append(r.db.reports, &reportNode{
+		id:         uuid.New().String(),
+		reporterID: report.UserID,
+		targetID:   report.ID,
+		reportType: report.Type,
+		status:     "pending",
+		category:   report.Category,
+		additional: report.Additional,
+		created:    r.db.timestamp(),
+	})
+
+	return nil
+}
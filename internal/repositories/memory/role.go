@@ -0,0 +1,148 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+type roleRepository struct {
+	db *DB
+}
+
+func (r *roleRepository) SyncBuiltInRoles(ctx context.Context, roles []models.Role, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, role := range roles {
+		r.db.roles[role.Name] = &roleNode{name: role.Name, permissions: slices.Clone(role.Permissions), builtIn: true}
+	}
+	return nil
+}
+
+func (r *roleRepository) GetRoles(ctx context.Context, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	ret := []map[string]interface{}{}
+	for _, role := range r.db.roles {
+		var assigned int64
+		for _, user := range r.db.users {
+			if _, ok := user.roles[role.name]; ok {
+				assigned++
+			}
+		}
+
+		permissions := make([]interface{}, 0, len(role.permissions))
+		for _, permission := range role.permissions {
+			permissions = append(permissions, permission)
+		}
+
+		ret = append(ret, map[string]interface{}{
+			"name":           role.name,
+			"permissions":    permissions,
+			"built_in":       role.builtIn,
+			"assigned_users": assigned,
+		})
+	}
+
+	sort.Slice(ret, func(i, j int) bool {
+		return ret[i]["name"].(string) < ret[j]["name"].(string)
+	})
+	return ret, nil
+}
+
+func (r *roleRepository) SaveRole(ctx context.Context, role models.Role, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if existing, ok := r.db.roles[role.Name]; ok && existing.builtIn {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("Role: %s is built in", role.Name))
+	}
+
+	r.db.roles[role.Name] = &roleNode{name: role.Name, permissions: slices.Clone(role.Permissions)}
+	return nil
+}
+
+func (r *roleRepository) DeleteRole(ctx context.Context, name string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	role, ok := r.db.roles[name]
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Role: %s not found", name))
+	}
+	if role.builtIn {
+		return fiber.NewError(fiber.StatusConflict, fmt.Sprintf("Role: %s is built in", name))
+	}
+
+	delete(r.db.roles, name)
+	for _, user := range r.db.users {
+		delete(user.roles, name)
+	}
+	return nil
+}
+
+func (r *roleRepository) AssignRole(ctx context.Context, userID, name string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+	if _, ok := r.db.roles[name]; !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Role: %s not found", name))
+	}
+
+	if _, ok := user.roles[name]; !ok {
+		user.roles[name] = r.db.timestamp()
+	}
+	return nil
+}
+
+func (r *roleRepository) RevokeRole(ctx context.Context, userID, name string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Role: %s not assigned to user %s", name, userID))
+	}
+	if _, ok := user.roles[name]; !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Role: %s not assigned to user %s", name, userID))
+	}
+
+	delete(user.roles, name)
+	return nil
+}
+
+func (r *roleRepository) GetPermissions(ctx context.Context, userID string, logger *zap.Logger) ([]string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	permissions := []string{}
+	user, ok := r.db.users[userID]
+	if !ok {
+		return permissions, nil
+	}
+
+	for _, role := range r.db.roles {
+		_, assigned := user.roles[role.name]
+		if role.name != user.props["role"] && !assigned {
+			continue
+		}
+		for _, permission := range role.permissions {
+			if !slices.Contains(permissions, permission) {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+
+	return permissions, nil
+}
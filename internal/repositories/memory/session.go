@@ -0,0 +1,192 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/utils"
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// refreshHashHistory matches the number of replaced hashes the Neo4j
+// RotateSession keeps.
+const refreshHashHistory = 20
+
+type sessionRepository struct {
+	db *DB
+}
+
+// PutSession opens a session for the user that lasts an hour, the
+// equivalent of a login, and returns its session_id.
+func (db *DB) PutSession(userID string) string {
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	now := db.timestamp()
+	node := &sessionNode{
+		id:       uuid.New().String(),
+		userID:   userID,
+		created:  now,
+		lastUsed: now,
+		expires:  time.Now().Add(time.Hour).UnixMilli(),
+	}
+	db.sessions[node.id] = node
+	return node.id
+}
+
+func (s *sessionNode) active() bool {
+	return s.revoked == 0 && s.expires > time.Now().UnixMilli()
+}
+
+func (r *sessionRepository) CreateSession(ctx context.Context, session models.Session, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if _, ok := r.db.users[session.UserID]; !ok {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("User: %s not found", session.UserID))
+	}
+
+	now := r.db.timestamp()
+	r.db.sessions[session.SessionID] = &sessionNode{
+		id:          session.SessionID,
+		userID:      session.UserID,
+		refreshHash: session.RefreshHash,
+		userAgent:   session.UserAgent,
+		ip:          session.IP,
+		created:     now,
+		lastUsed:    now,
+		expires:     session.ExpiresTimestamp,
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) RotateSession(ctx context.Context, sessionID, refreshHash string, next models.Session, logger *zap.Logger) (models.LoginResponse, error) {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	node, ok := r.db.sessions[sessionID]
+	if !ok {
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusUnauthorized, "Invalid refresh token")
+	}
+	user, ok := r.db.users[node.userID]
+	if !ok {
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusUnauthorized, "Invalid refresh token")
+	}
+
+	if !node.active() {
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusUnauthorized, "Session expired or revoked")
+	}
+
+	for _, previous := range node.previousHashes {
+		if previous == refreshHash {
+			node.revoked = r.db.timestamp()
+			node.revokedReason = "reuse"
+			logger.Warn("Refresh token reuse detected, session revoked", zap.String("session_id", sessionID))
+			return models.LoginResponse{}, fiber.NewError(fiber.StatusUnauthorized, "Refresh token reuse detected")
+		}
+	}
+
+	if node.refreshHash != refreshHash {
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusUnauthorized, "Invalid refresh token")
+	}
+
+	node.previousHashes = append([]string{node.refreshHash}, node.previousHashes...)
+	if len(node.previousHashes) > refreshHashHistory {
+		node.previousHashes = node.previousHashes[:refreshHashHistory]
+	}
+	node.refreshHash = next.RefreshHash
+	node.userAgent = next.UserAgent
+	node.ip = next.IP
+	node.lastUsed = r.db.timestamp()
+	node.expires = next.ExpiresTimestamp
+
+	record := map[string]interface{}{
+		"user_id":    user.props["user_id"],
+		"role":       user.props["role"],
+		"admit_year": user.props["admit_year"],
+		"language":   user.props["language"],
+	}
+
+	var res models.LoginResponse
+	if err := utils.MapToStruct(record, &res); err != nil {
+		logger.Error("Error decoding user properties", zap.Error(err))
+		return models.LoginResponse{}, fiber.NewError(fiber.StatusInternalServerError, "Error decoding user properties")
+	}
+
+	return res, nil
+}
+
+func (r *sessionRepository) GetSessions(ctx context.Context, userID string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var nodes []*sessionNode
+	for _, node := range r.db.sessions {
+		if node.userID == userID && node.active() {
+			nodes = append(nodes, node)
+		}
+	}
+
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].lastUsed != nodes[j].lastUsed {
+			return nodes[i].lastUsed > nodes[j].lastUsed
+		}
+		return nodes[i].id < nodes[j].id
+	})
+
+	sessions := []map[string]interface{}{}
+	for _, node := range nodes {
+		sessions = append(sessions, map[string]interface{}{
+			"session_id":          node.id,
+			"user_agent":          node.userAgent,
+			"ip":                  node.ip,
+			"created_timestamp":   node.created,
+			"last_used_timestamp": node.lastUsed,
+			"expires_timestamp":   node.expires,
+		})
+	}
+
+	return sessions, nil
+}
+
+func (r *sessionRepository) RevokeSession(ctx context.Context, userID, sessionID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	node, ok := r.db.sessions[sessionID]
+	if !ok || node.userID != userID || node.revoked != 0 {
+		return fiber.NewError(fiber.StatusNotFound, fmt.Sprintf("Session: %s not found", sessionID))
+	}
+
+	node.revoked = r.db.timestamp()
+	node.revokedReason = "logout"
+	return nil
+}
+
+func (r *sessionRepository) RevokeAllSessions(ctx context.Context, userID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	for _, node := range r.db.sessions {
+		if node.userID == userID && node.revoked == 0 {
+			node.revoked = r.db.timestamp()
+			node.revokedReason = "logout_all"
+		}
+	}
+
+	return nil
+}
+
+func (r *sessionRepository) IsSessionActive(ctx context.Context, userID, sessionID string, logger *zap.Logger) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	node, ok := r.db.sessions[sessionID]
+	return ok && node.userID == userID && node.active(), nil
+}
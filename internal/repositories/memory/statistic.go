@@ -0,0 +1,279 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"slices"
+	"sort"
+
+	"go.uber.org/zap"
+)
+
+type statisticRepository struct {
+	db *DB
+}
+
+// generationHistogram mirrors the {key, value} maps built by the post stat
+// query: distinct generations in first-seen order and their counts.
+func (db *DB) generationHistogram(userIDs []string) (int64, map[string]interface{}) {
+	var total int64
+	keys := []interface{}{}
+	counts := make(map[interface{}]int64)
+
+	for _, userID := range userIDs {
+		user, ok := db.users[userID]
+		if !ok {
+			continue
+		}
+		gen := user.props["generation"]
+		if gen == nil {
+			continue
+		}
+		total++
+		if _, seen := counts[gen]; !seen {
+			keys = append(keys, gen)
+		}
+		counts[gen]++
+	}
+
+	values := []interface{}{}
+	for _, key := range keys {
+		values = append(values, counts[key])
+	}
+
+	return total, map[string]interface{}{
+		"key":   keys,
+		"value": values,
+	}
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func (r *statisticRepository) GetPostStat(ctx context.Context, visibility []string, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var posts []map[string]interface{}
+
+	for _, post := range r.db.visiblePosts("", visibility) {
+		if !r.db.inDepartment(r.db.users[post.authorID], departmentID) {
+			continue
+		}
+		author := r.db.users[post.authorID].props
+
+		var commenters []string
+		for _, comment := range r.db.comments {
+			if comment.postID == post.props["post_id"] {
+				commenters = append(commenters, comment.authorID)
+			}
+		}
+		sort.Strings(commenters)
+
+		viewCount, viewGen := r.db.generationHistogram(sortedKeys(post.views))
+		likeCount, likeGen := r.db.generationHistogram(sortedKeys(post.likes))
+		commentCount, commentGen := r.db.generationHistogram(commenters)
+
+		row := map[string]interface{}{
+			"p.post_id":        post.props["post_id"],
+			"title":            post.props["title"],
+			"post_type":        post.props["post_type"],
+			"media_urls":       post.props["media_urls"],
+			"name":             concat(author, "first_name", "last_name"),
+			"user_id":          author["user_id"],
+			"profile_picture":  author["profile_picture"],
+			"view_count":       viewCount,
+			"like_count":       likeCount,
+			"comment_count":    commentCount,
+			"view_user_gen":    viewGen,
+			"like_user_gen":    likeGen,
+			"comment_user_gen": commentGen,
+		}
+
+		for key, value := range row {
+			if value == nil || value == "" {
+				delete(row, key)
+			}
+		}
+
+		posts = append(posts, row)
+	}
+
+	return posts, nil
+}
+
+func (r *statisticRepository) GetActivityStat(ctx context.Context, visibility []string, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var userCount, alumniCount, eventCount int64
+
+	for _, user := range r.db.users {
+		if user.props["is_verify"] == true {
+			userCount++
+		}
+		if user.props["role"] == "alumnus" {
+			alumniCount++
+		}
+	}
+	for _, post := range r.db.posts {
+		if post.props["post_type"] == "event" && post.visibleTo("", visibility) {
+			eventCount++
+		}
+	}
+
+	return map[string]interface{}{
+		"user_count":   userCount,
+		"alumni_count": alumniCount,
+		"event_count":  eventCount,
+	}, nil
+}
+
+func (r *statisticRepository) GetRegistryStat(ctx context.Context, departmentID string, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var totalUsers, verifiedUsers int64
+	users := make(map[string]int64)
+	verified := make(map[string]int64)
+
+	for _, user := range r.db.users {
+		gen, ok := user.props["generation"].(string)
+		if !ok || !r.db.inDepartment(user, departmentID) {
+			continue
+		}
+		totalUsers++
+		users[gen]++
+		if user.props["is_verify"] == true {
+			verifiedUsers++
+			verified[gen]++
+		}
+	}
+
+	gens := make([]string, 0, len(users))
+	for gen := range users {
+		gens = append(gens, gen)
+	}
+	sort.Strings(gens)
+
+	generationStats := []interface{}{}
+	for _, gen := range gens {
+		generationStats = append(generationStats, map[string]interface{}{
+			"generation":                    gen,
+			"users_in_generation":           users[gen],
+			"verified_in_generation":        verified[gen],
+			"generation_verification_ratio": float64(verified[gen]) / float64(users[gen]),
+		})
+	}
+
+	var overallRatio float64
+	if totalUsers > 0 {
+		overallRatio = float64(verifiedUsers) / float64(totalUsers)
+	}
+
+	return map[string]interface{}{
+		"generation_stats": generationStats,
+		"overall_stats": map[string]interface{}{
+			"total_users":                totalUsers,
+			"verified_users":             verifiedUsers,
+			"overall_verification_ratio": overallRatio,
+		},
+	}, nil
+}
+
+func (r *statisticRepository) GetGenerationSTStat(ctx context.Context, generation []string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var gens []map[string]interface{}
+
+	for _, gen := range generation {
+		counts := make(map[string]int64)
+		for _, user := range r.db.users {
+			if user.props["generation"] == gen && user.college.StudentType != "" {
+				counts[user.college.StudentType]++
+			}
+		}
+		if len(counts) == 0 {
+			continue
+		}
+
+		names := make([]string, 0, len(counts))
+		for name := range counts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		key := []interface{}{}
+		value := []interface{}{}
+		for _, name := range names {
+			key = append(key, name)
+			value = append(value, counts[name])
+		}
+
+		gens = append(gens, map[string]interface{}{
+			"generation_data": map[string]interface{}{
+				"gen": gen,
+				"data": map[string]interface{}{
+					"key":   key,
+					"value": value,
+				},
+			},
+		})
+	}
+
+	return gens, nil
+}
+
+func (r *statisticRepository) GetUserSalary(ctx context.Context, visibility []string, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var users []map[string]interface{}
+
+	for _, user := range r.db.sortedUsers() {
+		if !r.db.inDepartment(user, departmentID) || !slices.Contains(visibility, models.PrivacyOf(user.props).Salary) {
+			continue
+		}
+		for _, companyID := range r.db.sortedWorks(user) {
+			work := user.works[companyID]
+			if work.salaryMax == nil {
+				continue
+			}
+			users = append(users, map[string]interface{}{
+				"gen":        user.props["generation"],
+				"salary_max": work.salaryMax,
+				"salary_min": work.salaryMin,
+			})
+		}
+	}
+
+	return users, nil
+}
+
+func (r *statisticRepository) GetUserJob(ctx context.Context, visibility []string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var users []map[string]interface{}
+
+	for _, user := range r.db.sortedUsers() {
+		if !slices.Contains(visibility, models.PrivacyOf(user.props).Career) {
+			continue
+		}
+		for _, companyID := range r.db.sortedWorks(user) {
+			users = append(users, map[string]interface{}{
+				"company":  r.db.companies[companyID].name,
+				"position": user.works[companyID].position,
+			})
+		}
+	}
+
+	return users, nil
+}
@@ -0,0 +1,486 @@
+package memory
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/utils"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type userRepository struct {
+	db *DB
+}
+
+// sortedUsers returns every user ordered by user_id so results are stable.
+func (db *DB) sortedUsers() []*userNode {
+	users := make([]*userNode, 0, len(db.users))
+	for _, user := range db.users {
+		users = append(users, user)
+	}
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].props["user_id"].(string) < users[j].props["user_id"].(string)
+	})
+	return users
+}
+
+func (db *DB) sortedWorks(user *userNode) []string {
+	ids := make([]string, 0, len(user.works))
+	for id := range user.works {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		return user.works[ids[i]].created < user.works[ids[j]].created
+	})
+	return ids
+}
+
+func collegeValue(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+func userCursor(user *userNode) models.Cursor {
+	created, _ := user.props["created_timestamp"].(int64)
+	return models.Cursor{Timestamp: created, ID: user.props["user_id"].(string)}
+}
+
+func (r *userRepository) GetAllUser(ctx context.Context, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var users []map[string]interface{}
+
+	var alumni []*userNode
+	for _, user := range r.db.sortedUsers() {
+		if user.props["role"] == "alumnus" {
+			alumni = append(alumni, user)
+		}
+	}
+	alumni, nextCursor := paginate(alumni, page, userCursor, newestFirst)
+
+	for _, user := range alumni {
+		p := user.props
+
+		companies := []interface{}{}
+		for _, companyID := range r.db.sortedWorks(user) {
+			company := r.db.companies[companyID]
+			companies = append(companies, map[string]interface{}{
+				"company":  company.name,
+				"address":  company.address,
+				"position": user.works[companyID].position,
+			})
+		}
+
+		row := map[string]interface{}{
+			"user_id":           p["user_id"],
+			"username":          p["username"],
+			"first_name":        p["first_name"],
+			"last_name":         p["last_name"],
+			"first_name_eng":    p["first_name_eng"],
+			"last_name_eng":     p["last_name_eng"],
+			"name":              concat(p, "first_name", "last_name"),
+			"name_eng":          concat(p, "first_name_eng", "last_name_eng"),
+			"profile_picture":   p["profile_picture"],
+			"role":              p["role"],
+			"student_id":        p["student_id"],
+			"generation":        p["generation"],
+			"admit_year":        p["admit_year"],
+			"graduate_year":     p["graduate_year"],
+			"gpax":              p["gpax"],
+			"faculty":           collegeValue(user.college.Faculty),
+			"department":        collegeValue(user.college.Department),
+			"field":             collegeValue(user.college.Field),
+			"student_type":      collegeValue(user.college.StudentType),
+			"email":             p["email"],
+			"github":            p["github"],
+			"linkedin":          p["linkedin"],
+			"facebook":          p["facebook"],
+			"phone":             p["phone"],
+			"privacy_personal":  p["privacy_personal"],
+			"privacy_contact":   p["privacy_contact"],
+			"privacy_education": p["privacy_education"],
+			"privacy_career":    p["privacy_career"],
+			"privacy_salary":    p["privacy_salary"],
+			"companies":         companies,
+			"created_timestamp": userCursor(user).Timestamp,
+		}
+
+		users = append(users, utils.CleanNullValues(row).(map[string]interface{}))
+	}
+
+	return users, nextCursor, nil
+}
+
+func (r *userRepository) CreateProfile(ctx context.Context, user models.CreateProfileRequest, logger *zap.Logger) (map[string]interface{}, error) {
+	userID := uuid.New().String()
+	user.UserID = userID
+
+	params, err := utils.StructToMap(user)
+	if err != nil {
+		logger.Error("Failed to create user", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Error creating user")
+	}
+
+	r.db.PutUser(params)
+
+	return map[string]interface{}{
+		"user_id": userID,
+	}, nil
+}
+
+func (r *userRepository) FetchUserByID(ctx context.Context, id string, logger *zap.Logger) (map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	user, ok := r.db.users[id]
+	if !ok {
+		logger.Error(models.ErrRetrievalFailed, zap.String("user_id", id))
+		return nil, fiber.NewError(http.StatusInternalServerError, models.ErrRetrievalFailed)
+	}
+	p := user.props
+
+	var dob interface{}
+	switch v := p["dob"].(type) {
+	case time.Time:
+		dob = v.Format("2006-01-02")
+	case string:
+		dob = v
+	}
+
+	companies := []interface{}{}
+	for _, companyID := range r.db.sortedWorks(user) {
+		company := r.db.companies[companyID]
+		work := user.works[companyID]
+		companies = append(companies, map[string]interface{}{
+			"company_id": company.id,
+			"company":    company.name,
+			"address":    company.address,
+			"position":   work.position,
+			"salary_min": work.salaryMin,
+			"salary_max": work.salaryMax,
+		})
+	}
+
+	ret := map[string]interface{}{
+		"user_id":           p["user_id"],
+		"username":          p["username"],
+		"gender":            p["gender"],
+		"dob":               dob,
+		"first_name":        p["first_name"],
+		"last_name":         p["last_name"],
+		"first_name_eng":    p["first_name_eng"],
+		"last_name_eng":     p["last_name_eng"],
+		"name":              concat(p, "first_name", "last_name"),
+		"name_eng":          concat(p, "first_name_eng", "last_name_eng"),
+		"profile_picture":   p["profile_picture"],
+		"role":              p["role"],
+		"privacy_personal":  p["privacy_personal"],
+		"privacy_contact":   p["privacy_contact"],
+		"privacy_education": p["privacy_education"],
+		"privacy_career":    p["privacy_career"],
+		"privacy_salary":    p["privacy_salary"],
+		"student_info": map[string]interface{}{
+			"student_id":    p["student_id"],
+			"generation":    p["generation"],
+			"admit_year":    p["admit_year"],
+			"graduate_year": p["graduate_year"],
+			"gpax":          p["gpax"],
+		},
+		"college_info": map[string]interface{}{
+			"faculty":      collegeValue(user.college.Faculty),
+			"department":   collegeValue(user.college.Department),
+			"field":        collegeValue(user.college.Field),
+			"student_type": collegeValue(user.college.StudentType),
+		},
+		"companies": companies,
+		"contact_info": map[string]interface{}{
+			"email":    p["email"],
+			"github":   p["github"],
+			"linkedin": p["linkedin"],
+			"facebook": p["facebook"],
+			"phone":    p["phone"],
+		},
+	}
+
+	ret = utils.CleanNullValues(ret).(map[string]interface{})
+
+	if err := utils.ConvertMapDateFields(ret, []string{"dob"}, "2006-01-02"); err != nil {
+		logger.Error("Error converting date fields", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to parse date fields")
+	}
+
+	return ret, nil
+}
+
+func (r *userRepository) UpdateUserByID(ctx context.Context, id string, updatedData models.UpdateUserProfileRequest, logger *zap.Logger) (map[string]interface{}, error) {
+	properties, err := utils.StructToMap(updatedData)
+	if err != nil {
+		logger.Error("Failed to convert struct to map", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Internal server error")
+	}
+
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[id]
+	if !ok {
+		logger.Error("Error retrieving record from query result", zap.String("user_id", id))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Error retrieving user data")
+	}
+
+	for key, value := range properties {
+		if value == nil {
+			delete(user.props, key)
+			continue
+		}
+		user.props[key] = value
+	}
+
+	return copyProps(user.props), nil
+}
+
+func (r *userRepository) DeleteUserByID(ctx context.Context, userID string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	delete(r.db.users, userID)
+	for _, user := range r.db.users {
+		delete(user.friends, userID)
+	}
+	for _, post := range r.db.posts {
+		delete(post.likes, userID)
+		delete(post.views, userID)
+	}
+	for _, comment := range r.db.comments {
+		delete(comment.likes, userID)
+	}
+	for id, request := range r.db.requests {
+		if request.userID == userID {
+			delete(r.db.requests, id)
+		}
+	}
+
+	logger.Info("User deleted successfully", zap.String("userID", userID))
+	return nil
+}
+
+func (r *userRepository) FetchUserByFilter(ctx context.Context, filter models.UserRequestFilter, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	var users []map[string]interface{}
+
+	for _, user := range r.db.sortedUsers() {
+		if filter.Field != "" && user.college.Field != filter.Field {
+			continue
+		}
+		if filter.StudentType != "" && user.college.StudentType != filter.StudentType {
+			continue
+		}
+		if (filter.Company != "" || filter.PositionIndex != "") && !r.db.worksAs(user, filter.Company, filter.PositionIndex) {
+			continue
+		}
+		if !indexIn(user.props["admit_year_index"], filter.AdmitYearIndexes) ||
+			!indexIn(user.props["graduate_year_index"], filter.GraduateYearIndexes) ||
+			!indexIn(user.props["gpax_index"], filter.GPAXIndexes) {
+			continue
+		}
+
+		userMap := copyProps(user.props)
+		for key, value := range userMap {
+			if value == nil || value == "" {
+				delete(userMap, key)
+			}
+		}
+
+		users = append(users, userMap)
+	}
+
+	return users, nil
+}
+
+// worksAs reports whether user has a job at the named company with the
+// position index given; an empty name or index matches any.
+func (db *DB) worksAs(user *userNode, company, positionIndex string) bool {
+	for companyID, work := range user.works {
+		if company != "" && db.companies[companyID].name != company {
+			continue
+		}
+		if positionIndex != "" && work.positionIndex != positionIndex {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// indexIn reports whether index is one of indexes, or indexes is empty.
+func indexIn(index interface{}, indexes []string) bool {
+	if len(indexes) == 0 {
+		return true
+	}
+	token, _ := index.(string)
+	return slices.Contains(indexes, token)
+}
+
+func (r *userRepository) FullTextSearch(ctx context.Context, queryTerm models.UserFulltextSearch, logger *zap.Logger) ([]map[string]interface{}, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	term := strings.ToLower(queryTerm.Name)
+
+	var users []map[string]interface{}
+
+	for _, user := range r.db.sortedUsers() {
+		var score float64
+		for _, key := range []string{"first_name", "last_name", "first_name_eng", "last_name_eng"} {
+			name, ok := user.props[key].(string)
+			if !ok || name == "" {
+				continue
+			}
+			name = strings.ToLower(name)
+
+			switch {
+			case name == term:
+				score += 2
+			case queryTerm.Mode != "exact" && strings.Contains(name, term):
+				score++
+			}
+		}
+		if score == 0 {
+			continue
+		}
+
+		users = append(users, map[string]interface{}{
+			"user_id":      user.props["user_id"],
+			"fullname":     concat(user.props, "first_name", "last_name"),
+			"fullname_eng": concat(user.props, "first_name_eng", "last_name_eng"),
+			"score":        score,
+		})
+	}
+
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i]["score"].(float64) > users[j]["score"].(float64)
+	})
+	if len(users) > 10 {
+		users = users[:10]
+	}
+
+	return users, nil
+}
+
+func (r *userRepository) AddStudentInfo(ctx context.Context, id string, collegeInfo models.CollegeInfo, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	if user, ok := r.db.users[id]; ok {
+		r.db.department(collegeInfo.Faculty, collegeInfo.Department)
+		user.college = collegeInfo
+	}
+
+	return nil
+}
+
+func (r *userRepository) UpdateStudentInfo(ctx context.Context, id string, collegeInfo models.CollegeInfo, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[id]
+	if !ok {
+		logger.Warn("UserProfile not found", zap.String("userID", id))
+		return fiber.NewError(fiber.StatusNotFound, "UserProfile not found")
+	}
+
+	r.db.department(collegeInfo.Faculty, collegeInfo.Department)
+	user.college = collegeInfo
+	return nil
+}
+
+func (r *userRepository) DeleteStudentInfo(ctx context.Context, id string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[id]
+	if !ok {
+		logger.Warn("UserProfile not found", zap.String("userID", id))
+		return fiber.NewError(fiber.StatusNotFound, "UserProfile not found")
+	}
+
+	user.college = models.CollegeInfo{}
+	return nil
+}
+
+func (r *userRepository) UserExist(ctx context.Context, id string, logger *zap.Logger) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	_, ok := r.db.users[id]
+	return ok, nil
+}
+
+func (r *userRepository) UserVerify(ctx context.Context, id string, logger *zap.Logger) (bool, error) {
+	r.db.mu.RLock()
+	defer r.db.mu.RUnlock()
+
+	user, ok := r.db.users[id]
+	if !ok {
+		logger.Error("Error retrieving result", zap.String("user_id", id))
+		return false, fiber.NewError(http.StatusInternalServerError, "Error retrieving result")
+	}
+
+	verified, _ := user.props["is_verify"].(bool)
+	return verified, nil
+}
+
+func (r *userRepository) SetLanguage(ctx context.Context, userID, language string, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+
+	if language == "" {
+		delete(user.props, "language")
+	} else {
+		user.props["language"] = language
+	}
+	return nil
+}
+
+func (r *userRepository) SetPrivacy(ctx context.Context, userID string, settings models.PrivacySettings, logger *zap.Logger) error {
+	r.db.mu.Lock()
+	defer r.db.mu.Unlock()
+
+	user, ok := r.db.users[userID]
+	if !ok {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+
+	for group, visibility := range map[string]string{
+		models.PrivacyPersonal:  settings.Personal,
+		models.PrivacyContact:   settings.Contact,
+		models.PrivacyEducation: settings.Education,
+		models.PrivacyCareer:    settings.Career,
+		models.PrivacySalary:    settings.Salary,
+	} {
+		if visibility == "" {
+			delete(user.props, models.PrivacyProperty(group))
+		} else {
+			user.props[models.PrivacyProperty(group)] = visibility
+		}
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"alumni_api/internal/models"
 	"context"
+	"fmt"
 	"net/http"
 
 	"github.com/gofiber/fiber/v2"
@@ -27,18 +28,21 @@ func SendMessage(ctx context.Context, driver neo4j.DriverWithContext, msg models
           content: $content,
           created_timestamp: timestamp()
         })<-[:RECEIVED]-(r)
+        WITH s, m` + linkAttachment + `
         RETURN 
         m, s.username AS sender_username,
         s.first_name + " " + s.last_name AS sender_fullname,
         s.profile_picture AS sender_picture,
-        m.created_timestamp AS timestamp
+        m.created_timestamp AS timestamp,
+        ` + attachmentProjection + ` AS attachment
     `
 
 	params := map[string]interface{}{
-		"message_id": msg.MessageID,
-		"sender":     msg.SenderID,
-		"receiver":   msg.ReceiverID,
-		"content":    msg.Content.Raw,
+		"message_id":    msg.MessageID,
+		"sender":        msg.SenderID,
+		"receiver":      msg.ReceiverID,
+		"content":       msg.Content.Raw,
+		"attachment_id": msg.AttachmentID,
 	}
 
 	result, err := session.Run(ctx, query, params)
@@ -71,6 +75,10 @@ func SendMessage(ctx context.Context, driver neo4j.DriverWithContext, msg models
 		if timestamp, ok := record.Get("timestamp"); ok {
 			messageData["timestamp"] = timestamp
 		}
+
+		if attachment, ok := record.Get("attachment"); ok && attachment != nil {
+			messageData["attachment"] = attachment
+		}
 	}
 
 	return messageData, nil
@@ -97,21 +105,24 @@ func ReplyMessage(ctx context.Context, driver neo4j.DriverWithContext, msg model
         created_timestamp: timestamp()
       })<-[:RECEIVED]-(r),
       (m)-[:REPLIED]->(rm)
+    WITH s, m, rm` + linkAttachment + `
     RETURN
       m,
       rm.content AS reply_content,
       s.username AS sender_username,
       s.first_name + " " + s.last_name AS sender_fullname,
       s.profile_picture AS sender_picture,
-      m.created_timestamp AS timestamp
+      m.created_timestamp AS timestamp,
+      ` + attachmentProjection + ` AS attachment
   `
 
 	params := map[string]interface{}{
-		"message_id": msg.MessageID,
-		"reply_id":   msg.ReplyID,
-		"sender":     msg.SenderID,
-		"receiver":   msg.ReceiverID,
-		"content":    msg.Content.Raw,
+		"message_id":    msg.MessageID,
+		"reply_id":      msg.ReplyID,
+		"sender":        msg.SenderID,
+		"receiver":      msg.ReceiverID,
+		"content":       msg.Content.Raw,
+		"attachment_id": msg.AttachmentID,
 	}
 
 	result, err := session.Run(ctx, query, params)
@@ -149,6 +160,10 @@ func ReplyMessage(ctx context.Context, driver neo4j.DriverWithContext, msg model
 		if timestamp, ok := record.Get("timestamp"); ok {
 			messageData["timestamp"] = timestamp
 		}
+
+		if attachment, ok := record.Get("attachment"); ok && attachment != nil {
+			messageData["attachment"] = attachment
+		}
 	}
 
 	return messageData, nil
@@ -191,8 +206,6 @@ func DeleteMessage(ctx context.Context, driver neo4j.DriverWithContext, msg mode
 	})
 	defer session.Close(ctx)
 
-	msg.MessageID = uuid.New().String()
-
 	query := `
         MATCH (m:Message {message_id: $message_id})
         DETACH DELETE m
@@ -202,8 +215,10 @@ func DeleteMessage(ctx context.Context, driver neo4j.DriverWithContext, msg mode
 		"message_id": msg.MessageID,
 	}
 
-	_, err := session.Run(ctx, query, params)
-
+	result, err := session.Run(ctx, query, params)
+	if err == nil {
+		_, err = result.Consume(ctx)
+	}
 	if err != nil {
 		logger.Error("Failed to delete message", zap.Error(err))
 		return fiber.NewError(http.StatusInternalServerError, "Failed to delete message")
@@ -212,7 +227,7 @@ func DeleteMessage(ctx context.Context, driver neo4j.DriverWithContext, msg mode
 	return nil
 }
 
-func GetMessage(ctx context.Context, driver neo4j.DriverWithContext, sender_id, receiver_id string, logger *zap.Logger) (map[string]interface{}, error) {
+func GetMessage(ctx context.Context, driver neo4j.DriverWithContext, sender_id, receiver_id string, page models.Page, logger *zap.Logger) (map[string]interface{}, string, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeRead,
@@ -221,68 +236,197 @@ func GetMessage(ctx context.Context, driver neo4j.DriverWithContext, sender_id,
 
 	query := `
     MATCH (s:UserProfile)-[:SENT]->(m:Message)<-[:RECEIVED]-(r:UserProfile)
+    WHERE ((s.user_id = $sender_id AND r.user_id = $receiver_id)
+        OR (s.user_id = $receiver_id AND r.user_id = $sender_id))
+      AND ($cursor_ts IS NULL
+        OR m.created_timestamp < $cursor_ts
+        OR (m.created_timestamp = $cursor_ts AND m.message_id < $cursor_id))
     OPTIONAL MATCH (m)-[:REPLIED]->(rm:Message)  // Find the replied message (if exists)
-    WHERE (s.user_id = $sender_id AND r.user_id = $receiver_id)
-      OR (s.user_id = $receiver_id AND r.user_id = $sender_id)
-    WITH 
-      COLLECT(
-        CASE
-          WHEN s.user_id = $sender_id THEN {
-            id: s.user_id,
-            username: s.username,
-            name: s.first_name + " " + s.last_name,
-            picture: s.profile_picture,
-            message: {
-              message_id: m.message_id,
-              content: m.content,
-              created_timestamp: m.created_timestamp,
-              update_timestamp: m.updated_timestamp,
-              reply_message_id: rm.message_id,
-              reply_message_content: rm.content
-            }
-          }
-        END
-      ) AS me,
-      COLLECT(
-        CASE
-          WHEN s.user_id = $receiver_id THEN {
-            id: s.user_id,
-            username: s.username,
-            name: s.first_name + " " + s.last_name,
-            picture: s.profile_picture,
-            message: {
-              message_id: m.message_id,
-              content: m.content,
-              created_timestamp: m.created_timestamp,
-              update_timestamp: m.updated_timestamp,
-              reply_message_id: rm.message_id,
-              reply_message_content: rm.content
-            }
-          }
-        END
-      ) AS other
-    RETURN me, other
+    OPTIONAL MATCH (m)-[:HAS_ATTACHMENT]->(a:Attachment)
+    RETURN
+      s.user_id AS id,
+      s.username AS username,
+      s.first_name + " " + s.last_name AS name,
+      s.profile_picture AS picture,
+      {
+        message_id: m.message_id,
+        content: m.content,
+        created_timestamp: m.created_timestamp,
+        update_timestamp: m.updated_timestamp,
+        read_at: m.read_at,
+        reply_message_id: rm.message_id,
+        reply_message_content: rm.content,
+        attachment: ` + attachmentProjection + `
+      } AS message,
+      m.created_timestamp AS created_timestamp,
+      m.message_id AS message_id
+    ORDER BY created_timestamp DESC, message_id DESC
+    LIMIT $limit
     `
 
-	params := map[string]interface{}{
+	params := page.Params(map[string]interface{}{
 		"receiver_id": receiver_id,
 		"sender_id":   sender_id,
-	}
+	})
 
 	result, err := session.Run(ctx, query, params)
 
 	if err != nil {
 		logger.Error("Failed to send message", zap.Error(err))
-		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to send message")
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to send message")
 	}
 
-	record, err := result.Single(ctx)
+	records, err := result.Collect(ctx)
 	if err != nil {
 		logger.Error("Failed to collect results", zap.Error(err))
-		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
 	}
 
-	messageData := record.AsMap()
+	records, nextCursor := models.NextPage(records, page, recordCursor("created_timestamp", "message_id"))
 
-	return messageData, nil
+	me := []interface{}{}
+	other := []interface{}{}
+
+	for _, record := range records {
+		entry := record.AsMap()
+		delete(entry, "created_timestamp")
+		delete(entry, "message_id")
+
+		if entry["id"] == sender_id {
+			me = append(me, entry)
+		} else {
+			other = append(other, entry)
+		}
+	}
+
+	messageData := map[string]interface{}{
+		"me":    me,
+		"other": other,
+	}
+
+	return messageData, nextCursor, nil
+}
+
+// MarkRead sets read_at on the given message and on every earlier unread
+// message from the same sender to the reader.
+func MarkRead(ctx context.Context, driver neo4j.DriverWithContext, readerID, messageID string, logger *zap.Logger) (models.ReadReceipt, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (s:UserProfile)-[:SENT]->(target:Message {message_id: $message_id})<-[:RECEIVED]-(r:UserProfile {user_id: $reader_id})
+    WITH s, r, target, timestamp() AS now
+    OPTIONAL MATCH (s)-[:SENT]->(m:Message)<-[:RECEIVED]-(r)
+    WHERE m.read_at IS NULL AND m.created_timestamp <= target.created_timestamp
+    SET m.read_at = now
+    WITH s, now, m
+    ORDER BY m.created_timestamp
+    RETURN s.user_id AS sender_id, now AS read_at, collect(m.message_id) AS message_ids
+    `
+
+	params := map[string]interface{}{
+		"reader_id":  readerID,
+		"message_id": messageID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to mark message as read", zap.Error(err))
+		return models.ReadReceipt{}, fiber.NewError(http.StatusInternalServerError, "Failed to mark message as read")
+	}
+
+	if !result.Next(ctx) {
+		return models.ReadReceipt{}, fiber.NewError(http.StatusNotFound, fmt.Sprintf("Message: %s not found", messageID))
+	}
+	record := result.Record()
+
+	receipt := models.ReadReceipt{
+		MessageID:  messageID,
+		ReaderID:   readerID,
+		MessageIDs: []string{},
+	}
+
+	if senderID, ok := record.Get("sender_id"); ok {
+		receipt.SenderID, _ = senderID.(string)
+	}
+
+	if readAt, ok := record.Get("read_at"); ok {
+		receipt.ReadAt, _ = readAt.(int64)
+	}
+
+	if ids, ok := record.Get("message_ids"); ok {
+		for _, id := range ids.([]interface{}) {
+			receipt.MessageIDs = append(receipt.MessageIDs, id.(string))
+		}
+	}
+
+	return receipt, nil
+}
+
+// GetConversations lists everyone the user has exchanged messages with,
+// most recent conversation first, with the last message and how many of
+// the counterpart's messages are still unread.
+func GetConversations(ctx context.Context, driver neo4j.DriverWithContext, userID string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (me:UserProfile {user_id: $user_id})-[rel:SENT|RECEIVED]->(m:Message)<-[:SENT|RECEIVED]-(other:UserProfile)
+    WHERE other <> me
+    WITH other, m, type(rel) = "RECEIVED" AS incoming
+    ORDER BY m.created_timestamp DESC, m.message_id DESC
+    WITH other,
+      collect({message: m, incoming: incoming})[0] AS last,
+      sum(CASE WHEN incoming AND m.read_at IS NULL THEN 1 ELSE 0 END) AS unread_count
+    WITH other, last, unread_count, last.message.created_timestamp AS timestamp
+    WHERE $cursor_ts IS NULL
+      OR timestamp < $cursor_ts
+      OR (timestamp = $cursor_ts AND other.user_id < $cursor_id)
+    RETURN
+      other.user_id AS user_id,
+      other.username AS username,
+      other.first_name + " " + other.last_name AS name,
+      other.profile_picture AS picture,
+      {
+        message_id: last.message.message_id,
+        content: last.message.content,
+        sender_id: CASE WHEN last.incoming THEN other.user_id ELSE $user_id END,
+        read_at: last.message.read_at
+      } AS last_message,
+      timestamp,
+      unread_count
+    ORDER BY timestamp DESC, user_id DESC
+    LIMIT $limit
+    `
+
+	params := page.Params(map[string]interface{}{
+		"user_id": userID,
+	})
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to retrieve conversations", zap.Error(err))
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to retrieve conversations")
+	}
+
+	records, err := result.Collect(ctx)
+	if err != nil {
+		logger.Error("Failed to collect results", zap.Error(err))
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
+	}
+
+	records, nextCursor := models.NextPage(records, page, recordCursor("timestamp", "user_id"))
+
+	conversations := []map[string]interface{}{}
+	for _, record := range records {
+		conversations = append(conversations, record.AsMap())
+	}
+
+	return conversations, nextCursor, nil
 }
@@ -0,0 +1,242 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// runUserUpdate runs a write on a single UserProfile and reports a missing
+// user as 404.
+func runUserUpdate(ctx context.Context, driver neo4j.DriverWithContext, query string, params map[string]interface{}, action string, logger *zap.Logger) (map[string]interface{}, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to "+action, zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to "+action)
+	}
+
+	if !result.Next(ctx) {
+		return nil, fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s not found", params["user_id"]))
+	}
+
+	return result.Record().AsMap(), nil
+}
+
+// GetMFA returns the two-factor state of a user. The secrets are returned
+// encrypted, and only the number of remaining backup codes is exposed.
+func GetMFA(ctx context.Context, driver neo4j.DriverWithContext, userID string, logger *zap.Logger) (map[string]interface{}, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    RETURN
+      u.user_id AS user_id,
+      u.username AS username,
+      u.role AS role,
+      u.admit_year AS admit_year,
+      u.language AS language,
+      coalesce(u.mfa_enabled, false) AS mfa_enabled,
+      coalesce(u.mfa_required, false) AS mfa_required,
+      u.mfa_secret AS mfa_secret,
+      u.mfa_pending_secret AS mfa_pending_secret,
+      size(coalesce(u.mfa_backup_hashes, [])) AS backup_codes_remaining,
+      coalesce(u.mfa_failures, 0) AS mfa_failures,
+      coalesce(u.mfa_failed_timestamp, 0) AS mfa_failed_timestamp
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to retrieve two-factor settings", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve two-factor settings")
+	}
+
+	if !result.Next(ctx) {
+		return nil, fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+
+	return result.Record().AsMap(), nil
+}
+
+// SetPendingMFASecret stores a secret that only takes effect once a code
+// generated from it has been verified by EnableMFA.
+func SetPendingMFASecret(ctx context.Context, driver neo4j.DriverWithContext, userID string, secret []byte, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u.mfa_pending_secret = $secret
+    RETURN u.user_id AS user_id
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+		"secret":  secret,
+	}
+
+	_, err := runUserUpdate(ctx, driver, query, params, "save two-factor secret", logger)
+	return err
+}
+
+// EnableMFA promotes the pending secret, replaces the backup codes and
+// records step as used so the enrollment code cannot be replayed.
+func EnableMFA(ctx context.Context, driver neo4j.DriverWithContext, userID string, backupHashes []string, step int64, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    WHERE u.mfa_pending_secret IS NOT NULL
+    SET u.mfa_secret = u.mfa_pending_secret,
+        u.mfa_enabled = true,
+        u.mfa_backup_hashes = $backup_hashes,
+        u.mfa_last_step = $step,
+        u.mfa_failures = 0
+    REMOVE u.mfa_pending_secret
+    RETURN u.user_id AS user_id
+    `
+
+	params := map[string]interface{}{
+		"user_id":       userID,
+		"backup_hashes": backupHashes,
+		"step":          step,
+	}
+
+	_, err := runUserUpdate(ctx, driver, query, params, "enable two-factor authentication", logger)
+	return err
+}
+
+func DisableMFA(ctx context.Context, driver neo4j.DriverWithContext, userID string, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u.mfa_enabled = false
+    REMOVE u.mfa_secret, u.mfa_pending_secret, u.mfa_backup_hashes, u.mfa_last_step
+    RETURN u.user_id AS user_id
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+	}
+
+	_, err := runUserUpdate(ctx, driver, query, params, "disable two-factor authentication", logger)
+	return err
+}
+
+func SetBackupCodes(ctx context.Context, driver neo4j.DriverWithContext, userID string, backupHashes []string, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u.mfa_backup_hashes = $backup_hashes
+    RETURN u.user_id AS user_id
+    `
+
+	params := map[string]interface{}{
+		"user_id":       userID,
+		"backup_hashes": backupHashes,
+	}
+
+	_, err := runUserUpdate(ctx, driver, query, params, "save backup codes", logger)
+	return err
+}
+
+// UseTOTPStep marks a verified time step as used. It returns false when that
+// step or a later one was already used, which means the code is a replay.
+func UseTOTPStep(ctx context.Context, driver neo4j.DriverWithContext, userID string, step int64, logger *zap.Logger) (bool, error) {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    WITH u, coalesce(u.mfa_last_step, -1) < $step AS fresh
+    FOREACH (_ IN CASE WHEN fresh THEN [1] ELSE [] END |
+      SET u.mfa_last_step = $step, u.mfa_failures = 0
+    )
+    RETURN fresh
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+		"step":    step,
+	}
+
+	record, err := runUserUpdate(ctx, driver, query, params, "verify two-factor code", logger)
+	if err != nil {
+		return false, err
+	}
+
+	return record["fresh"] == true, nil
+}
+
+// UseBackupCode consumes a backup code, returning false when the user has no
+// unused code with that hash.
+func UseBackupCode(ctx context.Context, driver neo4j.DriverWithContext, userID, hash string, logger *zap.Logger) (bool, error) {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    WITH u, $hash IN coalesce(u.mfa_backup_hashes, []) AS found
+    FOREACH (_ IN CASE WHEN found THEN [1] ELSE [] END |
+      SET u.mfa_backup_hashes = [h IN u.mfa_backup_hashes WHERE h <> $hash], u.mfa_failures = 0
+    )
+    RETURN found
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+		"hash":    hash,
+	}
+
+	record, err := runUserUpdate(ctx, driver, query, params, "verify backup code", logger)
+	if err != nil {
+		return false, err
+	}
+
+	return record["found"] == true, nil
+}
+
+// RecordMFAFailure counts a wrong code. Failures older than window no
+// longer count.
+func RecordMFAFailure(ctx context.Context, driver neo4j.DriverWithContext, userID string, window time.Duration, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u.mfa_failures = CASE
+          WHEN coalesce(u.mfa_failed_timestamp, 0) < timestamp() - $window THEN 1
+          ELSE coalesce(u.mfa_failures, 0) + 1
+        END,
+        u.mfa_failed_timestamp = timestamp()
+    RETURN u.user_id AS user_id
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+		"window":  window.Milliseconds(),
+	}
+
+	_, err := runUserUpdate(ctx, driver, query, params, "record two-factor failure", logger)
+	return err
+}
+
+// SetMFARequired lets an admin require two-factor authentication on an
+// account. Accounts that are required but not enrolled enroll at next login.
+func SetMFARequired(ctx context.Context, driver neo4j.DriverWithContext, userID string, required bool, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u.mfa_required = $required
+    RETURN u.user_id AS user_id
+    `
+
+	params := map[string]interface{}{
+		"user_id":  userID,
+		"required": required,
+	}
+
+	_, err := runUserUpdate(ctx, driver, query, params, "update two-factor requirement", logger)
+	return err
+}
@@ -0,0 +1,632 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/services"
+	"context"
+	"time"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// NewNeo4jStore returns a Store whose repositories run the Cypher queries in
+// this package against the given driver.
+func NewNeo4jStore(driver neo4j.DriverWithContext) *Store {
+	return &Store{
+		User:         &neo4jUserRepository{driver: driver},
+		Post:         &neo4jPostRepository{driver: driver},
+		Message:      &neo4jMessageRepository{driver: driver},
+		Conversation: &neo4jConversationRepository{driver: driver},
+		Attachment:   &neo4jAttachmentRepository{driver: driver},
+		Friend:       &neo4jFriendRepository{driver: driver},
+		Company:      &neo4jCompanyRepository{driver: driver},
+		Auth:         &neo4jAuthRepository{driver: driver},
+		Session:      &neo4jSessionRepository{driver: driver},
+		MFA:          &neo4jMFARepository{driver: driver},
+		Identity:     &neo4jIdentityRepository{driver: driver},
+		Lockout:      &neo4jLockoutRepository{driver: driver},
+		Role:         &neo4jRoleRepository{driver: driver},
+		Department:   &neo4jDepartmentRepository{driver: driver},
+		Statistic:    &neo4jStatisticRepository{driver: driver},
+		Report:       &neo4jReportRepository{driver: driver},
+		Outbox:       &neo4jOutboxRepository{driver: driver},
+		Encrypted:    &neo4jEncryptedRepository{driver: driver},
+	}
+}
+
+type neo4jUserRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jUserRepository) GetAllUser(ctx context.Context, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	return GetAllUser(ctx, r.driver, page, logger)
+}
+
+func (r *neo4jUserRepository) CreateProfile(ctx context.Context, user models.CreateProfileRequest, logger *zap.Logger) (map[string]interface{}, error) {
+	return CreateProfile(ctx, r.driver, user, logger)
+}
+
+func (r *neo4jUserRepository) FetchUserByID(ctx context.Context, id string, logger *zap.Logger) (map[string]interface{}, error) {
+	return FetchUserByID(ctx, r.driver, id, logger)
+}
+
+func (r *neo4jUserRepository) UpdateUserByID(ctx context.Context, id string, updatedData models.UpdateUserProfileRequest, logger *zap.Logger) (map[string]interface{}, error) {
+	return UpdateUserByID(ctx, r.driver, id, updatedData, logger)
+}
+
+func (r *neo4jUserRepository) DeleteUserByID(ctx context.Context, userID string, logger *zap.Logger) error {
+	return DeleteUserByID(ctx, r.driver, userID, logger)
+}
+
+func (r *neo4jUserRepository) FetchUserByFilter(ctx context.Context, filter models.UserRequestFilter, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return FetchUserByFilter(ctx, r.driver, filter, logger)
+}
+
+func (r *neo4jUserRepository) FullTextSearch(ctx context.Context, queryTerm models.UserFulltextSearch, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return FullTextSeach(ctx, r.driver, queryTerm, logger)
+}
+
+func (r *neo4jUserRepository) AddStudentInfo(ctx context.Context, id string, collegeInfo models.CollegeInfo, logger *zap.Logger) error {
+	return AddStudentInfo(ctx, r.driver, id, collegeInfo, logger)
+}
+
+func (r *neo4jUserRepository) UpdateStudentInfo(ctx context.Context, id string, collegeInfo models.CollegeInfo, logger *zap.Logger) error {
+	return UpdateStudentInfo(ctx, r.driver, id, collegeInfo, logger)
+}
+
+func (r *neo4jUserRepository) DeleteStudentInfo(ctx context.Context, id string, logger *zap.Logger) error {
+	return DeleteStudentInfo(ctx, r.driver, id, logger)
+}
+
+func (r *neo4jUserRepository) UserExist(ctx context.Context, id string, logger *zap.Logger) (bool, error) {
+	return services.UserExist(ctx, r.driver, id, logger)
+}
+
+func (r *neo4jUserRepository) UserVerify(ctx context.Context, id string, logger *zap.Logger) (bool, error) {
+	return services.UserVerify(ctx, r.driver, id, logger)
+}
+
+func (r *neo4jUserRepository) SetLanguage(ctx context.Context, userID, language string, logger *zap.Logger) error {
+	return SetLanguage(ctx, r.driver, userID, language, logger)
+}
+
+func (r *neo4jUserRepository) SetPrivacy(ctx context.Context, userID string, settings models.PrivacySettings, logger *zap.Logger) error {
+	return SetPrivacy(ctx, r.driver, userID, settings, logger)
+}
+
+type neo4jPostRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jPostRepository) GetAllPosts(ctx context.Context, userID string, visibility []string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	return GetAllPosts(ctx, r.driver, userID, visibility, page, logger)
+}
+
+func (r *neo4jPostRepository) GetFeedCandidates(ctx context.Context, userID string, visibility []string, window int, logger *zap.Logger) ([]models.FeedCandidate, error) {
+	return GetFeedCandidates(ctx, r.driver, userID, visibility, window, logger)
+}
+
+func (r *neo4jPostRepository) PostVisible(ctx context.Context, postID, userID string, visibility []string, logger *zap.Logger) (bool, error) {
+	return services.PostVisible(ctx, r.driver, postID, userID, visibility, logger)
+}
+
+func (r *neo4jPostRepository) GetPostByID(ctx context.Context, postID, userID string, logger *zap.Logger) (map[string]interface{}, error) {
+	return GetPostByID(ctx, r.driver, postID, userID, logger)
+}
+
+func (r *neo4jPostRepository) CreatePost(ctx context.Context, userID string, post models.Post, logger *zap.Logger) (map[string]interface{}, error) {
+	return CreatePost(ctx, r.driver, userID, post, logger)
+}
+
+func (r *neo4jPostRepository) UpdatePostByID(ctx context.Context, postID string, updatedData models.UpdatePostRequest, logger *zap.Logger) error {
+	return UpdatePostByID(ctx, r.driver, postID, updatedData, logger)
+}
+
+func (r *neo4jPostRepository) DeletePostByID(ctx context.Context, postID string, logger *zap.Logger) error {
+	return DeletePostByID(ctx, r.driver, postID, logger)
+}
+
+func (r *neo4jPostRepository) LikePost(ctx context.Context, userID, postID string, logger *zap.Logger) error {
+	return LikePost(ctx, r.driver, userID, postID, logger)
+}
+
+func (r *neo4jPostRepository) UnlikePost(ctx context.Context, userID, postID string, logger *zap.Logger) error {
+	return UnlikePost(ctx, r.driver, userID, postID, logger)
+}
+
+func (r *neo4jPostRepository) GetCommentByPostID(ctx context.Context, postID, userID string, page models.Page, logger *zap.Logger) ([]models.Comment, string, error) {
+	return GetCommentByPostID(ctx, r.driver, postID, userID, page, logger)
+}
+
+func (r *neo4jPostRepository) CommentPost(ctx context.Context, userID, postID, comment string, logger *zap.Logger) (map[string]interface{}, error) {
+	return CommentPost(ctx, r.driver, userID, postID, comment, logger)
+}
+
+func (r *neo4jPostRepository) ReplyComment(ctx context.Context, userID, commentID, comment string, logger *zap.Logger) (map[string]interface{}, error) {
+	return ReplyComment(ctx, r.driver, userID, commentID, comment, logger)
+}
+
+func (r *neo4jPostRepository) UpdateCommentPost(ctx context.Context, commentID, comment string, logger *zap.Logger) error {
+	return UpdateCommentPost(ctx, r.driver, commentID, comment, logger)
+}
+
+func (r *neo4jPostRepository) DeleteCommentPost(ctx context.Context, commentID string, logger *zap.Logger) error {
+	return DeleteCommentPost(ctx, r.driver, commentID, logger)
+}
+
+func (r *neo4jPostRepository) LikeComment(ctx context.Context, userID, commentID string, logger *zap.Logger) error {
+	return LikeComment(ctx, r.driver, userID, commentID, logger)
+}
+
+func (r *neo4jPostRepository) UnlikeComment(ctx context.Context, userID, commentID string, logger *zap.Logger) error {
+	return UnlikeComment(ctx, r.driver, userID, commentID, logger)
+}
+
+func (r *neo4jPostRepository) AddView(ctx context.Context, userID, postID string, logger *zap.Logger) error {
+	return services.AddView(ctx, r.driver, userID, postID, logger)
+}
+
+func (r *neo4jPostRepository) GetAuthorUserID(ctx context.Context, postID string, logger *zap.Logger) (string, error) {
+	return services.GetAuthorUserID(ctx, r.driver, postID, logger)
+}
+
+func (r *neo4jPostRepository) GetCommentUserID(ctx context.Context, commentID string, logger *zap.Logger) (string, error) {
+	return services.GetCommentUserID(ctx, r.driver, commentID, logger)
+}
+
+type neo4jMessageRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jMessageRepository) SendMessage(ctx context.Context, msg models.Message, logger *zap.Logger) (map[string]interface{}, error) {
+	return SendMessage(ctx, r.driver, msg, logger)
+}
+
+func (r *neo4jMessageRepository) ReplyMessage(ctx context.Context, msg models.ReplyMessage, logger *zap.Logger) (map[string]interface{}, error) {
+	return ReplyMessage(ctx, r.driver, msg, logger)
+}
+
+func (r *neo4jMessageRepository) EditMessage(ctx context.Context, msg models.EditMessage, logger *zap.Logger) error {
+	return EditMessage(ctx, r.driver, msg, logger)
+}
+
+func (r *neo4jMessageRepository) DeleteMessage(ctx context.Context, msg models.DeleteMessage, logger *zap.Logger) error {
+	return DeleteMessage(ctx, r.driver, msg, logger)
+}
+
+func (r *neo4jMessageRepository) GetMessage(ctx context.Context, senderID, receiverID string, page models.Page, logger *zap.Logger) (map[string]interface{}, string, error) {
+	return GetMessage(ctx, r.driver, senderID, receiverID, page, logger)
+}
+
+func (r *neo4jMessageRepository) MarkRead(ctx context.Context, readerID, messageID string, logger *zap.Logger) (models.ReadReceipt, error) {
+	return MarkRead(ctx, r.driver, readerID, messageID, logger)
+}
+
+func (r *neo4jMessageRepository) GetConversations(ctx context.Context, userID string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	return GetConversations(ctx, r.driver, userID, page, logger)
+}
+
+type neo4jConversationRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jConversationRepository) CreateConversation(ctx context.Context, ownerID string, conv models.Conversation, logger *zap.Logger) (map[string]interface{}, error) {
+	return CreateConversation(ctx, r.driver, ownerID, conv, logger)
+}
+
+func (r *neo4jConversationRepository) GetConversationByID(ctx context.Context, conversationID string, logger *zap.Logger) (map[string]interface{}, error) {
+	return GetConversationByID(ctx, r.driver, conversationID, logger)
+}
+
+func (r *neo4jConversationRepository) UpdateConversation(ctx context.Context, conversationID, title string, logger *zap.Logger) error {
+	return UpdateConversation(ctx, r.driver, conversationID, title, logger)
+}
+
+func (r *neo4jConversationRepository) GetMemberRole(ctx context.Context, conversationID, userID string, logger *zap.Logger) (string, error) {
+	return GetMemberRole(ctx, r.driver, conversationID, userID, logger)
+}
+
+func (r *neo4jConversationRepository) GetMemberIDs(ctx context.Context, conversationID string, logger *zap.Logger) ([]string, error) {
+	return GetMemberIDs(ctx, r.driver, conversationID, logger)
+}
+
+func (r *neo4jConversationRepository) ShareConversation(ctx context.Context, userID1, userID2 string, logger *zap.Logger) (bool, error) {
+	return ShareConversation(ctx, r.driver, userID1, userID2, logger)
+}
+
+func (r *neo4jConversationRepository) AddMembers(ctx context.Context, conversationID string, userIDs []string, logger *zap.Logger) error {
+	return AddMembers(ctx, r.driver, conversationID, userIDs, logger)
+}
+
+func (r *neo4jConversationRepository) SetMemberRole(ctx context.Context, conversationID, userID, role string, logger *zap.Logger) error {
+	return SetMemberRole(ctx, r.driver, conversationID, userID, role, logger)
+}
+
+func (r *neo4jConversationRepository) RemoveMember(ctx context.Context, conversationID, userID string, logger *zap.Logger) error {
+	return RemoveMember(ctx, r.driver, conversationID, userID, logger)
+}
+
+func (r *neo4jConversationRepository) GetEventConversationID(ctx context.Context, postID string, logger *zap.Logger) (string, error) {
+	return GetEventConversationID(ctx, r.driver, postID, logger)
+}
+
+func (r *neo4jConversationRepository) SendGroupMessage(ctx context.Context, msg models.GroupMessage, logger *zap.Logger) (map[string]interface{}, error) {
+	return SendGroupMessage(ctx, r.driver, msg, logger)
+}
+
+func (r *neo4jConversationRepository) GetGroupMessages(ctx context.Context, conversationID string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	return GetGroupMessages(ctx, r.driver, conversationID, page, logger)
+}
+
+type neo4jAttachmentRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jAttachmentRepository) CreateAttachment(ctx context.Context, attachment models.Attachment, logger *zap.Logger) (map[string]interface{}, error) {
+	return CreateAttachment(ctx, r.driver, attachment, logger)
+}
+
+func (r *neo4jAttachmentRepository) GetAttachment(ctx context.Context, attachmentID string, logger *zap.Logger) (map[string]interface{}, error) {
+	return GetAttachment(ctx, r.driver, attachmentID, logger)
+}
+
+func (r *neo4jAttachmentRepository) CanAccessAttachment(ctx context.Context, attachmentID, userID string, logger *zap.Logger) (bool, error) {
+	return CanAccessAttachment(ctx, r.driver, attachmentID, userID, logger)
+}
+
+type neo4jSessionRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jSessionRepository) CreateSession(ctx context.Context, session models.Session, logger *zap.Logger) error {
+	return CreateSession(ctx, r.driver, session, logger)
+}
+
+func (r *neo4jSessionRepository) RotateSession(ctx context.Context, sessionID, refreshHash string, next models.Session, logger *zap.Logger) (models.LoginResponse, error) {
+	return RotateSession(ctx, r.driver, sessionID, refreshHash, next, logger)
+}
+
+func (r *neo4jSessionRepository) GetSessions(ctx context.Context, userID string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetSessions(ctx, r.driver, userID, logger)
+}
+
+func (r *neo4jSessionRepository) RevokeSession(ctx context.Context, userID, sessionID string, logger *zap.Logger) error {
+	return RevokeSession(ctx, r.driver, userID, sessionID, logger)
+}
+
+func (r *neo4jSessionRepository) RevokeAllSessions(ctx context.Context, userID string, logger *zap.Logger) error {
+	return RevokeAllSessions(ctx, r.driver, userID, logger)
+}
+
+func (r *neo4jSessionRepository) IsSessionActive(ctx context.Context, userID, sessionID string, logger *zap.Logger) (bool, error) {
+	return IsSessionActive(ctx, r.driver, userID, sessionID, logger)
+}
+
+type neo4jIdentityRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jIdentityRepository) SignInWithIdentity(ctx context.Context, identity models.Identity, logger *zap.Logger) (models.LoginResponse, error) {
+	return SignInWithIdentity(ctx, r.driver, identity, logger)
+}
+
+type neo4jRoleRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jRoleRepository) SyncBuiltInRoles(ctx context.Context, roles []models.Role, logger *zap.Logger) error {
+	return SyncBuiltInRoles(ctx, r.driver, roles, logger)
+}
+
+func (r *neo4jRoleRepository) GetRoles(ctx context.Context, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetRoles(ctx, r.driver, logger)
+}
+
+func (r *neo4jRoleRepository) SaveRole(ctx context.Context, role models.Role, logger *zap.Logger) error {
+	return SaveRole(ctx, r.driver, role, logger)
+}
+
+func (r *neo4jRoleRepository) DeleteRole(ctx context.Context, name string, logger *zap.Logger) error {
+	return DeleteRole(ctx, r.driver, name, logger)
+}
+
+func (r *neo4jRoleRepository) AssignRole(ctx context.Context, userID, name string, logger *zap.Logger) error {
+	return AssignRole(ctx, r.driver, userID, name, logger)
+}
+
+func (r *neo4jRoleRepository) RevokeRole(ctx context.Context, userID, name string, logger *zap.Logger) error {
+	return RevokeRole(ctx, r.driver, userID, name, logger)
+}
+
+func (r *neo4jRoleRepository) GetPermissions(ctx context.Context, userID string, logger *zap.Logger) ([]string, error) {
+	return GetPermissions(ctx, r.driver, userID, logger)
+}
+
+type neo4jDepartmentRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jDepartmentRepository) AssignDepartmentIDs(ctx context.Context, logger *zap.Logger) error {
+	return AssignDepartmentIDs(ctx, r.driver, logger)
+}
+
+func (r *neo4jDepartmentRepository) GetDepartments(ctx context.Context, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetDepartments(ctx, r.driver, logger)
+}
+
+func (r *neo4jDepartmentRepository) GetAdministeredDepartment(ctx context.Context, userID string, logger *zap.Logger) (string, error) {
+	return GetAdministeredDepartment(ctx, r.driver, userID, logger)
+}
+
+func (r *neo4jDepartmentRepository) AppointDepartmentAdmin(ctx context.Context, departmentID, userID string, logger *zap.Logger) error {
+	return AppointDepartmentAdmin(ctx, r.driver, departmentID, userID, logger)
+}
+
+func (r *neo4jDepartmentRepository) RemoveDepartmentAdmin(ctx context.Context, departmentID, userID string, logger *zap.Logger) error {
+	return RemoveDepartmentAdmin(ctx, r.driver, departmentID, userID, logger)
+}
+
+type neo4jLockoutRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jLockoutRepository) GetLoginThrottle(ctx context.Context, userID string, logger *zap.Logger) (models.LoginThrottle, error) {
+	return GetLoginThrottle(ctx, r.driver, userID, logger)
+}
+
+func (r *neo4jLockoutRepository) RecordLoginFailure(ctx context.Context, userID string, window time.Duration, event models.SecurityEvent, logger *zap.Logger) (models.LoginThrottle, error) {
+	return RecordLoginFailure(ctx, r.driver, userID, window, event, logger)
+}
+
+func (r *neo4jLockoutRepository) LockAccount(ctx context.Context, userID string, until int64, event models.SecurityEvent, logger *zap.Logger) (models.Mail, error) {
+	return LockAccount(ctx, r.driver, userID, until, event, logger)
+}
+
+func (r *neo4jLockoutRepository) UnlockAccount(ctx context.Context, userID string, event models.SecurityEvent, logger *zap.Logger) error {
+	return UnlockAccount(ctx, r.driver, userID, event, logger)
+}
+
+func (r *neo4jLockoutRepository) RecordSecurityEvent(ctx context.Context, userID string, event models.SecurityEvent, logger *zap.Logger) error {
+	return RecordSecurityEvent(ctx, r.driver, userID, event, logger)
+}
+
+func (r *neo4jLockoutRepository) ResetLoginFailures(ctx context.Context, userID string, logger *zap.Logger) error {
+	return ResetLoginFailures(ctx, r.driver, userID, logger)
+}
+
+func (r *neo4jLockoutRepository) GetSecurityEvents(ctx context.Context, userID string, limit int, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetSecurityEvents(ctx, r.driver, userID, limit, logger)
+}
+
+type neo4jMFARepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jMFARepository) GetMFA(ctx context.Context, userID string, logger *zap.Logger) (map[string]interface{}, error) {
+	return GetMFA(ctx, r.driver, userID, logger)
+}
+
+func (r *neo4jMFARepository) SetPendingMFASecret(ctx context.Context, userID string, secret []byte, logger *zap.Logger) error {
+	return SetPendingMFASecret(ctx, r.driver, userID, secret, logger)
+}
+
+func (r *neo4jMFARepository) EnableMFA(ctx context.Context, userID string, backupHashes []string, step int64, logger *zap.Logger) error {
+	return EnableMFA(ctx, r.driver, userID, backupHashes, step, logger)
+}
+
+func (r *neo4jMFARepository) DisableMFA(ctx context.Context, userID string, logger *zap.Logger) error {
+	return DisableMFA(ctx, r.driver, userID, logger)
+}
+
+func (r *neo4jMFARepository) SetBackupCodes(ctx context.Context, userID string, backupHashes []string, logger *zap.Logger) error {
+	return SetBackupCodes(ctx, r.driver, userID, backupHashes, logger)
+}
+
+func (r *neo4jMFARepository) UseTOTPStep(ctx context.Context, userID string, step int64, logger *zap.Logger) (bool, error) {
+	return UseTOTPStep(ctx, r.driver, userID, step, logger)
+}
+
+func (r *neo4jMFARepository) UseBackupCode(ctx context.Context, userID, hash string, logger *zap.Logger) (bool, error) {
+	return UseBackupCode(ctx, r.driver, userID, hash, logger)
+}
+
+func (r *neo4jMFARepository) RecordMFAFailure(ctx context.Context, userID string, window time.Duration, logger *zap.Logger) error {
+	return RecordMFAFailure(ctx, r.driver, userID, window, logger)
+}
+
+func (r *neo4jMFARepository) SetMFARequired(ctx context.Context, userID string, required bool, logger *zap.Logger) error {
+	return SetMFARequired(ctx, r.driver, userID, required, logger)
+}
+
+type neo4jFriendRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jFriendRepository) GetUserFriendByID(ctx context.Context, id string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetUserFriendByID(ctx, r.driver, id, logger)
+}
+
+func (r *neo4jFriendRepository) AddFriend(ctx context.Context, userID1, userID2 string, logger *zap.Logger) error {
+	return AddFriend(ctx, r.driver, userID1, userID2, logger)
+}
+
+func (r *neo4jFriendRepository) Unfriend(ctx context.Context, userID1, userID2 string, logger *zap.Logger) error {
+	return Unfriend(ctx, r.driver, userID1, userID2, logger)
+}
+
+func (r *neo4jFriendRepository) GetFOAF(ctx context.Context, userID, otherID string, degree int, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetFOAF(ctx, r.driver, userID, otherID, degree, logger)
+}
+
+type neo4jCompanyRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jCompanyRepository) CompanyFullTextSearch(ctx context.Context, queryTerm string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return CompanyFullTextSearch(ctx, r.driver, queryTerm, logger)
+}
+
+func (r *neo4jCompanyRepository) AddUserCompany(ctx context.Context, id string, companies models.UserRequestCompany, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return AddUserCompany(ctx, r.driver, id, companies, logger)
+}
+
+func (r *neo4jCompanyRepository) UpdateUserCompany(ctx context.Context, userID, companyID string, company models.UserCompanyUpdateRequest, logger *zap.Logger) error {
+	return UpdateUserCompany(ctx, r.driver, userID, companyID, company, logger)
+}
+
+func (r *neo4jCompanyRepository) DeleteUserCompany(ctx context.Context, userID, companyID string, logger *zap.Logger) error {
+	return DeleteUserCompany(ctx, r.driver, userID, companyID, logger)
+}
+
+func (r *neo4jCompanyRepository) FindCompanyAssociate(ctx context.Context, company models.Company, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return FindCompanyAssociate(ctx, r.driver, company, logger)
+}
+
+type neo4jAuthRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jAuthRepository) Login(ctx context.Context, username string, logger *zap.Logger) (models.LoginResponse, error) {
+	return Login(ctx, r.driver, username, logger)
+}
+
+func (r *neo4jAuthRepository) RegistryUser(ctx context.Context, user models.RegistryRequest, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
+	return RegistryUser(ctx, r.driver, user, logger)
+}
+
+func (r *neo4jAuthRepository) RegistryAlumnus(ctx context.Context, user models.RegistryOneTimeRequest, email string, logger *zap.Logger) (map[string]interface{}, error) {
+	return RegistryAlumnus(ctx, r.driver, user, email, logger)
+}
+
+func (r *neo4jAuthRepository) VerifyAccount(ctx context.Context, userID, token string, logger *zap.Logger) (map[string]interface{}, error) {
+	return VerifyAccount(ctx, r.driver, userID, token, logger)
+}
+
+func (r *neo4jAuthRepository) RequestChangePassword(ctx context.Context, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
+	return RequestChangePassword(ctx, r.driver, email, logger)
+}
+
+func (r *neo4jAuthRepository) ChangePassword(ctx context.Context, userID, password, token string, logger *zap.Logger) error {
+	return ChangePassword(ctx, r.driver, userID, password, token, logger)
+}
+
+func (r *neo4jAuthRepository) RequestChangeMail(ctx context.Context, userID, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
+	return RequestChangeMail(ctx, r.driver, userID, email, logger)
+}
+
+func (r *neo4jAuthRepository) VerifyEmail(ctx context.Context, userID, email, token string, logger *zap.Logger) error {
+	return VerifyEmail(ctx, r.driver, userID, email, token, logger)
+}
+
+func (r *neo4jAuthRepository) RequestAlumniOneTimeRegistry(ctx context.Context, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error) {
+	return RequestAlumniOneTimeRegistry(ctx, r.driver, email, logger)
+}
+
+func (r *neo4jAuthRepository) ApproveAlumnusRole(ctx context.Context, requestID, departmentID string, logger *zap.Logger) error {
+	return ApproveAlumnusRole(ctx, r.driver, requestID, departmentID, logger)
+}
+
+func (r *neo4jAuthRepository) RejectAlumnusRole(ctx context.Context, requestID, departmentID string, logger *zap.Logger) error {
+	return RejectAlumnusRole(ctx, r.driver, requestID, departmentID, logger)
+}
+
+func (r *neo4jAuthRepository) RequestAlumnusRole(ctx context.Context, userID string, logger *zap.Logger) error {
+	return RequestAlumnusRole(ctx, r.driver, userID, logger)
+}
+
+func (r *neo4jAuthRepository) GetAllRequest(ctx context.Context, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetAllRequest(ctx, r.driver, departmentID, logger)
+}
+
+func (r *neo4jAuthRepository) UsernameVerify(ctx context.Context, username string, logger *zap.Logger) (bool, error) {
+	return services.UsernameVerify(ctx, r.driver, username, logger)
+}
+
+func (r *neo4jAuthRepository) EmailExist(ctx context.Context, email string, logger *zap.Logger) (bool, error) {
+	return services.EmailExist(ctx, r.driver, email, logger)
+}
+
+func (r *neo4jAuthRepository) IsRequestApproved(ctx context.Context, userID string, logger *zap.Logger) (bool, error) {
+	return services.IsRequestApproved(ctx, r.driver, userID, logger)
+}
+
+type neo4jStatisticRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jStatisticRepository) GetPostStat(ctx context.Context, visibility []string, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetPostStat(ctx, r.driver, visibility, departmentID, logger)
+}
+
+func (r *neo4jStatisticRepository) GetActivityStat(ctx context.Context, visibility []string, logger *zap.Logger) (map[string]interface{}, error) {
+	return GetActivityStat(ctx, r.driver, visibility, logger)
+}
+
+func (r *neo4jStatisticRepository) GetRegistryStat(ctx context.Context, departmentID string, logger *zap.Logger) (map[string]interface{}, error) {
+	return GetRegistryStat(ctx, r.driver, departmentID, logger)
+}
+
+func (r *neo4jStatisticRepository) GetGenerationSTStat(ctx context.Context, generation []string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetGenerationSTStat(ctx, r.driver, generation, logger)
+}
+
+func (r *neo4jStatisticRepository) GetUserSalary(ctx context.Context, visibility []string, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetUserSalary(ctx, r.driver, visibility, departmentID, logger)
+}
+
+func (r *neo4jStatisticRepository) GetUserJob(ctx context.Context, visibility []string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	return GetUserJob(ctx, r.driver, visibility, logger)
+}
+
+type neo4jReportRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jReportRepository) FetchReport(ctx context.Context, page models.Page, departmentID string, logger *zap.Logger) ([]map[string]interface{}, string, error) {
+	return FetchReport(ctx, r.driver, page, departmentID, logger)
+}
+
+func (r *neo4jReportRepository) Report(ctx context.Context, report models.Report, logger *zap.Logger) error {
+	return Report(ctx, r.driver, report, logger)
+}
+
+type neo4jOutboxRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jOutboxRepository) EnqueueMail(ctx context.Context, mail models.Mail, logger *zap.Logger) error {
+	return EnqueueMail(ctx, r.driver, mail, logger)
+}
+
+func (r *neo4jOutboxRepository) ClaimMails(ctx context.Context, limit int, lease time.Duration, logger *zap.Logger) ([]models.Mail, error) {
+	return ClaimMails(ctx, r.driver, limit, lease, logger)
+}
+
+func (r *neo4jOutboxRepository) MarkMailSent(ctx context.Context, mailID string, logger *zap.Logger) error {
+	return MarkMailSent(ctx, r.driver, mailID, logger)
+}
+
+func (r *neo4jOutboxRepository) MarkMailFailed(ctx context.Context, mailID, lastError string, nextAttempt int64, dead bool, logger *zap.Logger) error {
+	return MarkMailFailed(ctx, r.driver, mailID, lastError, nextAttempt, dead, logger)
+}
+
+func (r *neo4jOutboxRepository) GetDeadLetters(ctx context.Context, page models.Page, logger *zap.Logger) ([]models.Mail, string, error) {
+	return GetDeadLetters(ctx, r.driver, page, logger)
+}
+
+func (r *neo4jOutboxRepository) RetryDeadLetter(ctx context.Context, mailID string, logger *zap.Logger) error {
+	return RetryDeadLetter(ctx, r.driver, mailID, logger)
+}
+
+type neo4jEncryptedRepository struct {
+	driver neo4j.DriverWithContext
+}
+
+func (r *neo4jEncryptedRepository) GetEncryptedValues(ctx context.Context, prop models.EncryptedProperty, after string, limit int, logger *zap.Logger) ([]models.EncryptedValue, error) {
+	return GetEncryptedValues(ctx, r.driver, prop, after, limit, logger)
+}
+
+func (r *neo4jEncryptedRepository) ReplaceEncryptedValues(ctx context.Context, prop models.EncryptedProperty, changes []models.EncryptedChange, logger *zap.Logger) (int, error) {
+	return ReplaceEncryptedValues(ctx, r.driver, prop, changes, logger)
+}
@@ -0,0 +1,230 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// decodeMail turns the properties of an OutboxMail into a Mail. Data is kept
+// as a JSON string, since Neo4j properties cannot hold maps.
+func decodeMail(props map[string]interface{}, logger *zap.Logger) (models.Mail, error) {
+	var mail models.Mail
+	if err := utils.MapToStruct(props, &mail); err != nil {
+		logger.Error("Failed to decode mail", zap.Error(err))
+		return models.Mail{}, fiber.NewError(http.StatusInternalServerError, "Error decoding mail")
+	}
+
+	if data, ok := props["data"].(string); ok {
+		if err := json.Unmarshal([]byte(data), &mail.Data); err != nil {
+			logger.Error("Failed to decode mail data", zap.String("mail_id", mail.MailID), zap.Error(err))
+			return models.Mail{}, fiber.NewError(http.StatusInternalServerError, "Error decoding mail")
+		}
+	}
+
+	return mail, nil
+}
+
+func decodeMails(records []map[string]interface{}, logger *zap.Logger) ([]models.Mail, error) {
+	mails := []models.Mail{}
+	for _, record := range records {
+		props, _ := record["mail"].(map[string]interface{})
+		mail, err := decodeMail(props, logger)
+		if err != nil {
+			return nil, err
+		}
+		mails = append(mails, mail)
+	}
+	return mails, nil
+}
+
+// EnqueueMail puts a mail in the outbox, due at once.
+func EnqueueMail(ctx context.Context, driver neo4j.DriverWithContext, mail models.Mail, logger *zap.Logger) error {
+	data, err := json.Marshal(mail.Data)
+	if err != nil {
+		logger.Error("Failed to encode mail data", zap.Error(err))
+		return fiber.NewError(http.StatusInternalServerError, "Failed to enqueue mail")
+	}
+
+	query := `
+    CREATE (m:OutboxMail {
+      mail_id: $mail_id,
+      to: $to,
+      template: $template,
+      language: $language,
+      data: $data,
+      status: $status,
+      attempts: 0,
+      next_attempt_timestamp: timestamp(),
+      created_timestamp: timestamp()
+    })
+    RETURN m.mail_id AS mail_id
+    `
+
+	params := map[string]interface{}{
+		"mail_id":  uuid.New().String(),
+		"to":       mail.To,
+		"template": mail.Template,
+		"language": mail.Language,
+		"data":     string(data),
+		"status":   models.MailPending,
+	}
+
+	_, err = runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "enqueue mail", logger)
+	return err
+}
+
+// ClaimMails takes up to limit due mails for sending. Each one counts as an
+// attempt and is not due again until lease has passed, so a worker that dies
+// while sending does not lose it and others do not send it twice meanwhile.
+func ClaimMails(ctx context.Context, driver neo4j.DriverWithContext, limit int, lease time.Duration, logger *zap.Logger) ([]models.Mail, error) {
+	// The first SET takes the write lock on each candidate, and the mail is
+	// checked again under it: another worker may have claimed or sent it
+	// between the match and the lock, and must not send it twice.
+	query := `
+    MATCH (m:OutboxMail {status: $status})
+    WHERE m.next_attempt_timestamp <= timestamp()
+    WITH m
+    ORDER BY m.next_attempt_timestamp, m.mail_id
+    LIMIT $limit
+    SET m._lock = true
+    WITH m, m.status = $status AND m.next_attempt_timestamp <= timestamp() AS due
+    FOREACH (_ IN CASE WHEN due THEN [1] ELSE [] END |
+      SET m.attempts = m.attempts + 1,
+          m.next_attempt_timestamp = timestamp() + $lease
+    )
+    REMOVE m._lock
+    WITH m, due
+    WHERE due
+    RETURN m {.*} AS mail
+    `
+
+	params := map[string]interface{}{
+		"status": models.MailPending,
+		"limit":  limit,
+		"lease":  lease.Milliseconds(),
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "claim mails", logger)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeMails(records, logger)
+}
+
+// MarkMailSent records a delivered mail and drops its data, which may hold
+// tokens that are no longer needed.
+func MarkMailSent(ctx context.Context, driver neo4j.DriverWithContext, mailID string, logger *zap.Logger) error {
+	query := `
+    MATCH (m:OutboxMail {mail_id: $mail_id})
+    SET m.status = $status, m.sent_timestamp = timestamp()
+    REMOVE m.data, m.last_error
+    RETURN m.mail_id AS mail_id
+    `
+
+	params := map[string]interface{}{
+		"mail_id": mailID,
+		"status":  models.MailSent,
+	}
+
+	_, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "mark mail sent", logger)
+	return err
+}
+
+// MarkMailFailed records a failed attempt. The mail is tried again at
+// nextAttempt, in milliseconds, unless it is dead.
+func MarkMailFailed(ctx context.Context, driver neo4j.DriverWithContext, mailID, lastError string, nextAttempt int64, dead bool, logger *zap.Logger) error {
+	query := `
+    MATCH (m:OutboxMail {mail_id: $mail_id})
+    SET m.status = $status,
+      m.last_error = $last_error,
+      m.next_attempt_timestamp = $next_attempt
+    RETURN m.mail_id AS mail_id
+    `
+
+	status := models.MailPending
+	if dead {
+		status = models.MailDead
+	}
+
+	params := map[string]interface{}{
+		"mail_id":      mailID,
+		"status":       status,
+		"last_error":   lastError,
+		"next_attempt": nextAttempt,
+	}
+
+	_, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "mark mail failed", logger)
+	return err
+}
+
+// GetDeadLetters lists the mails that gave up, newest first.
+func GetDeadLetters(ctx context.Context, driver neo4j.DriverWithContext, page models.Page, logger *zap.Logger) ([]models.Mail, string, error) {
+	query := `
+    MATCH (m:OutboxMail {status: $status})
+    WHERE $cursor_ts IS NULL
+      OR m.created_timestamp < $cursor_ts
+      OR (m.created_timestamp = $cursor_ts AND m.mail_id < $cursor_id)
+    RETURN m {.*} AS mail
+    ORDER BY m.created_timestamp DESC, m.mail_id DESC
+    LIMIT $limit
+    `
+
+	params := page.Params(map[string]interface{}{
+		"status": models.MailDead,
+	})
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeRead, query, params, "retrieve dead letters", logger)
+	if err != nil {
+		return nil, "", err
+	}
+
+	mails, err := decodeMails(records, logger)
+	if err != nil {
+		return nil, "", err
+	}
+
+	mails, nextCursor := models.NextPage(mails, page, mailCursor)
+	return mails, nextCursor, nil
+}
+
+func mailCursor(mail models.Mail) models.Cursor {
+	return models.Cursor{Timestamp: mail.CreatedTimestamp, ID: mail.MailID}
+}
+
+// RetryDeadLetter puts a dead mail back in the outbox with its attempts
+// reset.
+func RetryDeadLetter(ctx context.Context, driver neo4j.DriverWithContext, mailID string, logger *zap.Logger) error {
+	query := `
+    MATCH (m:OutboxMail {mail_id: $mail_id, status: $dead})
+    SET m.status = $pending, m.attempts = 0, m.next_attempt_timestamp = timestamp()
+    RETURN m.mail_id AS mail_id
+    `
+
+	params := map[string]interface{}{
+		"mail_id": mailID,
+		"dead":    models.MailDead,
+		"pending": models.MailPending,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "retry dead letter", logger)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("Dead letter: %s not found", mailID))
+	}
+
+	return nil
+}
@@ -0,0 +1,16 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+)
+
+// recordCursor reads the cursor of a paginated row from the given columns.
+func recordCursor(tsKey, idKey string) func(*neo4j.Record) models.Cursor {
+	return func(record *neo4j.Record) models.Cursor {
+		ts, _, _ := neo4j.GetRecordValue[int64](record, tsKey)
+		id, _, _ := neo4j.GetRecordValue[string](record, idKey)
+		return models.Cursor{Timestamp: ts, ID: id}
+	}
+}
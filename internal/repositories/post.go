@@ -4,6 +4,7 @@ import (
 	"alumni_api/internal/models"
 	"alumni_api/internal/services"
 	"alumni_api/internal/utils"
+	"cmp"
 	"context"
 	"net/http"
 	"slices"
@@ -15,7 +16,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func GetAllPosts(ctx context.Context, driver neo4j.DriverWithContext, userId string, logger *zap.Logger) ([]map[string]interface{}, error) {
+func GetAllPosts(ctx context.Context, driver neo4j.DriverWithContext, userId string, visibility []string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeRead,
@@ -24,6 +25,13 @@ func GetAllPosts(ctx context.Context, driver neo4j.DriverWithContext, userId str
 
 	query := `
     MATCH (p:Post)<-[:HAS_POST]-(author:UserProfile)
+    WHERE (coalesce(p.visibility, "all") IN $visibility OR author.user_id = $user_id)
+      AND ($cursor_ts IS NULL
+        OR p.created_timestamp < $cursor_ts
+        OR (p.created_timestamp = $cursor_ts AND p.post_id < $cursor_id))
+    WITH p, author
+    ORDER BY p.created_timestamp DESC, p.post_id DESC
+    LIMIT $limit
     OPTIONAL MATCH (p)<-[l:LIKES]-(:UserProfile)
     OPTIONAL MATCH (p)<-[v:HAS_VIEWED]-(:UserProfile)
     OPTIONAL MATCH (p)<-[c:COMMENTED_ON]-(:Comment)
@@ -42,27 +50,32 @@ func GetAllPosts(ctx context.Context, driver neo4j.DriverWithContext, userId str
       SIZE(COLLECT(DISTINCT l)) AS likes_count,
       SIZE(COLLECT(DISTINCT v)) AS views_count,
       SIZE(COLLECT(DISTINCT c)) AS comments_count,
-      CASE WHEN userLike IS NULL THEN false ELSE true END AS has_liked
+      CASE WHEN userLike IS NULL THEN false ELSE true END AS has_liked,
+      p.created_timestamp AS created_timestamp
+    ORDER BY created_timestamp DESC, post_id DESC
   `
 
-	pararms := map[string]interface{}{
-		"user_id": userId,
-	}
+	pararms := page.Params(map[string]interface{}{
+		"user_id":    userId,
+		"visibility": visibility,
+	})
 
 	// Run the query
 	result, err := session.Run(ctx, query, pararms)
 	if err != nil {
 		logger.Error("Failed to retrieve posts", zap.Error(err))
-		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve posts")
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to retrieve posts")
 	}
 
 	// Collect the results
 	records, err := result.Collect(ctx)
 	if err != nil {
 		logger.Error("Failed to collect results", zap.Error(err))
-		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
 	}
 
+	records, nextCursor := models.NextPage(records, page, recordCursor("created_timestamp", "post_id"))
+
 	var posts []map[string]interface{}
 
 	// Iterate over the records and prepare the results
@@ -79,7 +92,103 @@ func GetAllPosts(ctx context.Context, driver neo4j.DriverWithContext, userId str
 		posts = append(posts, post)
 	}
 
-	return posts, nil
+	return posts, nextCursor, nil
+}
+
+// GetFeedCandidates returns the newest posts, up to window of them, along
+// with the signals that relate each post to the viewer.
+func GetFeedCandidates(ctx context.Context, driver neo4j.DriverWithContext, userID string, visibility []string, window int, logger *zap.Logger) ([]models.FeedCandidate, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (p:Post)<-[:HAS_POST]-(author:UserProfile)
+    WHERE coalesce(p.visibility, "all") IN $visibility OR author.user_id = $user_id
+    WITH p, author
+    ORDER BY p.created_timestamp DESC
+    LIMIT $window
+    OPTIONAL MATCH (viewer:UserProfile {user_id: $user_id})
+    OPTIONAL MATCH (p)<-[l:LIKES]-(:UserProfile)
+    WITH p, author, viewer, count(DISTINCT l) AS likes_count
+    OPTIONAL MATCH (p)<-[v:HAS_VIEWED]-(:UserProfile)
+    WITH p, author, viewer, likes_count, count(DISTINCT v) AS views_count
+    OPTIONAL MATCH (p)<-[c:COMMENTED_ON]-(:Comment)
+    WITH p, author, viewer, likes_count, views_count, count(DISTINCT c) AS comments_count
+    OPTIONAL MATCH (viewer)-[:HAS_WORK_WITH]->(shared:Company)<-[:HAS_WORK_WITH]-(author)
+    WITH p, author, viewer, likes_count, views_count, comments_count, count(DISTINCT shared) AS shared_companies
+    RETURN
+      p.post_id AS post_id,
+      p.title AS title,
+      p.post_type AS post_type,
+      p.media_urls AS media_urls,
+      p.redirect_link AS redirect_link,
+      p.start_date AS start_date,
+      p.end_date AS end_date,
+      author.first_name + " " + author.last_name AS name,
+      author.user_id AS user_id,
+      author.profile_picture AS profile_picture,
+      likes_count,
+      views_count,
+      comments_count,
+      viewer IS NOT NULL AND EXISTS { MATCH (viewer)-[:LIKES]->(p) } AS has_liked,
+      p.created_timestamp AS created_timestamp,
+      viewer IS NOT NULL AND EXISTS { MATCH (viewer)-[:FRIEND]-(author) } AS is_friend,
+      coalesce(viewer.generation = author.generation, false) AS same_generation,
+      viewer IS NOT NULL AND EXISTS { MATCH (viewer)-->(:StudentType)<--(author) } AS same_student_type,
+      shared_companies
+  `
+
+	params := map[string]interface{}{
+		"user_id":    userID,
+		"visibility": visibility,
+		"window":     window,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to retrieve posts", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve posts")
+	}
+
+	records, err := result.Collect(ctx)
+	if err != nil {
+		logger.Error("Failed to collect results", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to collect results")
+	}
+
+	var candidates []models.FeedCandidate
+
+	for _, record := range records {
+		post := record.AsMap()
+
+		candidate := models.FeedCandidate{
+			CreatedAt:       utils.SafeInt64(post["created_timestamp"]),
+			IsFriend:        post["is_friend"] == true,
+			SameGeneration:  post["same_generation"] == true,
+			SameStudentType: post["same_student_type"] == true,
+			SharedCompanies: utils.SafeInt64(post["shared_companies"]),
+			Likes:           utils.SafeInt64(post["likes_count"]),
+			Views:           utils.SafeInt64(post["views_count"]),
+			Comments:        utils.SafeInt64(post["comments_count"]),
+		}
+
+		for _, key := range []string{"is_friend", "same_generation", "same_student_type", "shared_companies"} {
+			delete(post, key)
+		}
+		for key, value := range post {
+			if value == nil || value == "" {
+				delete(post, key)
+			}
+		}
+		candidate.Post = post
+
+		candidates = append(candidates, candidate)
+	}
+
+	return candidates, nil
 }
 
 func GetPostByID(ctx context.Context, driver neo4j.DriverWithContext, postID, userID string, logger *zap.Logger) (map[string]interface{}, error) {
@@ -325,17 +434,26 @@ func UnlikePost(ctx context.Context, driver neo4j.DriverWithContext, userID, pos
 	return nil
 }
 
-func GetCommentByPostID(ctx context.Context, driver neo4j.DriverWithContext, postID, userID string, logger *zap.Logger) ([]models.Comment, error) {
+func GetCommentByPostID(ctx context.Context, driver neo4j.DriverWithContext, postID, userID string, page models.Page, logger *zap.Logger) ([]models.Comment, string, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeRead,
 	})
 	defer session.Close(ctx)
 
+	// Pages are made of top-level comments, oldest first; every reply below a
+	// returned comment comes along so the tree is never cut in half.
 	query := `
     MATCH (p:Post {post_id: $post_id})<-[:HAS_POST]-(:UserProfile)
-    MATCH (comment:Comment)-[:COMMENTED_ON]->(target)
-    WHERE target = p OR target:Comment
+    MATCH (root:Comment)-[:COMMENTED_ON]->(p)
+    WHERE $cursor_ts IS NULL
+      OR root.created_timestamp > $cursor_ts
+      OR (root.created_timestamp = $cursor_ts AND root.comment_id > $cursor_id)
+    WITH root
+    ORDER BY root.created_timestamp, root.comment_id
+    LIMIT $limit
+    MATCH (comment:Comment)-[:COMMENTED_ON*0..]->(root)
+    MATCH (comment)-[:COMMENTED_ON]->(target)
     OPTIONAL MATCH (comment)-[:COMMENTED_BY]->(user:UserProfile)
     OPTIONAL MATCH (comment)<-[l:LIKES]-(:UserProfile)
     OPTIONAL MATCH (comment)<-[userLike:LIKES]-(:UserProfile {user_id: $user_id})
@@ -353,30 +471,60 @@ func GetCommentByPostID(ctx context.Context, driver neo4j.DriverWithContext, pos
             ELSE target.comment_id
         END AS parent_comment_id,
         count(l) AS like_count,
-        CASE WHEN userLike IS NULL THEN false ELSE true END AS has_liked
+        CASE WHEN userLike IS NULL THEN false ELSE true END AS has_liked,
+        root.comment_id AS root_id,
+        root.created_timestamp AS root_timestamp
     ORDER BY created_timestamp
     `
 
-	params := map[string]any{
+	params := page.Params(map[string]any{
 		"post_id": postID,
 		"user_id": userID,
-	}
+	})
 
 	result, err := session.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Comment query failed", zap.Error(err))
-		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to fetch comments")
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to fetch comments")
 	}
 
 	records, err := result.Collect(ctx)
 	if err != nil {
 		logger.Error("Failed to collect comments", zap.Error(err))
-		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to process comments")
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to process comments")
+	}
+
+	var roots []*neo4j.Record
+	seen := make(map[string]bool)
+	for _, record := range records {
+		rootID := utils.SafeString(record.Values[11])
+		if !seen[rootID] {
+			seen[rootID] = true
+			roots = append(roots, record)
+		}
+	}
+	rootCursor := recordCursor("root_timestamp", "root_id")
+	slices.SortFunc(roots, func(a, b *neo4j.Record) int {
+		x, y := rootCursor(a), rootCursor(b)
+		if c := cmp.Compare(x.Timestamp, y.Timestamp); c != 0 {
+			return c
+		}
+		return cmp.Compare(x.ID, y.ID)
+	})
+
+	roots, nextCursor := models.NextPage(roots, page, rootCursor)
+
+	inPage := make(map[string]bool)
+	for _, root := range roots {
+		inPage[root.Values[11].(string)] = true
 	}
 
 	var comments []models.Comment
 
 	for _, record := range records {
+		if !inPage[utils.SafeString(record.Values[11])] {
+			continue
+		}
 		comment := models.Comment{
 			CommentID:       record.Values[0].(string),
 			Content:         record.Values[1].(string),
@@ -393,7 +541,7 @@ func GetCommentByPostID(ctx context.Context, driver neo4j.DriverWithContext, pos
 		comments = append(comments, comment)
 	}
 	nested := services.BuildCommentTree(comments)
-	return nested, nil
+	return nested, nextCursor, nil
 }
 
 func CommentPost(ctx context.Context, driver neo4j.DriverWithContext, userID, postID, comment string, logger *zap.Logger) (map[string]interface{}, error) {
@@ -0,0 +1,245 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// UserRepository covers UserProfile nodes and the student/college info attached to them.
+type UserRepository interface {
+	GetAllUser(ctx context.Context, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error)
+	CreateProfile(ctx context.Context, user models.CreateProfileRequest, logger *zap.Logger) (map[string]interface{}, error)
+	FetchUserByID(ctx context.Context, id string, logger *zap.Logger) (map[string]interface{}, error)
+	UpdateUserByID(ctx context.Context, id string, updatedData models.UpdateUserProfileRequest, logger *zap.Logger) (map[string]interface{}, error)
+	DeleteUserByID(ctx context.Context, userID string, logger *zap.Logger) error
+	FetchUserByFilter(ctx context.Context, filter models.UserRequestFilter, logger *zap.Logger) ([]map[string]interface{}, error)
+	FullTextSearch(ctx context.Context, queryTerm models.UserFulltextSearch, logger *zap.Logger) ([]map[string]interface{}, error)
+	AddStudentInfo(ctx context.Context, id string, collegeInfo models.CollegeInfo, logger *zap.Logger) error
+	UpdateStudentInfo(ctx context.Context, id string, collegeInfo models.CollegeInfo, logger *zap.Logger) error
+	DeleteStudentInfo(ctx context.Context, id string, logger *zap.Logger) error
+	UserExist(ctx context.Context, id string, logger *zap.Logger) (bool, error)
+	UserVerify(ctx context.Context, id string, logger *zap.Logger) (bool, error)
+	SetLanguage(ctx context.Context, userID, language string, logger *zap.Logger) error
+	SetPrivacy(ctx context.Context, userID string, settings models.PrivacySettings, logger *zap.Logger) error
+}
+
+// PostRepository covers posts, comments, likes and views.
+type PostRepository interface {
+	GetAllPosts(ctx context.Context, userID string, visibility []string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error)
+	GetFeedCandidates(ctx context.Context, userID string, visibility []string, window int, logger *zap.Logger) ([]models.FeedCandidate, error)
+	PostVisible(ctx context.Context, postID, userID string, visibility []string, logger *zap.Logger) (bool, error)
+	GetPostByID(ctx context.Context, postID, userID string, logger *zap.Logger) (map[string]interface{}, error)
+	CreatePost(ctx context.Context, userID string, post models.Post, logger *zap.Logger) (map[string]interface{}, error)
+	UpdatePostByID(ctx context.Context, postID string, updatedData models.UpdatePostRequest, logger *zap.Logger) error
+	DeletePostByID(ctx context.Context, postID string, logger *zap.Logger) error
+	LikePost(ctx context.Context, userID, postID string, logger *zap.Logger) error
+	UnlikePost(ctx context.Context, userID, postID string, logger *zap.Logger) error
+	GetCommentByPostID(ctx context.Context, postID, userID string, page models.Page, logger *zap.Logger) ([]models.Comment, string, error)
+	CommentPost(ctx context.Context, userID, postID, comment string, logger *zap.Logger) (map[string]interface{}, error)
+	ReplyComment(ctx context.Context, userID, commentID, comment string, logger *zap.Logger) (map[string]interface{}, error)
+	UpdateCommentPost(ctx context.Context, commentID, comment string, logger *zap.Logger) error
+	DeleteCommentPost(ctx context.Context, commentID string, logger *zap.Logger) error
+	LikeComment(ctx context.Context, userID, commentID string, logger *zap.Logger) error
+	UnlikeComment(ctx context.Context, userID, commentID string, logger *zap.Logger) error
+	AddView(ctx context.Context, userID, postID string, logger *zap.Logger) error
+	GetAuthorUserID(ctx context.Context, postID string, logger *zap.Logger) (string, error)
+	GetCommentUserID(ctx context.Context, commentID string, logger *zap.Logger) (string, error)
+}
+
+// MessageRepository covers direct messages between two users.
+type MessageRepository interface {
+	SendMessage(ctx context.Context, msg models.Message, logger *zap.Logger) (map[string]interface{}, error)
+	ReplyMessage(ctx context.Context, msg models.ReplyMessage, logger *zap.Logger) (map[string]interface{}, error)
+	EditMessage(ctx context.Context, msg models.EditMessage, logger *zap.Logger) error
+	DeleteMessage(ctx context.Context, msg models.DeleteMessage, logger *zap.Logger) error
+	GetMessage(ctx context.Context, senderID, receiverID string, page models.Page, logger *zap.Logger) (map[string]interface{}, string, error)
+	MarkRead(ctx context.Context, readerID, messageID string, logger *zap.Logger) (models.ReadReceipt, error)
+	GetConversations(ctx context.Context, userID string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error)
+}
+
+// ConversationRepository covers group chats: Conversation nodes, the
+// MEMBER_OF edges carrying each member's role and the messages POSTED_IN them.
+type ConversationRepository interface {
+	CreateConversation(ctx context.Context, ownerID string, conv models.Conversation, logger *zap.Logger) (map[string]interface{}, error)
+	GetConversationByID(ctx context.Context, conversationID string, logger *zap.Logger) (map[string]interface{}, error)
+	UpdateConversation(ctx context.Context, conversationID, title string, logger *zap.Logger) error
+	GetMemberRole(ctx context.Context, conversationID, userID string, logger *zap.Logger) (string, error)
+	GetMemberIDs(ctx context.Context, conversationID string, logger *zap.Logger) ([]string, error)
+	ShareConversation(ctx context.Context, userID1, userID2 string, logger *zap.Logger) (bool, error)
+	AddMembers(ctx context.Context, conversationID string, userIDs []string, logger *zap.Logger) error
+	SetMemberRole(ctx context.Context, conversationID, userID, role string, logger *zap.Logger) error
+	RemoveMember(ctx context.Context, conversationID, userID string, logger *zap.Logger) error
+	GetEventConversationID(ctx context.Context, postID string, logger *zap.Logger) (string, error)
+	SendGroupMessage(ctx context.Context, msg models.GroupMessage, logger *zap.Logger) (map[string]interface{}, error)
+	GetGroupMessages(ctx context.Context, conversationID string, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error)
+}
+
+// AttachmentRepository covers the Attachment nodes a user UPLOADED and the
+// HAS_ATTACHMENT edges linking them to the message they were sent with.
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment models.Attachment, logger *zap.Logger) (map[string]interface{}, error)
+	GetAttachment(ctx context.Context, attachmentID string, logger *zap.Logger) (map[string]interface{}, error)
+	CanAccessAttachment(ctx context.Context, attachmentID, userID string, logger *zap.Logger) (bool, error)
+}
+
+// FriendRepository covers FRIEND edges between users.
+type FriendRepository interface {
+	GetUserFriendByID(ctx context.Context, id string, logger *zap.Logger) ([]map[string]interface{}, error)
+	AddFriend(ctx context.Context, userID1, userID2 string, logger *zap.Logger) error
+	Unfriend(ctx context.Context, userID1, userID2 string, logger *zap.Logger) error
+	GetFOAF(ctx context.Context, userID, otherID string, degree int, logger *zap.Logger) ([]map[string]interface{}, error)
+}
+
+// CompanyRepository covers Company nodes and HAS_WORK_WITH edges.
+type CompanyRepository interface {
+	CompanyFullTextSearch(ctx context.Context, queryTerm string, logger *zap.Logger) ([]map[string]interface{}, error)
+	AddUserCompany(ctx context.Context, id string, companies models.UserRequestCompany, logger *zap.Logger) ([]map[string]interface{}, error)
+	UpdateUserCompany(ctx context.Context, userID, companyID string, company models.UserCompanyUpdateRequest, logger *zap.Logger) error
+	DeleteUserCompany(ctx context.Context, userID, companyID string, logger *zap.Logger) error
+	FindCompanyAssociate(ctx context.Context, company models.Company, logger *zap.Logger) ([]map[string]interface{}, error)
+}
+
+// AuthRepository covers registration, login, account recovery and role requests.
+// The methods that owe the user an email return it for the caller to put in
+// the outbox.
+type AuthRepository interface {
+	Login(ctx context.Context, username string, logger *zap.Logger) (models.LoginResponse, error)
+	RegistryUser(ctx context.Context, user models.RegistryRequest, logger *zap.Logger) (map[string]interface{}, models.Mail, error)
+	RegistryAlumnus(ctx context.Context, user models.RegistryOneTimeRequest, email string, logger *zap.Logger) (map[string]interface{}, error)
+	VerifyAccount(ctx context.Context, userID, token string, logger *zap.Logger) (map[string]interface{}, error)
+	RequestChangePassword(ctx context.Context, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error)
+	ChangePassword(ctx context.Context, userID, password, token string, logger *zap.Logger) error
+	RequestChangeMail(ctx context.Context, userID, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error)
+	VerifyEmail(ctx context.Context, userID, email, token string, logger *zap.Logger) error
+	RequestAlumniOneTimeRegistry(ctx context.Context, email string, logger *zap.Logger) (map[string]interface{}, models.Mail, error)
+	ApproveAlumnusRole(ctx context.Context, requestID, departmentID string, logger *zap.Logger) error
+	RejectAlumnusRole(ctx context.Context, requestID, departmentID string, logger *zap.Logger) error
+	RequestAlumnusRole(ctx context.Context, userID string, logger *zap.Logger) error
+	GetAllRequest(ctx context.Context, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error)
+	UsernameVerify(ctx context.Context, username string, logger *zap.Logger) (bool, error)
+	EmailExist(ctx context.Context, email string, logger *zap.Logger) (bool, error)
+	IsRequestApproved(ctx context.Context, userID string, logger *zap.Logger) (bool, error)
+}
+
+// SessionRepository covers the Session nodes a user HAS_SESSION, one per
+// signed-in device, and the rotation of their refresh tokens.
+type SessionRepository interface {
+	CreateSession(ctx context.Context, session models.Session, logger *zap.Logger) error
+	RotateSession(ctx context.Context, sessionID, refreshHash string, next models.Session, logger *zap.Logger) (models.LoginResponse, error)
+	GetSessions(ctx context.Context, userID string, logger *zap.Logger) ([]map[string]interface{}, error)
+	RevokeSession(ctx context.Context, userID, sessionID string, logger *zap.Logger) error
+	RevokeAllSessions(ctx context.Context, userID string, logger *zap.Logger) error
+	IsSessionActive(ctx context.Context, userID, sessionID string, logger *zap.Logger) (bool, error)
+}
+
+// MFARepository covers the TOTP two-factor settings kept on UserProfile.
+type MFARepository interface {
+	GetMFA(ctx context.Context, userID string, logger *zap.Logger) (map[string]interface{}, error)
+	SetPendingMFASecret(ctx context.Context, userID string, secret []byte, logger *zap.Logger) error
+	EnableMFA(ctx context.Context, userID string, backupHashes []string, step int64, logger *zap.Logger) error
+	DisableMFA(ctx context.Context, userID string, logger *zap.Logger) error
+	SetBackupCodes(ctx context.Context, userID string, backupHashes []string, logger *zap.Logger) error
+	UseTOTPStep(ctx context.Context, userID string, step int64, logger *zap.Logger) (bool, error)
+	UseBackupCode(ctx context.Context, userID, hash string, logger *zap.Logger) (bool, error)
+	RecordMFAFailure(ctx context.Context, userID string, window time.Duration, logger *zap.Logger) error
+	SetMFARequired(ctx context.Context, userID string, required bool, logger *zap.Logger) error
+}
+
+// LockoutRepository covers the failed-login tracking on UserProfile and the
+// SecurityEvent nodes it HAS_SECURITY_EVENT.
+type LockoutRepository interface {
+	GetLoginThrottle(ctx context.Context, userID string, logger *zap.Logger) (models.LoginThrottle, error)
+	RecordLoginFailure(ctx context.Context, userID string, window time.Duration, event models.SecurityEvent, logger *zap.Logger) (models.LoginThrottle, error)
+	LockAccount(ctx context.Context, userID string, until int64, event models.SecurityEvent, logger *zap.Logger) (models.Mail, error)
+	UnlockAccount(ctx context.Context, userID string, event models.SecurityEvent, logger *zap.Logger) error
+	RecordSecurityEvent(ctx context.Context, userID string, event models.SecurityEvent, logger *zap.Logger) error
+	ResetLoginFailures(ctx context.Context, userID string, logger *zap.Logger) error
+	GetSecurityEvents(ctx context.Context, userID string, limit int, logger *zap.Logger) ([]map[string]interface{}, error)
+}
+
+// RoleRepository covers the Role nodes permissions are granted through and
+// the roles a UserProfile HAS_ROLE.
+type RoleRepository interface {
+	SyncBuiltInRoles(ctx context.Context, roles []models.Role, logger *zap.Logger) error
+	GetRoles(ctx context.Context, logger *zap.Logger) ([]map[string]interface{}, error)
+	SaveRole(ctx context.Context, role models.Role, logger *zap.Logger) error
+	DeleteRole(ctx context.Context, name string, logger *zap.Logger) error
+	AssignRole(ctx context.Context, userID, name string, logger *zap.Logger) error
+	RevokeRole(ctx context.Context, userID, name string, logger *zap.Logger) error
+	GetPermissions(ctx context.Context, userID string, logger *zap.Logger) ([]string, error)
+}
+
+// DepartmentRepository covers the Department nodes users belong to through
+// their Field, and the department admins who ADMINISTERS one.
+type DepartmentRepository interface {
+	AssignDepartmentIDs(ctx context.Context, logger *zap.Logger) error
+	GetDepartments(ctx context.Context, logger *zap.Logger) ([]map[string]interface{}, error)
+	GetAdministeredDepartment(ctx context.Context, userID string, logger *zap.Logger) (string, error)
+	AppointDepartmentAdmin(ctx context.Context, departmentID, userID string, logger *zap.Logger) error
+	RemoveDepartmentAdmin(ctx context.Context, departmentID, userID string, logger *zap.Logger) error
+}
+
+// IdentityRepository covers the OpenID Connect identities a UserProfile
+// HAS_IDENTITY.
+type IdentityRepository interface {
+	SignInWithIdentity(ctx context.Context, identity models.Identity, logger *zap.Logger) (models.LoginResponse, error)
+}
+
+// StatisticRepository covers the aggregate queries behind /stat.
+type StatisticRepository interface {
+	GetPostStat(ctx context.Context, visibility []string, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error)
+	GetActivityStat(ctx context.Context, visibility []string, logger *zap.Logger) (map[string]interface{}, error)
+	GetRegistryStat(ctx context.Context, departmentID string, logger *zap.Logger) (map[string]interface{}, error)
+	GetGenerationSTStat(ctx context.Context, generation []string, logger *zap.Logger) ([]map[string]interface{}, error)
+	GetUserSalary(ctx context.Context, visibility []string, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error)
+	GetUserJob(ctx context.Context, visibility []string, logger *zap.Logger) ([]map[string]interface{}, error)
+}
+
+// ReportRepository covers user reports against posts, comments and users.
+type ReportRepository interface {
+	FetchReport(ctx context.Context, page models.Page, departmentID string, logger *zap.Logger) ([]map[string]interface{}, string, error)
+	Report(ctx context.Context, report models.Report, logger *zap.Logger) error
+}
+
+// OutboxRepository covers the OutboxMail nodes the mail worker sends from.
+type OutboxRepository interface {
+	EnqueueMail(ctx context.Context, mail models.Mail, logger *zap.Logger) error
+	ClaimMails(ctx context.Context, limit int, lease time.Duration, logger *zap.Logger) ([]models.Mail, error)
+	MarkMailSent(ctx context.Context, mailID string, logger *zap.Logger) error
+	MarkMailFailed(ctx context.Context, mailID, lastError string, nextAttempt int64, dead bool, logger *zap.Logger) error
+	GetDeadLetters(ctx context.Context, page models.Page, logger *zap.Logger) ([]models.Mail, string, error)
+	RetryDeadLetter(ctx context.Context, mailID string, logger *zap.Logger) error
+}
+
+// EncryptedRepository reads and rewrites the encrypted properties listed in
+// models.EncryptedProperties, for re-encrypting them under a new key.
+type EncryptedRepository interface {
+	GetEncryptedValues(ctx context.Context, prop models.EncryptedProperty, after string, limit int, logger *zap.Logger) ([]models.EncryptedValue, error)
+	ReplaceEncryptedValues(ctx context.Context, prop models.EncryptedProperty, changes []models.EncryptedChange, logger *zap.Logger) (int, error)
+}
+
+// Store groups every repository the controllers depend on so a single value
+// can be wired into the routes, backed either by Neo4j or by memory.
+type Store struct {
+	User         UserRepository
+	Post         PostRepository
+	Message      MessageRepository
+	Conversation ConversationRepository
+	Attachment   AttachmentRepository
+	Friend       FriendRepository
+	Company      CompanyRepository
+	Auth         AuthRepository
+	Session      SessionRepository
+	MFA          MFARepository
+	Identity     IdentityRepository
+	Lockout      LockoutRepository
+	Role         RoleRepository
+	Department   DepartmentRepository
+	Statistic    StatisticRepository
+	Report       ReportRepository
+	Outbox       OutboxRepository
+	Encrypted    EncryptedRepository
+}
@@ -0,0 +1,227 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+func runQuery(ctx context.Context, driver neo4j.DriverWithContext, accessMode neo4j.AccessMode, query string, params map[string]interface{}, action string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   accessMode,
+	})
+	defer session.Close(ctx)
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to "+action, zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to "+action)
+	}
+
+	records := []map[string]interface{}{}
+	for result.Next(ctx) {
+		records = append(records, result.Record().AsMap())
+	}
+
+	return records, nil
+}
+
+// SyncBuiltInRoles creates the built-in roles and resets their permissions
+// to the ones defined in code.
+func SyncBuiltInRoles(ctx context.Context, driver neo4j.DriverWithContext, roles []models.Role, logger *zap.Logger) error {
+	query := `
+    UNWIND $roles AS role
+    MERGE (r:Role {name: role.name})
+    ON CREATE SET r.created_timestamp = timestamp()
+    SET r.permissions = role.permissions, r.built_in = true
+    RETURN r.name AS name
+    `
+
+	params := map[string]interface{}{
+		"roles": rolesParam(roles),
+	}
+
+	_, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "sync built-in roles", logger)
+	return err
+}
+
+func rolesParam(roles []models.Role) []map[string]interface{} {
+	ret := make([]map[string]interface{}, 0, len(roles))
+	for _, role := range roles {
+		ret = append(ret, map[string]interface{}{
+			"name":        role.Name,
+			"permissions": role.Permissions,
+		})
+	}
+	return ret
+}
+
+func GetRoles(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) ([]map[string]interface{}, error) {
+	query := `
+    MATCH (r:Role)
+    OPTIONAL MATCH (u:UserProfile)-[:HAS_ROLE]->(r)
+    RETURN
+      r.name AS name,
+      r.permissions AS permissions,
+      coalesce(r.built_in, false) AS built_in,
+      count(u) AS assigned_users
+    ORDER BY r.name
+    `
+
+	return runQuery(ctx, driver, neo4j.AccessModeRead, query, nil, "retrieve roles", logger)
+}
+
+// SaveRole creates a role or replaces its permissions. Built-in roles cannot
+// be changed.
+func SaveRole(ctx context.Context, driver neo4j.DriverWithContext, role models.Role, logger *zap.Logger) error {
+	query := `
+    MERGE (r:Role {name: $name})
+    ON CREATE SET r.created_timestamp = timestamp(), r.built_in = false
+    WITH r, coalesce(r.built_in, false) AS built_in
+    FOREACH (_ IN CASE WHEN built_in THEN [] ELSE [1] END |
+      SET r.permissions = $permissions, r.updated_timestamp = timestamp()
+    )
+    RETURN built_in
+    `
+
+	params := map[string]interface{}{
+		"name":        role.Name,
+		"permissions": role.Permissions,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "save role", logger)
+	if err != nil {
+		return err
+	}
+
+	if len(records) > 0 && records[0]["built_in"] == true {
+		return fiber.NewError(http.StatusConflict, fmt.Sprintf("Role: %s is built in", role.Name))
+	}
+
+	return nil
+}
+
+// DeleteRole deletes a role that is not built in, and with it every
+// assignment of it.
+func DeleteRole(ctx context.Context, driver neo4j.DriverWithContext, name string, logger *zap.Logger) error {
+	query := `
+    MATCH (r:Role {name: $name})
+    WITH r, coalesce(r.built_in, false) AS built_in
+    FOREACH (_ IN CASE WHEN built_in THEN [] ELSE [1] END | DETACH DELETE r)
+    RETURN built_in
+    `
+
+	params := map[string]interface{}{
+		"name": name,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "delete role", logger)
+	if err != nil {
+		return err
+	}
+
+	if len(records) == 0 {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("Role: %s not found", name))
+	}
+
+	if records[0]["built_in"] == true {
+		return fiber.NewError(http.StatusConflict, fmt.Sprintf("Role: %s is built in", name))
+	}
+
+	return nil
+}
+
+func AssignRole(ctx context.Context, driver neo4j.DriverWithContext, userID, name string, logger *zap.Logger) error {
+	query := `
+    OPTIONAL MATCH (u:UserProfile {user_id: $user_id})
+    OPTIONAL MATCH (r:Role {name: $name})
+    FOREACH (_ IN CASE WHEN u IS NOT NULL AND r IS NOT NULL THEN [1] ELSE [] END |
+      MERGE (u)-[h:HAS_ROLE]->(r)
+      ON CREATE SET h.assigned_timestamp = timestamp()
+    )
+    RETURN u IS NOT NULL AS user_exists, r IS NOT NULL AS role_exists
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+		"name":    name,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "assign role", logger)
+	if err != nil {
+		return err
+	}
+
+	if records[0]["user_exists"] != true {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s not found", userID))
+	}
+
+	if records[0]["role_exists"] != true {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("Role: %s not found", name))
+	}
+
+	return nil
+}
+
+func RevokeRole(ctx context.Context, driver neo4j.DriverWithContext, userID, name string, logger *zap.Logger) error {
+	query := `
+    MATCH (:UserProfile {user_id: $user_id})-[h:HAS_ROLE]->(:Role {name: $name})
+    DELETE h
+    RETURN count(h) AS deleted
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+		"name":    name,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeWrite, query, params, "revoke role", logger)
+	if err != nil {
+		return err
+	}
+
+	if deleted, _ := records[0]["deleted"].(int64); deleted == 0 {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("Role: %s not assigned to user %s", name, userID))
+	}
+
+	return nil
+}
+
+// GetPermissions returns everything a user may do: the permissions of the
+// role named by their UserProfile.role and of every role they HAS_ROLE.
+func GetPermissions(ctx context.Context, driver neo4j.DriverWithContext, userID string, logger *zap.Logger) ([]string, error) {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    OPTIONAL MATCH (r:Role)
+    WHERE r.name = u.role OR (u)-[:HAS_ROLE]->(r)
+    UNWIND coalesce(r.permissions, []) AS permission
+    RETURN collect(DISTINCT permission) AS permissions
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+	}
+
+	records, err := runQuery(ctx, driver, neo4j.AccessModeRead, query, params, "retrieve permissions", logger)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions := []string{}
+	if len(records) > 0 {
+		values, _ := records[0]["permissions"].([]interface{})
+		for _, value := range values {
+			if permission, ok := value.(string); ok {
+				permissions = append(permissions, permission)
+			}
+		}
+	}
+
+	return permissions, nil
+}
@@ -0,0 +1,277 @@
+package repositories
+
+import (
+	"alumni_api/internal/models"
+	"alumni_api/internal/utils"
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
+	"go.uber.org/zap"
+)
+
+// refreshHashHistory is how many replaced refresh token hashes a session
+// remembers for reuse detection.
+const refreshHashHistory = 20
+
+func CreateSession(ctx context.Context, driver neo4j.DriverWithContext, s models.Session, logger *zap.Logger) error {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    CREATE (u)-[:HAS_SESSION]->(s:Session {
+      session_id: $session_id,
+      refresh_hash: $refresh_hash,
+      previous_hashes: [],
+      user_agent: $user_agent,
+      ip: $ip,
+      created_timestamp: timestamp(),
+      last_used_timestamp: timestamp(),
+      expires_timestamp: $expires_timestamp
+    })
+    RETURN s.session_id AS session_id
+    `
+
+	params := map[string]interface{}{
+		"user_id":           s.UserID,
+		"session_id":        s.SessionID,
+		"refresh_hash":      s.RefreshHash,
+		"user_agent":        s.UserAgent,
+		"ip":                s.IP,
+		"expires_timestamp": s.ExpiresTimestamp,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to create session", zap.Error(err))
+		return fiber.NewError(http.StatusInternalServerError, "Failed to create session")
+	}
+
+	if !result.Next(ctx) {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("User: %s not found", s.UserID))
+	}
+
+	return nil
+}
+
+// RotateSession swaps the session's refresh token hash for next.RefreshHash
+// when refreshHash is the current one, and returns the user to issue a new
+// access token for. Presenting a hash the session already replaced means the
+// token was copied, so the whole session is revoked.
+func RotateSession(ctx context.Context, driver neo4j.DriverWithContext, sessionID, refreshHash string, next models.Session, logger *zap.Logger) (models.LoginResponse, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	// The first SET takes the write lock on the session before its hashes
+	// are read, so two refreshes with the same token cannot both succeed.
+	query := `
+    MATCH (u:UserProfile)-[:HAS_SESSION]->(s:Session {session_id: $session_id})
+    SET s._lock = true
+    WITH u, s,
+      s.revoked_timestamp IS NULL AND s.expires_timestamp > timestamp() AS active,
+      s.refresh_hash = $refresh_hash AS current,
+      $refresh_hash IN coalesce(s.previous_hashes, []) AS reused
+    FOREACH (_ IN CASE WHEN active AND current THEN [1] ELSE [] END |
+      SET s.previous_hashes = ([s.refresh_hash] + coalesce(s.previous_hashes, []))[..$history],
+          s.refresh_hash = $new_hash,
+          s.user_agent = $user_agent,
+          s.ip = $ip,
+          s.last_used_timestamp = timestamp(),
+          s.expires_timestamp = $expires_timestamp
+    )
+    FOREACH (_ IN CASE WHEN active AND reused THEN [1] ELSE [] END |
+      SET s.revoked_timestamp = timestamp(), s.revoked_reason = "reuse"
+    )
+    REMOVE s._lock
+    RETURN active, current, reused,
+      u.user_id AS user_id, u.role AS role, u.admit_year AS admit_year,
+      u.language AS language
+    `
+
+	params := map[string]interface{}{
+		"session_id":        sessionID,
+		"refresh_hash":      refreshHash,
+		"new_hash":          next.RefreshHash,
+		"user_agent":        next.UserAgent,
+		"ip":                next.IP,
+		"expires_timestamp": next.ExpiresTimestamp,
+		"history":           refreshHashHistory,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to rotate session", zap.Error(err))
+		return models.LoginResponse{}, fiber.NewError(http.StatusInternalServerError, "Failed to rotate session")
+	}
+
+	record, err := result.Single(ctx)
+	if err != nil {
+		return models.LoginResponse{}, fiber.NewError(http.StatusUnauthorized, "Invalid refresh token")
+	}
+
+	data := record.AsMap()
+	if data["active"] != true {
+		return models.LoginResponse{}, fiber.NewError(http.StatusUnauthorized, "Session expired or revoked")
+	}
+	if data["reused"] == true {
+		logger.Warn("Refresh token reuse detected, session revoked", zap.String("session_id", sessionID))
+		return models.LoginResponse{}, fiber.NewError(http.StatusUnauthorized, "Refresh token reuse detected")
+	}
+	if data["current"] != true {
+		return models.LoginResponse{}, fiber.NewError(http.StatusUnauthorized, "Invalid refresh token")
+	}
+
+	var res models.LoginResponse
+	if err := utils.MapToStruct(data, &res); err != nil {
+		logger.Error("Error decoding user properties", zap.Error(err))
+		return models.LoginResponse{}, fiber.NewError(http.StatusInternalServerError, "Error decoding user properties")
+	}
+
+	return res, nil
+}
+
+// GetSessions lists the user's active sessions, most recently used first.
+func GetSessions(ctx context.Context, driver neo4j.DriverWithContext, userID string, logger *zap.Logger) ([]map[string]interface{}, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})-[:HAS_SESSION]->(s:Session)
+    WHERE s.revoked_timestamp IS NULL AND s.expires_timestamp > timestamp()
+    RETURN
+      s.session_id AS session_id,
+      s.user_agent AS user_agent,
+      s.ip AS ip,
+      s.created_timestamp AS created_timestamp,
+      s.last_used_timestamp AS last_used_timestamp,
+      s.expires_timestamp AS expires_timestamp
+    ORDER BY s.last_used_timestamp DESC, s.session_id
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to retrieve sessions", zap.Error(err))
+		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve sessions")
+	}
+
+	sessions := []map[string]interface{}{}
+	for result.Next(ctx) {
+		sessions = append(sessions, result.Record().AsMap())
+	}
+
+	return sessions, nil
+}
+
+func RevokeSession(ctx context.Context, driver neo4j.DriverWithContext, userID, sessionID string, logger *zap.Logger) error {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (:UserProfile {user_id: $user_id})-[:HAS_SESSION]->(s:Session {session_id: $session_id})
+    WHERE s.revoked_timestamp IS NULL
+    SET s.revoked_timestamp = timestamp(), s.revoked_reason = "logout"
+    RETURN s.session_id AS session_id
+    `
+
+	params := map[string]interface{}{
+		"user_id":    userID,
+		"session_id": sessionID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to revoke session", zap.Error(err))
+		return fiber.NewError(http.StatusInternalServerError, "Failed to revoke session")
+	}
+
+	if !result.Next(ctx) {
+		return fiber.NewError(http.StatusNotFound, fmt.Sprintf("Session: %s not found", sessionID))
+	}
+
+	return nil
+}
+
+// RevokeAllSessions signs the user out everywhere.
+func RevokeAllSessions(ctx context.Context, driver neo4j.DriverWithContext, userID string, logger *zap.Logger) error {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeWrite,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (:UserProfile {user_id: $user_id})-[:HAS_SESSION]->(s:Session)
+    WHERE s.revoked_timestamp IS NULL
+    SET s.revoked_timestamp = timestamp(), s.revoked_reason = "logout_all"
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err == nil {
+		_, err = result.Consume(ctx)
+	}
+	if err != nil {
+		logger.Error("Failed to revoke sessions", zap.Error(err))
+		return fiber.NewError(http.StatusInternalServerError, "Failed to revoke sessions")
+	}
+
+	return nil
+}
+
+// IsSessionActive reports whether the session belongs to the user and has
+// neither been revoked nor expired. JWTMiddleware calls it on every request.
+func IsSessionActive(ctx context.Context, driver neo4j.DriverWithContext, userID, sessionID string, logger *zap.Logger) (bool, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (:UserProfile {user_id: $user_id})-[:HAS_SESSION]->(s:Session {session_id: $session_id})
+    WHERE s.revoked_timestamp IS NULL AND s.expires_timestamp > timestamp()
+    RETURN count(s) > 0 AS active
+    `
+
+	params := map[string]interface{}{
+		"user_id":    userID,
+		"session_id": sessionID,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Failed to check session", zap.Error(err))
+		return false, fiber.NewError(http.StatusInternalServerError, "Failed to check session")
+	}
+
+	record, err := result.Single(ctx)
+	if err != nil {
+		logger.Error("Failed to check session", zap.Error(err))
+		return false, fiber.NewError(http.StatusInternalServerError, "Failed to check session")
+	}
+
+	active, _ := record.Get("active")
+	return active == true, nil
+}
@@ -1,6 +1,7 @@
 package repositories
 
 import (
+	"alumni_api/internal/models"
 	"context"
 	"net/http"
 
@@ -9,7 +10,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func GetPostStat(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) ([]map[string]interface{}, error) {
+func GetPostStat(ctx context.Context, driver neo4j.DriverWithContext, visibility []string, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeRead,
@@ -18,6 +19,7 @@ func GetPostStat(ctx context.Context, driver neo4j.DriverWithContext, logger *za
 
 	query := `
     MATCH (p:Post)<-[:HAS_POST]-(author:UserProfile)
+    WHERE coalesce(p.visibility, "all") IN $visibility AND ` + inDepartment("author") + `
 
     OPTIONAL MATCH (p)<-[v:HAS_VIEWED]-(view_user:UserProfile)
     WITH p, author, collect(view_user.generation) AS view_gens, collect(DISTINCT view_user.generation) AS view_gen_unique
@@ -54,8 +56,13 @@ func GetPostStat(ctx context.Context, driver neo4j.DriverWithContext, logger *za
       } AS comment_user_gen
   `
 
+	params := map[string]interface{}{
+		"visibility":    visibility,
+		"department_id": departmentParam(departmentID),
+	}
+
 	// Run the query
-	result, err := session.Run(ctx, query, nil)
+	result, err := session.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to retrieve posts", zap.Error(err))
 		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve posts")
@@ -87,7 +94,7 @@ func GetPostStat(ctx context.Context, driver neo4j.DriverWithContext, logger *za
 	return posts, nil
 }
 
-func GetActivityStat(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) (map[string]interface{}, error) {
+func GetActivityStat(ctx context.Context, driver neo4j.DriverWithContext, visibility []string, logger *zap.Logger) (map[string]interface{}, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeRead,
@@ -100,15 +107,19 @@ func GetActivityStat(ctx context.Context, driver neo4j.DriverWithContext, logger
       count(CASE WHEN u.is_verify = true THEN 1 END) AS user_count,
       count(CASE WHEN u.role = "alumnus" THEN 1 END) AS alumni_count
 
-    MATCH (p:Post)
-    WHERE p.post_type = "event"
+    OPTIONAL MATCH (p:Post)
+    WHERE p.post_type = "event" AND coalesce(p.visibility, "all") IN $visibility
     RETURN 
       user_count,
       alumni_count,
       count(p) AS event_count
   `
 
-	result, err := session.Run(ctx, query, nil)
+	params := map[string]interface{}{
+		"visibility": visibility,
+	}
+
+	result, err := session.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to retrieve registry stat", zap.Error(err))
 		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve posts")
@@ -123,7 +134,7 @@ func GetActivityStat(ctx context.Context, driver neo4j.DriverWithContext, logger
 	return record.AsMap(), nil
 }
 
-func GetRegistryStat(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) (map[string]interface{}, error) {
+func GetRegistryStat(ctx context.Context, driver neo4j.DriverWithContext, departmentID string, logger *zap.Logger) (map[string]interface{}, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeRead,
@@ -132,7 +143,7 @@ func GetRegistryStat(ctx context.Context, driver neo4j.DriverWithContext, logger
 
 	query := `
     MATCH (u:UserProfile)
-    WHERE u.generation IS NOT NULL
+    WHERE u.generation IS NOT NULL AND ` + inDepartment("u") + `
     WITH
       count(u) AS total_users,
       sum(CASE WHEN u.is_verify = true THEN 1 ELSE 0 END) AS verified_users,
@@ -171,7 +182,11 @@ func GetRegistryStat(ctx context.Context, driver neo4j.DriverWithContext, logger
       } AS overall_stats
   `
 
-	result, err := session.Run(ctx, query, nil)
+	params := map[string]interface{}{
+		"department_id": departmentParam(departmentID),
+	}
+
+	result, err := session.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to retrieve registry stat", zap.Error(err))
 		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve posts")
@@ -243,7 +258,9 @@ func GetGenerationSTStat(ctx context.Context, driver neo4j.DriverWithContext, ge
 	return gens, nil
 }
 
-func GetUserSalary(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) ([]map[string]interface{}, error) {
+// GetUserSalary lists the salaries of the users whose salary visibility is
+// one of visibility.
+func GetUserSalary(ctx context.Context, driver neo4j.DriverWithContext, visibility []string, departmentID string, logger *zap.Logger) ([]map[string]interface{}, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeRead,
@@ -252,13 +269,21 @@ func GetUserSalary(ctx context.Context, driver neo4j.DriverWithContext, logger *
 
 	query := `
     MATCH (u:UserProfile)-[r:HAS_WORK_WITH]->(c:Company)
-    WHERE r.salary_max IS NOT NULL
+    WHERE r.salary_max IS NOT NULL AND ` + inDepartment("u") + `
+      AND coalesce(u.privacy_salary, $default_visibility) IN $visibility
     RETURN
       u.generation AS gen,
       r.salary_max AS salary_max,
       r.salary_min AS salary_min
   `
-	result, err := session.Run(ctx, query, nil)
+
+	params := map[string]interface{}{
+		"department_id":      departmentParam(departmentID),
+		"visibility":         visibility,
+		"default_visibility": models.DefaultPrivacy.Salary,
+	}
+
+	result, err := session.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to retrieve posts", zap.Error(err))
 		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve posts")
@@ -279,7 +304,9 @@ func GetUserSalary(ctx context.Context, driver neo4j.DriverWithContext, logger *
 	return users, nil
 }
 
-func GetUserJob(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) ([]map[string]interface{}, error) {
+// GetUserJob lists the positions of the users whose career visibility is
+// one of visibility.
+func GetUserJob(ctx context.Context, driver neo4j.DriverWithContext, visibility []string, logger *zap.Logger) ([]map[string]interface{}, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeRead,
@@ -288,11 +315,18 @@ func GetUserJob(ctx context.Context, driver neo4j.DriverWithContext, logger *zap
 
 	query := `
     MATCH (u:UserProfile)-[r:HAS_WORK_WITH]->(c:Company)
+    WHERE coalesce(u.privacy_career, $default_visibility) IN $visibility
     RETURN
       c.name AS company,
       r.position AS position
   `
-	result, err := session.Run(ctx, query, nil)
+
+	params := map[string]interface{}{
+		"visibility":         visibility,
+		"default_visibility": models.DefaultPrivacy.Career,
+	}
+
+	result, err := session.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to retrieve posts", zap.Error(err))
 		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to retrieve posts")
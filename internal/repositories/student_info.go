@@ -20,6 +20,7 @@ func AddStudentInfo(ctx context.Context, driver neo4j.DriverWithContext, id stri
 	query := `
       MERGE (faculty:Faculty {name: $faculty})
       MERGE (faculty)-[:HAS_DEPARTMENT]->(department:Department {name: $department})
+      ON CREATE SET department.department_id = randomUUID()
       MERGE (department)-[:HAS_FIELD]->(field:Field {name: $field})
       MERGE (field)-[:HAS_STUDENT_TYPE]->(studentType:StudentType {name: $studentType})
 
@@ -81,6 +82,7 @@ func UpdateStudentInfo(ctx context.Context, driver neo4j.DriverWithContext, id s
 
     MERGE (f:Faculty {name: $faculty})
     MERGE (f)-[:HAS_DEPARTMENT]->(d:Department {name: $department})
+    ON CREATE SET d.department_id = randomUUID()
     MERGE (d)-[:HAS_FIELD]->(fld:Field {name: $field})
     MERGE (fld)-[:HAS_STUDENT_TYPE]->(st:StudentType {name: $studentType})
 
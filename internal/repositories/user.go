@@ -14,12 +14,19 @@ import (
 	"go.uber.org/zap"
 )
 
-func GetAllUser(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) ([]map[string]interface{}, error) {
+func GetAllUser(ctx context.Context, driver neo4j.DriverWithContext, page models.Page, logger *zap.Logger) ([]map[string]interface{}, string, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{DatabaseName: "neo4j", AccessMode: neo4j.AccessModeRead})
 	defer session.Close(ctx)
 
 	query := `
 		MATCH (u:UserProfile {role: "alumnus"})
+    WITH u, coalesce(u.created_timestamp, 0) AS created_timestamp
+    WHERE $cursor_ts IS NULL
+      OR created_timestamp < $cursor_ts
+      OR (created_timestamp = $cursor_ts AND u.user_id < $cursor_id)
+    WITH u, created_timestamp
+    ORDER BY created_timestamp DESC, u.user_id DESC
+    LIMIT $limit
     OPTIONAL MATCH (u)-[r:HAS_WORK_WITH]->(c:Company)
     OPTIONAL MATCH (u)-->(st:StudentType)<--(fld:Field)<--(d:Department)<--(f:Faculty)
     RETURN
@@ -47,25 +54,34 @@ func GetAllUser(ctx context.Context, driver neo4j.DriverWithContext, logger *zap
       u.linkdin AS linkedin,
       u.facebook AS facebook,
       u.phone AS phone,
+      u.privacy_personal AS privacy_personal,
+      u.privacy_contact AS privacy_contact,
+      u.privacy_education AS privacy_education,
+      u.privacy_career AS privacy_career,
+      u.privacy_salary AS privacy_salary,
       collect({
         company: c.name,
         address: c.address,
         position: r.position
-      }) AS companies
+      }) AS companies,
+      created_timestamp
+    ORDER BY created_timestamp DESC, user_id DESC
 	 `
 
-	result, err := session.Run(ctx, query, nil)
+	result, err := session.Run(ctx, query, page.Params(map[string]interface{}{}))
 	if err != nil {
 		logger.Error(models.ErrRetrievalFailed, zap.Error(err))
-		return nil, fiber.NewError(http.StatusInternalServerError, models.ErrRetrievalFailed)
+		return nil, "", fiber.NewError(http.StatusInternalServerError, models.ErrRetrievalFailed)
 	}
 
 	records, err := result.Collect(ctx)
 	if err != nil {
 		logger.Error("Failed to collect query results", zap.Error(err))
-		return nil, fiber.NewError(http.StatusInternalServerError, "Error retrieving data")
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Error retrieving data")
 	}
 
+	records, nextCursor := models.NextPage(records, page, recordCursor("created_timestamp", "user_id"))
+
 	var users []map[string]interface{}
 
 	for _, record := range records {
@@ -73,7 +89,7 @@ func GetAllUser(ctx context.Context, driver neo4j.DriverWithContext, logger *zap
 		users = append(users, user)
 	}
 
-	return users, nil
+	return users, nextCursor, nil
 }
 
 func CreateProfile(ctx context.Context, driver neo4j.DriverWithContext, user models.CreateProfileRequest, logger *zap.Logger) (map[string]interface{}, error) {
@@ -111,7 +127,10 @@ func CreateProfile(ctx context.Context, driver neo4j.DriverWithContext, user mod
 		fieldCount++
 	}
 
-	queryBuilder.WriteString("}) RETURN u.user_id AS user_id")
+	if fieldCount > 0 {
+		queryBuilder.WriteString(", ")
+	}
+	queryBuilder.WriteString("created_timestamp: timestamp()}) RETURN u.user_id AS user_id")
 	query = queryBuilder.String()
 
 	// Run the query
@@ -150,6 +169,11 @@ func FetchUserByID(ctx context.Context, driver neo4j.DriverWithContext, id strin
           u.first_name_eng + " " + u.last_name_eng AS name_eng,
           u.profile_picture AS profile_picture,
           u.role AS role,
+          u.privacy_personal AS privacy_personal,
+          u.privacy_contact AS privacy_contact,
+          u.privacy_education AS privacy_education,
+          u.privacy_career AS privacy_career,
+          u.privacy_salary AS privacy_salary,
           {
             student_id: u.student_id,
             generation: u.generation,
@@ -322,25 +346,49 @@ func FetchUserByFilter(ctx context.Context, driver neo4j.DriverWithContext, filt
 	})
 	defer session.Close(ctx)
 
-	query := "MATCH "
+	var conditions []string
 	params := make(map[string]interface{})
 
-	if filter.Field != "" && filter.StudentType != "" {
-		query += `
-      (:StudentType {name: $studentTypeName})
-      <-[:BELONGS_TO_STUDENT_TYPE]-(u:UserProfile)-[:BELONGS_TO_FIELD]->
-      (:Field {name: $fieldName})
-    `
-		params["fieldName"] = filter.Field
-		params["studentTypeName"] = filter.StudentType
-	} else if filter.Field != "" {
-		query += "(u:UserProfile)-[:BELONG_TO_FIELD]->(:Field {name: $fieldName})"
+	if filter.Field != "" {
+		conditions = append(conditions, "EXISTS { (u)-[:BELONGS_TO_FIELD]->(:Field {name: $fieldName}) }")
 		params["fieldName"] = filter.Field
-	} else if filter.StudentType != "" {
-		query += "(u:UserProfile)-[:BELONG_TO_STUDENT_TYPE]->(:StudentType {name: $studentTypeName})"
+	}
+	if filter.StudentType != "" {
+		conditions = append(conditions, "EXISTS { (u)-[:BELONGS_TO_STUDENT_TYPE]->(:StudentType {name: $studentTypeName}) }")
 		params["studentTypeName"] = filter.StudentType
 	}
 
+	// The company and position have to match on the same job.
+	if filter.Company != "" || filter.PositionIndex != "" {
+		var work []string
+		if filter.Company != "" {
+			work = append(work, "c.name = $companyName")
+			params["companyName"] = filter.Company
+		}
+		if filter.PositionIndex != "" {
+			work = append(work, "r.position_index = $positionIndex")
+			params["positionIndex"] = filter.PositionIndex
+		}
+		conditions = append(conditions, "EXISTS { MATCH (u)-[r:HAS_WORK_WITH]->(c:Company) WHERE "+strings.Join(work, " AND ")+" }")
+	}
+
+	if len(filter.AdmitYearIndexes) > 0 {
+		conditions = append(conditions, "u.admit_year_index IN $admitYearIndexes")
+		params["admitYearIndexes"] = filter.AdmitYearIndexes
+	}
+	if len(filter.GraduateYearIndexes) > 0 {
+		conditions = append(conditions, "u.graduate_year_index IN $graduateYearIndexes")
+		params["graduateYearIndexes"] = filter.GraduateYearIndexes
+	}
+	if len(filter.GPAXIndexes) > 0 {
+		conditions = append(conditions, "u.gpax_index IN $gpaxIndexes")
+		params["gpaxIndexes"] = filter.GPAXIndexes
+	}
+
+	query := "MATCH (u:UserProfile)"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
 	query += " RETURN u"
 
 	// Execute the query
@@ -440,3 +488,57 @@ func FullTextSeach(ctx context.Context, driver neo4j.DriverWithContext, query_te
 
 	return users, nil
 }
+
+// SetLanguage stores the language the user is answered and mailed in, or
+// removes it when language is empty.
+func SetLanguage(ctx context.Context, driver neo4j.DriverWithContext, userID, language string, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u.language = $language
+    RETURN u.user_id AS user_id
+    `
+
+	params := map[string]interface{}{
+		"user_id":  userID,
+		"language": nil,
+	}
+	if language != "" {
+		params["language"] = language
+	}
+
+	_, err := runUserUpdate(ctx, driver, query, params, "update language", logger)
+	return err
+}
+
+// SetPrivacy stores who may see each field group of the user's profile. An
+// empty setting is removed, which goes back to the default.
+func SetPrivacy(ctx context.Context, driver neo4j.DriverWithContext, userID string, settings models.PrivacySettings, logger *zap.Logger) error {
+	query := `
+    MATCH (u:UserProfile {user_id: $user_id})
+    SET u.privacy_personal = $personal,
+        u.privacy_contact = $contact,
+        u.privacy_education = $education,
+        u.privacy_career = $career,
+        u.privacy_salary = $salary
+    RETURN u.user_id AS user_id
+    `
+
+	params := map[string]interface{}{
+		"user_id": userID,
+	}
+	for group, visibility := range map[string]string{
+		models.PrivacyPersonal:  settings.Personal,
+		models.PrivacyContact:   settings.Contact,
+		models.PrivacyEducation: settings.Education,
+		models.PrivacyCareer:    settings.Career,
+		models.PrivacySalary:    settings.Salary,
+	} {
+		params[group] = nil
+		if visibility != "" {
+			params[group] = visibility
+		}
+	}
+
+	_, err := runUserUpdate(ctx, driver, query, params, "update privacy", logger)
+	return err
+}
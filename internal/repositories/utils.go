@@ -11,7 +11,7 @@ import (
 	"go.uber.org/zap"
 )
 
-func FetchReport(ctx context.Context, driver neo4j.DriverWithContext, logger *zap.Logger) ([]map[string]interface{}, error) {
+func FetchReport(ctx context.Context, driver neo4j.DriverWithContext, page models.Page, departmentID string, logger *zap.Logger) ([]map[string]interface{}, string, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
 		AccessMode:   neo4j.AccessModeWrite,
@@ -20,6 +20,10 @@ func FetchReport(ctx context.Context, driver neo4j.DriverWithContext, logger *za
 
 	query := `
     MATCH (author:UserProfile)-[:HAS_POST]->(p:Post)-[:BEEN_REPORT]->(r:Report)<-[:REPORT]-(u:UserProfile)
+    WHERE ($cursor_ts IS NULL
+      OR r.created_timestamp < $cursor_ts
+      OR (r.created_timestamp = $cursor_ts AND r.report_id < $cursor_id))
+      AND ` + inDepartment("author") + `
     RETURN
       p.post_id AS post_id,
       p.title AS title,
@@ -43,23 +47,28 @@ func FetchReport(ctx context.Context, driver neo4j.DriverWithContext, logger *za
       r.status AS status,
       r.type AS type,
       r.created_timestamp AS created_timestamp
-    LIMIT 100
+    ORDER BY created_timestamp DESC, report_id DESC
+    LIMIT $limit
   `
 
-	var params = map[string]interface{}{}
+	var params = page.Params(map[string]interface{}{
+		"department_id": departmentParam(departmentID),
+	})
 
 	result, err := session.Run(ctx, query, params)
 	if err != nil {
 		logger.Error("Failed to create post", zap.Error(err))
-		return nil, fiber.NewError(http.StatusInternalServerError, "Failed to create post")
+		return nil, "", fiber.NewError(http.StatusInternalServerError, "Failed to create post")
 	}
 
 	records, err := result.Collect(ctx)
 	if err != nil {
 		logger.Error(models.ErrRetrievalFailed, zap.Error(err))
-		return nil, fiber.NewError(http.StatusInternalServerError, models.ErrRetrievalFailed)
+		return nil, "", fiber.NewError(http.StatusInternalServerError, models.ErrRetrievalFailed)
 	}
 
+	records, nextCursor := models.NextPage(records, page, recordCursor("created_timestamp", "report_id"))
+
 	var reports []map[string]interface{}
 
 	for _, record := range records {
@@ -67,7 +76,7 @@ func FetchReport(ctx context.Context, driver neo4j.DriverWithContext, logger *za
 		reports = append(reports, reportMap)
 	}
 
-	return reports, nil
+	return reports, nextCursor, nil
 }
 
 func Report(ctx context.Context, driver neo4j.DriverWithContext, report models.Report, logger *zap.Logger) error {
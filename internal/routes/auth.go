@@ -3,33 +3,53 @@ package routes
 import (
 	"alumni_api/internal/controllers"
 	"alumni_api/internal/middlewares"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func AuthRoutes(group fiber.Router, driver neo4j.DriverWithContext, logger *zap.Logger) {
+func AuthRoutes(group fiber.Router, store *repositories.Store, logger *zap.Logger) {
 	auth := group.Group("/auth")
 
-	auth.Post("/registry/user", controllers.RegistryUser(driver, logger))
-	auth.Post("/registry/alumnus", controllers.RegistryAlumnus(driver, logger))
-	auth.Post("/login", controllers.Login(driver, logger))
-	auth.Post("/logout", controllers.Logout(driver, logger))
-	auth.Post("/verify-account", controllers.VerifyAccount(driver, logger))
-	auth.Post("/request_OTR", controllers.RequestAlumniOneTimeRegistry(driver, logger))
-	auth.Post("/request/password_reset", controllers.RequestChangePassword(driver, logger))
-	auth.Post("/request/password_reset/confirm", controllers.ChangePassword(driver, logger))
+	auth.Post("/registry/user", controllers.RegistryUser(store, logger))
+	auth.Post("/registry/alumnus", controllers.RegistryAlumnus(store, logger))
+	auth.Post("/login", controllers.Login(store, logger))
+	auth.Post("/login/mfa", controllers.LoginMFA(store, logger))
+	auth.Post("/login/mfa/setup", controllers.LoginMFASetup(store, logger))
+	auth.Post("/logout", controllers.Logout(store, logger))
+	auth.Post("/refresh", controllers.RefreshSession(store, logger))
+	auth.Post("/verify-account", controllers.VerifyAccount(store, logger))
+	auth.Post("/request_OTR", controllers.RequestAlumniOneTimeRegistry(store, logger))
+	auth.Post("/request/password_reset", controllers.RequestChangePassword(store, logger))
+	auth.Post("/request/password_reset/confirm", controllers.ChangePassword(store, logger))
 
 	authWithAuth := group.Group("/auth")
-	authWithAuth.Use(middlewares.JWTMiddleware(logger))
+	authWithAuth.Use(middlewares.JWTMiddleware(store, logger))
 
-	authWithAuth.Get("/verify-token", controllers.VerifyToken(driver, logger))
+	authWithAuth.Get("/verify-token", controllers.VerifyToken(store, logger))
 
-	authWithAuth.Post("/request/email_change", controllers.RequestChangeEmail(driver, logger))
-	authWithAuth.Post("/request/email_change/confirm", controllers.VerifyEmail(driver, logger))
+	authWithAuth.Get("/sessions", controllers.GetSessions(store, logger))
+	authWithAuth.Delete("/sessions", controllers.RevokeAllSessions(store, logger))
+	authWithAuth.Delete("/sessions/:session_id", controllers.RevokeSession(store, logger))
 
-	authWithAuth.Get("/request", controllers.GetAllRequest(driver, logger))
-	authWithAuth.Post("/request/role", controllers.RequestAlumnusRole(driver, logger))
-	authWithAuth.Post("/request/:request_id/approve", controllers.ApproveAlumnusRole(driver, logger))
-	authWithAuth.Post("/request/:request_id/reject", controllers.RejectAlumnusRole(driver, logger))
+	authWithAuth.Get("/permissions", controllers.GetMyPermissions(store, logger))
+	authWithAuth.Put("/language", controllers.SetLanguage(store, logger))
+	authWithAuth.Get("/security_events", controllers.GetSecurityEvents(store, logger))
+	authWithAuth.Post("/unlock/:user_id", middlewares.RequirePermission(store, logger, models.PermissionAccountManage), controllers.UnlockAccount(store, logger))
+
+	authWithAuth.Get("/mfa", controllers.GetMFAStatus(store, logger))
+	authWithAuth.Post("/mfa/enroll", controllers.EnrollMFA(store, logger))
+	authWithAuth.Post("/mfa/enable", controllers.EnableMFA(store, logger))
+	authWithAuth.Post("/mfa/disable", controllers.DisableMFA(store, logger))
+	authWithAuth.Post("/mfa/backup_codes", controllers.RegenerateBackupCodes(store, logger))
+	authWithAuth.Put("/mfa/required/:user_id", middlewares.RequirePermission(store, logger, models.PermissionAccountManage), controllers.SetMFARequired(store, logger))
+
+	authWithAuth.Post("/request/email_change", controllers.RequestChangeEmail(store, logger))
+	authWithAuth.Post("/request/email_change/confirm", controllers.VerifyEmail(store, logger))
+
+	authWithAuth.Get("/request", middlewares.RequirePermission(store, logger, models.PermissionRoleRequestReview), controllers.GetAllRequest(store, logger))
+	authWithAuth.Post("/request/role", controllers.RequestAlumnusRole(store, logger))
+	authWithAuth.Post("/request/:request_id/approve", middlewares.RequirePermission(store, logger, models.PermissionRoleRequestReview), controllers.ApproveAlumnusRole(store, logger))
+	authWithAuth.Post("/request/:request_id/reject", middlewares.RequirePermission(store, logger, models.PermissionRoleRequestReview), controllers.RejectAlumnusRole(store, logger))
 }
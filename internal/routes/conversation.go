@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"alumni_api/internal/controllers"
+	"alumni_api/internal/middlewares"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/websockets"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+func ConversationRoutes(group fiber.Router, store *repositories.Store, hub *websockets.Hub, logger *zap.Logger) {
+	hub.AuthorizeRooms(controllers.AuthorizeRoom(store, logger))
+
+	conv := group.Group("/conversations")
+	conv.Use(middlewares.JWTMiddleware(store, logger))
+
+	// Conversation endpoints
+	conv.Post("", controllers.CreateConversation(store, hub, logger))
+	conv.Get("/:conversation_id", controllers.GetConversationByID(store, logger))
+	conv.Put("/:conversation_id", controllers.UpdateConversation(store, hub, logger))
+	conv.Post("/:conversation_id/leave", controllers.LeaveConversation(store, hub, logger))
+
+	// Member endpoints
+	conv.Post("/:conversation_id/members", controllers.InviteConversationMembers(store, hub, logger))
+	conv.Put("/:conversation_id/members/:user_id", controllers.SetConversationRole(store, hub, logger))
+	conv.Delete("/:conversation_id/members/:user_id", controllers.RemoveConversationMember(store, hub, logger))
+
+	// Message endpoints
+	conv.Post("/:conversation_id/messages", controllers.SendGroupMessage(store, hub, logger))
+	conv.Get("/:conversation_id/messages", controllers.GetGroupMessages(store, logger))
+
+	// Event chats
+	eventChat := group.Group("/post/:post_id/chat")
+	eventChat.Use(middlewares.JWTMiddleware(store, logger))
+	eventChat.Post("", controllers.JoinEventChat(store, hub, logger))
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"alumni_api/internal/controllers"
+	"alumni_api/internal/middlewares"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+func DepartmentRoutes(group fiber.Router, store *repositories.Store, logger *zap.Logger) {
+	department := group.Group("/departments")
+	department.Use(middlewares.JWTMiddleware(store, logger))
+	department.Use(middlewares.RequirePermission(store, logger, models.PermissionRoleManage))
+
+	department.Get("/", controllers.GetDepartments(store, logger))
+	department.Put("/:department_id/admins/:user_id", controllers.AppointDepartmentAdmin(store, logger))
+	department.Delete("/:department_id/admins/:user_id", controllers.RemoveDepartmentAdmin(store, logger))
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"alumni_api/internal/controllers"
+	"alumni_api/internal/middlewares"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+func MailRoutes(group fiber.Router, store *repositories.Store, logger *zap.Logger) {
+	mail := group.Group("/mail")
+	mail.Use(middlewares.JWTMiddleware(store, logger))
+	mail.Use(middlewares.RequirePermission(store, logger, models.PermissionMailManage))
+
+	mail.Get("/dead_letters", controllers.GetDeadLetters(store, logger))
+	mail.Post("/dead_letters/:mail_id/retry", controllers.RetryDeadLetter(store, logger))
+}
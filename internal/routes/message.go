@@ -3,28 +3,51 @@ package routes
 import (
 	"alumni_api/internal/controllers"
 	"alumni_api/internal/middlewares"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/storage"
 	"alumni_api/internal/websockets"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/websocket/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func MessageRoutes(group fiber.Router, driver neo4j.DriverWithContext, logger *zap.Logger) {
-	group.Use("/ws", middlewares.WebSocketMiddleware(logger))
-	group.Get("/ws", websocket.New(websockets.WebsocketHandler))
+func MessageRoutes(group fiber.Router, store *repositories.Store, hub *websockets.Hub, files storage.Storage, logger *zap.Logger) {
+	group.Use("/ws", middlewares.WebSocketMiddleware(store, logger))
+	group.Get("/ws", websocket.New(websockets.Handler(hub)))
+
+	hub.Handle(websockets.TypeRead, controllers.ReadMessageFrame(store, hub, logger))
+	hub.Handle(websockets.TypePresence, controllers.PresenceFrame(store, hub, logger))
+	hub.OnPresence(controllers.NotifyFriendsPresence(store, hub, logger))
 
 	msg := group.Group("/user/:user_id/message")
-	msg.Use(middlewares.JWTMiddleware(logger))
+	msg.Use(middlewares.JWTMiddleware(store, logger))
 
 	chatMsg := group.Group("/user/:user_id/chat_message")
-	chatMsg.Use(middlewares.JWTMiddleware(logger))
+	chatMsg.Use(middlewares.JWTMiddleware(store, logger))
+
+	conversations := group.Group("/user/:user_id/conversations")
+	conversations.Use(middlewares.JWTMiddleware(store, logger))
+
+	presence := group.Group("/user/:user_id/friends")
+	presence.Use(middlewares.JWTMiddleware(store, logger))
+
+	attachments := group.Group("/attachments")
+	attachments.Use(middlewares.JWTMiddleware(store, logger))
 
 	// Message endpoints
-	msg.Post("/send", controllers.SendMessage(driver, logger))
-	msg.Post("/reply", controllers.ReplyMessage(driver, logger))
-	msg.Put("/:message_id", controllers.EditMessage(driver, logger))
-	msg.Delete("/:message_id", controllers.DeleteMessage(driver, logger))
+	msg.Post("/send", controllers.SendMessage(store, hub, logger))
+	msg.Post("/reply", controllers.ReplyMessage(store, hub, logger))
+	msg.Post("/attachment", controllers.UploadAttachment(store, files, logger))
+	msg.Put("/:message_id", controllers.EditMessage(store, logger))
+	msg.Delete("/:message_id", controllers.DeleteMessage(store, logger))
+
+	chatMsg.Get("/:other_user_id", controllers.GetChatMessage(store, logger))
+	conversations.Get("/", controllers.GetConversations(store, logger))
+
+	// Attachment endpoints
+	attachments.Get("/:attachment_id", controllers.DownloadAttachment(store, files, logger))
+	attachments.Get("/:attachment_id/url", controllers.GetAttachmentURL(store, files, logger))
 
-	chatMsg.Get("/:other_user_id", controllers.GetChatMessage(driver, logger))
+	// Presence endpoints
+	presence.Get("/online", controllers.GetOnlineFriends(store, hub, logger))
 }
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"alumni_api/internal/auth"
+	"alumni_api/internal/controllers"
+	"alumni_api/internal/repositories"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+func OIDCRoutes(group fiber.Router, store *repositories.Store, providers *auth.OIDCProviders, logger *zap.Logger) {
+	oidc := group.Group("/auth/oidc")
+
+	oidc.Get("/", controllers.GetOIDCProviders(providers, logger))
+	oidc.Get("/:provider", controllers.StartOIDCLogin(providers, logger))
+	oidc.Post("/:provider/callback", controllers.OIDCCallback(store, providers, logger))
+}
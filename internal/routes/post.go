@@ -3,38 +3,39 @@ package routes
 import (
 	"alumni_api/internal/controllers"
 	"alumni_api/internal/middlewares"
+	"alumni_api/internal/repositories"
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func PostRoutes(group fiber.Router, driver neo4j.DriverWithContext, logger *zap.Logger) {
+func PostRoutes(group fiber.Router, store *repositories.Store, logger *zap.Logger) {
 
 	post := group.Group("/post")
 	// accessible to public
-	post.Get("/all", controllers.GetAllPost(driver, logger))
-	post.Get("/:post_id", controllers.GetPostByID(driver, logger))
-	post.Get("/:post_id/comment", controllers.GetCommentByPostID(driver, logger))
+	post.Get("/all", controllers.GetAllPost(store, logger))
+	post.Get("/feed", controllers.GetFeed(store, logger))
+	post.Get("/:post_id", controllers.GetPostByID(store, logger))
+	post.Get("/:post_id/comment", controllers.GetCommentByPostID(store, logger))
 
 	postWithAuth := group.Group("/post")
-	postWithAuth.Use(middlewares.JWTMiddleware(logger))
+	postWithAuth.Use(middlewares.JWTMiddleware(store, logger))
 
 	// post
-	postWithAuth.Post("", controllers.CreatePost(driver, logger))
-	postWithAuth.Put("/:post_id", controllers.UpdatePostByID(driver, logger))
-	postWithAuth.Delete("/:post_id", controllers.DeletePostByID(driver, logger))
+	postWithAuth.Post("", controllers.CreatePost(store, logger))
+	postWithAuth.Put("/:post_id", controllers.UpdatePostByID(store, logger))
+	postWithAuth.Delete("/:post_id", controllers.DeletePostByID(store, logger))
 
 	// like post
-	postWithAuth.Post("/:post_id/like", controllers.LikePost(driver, logger))
-	postWithAuth.Delete("/:post_id/like", controllers.UnlikePost(driver, logger))
+	postWithAuth.Post("/:post_id/like", controllers.LikePost(store, logger))
+	postWithAuth.Delete("/:post_id/like", controllers.UnlikePost(store, logger))
 
 	// comment post
-	postWithAuth.Post("/:post_id/comment", controllers.CommentPost(driver, logger))
-	postWithAuth.Post("/:post_id/comment/:comment_id", controllers.ReplyComment(driver, logger))
-	postWithAuth.Put("/:post_id/comment/:comment_id", controllers.UpdateCommentPost(driver, logger))
-	postWithAuth.Delete("/:post_id/comment/:comment_id", controllers.DeleteCommentPost(driver, logger))
+	postWithAuth.Post("/:post_id/comment", controllers.CommentPost(store, logger))
+	postWithAuth.Post("/:post_id/comment/:comment_id", controllers.ReplyComment(store, logger))
+	postWithAuth.Put("/:post_id/comment/:comment_id", controllers.UpdateCommentPost(store, logger))
+	postWithAuth.Delete("/:post_id/comment/:comment_id", controllers.DeleteCommentPost(store, logger))
 
 	// like comment
-	postWithAuth.Post("/comment/:comment_id/like", controllers.LikeComment(driver, logger))
-	postWithAuth.Delete("/comment/:comment_id/like", controllers.UnlikeComment(driver, logger))
+	postWithAuth.Post("/comment/:comment_id/like", controllers.LikeComment(store, logger))
+	postWithAuth.Delete("/comment/:comment_id/like", controllers.UnlikeComment(store, logger))
 }
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"alumni_api/internal/controllers"
+	"alumni_api/internal/middlewares"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+func RoleRoutes(group fiber.Router, store *repositories.Store, logger *zap.Logger) {
+	role := group.Group("/roles")
+	role.Use(middlewares.JWTMiddleware(store, logger))
+	role.Use(middlewares.RequirePermission(store, logger, models.PermissionRoleManage))
+
+	role.Get("/", controllers.GetRoles(store, logger))
+	role.Put("/:name", controllers.SaveRole(store, logger))
+	role.Delete("/:name", controllers.DeleteRole(store, logger))
+	role.Post("/:name/users/:user_id", controllers.AssignRole(store, logger))
+	role.Delete("/:name/users/:user_id", controllers.RevokeRole(store, logger))
+}
@@ -3,21 +3,22 @@ package routes
 import (
 	"alumni_api/internal/controllers"
 	"alumni_api/internal/middlewares"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func StatRoutes(group fiber.Router, driver neo4j.DriverWithContext, logger *zap.Logger) {
+func StatRoutes(group fiber.Router, store *repositories.Store, logger *zap.Logger) {
 	stat := group.Group("/stat")
-	stat.Get("/activity", controllers.GetActivityStat(driver, logger))
+	stat.Get("/activity", controllers.GetActivityStat(store, logger))
 
 	statWithAuth := group.Group("/stat")
-	statWithAuth.Use(middlewares.JWTMiddleware(logger))
+	statWithAuth.Use(middlewares.JWTMiddleware(store, logger))
 
-	statWithAuth.Get("/post", controllers.GetPostStat(driver, logger))
-	statWithAuth.Get("/registry", controllers.GetRegistryStat(driver, logger))
-	statWithAuth.Post("/generation", controllers.GetGenerationSTStat(driver, logger))
-	statWithAuth.Get("/salary", controllers.GetUserSalary(driver, logger))
-	statWithAuth.Get("/job", controllers.GetUserJob(driver, logger))
+	statWithAuth.Get("/post", middlewares.RequirePermission(store, logger, models.PermissionStatRead), controllers.GetPostStat(store, logger))
+	statWithAuth.Get("/registry", middlewares.RequirePermission(store, logger, models.PermissionStatRead), controllers.GetRegistryStat(store, logger))
+	statWithAuth.Post("/generation", controllers.GetGenerationSTStat(store, logger))
+	statWithAuth.Get("/salary", middlewares.RequirePermission(store, logger, models.PermissionStatRead), controllers.GetUserSalary(store, logger))
+	statWithAuth.Get("/job", controllers.GetUserJob(store, logger))
 }
@@ -3,13 +3,18 @@ package routes
 import (
 	"alumni_api/internal/controllers"
 	"alumni_api/internal/middlewares"
+	"alumni_api/internal/repositories"
+	"alumni_api/internal/storage"
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func UploadRoutes(group fiber.Router, driver neo4j.DriverWithContext, logger *zap.Logger) {
+func UploadRoutes(group fiber.Router, store *repositories.Store, files storage.Storage, logger *zap.Logger) {
+	// Public images and signed links need no session.
+	group.Get("/uploads/*", controllers.ServeUpload(files, logger))
+	group.Get("/files/*", controllers.ServeSignedFile(files, logger))
+
 	upload := group.Group("/upload")
-	upload.Use(middlewares.JWTMiddleware(logger))
-	upload.Post("", controllers.Upload(driver, logger))
+	upload.Use(middlewares.JWTMiddleware(store, logger))
+	upload.Post("", controllers.Upload(store, files, logger))
 }
@@ -3,18 +3,19 @@ package routes
 import (
 	"alumni_api/internal/controllers"
 	"alumni_api/internal/middlewares"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories"
 	"github.com/gofiber/fiber/v2"
-	"github.com/neo4j/neo4j-go-driver/v5/neo4j"
 	"go.uber.org/zap"
 )
 
-func UtilsRoute(group fiber.Router, driver neo4j.DriverWithContext, logger *zap.Logger) {
+func UtilsRoute(group fiber.Router, store *repositories.Store, logger *zap.Logger) {
 	utils := group.Group("/utils")
-	utils.Get("/fulltext_search/company", controllers.CompanyFullTextSearch(driver, logger))
+	utils.Get("/fulltext_search/company", controllers.CompanyFullTextSearch(store, logger))
 
 	utilsWithAuth := group.Group("/utils")
-	utilsWithAuth.Use(middlewares.JWTMiddleware(logger))
+	utilsWithAuth.Use(middlewares.JWTMiddleware(store, logger))
 
-	utilsWithAuth.Get("/report", controllers.FetchReport(driver, logger))
-	utilsWithAuth.Post("/report", controllers.Report(driver, logger))
+	utilsWithAuth.Get("/report", middlewares.RequirePermission(store, logger, models.PermissionReportReview), controllers.FetchReport(store, logger))
+	utilsWithAuth.Post("/report", controllers.Report(store, logger))
 }
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"alumni_api/internal/controllers"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
+)
+
+// WellKnownRoutes are served from the root, outside the versioned API.
+func WellKnownRoutes(app fiber.Router, logger *zap.Logger) {
+	wellKnown := app.Group("/.well-known")
+	wellKnown.Get("/jwks.json", controllers.JWKS(logger))
+}
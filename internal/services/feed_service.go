@@ -0,0 +1,115 @@
+package services
+
+import (
+	"alumni_api/config"
+	"alumni_api/internal/models"
+	"math"
+	"sort"
+	"time"
+)
+
+// engagementScale is the count at which an engagement signal reaches half of
+// its weight.
+const engagementScale = 10
+
+func saturate(count int64) float64 {
+	return float64(count) / float64(count+engagementScale)
+}
+
+func flag(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+func round(value float64) float64 {
+	return math.Round(value*1e4) / 1e4
+}
+
+// ScoreFeedCandidate returns the ranking score of a candidate and the
+// contribution of every signal that added to it.
+func ScoreFeedCandidate(candidate models.FeedCandidate, cfg config.FeedConfig, now time.Time) (float64, map[string]float64) {
+	ageHours := math.Max(0, float64(now.UnixMilli()-candidate.CreatedAt)/float64(time.Hour/time.Millisecond))
+	recency := 1.0
+	if cfg.HalfLifeHours > 0 {
+		recency = math.Pow(0.5, ageHours/cfg.HalfLifeHours)
+	}
+
+	contributions := map[string]float64{
+		"recency":      cfg.Recency * recency,
+		"friend":       cfg.Friend * flag(candidate.IsFriend),
+		"generation":   cfg.Generation * flag(candidate.SameGeneration),
+		"student_type": cfg.StudentType * flag(candidate.SameStudentType),
+		"company":      cfg.Company * flag(candidate.SharedCompanies > 0),
+		"likes":        cfg.Likes * saturate(candidate.Likes),
+		"views":        cfg.Views * saturate(candidate.Views),
+		"comments":     cfg.Comments * saturate(candidate.Comments),
+	}
+
+	var score float64
+	explanation := make(map[string]float64)
+	for signal, value := range contributions {
+		if value == 0 {
+			continue
+		}
+		score += value
+		explanation[signal] = round(value)
+	}
+
+	return round(score), explanation
+}
+
+func feedCursor(item map[string]interface{}) models.Cursor {
+	postID, _ := item["post_id"].(string)
+	return models.Cursor{
+		Timestamp: int64(math.Round(item["score"].(float64) * 1e4)),
+		ID:        postID,
+	}
+}
+
+// RankFeed scores the candidates, orders them best first and returns the
+// requested page. The cursor carries the score of the last item rather than
+// its position, so the next page continues below it. It also carries the
+// time the first page was ranked at, and later pages are scored as of then:
+// recency decays posts at different rates, so scores taken at another time
+// could move posts across the cursor and repeat or skip them.
+func RankFeed(candidates []models.FeedCandidate, cfg config.FeedConfig, now time.Time, page models.Page) ([]map[string]interface{}, string) {
+	if page.After != nil && page.After.RankedAt != 0 {
+		now = time.UnixMilli(page.After.RankedAt)
+	}
+
+	items := make([]map[string]interface{}, 0, len(candidates))
+	for _, candidate := range candidates {
+		score, explanation := ScoreFeedCandidate(candidate, cfg, now)
+
+		item := candidate.Post
+		item["score"] = score
+		item["explanation"] = explanation
+		items = append(items, item)
+	}
+
+	less := func(a, b models.Cursor) bool {
+		if a.Timestamp != b.Timestamp {
+			return a.Timestamp > b.Timestamp
+		}
+		return a.ID > b.ID
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return less(feedCursor(items[i]), feedCursor(items[j]))
+	})
+
+	var ranked []map[string]interface{}
+	for _, item := range items {
+		if page.After == nil || less(*page.After, feedCursor(item)) {
+			ranked = append(ranked, item)
+		}
+	}
+
+	return models.NextPage(ranked, page, func(item map[string]interface{}) models.Cursor {
+		cursor := feedCursor(item)
+		cursor.RankedAt = now.UnixMilli()
+		return cursor
+	})
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+// maxImagePixels bounds the decoded size of an upload so a small file
+// cannot expand into gigabytes of pixels.
+const maxImagePixels = 40_000_000
+
+// Rendition is a downscaled copy of an upload that fits in MaxSize×MaxSize.
+type Rendition struct {
+	Name    string
+	MaxSize int
+}
+
+// ImageRenditions are generated for every image upload, for profile
+// pictures and post media alike.
+var ImageRenditions = []Rendition{
+	{Name: "thumbnail", MaxSize: 160},
+	{Name: "medium", MaxSize: 800},
+}
+
+// ProcessedImage is an upload after validation and re-encoding. Original
+// carries no metadata; Renditions are keyed by Rendition.Name.
+type ProcessedImage struct {
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+	Original    []byte
+	Renditions  map[string][]byte
+}
+
+// ProcessImage sniffs the upload instead of trusting the client's
+// Content-Type or file name, decodes it fully, and re-encodes it. JPEG stays
+// JPEG and other formats become PNG, which drops EXIF, GPS and any other
+// metadata; the EXIF orientation is applied to the pixels first. GIFs keep
+// their original bytes so animations survive, since GIF carries no EXIF.
+func ProcessImage(data []byte) (ProcessedImage, error) {
+	sniffed := http.DetectContentType(data)
+
+	switch sniffed {
+	case "image/jpeg", "image/png", "image/gif", "image/webp":
+	default:
+		return ProcessedImage{}, fiber.NewError(fiber.StatusUnsupportedMediaType, "Unsupported image type: "+sniffed)
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, fiber.NewError(fiber.StatusBadRequest, "Invalid image")
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > maxImagePixels {
+		return ProcessedImage{}, fiber.NewError(fiber.StatusBadRequest, "Image dimensions are too large")
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, fiber.NewError(fiber.StatusBadRequest, "Invalid image")
+	}
+
+	out := ProcessedImage{
+		ContentType: "image/png",
+		Extension:   ".png",
+		Renditions:  make(map[string][]byte),
+	}
+	encode := func(img image.Image) ([]byte, error) {
+		var buf bytes.Buffer
+		err := png.Encode(&buf, img)
+		return buf.Bytes(), err
+	}
+
+	switch sniffed {
+	case "image/jpeg":
+		img = orient(img, jpegOrientation(data))
+		out.ContentType = "image/jpeg"
+		out.Extension = ".jpg"
+		encode = func(img image.Image) ([]byte, error) {
+			var buf bytes.Buffer
+			err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 88})
+			return buf.Bytes(), err
+		}
+	case "image/gif":
+		// Validate every frame, not just the first one image.Decode read.
+		if _, err := gif.DecodeAll(bytes.NewReader(data)); err != nil {
+			return ProcessedImage{}, fiber.NewError(fiber.StatusBadRequest, "Invalid image")
+		}
+	}
+
+	bounds := img.Bounds()
+	out.Width, out.Height = bounds.Dx(), bounds.Dy()
+
+	if sniffed == "image/gif" {
+		out.ContentType = "image/gif"
+		out.Extension = ".gif"
+		out.Original = data
+	} else if out.Original, err = encode(img); err != nil {
+		return ProcessedImage{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to encode image")
+	}
+
+	for _, rendition := range ImageRenditions {
+		scaled, err := encode(fit(img, rendition.MaxSize))
+		if err != nil {
+			return ProcessedImage{}, fiber.NewError(fiber.StatusInternalServerError, "Failed to encode image")
+		}
+		out.Renditions[rendition.Name] = scaled
+	}
+
+	return out, nil
+}
+
+// RenditionExtension is the file extension of the renditions of an image
+// processed into contentType. Renditions of a GIF are still PNG frames.
+func RenditionExtension(contentType string) string {
+	if contentType == "image/jpeg" {
+		return ".jpg"
+	}
+	return ".png"
+}
+
+// fit downscales img to fit in size×size, keeping its aspect ratio. Images
+// that already fit are returned as they are.
+func fit(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= size && h <= size {
+		return img
+	}
+
+	if w >= h {
+		h = max(1, h*size/w)
+		w = size
+	} else {
+		w = max(1, w*size/h)
+		h = size
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Over, nil)
+	return dst
+}
+
+// orient applies an EXIF orientation (1-8) so the pixels are upright once
+// the tag is gone.
+func orient(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			dst.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+
+	return dst
+}
+
+// jpegOrientation reads the orientation tag from the EXIF block of a JPEG,
+// returning 1 (upright) when there is none.
+func jpegOrientation(data []byte) int {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return 1
+	}
+
+	for i := 2; i+4 <= len(data) && data[i] == 0xFF; {
+		marker := data[i+1]
+		if marker == 0xD9 || marker == 0xDA {
+			break
+		}
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			break
+		}
+
+		segment := data[i+4 : end]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return tiffOrientation(segment[6:])
+		}
+		i = end
+	}
+
+	return 1
+}
+
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 1
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 1
+	}
+
+	ifd := int(order.Uint32(tiff[4:]))
+	if ifd+2 > len(tiff) {
+		return 1
+	}
+
+	count := int(order.Uint16(tiff[ifd:]))
+	for n := 0; n < count; n++ {
+		entry := ifd + 2 + n*12
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8:]))
+		}
+	}
+
+	return 1
+}
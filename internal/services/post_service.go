@@ -36,6 +36,47 @@ func AddView(ctx context.Context, driver neo4j.DriverWithContext, user_id, post_
 	return nil
 }
 
+// PostVisible reports whether the post exists and may be read by a caller
+// allowed the given visibilities. Authors always see their own posts.
+func PostVisible(ctx context.Context, driver neo4j.DriverWithContext, postID, userID string, visibility []string, logger *zap.Logger) (bool, error) {
+	session := driver.NewSession(ctx, neo4j.SessionConfig{
+		DatabaseName: "neo4j",
+		AccessMode:   neo4j.AccessModeRead,
+	})
+	defer session.Close(ctx)
+
+	query := `
+    MATCH (author:UserProfile)-[:HAS_POST]->(p:Post {post_id: $post_id})
+    WHERE coalesce(p.visibility, "all") IN $visibility OR author.user_id = $user_id
+    RETURN COUNT(p) > 0 AS visible
+    `
+
+	params := map[string]interface{}{
+		"post_id":    postID,
+		"user_id":    userID,
+		"visibility": visibility,
+	}
+
+	result, err := session.Run(ctx, query, params)
+	if err != nil {
+		logger.Error("Error running query", zap.Error(err))
+		return false, fiber.NewError(http.StatusInternalServerError, "Error checking post visibility")
+	}
+
+	record, err := result.Single(ctx)
+	if err != nil {
+		logger.Error("Error retrieving result", zap.Error(err))
+		return false, fiber.NewError(http.StatusInternalServerError, "Error retrieving result")
+	}
+
+	visible, ok := record.Get("visible")
+	if !ok {
+		return false, nil
+	}
+
+	return visible.(bool), nil
+}
+
 func GetAuthorUserID(ctx context.Context, driver neo4j.DriverWithContext, postID string, logger *zap.Logger) (string, error) {
 	session := driver.NewSession(ctx, neo4j.SessionConfig{
 		DatabaseName: "neo4j",
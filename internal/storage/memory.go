@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"sync"
+	"time"
+)
+
+type memoryObject struct {
+	data        []byte
+	contentType string
+}
+
+// Memory keeps objects in a map. It is meant for tests and for trying the
+// API without a disk or a bucket.
+type Memory struct {
+	*Signer
+	mu      sync.RWMutex
+	objects map[string]memoryObject
+}
+
+func NewMemory(signer *Signer) *Memory {
+	return &Memory{Signer: signer, objects: make(map[string]memoryObject)}
+}
+
+func (m *Memory) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.objects[key] = memoryObject{data: data, contentType: contentType}
+	return nil
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (io.ReadCloser, Object, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	obj, ok := m.objects[key]
+	if !ok {
+		return nil, Object{}, ErrNotFound
+	}
+
+	return io.NopCloser(bytes.NewReader(obj.data)), Object{Size: int64(len(obj.data)), ContentType: obj.contentType}, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.objects, key)
+	return nil
+}
+
+func (m *Memory) SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	return m.URL(key, ttl)
+}
@@ -0,0 +1,61 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Signer issues and checks the expiring URLs of backends that are served by
+// the API. A URL is baseURL/key?expires=<unix seconds>&signature=<hmac>.
+type Signer struct {
+	key     []byte
+	baseURL string
+	now     func() time.Time
+}
+
+func NewSigner(key []byte, baseURL string) *Signer {
+	return &Signer{key: key, baseURL: baseURL, now: time.Now}
+}
+
+func (s *Signer) sign(key, expires string) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(key + "\n" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// URL returns the signed URL of key, valid for ttl.
+func (s *Signer) URL(key string, ttl time.Duration) (string, error) {
+	if err := validKey(key); err != nil {
+		return "", err
+	}
+
+	expires := strconv.FormatInt(s.now().Add(ttl).Unix(), 10)
+	query := url.Values{
+		"expires":   {expires},
+		"signature": {s.sign(key, expires)},
+	}
+
+	return s.baseURL + "/" + key + "?" + query.Encode(), nil
+}
+
+// VerifyURL checks the expires and signature query parameters of a URL
+// issued by URL for key.
+func (s *Signer) VerifyURL(key, expires, signature string) error {
+	if !hmac.Equal([]byte(s.sign(key, expires)), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if s.now().Unix() > unix {
+		return ErrExpired
+	}
+
+	return nil
+}
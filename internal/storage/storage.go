@@ -0,0 +1,78 @@
+// Package storage keeps uploaded files in a blob store chosen by
+// config.Config: a local directory, an S3-compatible bucket or memory.
+package storage
+
+import (
+	"alumni_api/config"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("storage: object not found")
+	ErrInvalidKey       = errors.New("storage: invalid object key")
+	ErrInvalidSignature = errors.New("storage: invalid signature")
+	ErrExpired          = errors.New("storage: signed URL expired")
+)
+
+// Object describes a stored blob. ContentType is empty when the backend does
+// not keep it.
+type Object struct {
+	Size        int64
+	ContentType string
+}
+
+// Storage is a flat key/value blob store. Keys are slash separated paths
+// such as "attachments/<uuid>".
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, Object, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a URL anyone can GET the object from until ttl
+	// has passed.
+	SignedURL(ctx context.Context, key string, ttl time.Duration) (string, error)
+}
+
+// URLVerifier is implemented by backends whose signed URLs are served by the
+// API itself rather than by the backend.
+type URLVerifier interface {
+	VerifyURL(key, expires, signature string) error
+}
+
+// New returns the backend selected by cfg.StorageBackend.
+func New(cfg config.Config) (Storage, error) {
+	switch cfg.StorageBackend {
+	case "", "local":
+		return NewLocal(cfg.StorageDir, NewSigner(cfg.StorageSigningKey, cfg.StorageURL))
+	case "s3":
+		return NewS3(S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			PathStyle: cfg.S3PathStyle,
+		})
+	case "memory":
+		return NewMemory(NewSigner(cfg.StorageSigningKey, cfg.StorageURL)), nil
+	}
+
+	return nil, fmt.Errorf("storage: unknown backend %q", cfg.StorageBackend)
+}
+
+// validKey rejects keys that could escape the backend's namespace.
+func validKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return ErrInvalidKey
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return ErrInvalidKey
+		}
+	}
+	return nil
+}
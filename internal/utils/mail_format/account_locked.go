@@ -0,0 +1,38 @@
+package mail_format
+
+var AccountLockedMail = map[string]Localized{
+	"en": {
+		Subject: "Alumni Account Locked",
+		Title:   "Your Account Was Locked",
+		Content: `
+          <div class="content">
+              <h2 style="color: #1e88e5; text-align: center;">Your Account Was Locked</h2>
+              
+              <p>Hi,</p>
+              <p>Someone entered the wrong password for your [CPE Alumni] account too many times, so we have locked it until {{.Until}} to keep it safe.</p>
+              <p>If this was you, you can sign in again once the lock expires. If it was not, we recommend resetting your password.</p>
+              <a href="{{.Client}}/reset_password" class="button">Reset Password</a>
+              <p class="help-text">If you need the account unlocked sooner, reply to this email and an administrator will help you.</p>
+              <p>Thanks,<br>the CPE Alumni team</p>
+              <h3 style="color: #1e88e5; text-align: center;">Ref: {{.Ref}}</h3>
+          </div>
+  `,
+	},
+	"th": {
+		Subject: "บัญชีศิษย์เก่าของคุณถูกล็อก",
+		Title:   "บัญชีของคุณถูกล็อก",
+		Content: `
+          <div class="content">
+              <h2 style="color: #1e88e5; text-align: center;">บัญชีของคุณถูกล็อก</h2>
+              
+              <p>สวัสดีครับ/ค่ะ</p>
+              <p>มีการใส่รหัสผ่านบัญชี [CPE Alumni] ของคุณผิดหลายครั้งเกินไป เราจึงล็อกบัญชีไว้จนถึง {{.Until}} เพื่อความปลอดภัย</p>
+              <p>หากเป็นคุณเอง คุณสามารถเข้าสู่ระบบได้อีกครั้งเมื่อพ้นเวลาดังกล่าว หากไม่ใช่ เราแนะนำให้รีเซ็ตรหัสผ่าน</p>
+              <a href="{{.Client}}/reset_password" class="button">รีเซ็ตรหัสผ่าน</a>
+              <p class="help-text">หากต้องการปลดล็อกบัญชีก่อนเวลา กรุณาตอบกลับอีเมลนี้ ผู้ดูแลระบบจะช่วยเหลือคุณ</p>
+              <p>ขอบคุณครับ/ค่ะ<br>ทีมงาน CPE Alumni</p>
+              <h3 style="color: #1e88e5; text-align: center;">Ref: {{.Ref}}</h3>
+          </div>
+  `,
+	},
+}
@@ -0,0 +1,25 @@
+package mail_format
+
+import "alumni_api/internal/models"
+
+// Localized is one language's version of a mail. Content is an html/template
+// rendered inside Layout.
+type Localized struct {
+	Subject string
+	Title   string
+	Content string
+}
+
+// DefaultLanguage is used when a mail has no version in the language asked
+// for.
+const DefaultLanguage = "en"
+
+// Templates holds every mail by template name, then by language.
+var Templates = map[string]map[string]Localized{
+	models.MailVerifyEmail:         VerifyMail,
+	models.MailVerifyChangeEmail:   VerifyChangeMail,
+	models.MailResetPassword:       ResetPasswordMail,
+	models.MailOneTimeRegistry:     OneTimeRegistrySucc,
+	models.MailOneTimeRegistryFail: OneTimeRegistryFail,
+	models.MailAccountLocked:       AccountLockedMail,
+}
@@ -1,86 +1,36 @@
 package mail_format
 
-const ResetPasswordMail = `
-  <!DOCTYPE html>
-  <html lang="en">
-  <head>
-      <meta charset="UTF-8">
-      <meta name="viewport" content="width=device-width, initial-scale=1.0">
-      <title>Reset Your Password</title>
-      <style>
-          body {
-              font-family: Helvetica, Arial, sans-serif;
-              line-height: 1.6;
-              color: #333333;
-              margin: 0;
-              padding: 0;
-              background-color: #f0f0f0;
-          }
-          .container {
-              max-width: 600px;
-              margin: 0 auto;
-              padding: 20px;
-          }
-          .header {
-              text-align: center;
-              padding: 20px 0;
-          }
-          .logo {
-              max-width: 150px;
-              height: auto;
-          }
-          .content {
-              background-color: #ffffff;
-              padding: 30px;
-              border-radius: 5px;
-              box-shadow: 0 2px 5px rgba(0, 0, 0, 0.1);
-          }
-          .button {
-              display: block;
-              width: 200px;
-              margin: 30px auto;
-              padding: 12px 0;
-              background-color: #1e88e5;
-              color: #ffffff;
-              text-align: center;
-              text-decoration: none;
-              font-weight: bold;
-              border-radius: 5px;
-          }
-          .footer {
-              margin-top: 30px;
-              text-align: center;
-              font-size: 12px;
-              color: #777777;
-          }
-          .help-text {
-              font-size: 14px;
-              color: #555555;
-              margin-top: 20px;
-          }
-      </style>
-  </head>
-  <body>
-      <div class="container">
-          <div class="header">
-              <img src="c:\Users\CPE\Desktop\CPE-Alumni\cpealumni.png" alt="Customer Portal Logo" class="logo">
-          </div>
+var ResetPasswordMail = map[string]Localized{
+	"en": {
+		Subject: "Alumni Password Reset",
+		Title:   "Reset Your Password",
+		Content: `
           <div class="content">
               <h2 style="color: #1e88e5; text-align: center;">Reset Your Password</h2>
               
-              <p>Hi [name],</p>
+              <p>Hi,</p>
               <p>You recently requested to reset the password for your [CPE Alumni] account. Click the button below to proceed.</p>
-              <a href="%s/reset_password?token=%s" class="button">Reset Password</a>
+              <a href="{{.Client}}/reset_password?token={{.Token}}" class="button">Reset Password</a>
               <p class="help-text">If you did not request a password reset, please ignore this email or reply to let us know. This password reset link is only valid for the next 30 minutes.</p>
               <p>Thanks,<br>the CPE Alumni team</p>
-              <h3 style="color: #1e88e5; text-align: center;">Ref: %s</h3>
+              <h3 style="color: #1e88e5; text-align: center;">Ref: {{.Ref}}</h3>
           </div>
-          <div class="footer">
-              <p>&copy; 2025 CPE Alumni</p>
-              <p>126 Pracha Uthit Rd, Bang Mot, Thung Khru, Bangkok</p>
-              <p><a href="#" style="color: #1e88e5;">Privacy Policy</a></p>
+  `,
+	},
+	"th": {
+		Subject: "รีเซ็ตรหัสผ่านศิษย์เก่า",
+		Title:   "รีเซ็ตรหัสผ่านของคุณ",
+		Content: `
+          <div class="content">
+              <h2 style="color: #1e88e5; text-align: center;">รีเซ็ตรหัสผ่านของคุณ</h2>
+              
+              <p>สวัสดีครับ/ค่ะ</p>
+              <p>คุณได้ขอรีเซ็ตรหัสผ่านสำหรับบัญชี [CPE Alumni] ของคุณ กรุณากดปุ่มด้านล่างเพื่อดำเนินการต่อ</p>
+              <a href="{{.Client}}/reset_password?token={{.Token}}" class="button">รีเซ็ตรหัสผ่าน</a>
+              <p class="help-text">หากคุณไม่ได้ขอรีเซ็ตรหัสผ่าน กรุณาเพิกเฉยต่ออีเมลนี้หรือตอบกลับเพื่อแจ้งให้เราทราบ ลิงก์นี้ใช้ได้ภายใน 30 นาทีเท่านั้น</p>
+              <p>ขอบคุณครับ/ค่ะ<br>ทีมงาน CPE Alumni</p>
+              <h3 style="color: #1e88e5; text-align: center;">Ref: {{.Ref}}</h3>
           </div>
-      </div>
-  </body>
-  </html>
-`
+  `,
+	},
+}
@@ -21,6 +21,13 @@ func OptionalString(v any) *string {
 	return &s
 }
 
+func SafeInt64(v any) int64 {
+	if v == nil {
+		return 0
+	}
+	return v.(int64)
+}
+
 func CheckMapWithTimeField(data interface{}) bool {
 	// Use reflection to get the value of the data
 	v := reflect.ValueOf(data)
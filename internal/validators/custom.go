@@ -1,9 +1,11 @@
 package validators
 
 import (
+	"alumni_api/internal/models"
 	"github.com/go-playground/validator/v10"
 	"reflect"
 	"regexp"
+	"slices"
 	"strings"
 )
 
@@ -13,6 +15,10 @@ func ValidatePhone(fl validator.FieldLevel) bool {
 	return re.MatchString(fl.Field().String())
 }
 
+func permissionValidation(fl validator.FieldLevel) bool {
+	return slices.Contains(models.Permissions, fl.Field().String())
+}
+
 func CPEGenerationValidation(fl validator.FieldLevel) bool {
 	value := fl.Field().String()
 	matched, _ := regexp.MatchString(`^CPE[1-9][0-9]*$`, value)
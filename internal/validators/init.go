@@ -18,6 +18,7 @@ func Init() {
 	validate.RegisterValidation("customname", nameValidation)
 	validate.RegisterValidation("cpe_generation", CPEGenerationValidation)
 	validate.RegisterValidation("phone", ValidatePhone)
+	validate.RegisterValidation("permission", permissionValidation)
 }
 
 func ValidateValuer(field reflect.Value) interface{} {
@@ -0,0 +1,30 @@
+package validators
+
+import (
+	"alumni_api/internal/models"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Page parses the cursor and limit query parameters of a list endpoint.
+func Page(c *fiber.Ctx) (models.Page, error) {
+	var req models.PageRequest
+	if err := Query(c, &req); err != nil {
+		return models.Page{}, err
+	}
+
+	page := models.Page{Limit: req.Limit}
+	if page.Limit == 0 {
+		page.Limit = models.DefaultPageLimit
+	}
+
+	if req.Cursor != "" {
+		cursor, err := models.DecodeCursor(req.Cursor)
+		if err != nil {
+			return models.Page{}, fiber.NewError(fiber.StatusBadRequest, models.ErrInvalidCursor)
+		}
+		page.After = cursor
+	}
+
+	return page, nil
+}
@@ -2,6 +2,9 @@ package validators
 
 import (
 	"alumni_api/internal/models"
+	"fmt"
+	"regexp"
+	"slices"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -20,14 +23,21 @@ func SameUser(c *fiber.Ctx, user_id string) error {
 	return nil
 }
 
-func UserAdmin(c *fiber.Ctx) error {
-	claims, ok := c.Locals("claims").(*models.Claims)
-	if !ok {
-		return fiber.NewError(fiber.StatusUnauthorized, "Unauthorized")
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+// RoleName checks a role name: lower case letters, digits and underscores.
+func RoleName(name string) error {
+	if !roleNamePattern.MatchString(name) {
+		return fiber.NewError(fiber.StatusBadRequest, fmt.Sprintf("Invalid role name: %s", name))
 	}
 
-	if claims.Role != "admin" {
-		return fiber.NewError(fiber.StatusForbidden, "This user do not prossess admin permission")
+	return nil
+}
+
+// ConversationRole checks the caller's role in a group conversation.
+func ConversationRole(role string, allowed ...string) error {
+	if !slices.Contains(allowed, role) {
+		return fiber.NewError(fiber.StatusForbidden, "Your conversation role does not allow this action")
 	}
 
 	return nil
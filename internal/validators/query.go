@@ -5,6 +5,7 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"reflect"
+	"strings"
 )
 
 func Query(c *fiber.Ctx, req interface{}) error {
@@ -30,7 +31,7 @@ func Query(c *fiber.Ctx, req interface{}) error {
 		for _, e := range validationErrors {
 			errorMessages = append(errorMessages, e.Error())
 		}
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"errors": errorMessages})
+		return fiber.NewError(fiber.StatusBadRequest, strings.Join(errorMessages, "; "))
 	}
 
 	return nil
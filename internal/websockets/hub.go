@@ -0,0 +1,429 @@
+package websockets
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// Frame types understood by the hub itself. Features built on top of the hub
+// register their own types with Hub.Handle.
+const (
+	TypeAck          = "ack"
+	TypeError        = "error"
+	TypeSubscribe    = "subscribe"
+	TypeUnsubscribe  = "unsubscribe"
+	TypeMessage      = "message"
+	TypeTyping       = "typing"
+	TypeRead         = "read"
+	TypePresence     = "presence"
+	TypeConversation = "conversation"
+)
+
+// Envelope is the JSON frame exchanged in both directions. Every frame the
+// server sends carries an ID the client acknowledges with an "ack" frame;
+// frames the client sends with an ID are acknowledged the same way.
+type Envelope struct {
+	Type    string          `json:"type"`
+	ID      string          `json:"id,omitempty"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// NewEnvelope marshals payload into a frame with a fresh ID.
+func NewEnvelope(frameType string, payload interface{}) (Envelope, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	return Envelope{
+		Type:    frameType,
+		ID:      uuid.New().String(),
+		Payload: data,
+	}, nil
+}
+
+// NewEvent marshals payload into a frame without an ID. Events such as typing
+// and presence are only worth delivering while they are fresh, so clients do
+// not ack them and they are never retried.
+func NewEvent(frameType string, payload interface{}) (Envelope, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Envelope{}, err
+	}
+
+	return Envelope{Type: frameType, Payload: data}, nil
+}
+
+// needsAck reports whether the client is expected to acknowledge the frame.
+func (e Envelope) needsAck() bool {
+	return e.ID != "" && e.Type != TypeAck && e.Type != TypeError
+}
+
+type roomPayload struct {
+	Room string `json:"room"`
+}
+
+type errorPayload struct {
+	Message string `json:"message"`
+}
+
+type typingPayload struct {
+	UserID string `json:"user_id"`
+	Typing bool   `json:"typing"`
+}
+
+// PresencePayload describes whether a user is connected. LastSeen is the
+// time in milliseconds their last connection closed, zero while online or
+// when they have not connected since the server started.
+type PresencePayload struct {
+	UserID   string `json:"user_id"`
+	Online   bool   `json:"online"`
+	LastSeen int64  `json:"last_seen,omitempty"`
+}
+
+// HandlerFunc handles one inbound frame type. Returning an error sends an
+// "error" frame back to the client instead of an ack.
+type HandlerFunc func(client *Client, env Envelope) error
+
+// Hub tracks every open connection by user and by subscribed room.
+type Hub struct {
+	mu        sync.RWMutex
+	clients   map[string]map[*Client]bool
+	rooms     map[string]map[*Client]bool
+	handlers  map[string]HandlerFunc
+	authorize func(userID, room string) bool
+	presence  func(userID string, online bool)
+	lastSeen  map[string]int64
+	logger    *zap.Logger
+}
+
+func NewHub(logger *zap.Logger) *Hub {
+	hub := &Hub{
+		clients:   make(map[string]map[*Client]bool),
+		rooms:     make(map[string]map[*Client]bool),
+		handlers:  make(map[string]HandlerFunc),
+		authorize: DirectRoomMember,
+		presence:  func(string, bool) {},
+		lastSeen:  make(map[string]int64),
+		logger:    logger,
+	}
+
+	hub.Handle(TypeSubscribe, hub.handleSubscribe)
+	hub.Handle(TypeUnsubscribe, hub.handleUnsubscribe)
+	hub.Handle(TypeTyping, hub.handleTyping)
+
+	return hub
+}
+
+// DirectRoom names the room shared by the two users of a direct chat.
+func DirectRoom(userID1, userID2 string) string {
+	ids := []string{userID1, userID2}
+	sort.Strings(ids)
+	return "dm:" + ids[0] + ":" + ids[1]
+}
+
+// DirectRoomMember reports whether the user is one of the two members of a
+// DirectRoom.
+func DirectRoomMember(userID, room string) bool {
+	_, ok := DirectRoomPeer(userID, room)
+	return ok
+}
+
+// DirectRoomPeer returns the other member of a DirectRoom the user is in.
+func DirectRoomPeer(userID, room string) (string, bool) {
+	ids, ok := strings.CutPrefix(room, "dm:")
+	if !ok {
+		return "", false
+	}
+	first, second, ok := strings.Cut(ids, ":")
+	switch {
+	case !ok:
+		return "", false
+	case first == userID:
+		return second, true
+	case second == userID:
+		return first, true
+	}
+	return "", false
+}
+
+// ConversationRoom names the room of a group conversation.
+func ConversationRoom(conversationID string) string {
+	return "conv:" + conversationID
+}
+
+// ConversationID returns the conversation behind a ConversationRoom name.
+func ConversationID(room string) (string, bool) {
+	return strings.CutPrefix(room, "conv:")
+}
+
+// Handle registers the handler for an inbound frame type.
+func (h *Hub) Handle(frameType string, fn HandlerFunc) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.handlers[frameType] = fn
+}
+
+// AuthorizeRooms replaces the check run before a client joins a room, or
+// sends a typing indicator into a DirectRoom. The default only admits the
+// two members of a DirectRoom.
+func (h *Hub) AuthorizeRooms(fn func(userID, room string) bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.authorize = fn
+}
+
+// OnPresence registers a callback run when a user opens their first
+// connection or closes their last one.
+func (h *Hub) OnPresence(fn func(userID string, online bool)) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.presence = fn
+}
+
+// register adds the client and reports whether the user just came online.
+func (h *Hub) register(client *Client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.clients[client.UserID] == nil {
+		h.clients[client.UserID] = make(map[*Client]bool)
+	}
+	h.clients[client.UserID][client] = true
+
+	if len(h.clients[client.UserID]) > 1 {
+		return false
+	}
+	delete(h.lastSeen, client.UserID)
+	return true
+}
+
+// unregister removes the client and reports whether the user went offline.
+func (h *Hub) unregister(client *Client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.clients[client.UserID], client)
+	offline := len(h.clients[client.UserID]) == 0
+	if offline {
+		delete(h.clients, client.UserID)
+		h.lastSeen[client.UserID] = time.Now().UnixMilli()
+	}
+
+	for room := range client.rooms {
+		delete(h.rooms[room], client)
+		if len(h.rooms[room]) == 0 {
+			delete(h.rooms, room)
+		}
+	}
+
+	return offline
+}
+
+func (h *Hub) notifyPresence(userID string, online bool) {
+	h.mu.RLock()
+	fn := h.presence
+	h.mu.RUnlock()
+
+	fn(userID, online)
+}
+
+// SendToUser delivers the frame to every connection of the user and returns
+// how many connections it was queued on.
+func (h *Hub) SendToUser(userID string, env Envelope) int {
+	return h.Publish("", env, userID)
+}
+
+// Publish delivers the frame to every subscriber of room and to every
+// connection of the given users, once per connection.
+func (h *Hub) Publish(room string, env Envelope, userIDs ...string) int {
+	h.mu.RLock()
+	targets := make(map[*Client]bool)
+	for client := range h.rooms[room] {
+		targets[client] = true
+	}
+	for _, userID := range userIDs {
+		for client := range h.clients[userID] {
+			targets[client] = true
+		}
+	}
+	h.mu.RUnlock()
+
+	for client := range targets {
+		client.enqueue(env)
+	}
+
+	return len(targets)
+}
+
+// Evict unsubscribes every connection of the user from room, for example
+// once they leave a group conversation.
+func (h *Hub) Evict(room, userID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for client := range h.clients[userID] {
+		delete(h.rooms[room], client)
+		delete(client.rooms, room)
+	}
+	if len(h.rooms[room]) == 0 {
+		delete(h.rooms, room)
+	}
+}
+
+// Online reports whether the user has at least one open connection.
+func (h *Hub) Online(userID string) bool {
+	return h.Connections(userID) > 0
+}
+
+// Presence reports the user's current presence.
+func (h *Hub) Presence(userID string) PresencePayload {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return PresencePayload{
+		UserID:   userID,
+		Online:   len(h.clients[userID]) > 0,
+		LastSeen: h.lastSeen[userID],
+	}
+}
+
+// Connections returns the number of open connections of the user.
+func (h *Hub) Connections(userID string) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return len(h.clients[userID])
+}
+
+// Pending returns how many frames sent to the user are still waiting for
+// an ack, summed over the user's connections.
+func (h *Hub) Pending(userID string) int {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients[userID]))
+	for client := range h.clients[userID] {
+		clients = append(clients, client)
+	}
+	h.mu.RUnlock()
+
+	var count int
+	for _, client := range clients {
+		count += client.pendingCount()
+	}
+	return count
+}
+
+func (h *Hub) dispatch(client *Client, env Envelope) {
+	if env.Type == TypeAck {
+		client.acknowledge(env.ID)
+		return
+	}
+
+	h.mu.RLock()
+	handler, ok := h.handlers[env.Type]
+	h.mu.RUnlock()
+
+	err := errors.New("unknown frame type: " + env.Type)
+	if ok {
+		err = handler(client, env)
+	}
+
+	if err != nil {
+		payload, _ := json.Marshal(errorPayload{Message: err.Error()})
+		client.enqueue(Envelope{Type: TypeError, ID: env.ID, Payload: payload})
+		return
+	}
+
+	if env.ID != "" {
+		client.enqueue(Envelope{Type: TypeAck, ID: env.ID})
+	}
+}
+
+func decodeRoom(env Envelope) (string, error) {
+	var payload roomPayload
+	if err := json.Unmarshal(env.Payload, &payload); err != nil || payload.Room == "" {
+		return "", errors.New("payload.room is required")
+	}
+	return payload.Room, nil
+}
+
+func (h *Hub) handleSubscribe(client *Client, env Envelope) error {
+	room, err := decodeRoom(env)
+	if err != nil {
+		return err
+	}
+
+	h.mu.RLock()
+	authorize := h.authorize
+	h.mu.RUnlock()
+
+	if !authorize(client.UserID, room) {
+		return errors.New("not allowed to join room " + room)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.rooms[room] == nil {
+		h.rooms[room] = make(map[*Client]bool)
+	}
+	h.rooms[room][client] = true
+	client.rooms[room] = true
+
+	return nil
+}
+
+func (h *Hub) handleUnsubscribe(client *Client, env Envelope) error {
+	room, err := decodeRoom(env)
+	if err != nil {
+		return err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.rooms[room], client)
+	if len(h.rooms[room]) == 0 {
+		delete(h.rooms, room)
+	}
+	delete(client.rooms, room)
+
+	return nil
+}
+
+// handleTyping relays a typing indicator to the other member of a direct
+// chat and to anyone watching that room, if the client may use that room.
+func (h *Hub) handleTyping(client *Client, env Envelope) error {
+	var payload typingPayload
+	if err := json.Unmarshal(env.Payload, &payload); err != nil || payload.UserID == "" {
+		return errors.New("payload.user_id is required")
+	}
+
+	room := DirectRoom(client.UserID, payload.UserID)
+
+	h.mu.RLock()
+	authorize := h.authorize
+	h.mu.RUnlock()
+
+	if !authorize(client.UserID, room) {
+		return errors.New("not allowed to send typing to " + payload.UserID)
+	}
+
+	event, err := NewEvent(TypeTyping, typingPayload{UserID: client.UserID, Typing: payload.Typing})
+	if err != nil {
+		return err
+	}
+
+	h.Publish(room, event, payload.UserID)
+	return nil
+}
@@ -3,49 +3,240 @@ package websockets
 import (
 	"alumni_api/internal/models"
 	"encoding/json"
-	"github.com/gofiber/websocket/v2"
-	"log"
 	"sync"
+	"time"
+
+	"github.com/gofiber/websocket/v2"
+	"go.uber.org/zap"
+)
+
+const (
+	writeWait      = 10 * time.Second
+	pongWait       = 60 * time.Second
+	pingPeriod     = (pongWait * 9) / 10
+	ackTimeout     = 10 * time.Second
+	maxAttempts    = 3
+	sendBuffer     = 64
+	maxMessageSize = 64 << 10
 )
 
-// Map to store active connections
-var Clients = make(map[string]*websocket.Conn)
-var Mutex = sync.Mutex{}
+type pendingFrame struct {
+	env      Envelope
+	sentAt   time.Time
+	attempts int
+}
+
+// Client is one WebSocket connection. A user may hold several at once, one
+// per tab or device.
+type Client struct {
+	UserID string
+
+	hub       *Hub
+	conn      *websocket.Conn
+	send      chan Envelope
+	done      chan struct{}
+	closeOnce sync.Once
 
-func WebsocketHandler(c *websocket.Conn) {
-	defer c.Close()
+	mu      sync.Mutex
+	pending map[string]*pendingFrame
 
-	claims, ok := c.Locals("claims").(*models.Claims)
-	if !ok {
+	// rooms is guarded by hub.mu.
+	rooms map[string]bool
+}
+
+// Handler upgrades authenticated requests into hub connections. It expects
+// WebSocketMiddleware to have stored the claims.
+func Handler(hub *Hub) func(*websocket.Conn) {
+	return func(conn *websocket.Conn) {
+		claims, ok := conn.Locals("claims").(*models.Claims)
+		if !ok {
+			conn.Close()
+			return
+		}
+
+		hub.Serve(conn, claims.UserID)
+	}
+}
+
+// Serve runs the connection until it closes. Reads happen on the calling
+// goroutine and writes on a dedicated one, so the socket is never written
+// concurrently.
+func (h *Hub) Serve(conn *websocket.Conn, userID string) {
+	client := &Client{
+		UserID:  userID,
+		hub:     h,
+		conn:    conn,
+		send:    make(chan Envelope, sendBuffer),
+		done:    make(chan struct{}),
+		pending: make(map[string]*pendingFrame),
+		rooms:   make(map[string]bool),
+	}
+
+	if h.register(client) {
+		h.notifyPresence(userID, true)
+	}
+	defer func() {
+		if h.unregister(client) {
+			h.notifyPresence(userID, false)
+		}
+	}()
+	defer client.close()
+
+	go client.writePump()
+	client.readPump()
+}
+
+func (c *Client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// Send queues a frame on this connection only.
+func (c *Client) Send(env Envelope) {
+	c.enqueue(env)
+}
+
+// enqueue hands a frame to the writer. A client that cannot keep up is
+// disconnected rather than allowed to block the publisher.
+func (c *Client) enqueue(env Envelope) {
+	select {
+	case <-c.done:
 		return
+	default:
 	}
 
-	Mutex.Lock()
-	Clients[claims.UserID] = c
-	Mutex.Unlock()
+	if env.needsAck() {
+		c.mu.Lock()
+		c.pending[env.ID] = &pendingFrame{env: env}
+		c.mu.Unlock()
+	}
 
-	for {
-		_, msg, err := c.ReadMessage()
-		if err != nil {
-			log.Println("WebSocket error:", err)
-			break
+	select {
+	case c.send <- env:
+	default:
+		c.hub.logger.Warn("WebSocket send buffer full, dropping connection", zap.String("user_id", c.UserID))
+		c.close()
+	}
+}
+
+func (c *Client) acknowledge(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.pending, id)
+}
+
+func (c *Client) pendingCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.pending)
+}
+
+func (c *Client) write(env Envelope) error {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return err
+	}
+
+	if env.needsAck() {
+		c.mu.Lock()
+		if frame, ok := c.pending[env.ID]; ok {
+			frame.sentAt = time.Now()
+			frame.attempts++
+		}
+		c.mu.Unlock()
+	}
+
+	c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+	return c.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+// overdue returns the frames whose ack timed out, dropping those that ran
+// out of attempts.
+func (c *Client) overdue() []Envelope {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var frames []Envelope
+	for id, frame := range c.pending {
+		if frame.attempts == 0 || time.Since(frame.sentAt) < ackTimeout {
+			continue
 		}
-		log.Printf("Received from %s: %s", claims.UserID, msg)
+		if frame.attempts >= maxAttempts {
+			c.hub.logger.Warn("WebSocket frame was never acknowledged",
+				zap.String("user_id", c.UserID), zap.String("id", id), zap.String("type", frame.env.Type))
+			delete(c.pending, id)
+			continue
+		}
+		frames = append(frames, frame.env)
 	}
+	return frames
+}
+
+func (c *Client) writePump() {
+	ping := time.NewTicker(pingPeriod)
+	retry := time.NewTicker(ackTimeout / 2)
+	defer func() {
+		ping.Stop()
+		retry.Stop()
+		c.close()
+	}()
+
+	for {
+		select {
+		case <-c.done:
+			return
+
+		case env := <-c.send:
+			if err := c.write(env); err != nil {
+				c.hub.logger.Warn("WebSocket write failed", zap.String("user_id", c.UserID), zap.Error(err))
+				return
+			}
 
-	Mutex.Lock()
-	delete(Clients, claims.UserID)
-	Mutex.Unlock()
+		case <-retry.C:
+			for _, env := range c.overdue() {
+				if err := c.write(env); err != nil {
+					c.hub.logger.Warn("WebSocket write failed", zap.String("user_id", c.UserID), zap.Error(err))
+					return
+				}
+			}
+
+		case <-ping.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
 }
 
-// SendWebSocketNotification sends a JSON message to a connected user
-func SendNotification(userID string, message interface{}) {
-	Mutex.Lock()
-	conn, online := Clients[userID]
-	Mutex.Unlock()
+func (c *Client) readPump() {
+	c.conn.SetReadLimit(maxMessageSize)
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		return c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		_, data, err := c.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				c.hub.logger.Warn("WebSocket read failed", zap.String("user_id", c.UserID), zap.Error(err))
+			}
+			return
+		}
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+
+		var env Envelope
+		if err := json.Unmarshal(data, &env); err != nil || env.Type == "" {
+			payload, _ := json.Marshal(errorPayload{Message: "invalid frame"})
+			c.enqueue(Envelope{Type: TypeError, Payload: payload})
+			continue
+		}
 
-	if online {
-		jsonMessage, _ := json.Marshal(message)
-		_ = conn.WriteMessage(websocket.TextMessage, jsonMessage)
+		c.hub.dispatch(c, env)
 	}
 }
@@ -2,13 +2,21 @@ package main
 
 import (
 	"alumni_api/config"
+	"alumni_api/internal/auth"
 	"alumni_api/internal/db"
 	"alumni_api/internal/logger"
+	"alumni_api/internal/mailer"
 	"alumni_api/internal/middlewares"
+	"alumni_api/internal/models"
 	"alumni_api/internal/queue"
+	"alumni_api/internal/reencrypt"
+	"alumni_api/internal/repositories"
 	"alumni_api/internal/routes"
+	"alumni_api/internal/storage"
 	"alumni_api/internal/validators"
+	"alumni_api/internal/websockets"
 	"context"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
@@ -34,6 +42,54 @@ func main() {
 
 	queue.Init()
 
+	store := repositories.NewNeo4jStore(driver)
+
+	// "reencrypt" rewrites encrypted data under the current key and exits,
+	// for running once after a key rotation.
+	if len(os.Args) > 1 && os.Args[1] == "reencrypt" {
+		if _, err := reencrypt.NewJob(store.Encrypted, logger).Run(ctx); err != nil {
+			logger.Fatal("Re-encryption failed", zap.Error(err))
+		}
+		return
+	}
+
+	if config.LoadEncryptionConfig().Reencrypt {
+		go func() {
+			if _, err := reencrypt.NewJob(store.Encrypted, logger).Run(ctx); err != nil {
+				logger.Error("Re-encryption failed", zap.Error(err))
+			}
+		}()
+	}
+
+	if err := store.Role.SyncBuiltInRoles(ctx, models.BuiltInRoles, logger); err != nil {
+		logger.Fatal("Could not sync built-in roles", zap.Error(err))
+	}
+
+	if err := store.Department.AssignDepartmentIDs(ctx, logger); err != nil {
+		logger.Fatal("Could not assign department ids", zap.Error(err))
+	}
+
+	files, err := storage.New(cfg)
+	if err != nil {
+		logger.Fatal("Could not set up file storage", zap.Error(err))
+	}
+
+	mailCfg := config.LoadMailConfig()
+	sender, err := mailer.New(mailCfg)
+	if err != nil {
+		logger.Fatal("Could not set up mailer", zap.Error(err))
+	}
+
+	mailWorker, err := mailer.NewWorker(store.Outbox, sender, mailCfg, logger)
+	if err != nil {
+		logger.Fatal("Could not load mail templates", zap.Error(err))
+	}
+	go mailWorker.Run(ctx)
+
+	hub := websockets.NewHub(logger)
+
+	providers := auth.NewOIDCProviders(config.LoadOIDCConfig())
+
 	// Set up Fiber app
 	app := fiber.New()
 	api := app.Group("/v1")
@@ -43,7 +99,6 @@ func main() {
 		AllowHeaders:     "Content-Type,Authorization",
 		AllowCredentials: true,
 	}))
-	api.Static("/uploads", "/app/uploads")
 
 	api.Get("/", func(c *fiber.Ctx) error {
 		return c.SendString("Hello, World!")
@@ -53,19 +108,33 @@ func main() {
 
 	app.Use(middlewares.REDWithQueueMiddleware(logger))
 
-	routes.UserRoutes(api, driver, logger)
+	routes.UserRoutes(api, store, logger)
+
+	// Before AuthRoutes, whose JWT middleware covers everything under /auth
+	// registered after it.
+	routes.OIDCRoutes(api, store, providers, logger)
+
+	routes.AuthRoutes(api, store, logger)
+
+	routes.PostRoutes(api, store, logger)
+
+	routes.UploadRoutes(api, store, files, logger)
+
+	routes.MessageRoutes(api, store, hub, files, logger)
+
+	routes.ConversationRoutes(api, store, hub, logger)
 
-	routes.AuthRoutes(api, driver, logger)
+	routes.StatRoutes(api, store, logger)
 
-	routes.PostRoutes(api, driver, logger)
+	routes.RoleRoutes(api, store, logger)
 
-	routes.UploadRoutes(api, driver, logger)
+	routes.DepartmentRoutes(api, store, logger)
 
-	routes.MessageRoutes(api, driver, logger)
+	routes.MailRoutes(api, store, logger)
 
-	routes.StatRoutes(api, driver, logger)
+	routes.UtilsRoute(api, store, logger)
 
-	routes.UtilsRoute(api, driver, logger)
+	routes.WellKnownRoutes(app, logger)
 
 	// Start the server
 	if err := app.Listen(cfg.ServerPort); err != nil {
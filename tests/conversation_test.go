@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"alumni_api/internal/websockets"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupConversation(t *testing.T) {
+	app, db, _ := newTestAppWithHub(t)
+	addr := serve(t, app)
+
+	owner := db.PutUser(map[string]interface{}{"username": "owner", "first_name": "Own", "last_name": "Er", "role": "alumnus"})
+	admin := db.PutUser(map[string]interface{}{"username": "admin", "first_name": "Ad", "last_name": "Min", "role": "alumnus"})
+	member := db.PutUser(map[string]interface{}{"username": "member", "first_name": "Mem", "last_name": "Ber", "role": "alumnus"})
+	outsider := db.PutUser(map[string]interface{}{"username": "outsider", "role": "alumnus"})
+
+	status, body := doRequest(t, app, http.MethodPost, "/v1/conversations",
+		`{"title":"CPE30 committee","member_ids":["`+admin+`"]}`, owner, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var conv struct {
+		ConversationID string                   `json:"conversation_id"`
+		Title          string                   `json:"title"`
+		Members        []map[string]interface{} `json:"members"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &conv))
+	require.Len(t, conv.Members, 2)
+	assert.Equal(t, "owner", conv.Members[0]["role"])
+	base := "/v1/conversations/" + conv.ConversationID
+
+	status, body = doRequest(t, app, http.MethodPut, base+"/members/"+admin, `{"role":"admin"}`, owner, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, body = doRequest(t, app, http.MethodPost, base+"/members", `{"user_ids":["`+member+`"]}`, admin, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	// Plain members cannot invite and outsiders cannot see the conversation.
+	status, _ = doRequest(t, app, http.MethodPost, base+"/members", `{"user_ids":["`+outsider+`"]}`, member, "alumnus")
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = doRequest(t, app, http.MethodGet, base, "", outsider, "alumnus")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	conn := dial(t, app, addr, member, "alumnus")
+	require.NoError(t, conn.WriteJSON(map[string]interface{}{
+		"type": websockets.TypeSubscribe, "id": "join", "payload": map[string]string{"room": websockets.ConversationRoom(conv.ConversationID)},
+	}))
+	require.Equal(t, websockets.TypeAck, readFrame(t, conn).Type)
+
+	outsiderConn := dial(t, app, addr, outsider, "alumnus")
+	require.NoError(t, outsiderConn.WriteJSON(map[string]interface{}{
+		"type": websockets.TypeSubscribe, "id": "join", "payload": map[string]string{"room": websockets.ConversationRoom(conv.ConversationID)},
+	}))
+	require.Equal(t, websockets.TypeError, readFrame(t, outsiderConn).Type)
+
+	status, body = doRequest(t, app, http.MethodPost, base+"/messages", `{"content":"Agenda for Saturday"}`, owner, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var first map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &first))
+
+	env := readFrame(t, conn)
+	require.Equal(t, websockets.TypeMessage, env.Type)
+	assert.Contains(t, string(env.Payload), "Agenda for Saturday")
+
+	status, body = doRequest(t, app, http.MethodPost, base+"/messages",
+		`{"content":"I'll bring snacks","reply_id":"`+first["message_id"].(string)+`"}`, member, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var reply map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &reply))
+	assert.Equal(t, "Agenda for Saturday", reply["reply_content"])
+
+	status, body = doRequest(t, app, http.MethodGet, base+"/messages", "", admin, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var messages page
+	require.NoError(t, json.Unmarshal(body.Data, &messages))
+	require.Len(t, messages.Items, 2)
+	assert.Equal(t, "I'll bring snacks", messages.Items[0]["content"])
+	assert.Equal(t, "Agenda for Saturday", messages.Items[0]["reply_message_content"])
+
+	// Admins cannot remove each other; the owner leaving hands over to the admin.
+	status, _ = doRequest(t, app, http.MethodDelete, base+"/members/"+owner, "", admin, "alumnus")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, body = doRequest(t, app, http.MethodPost, base+"/leave", "", owner, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	require.NoError(t, json.Unmarshal(body.Data, &conv))
+	require.Len(t, conv.Members, 2)
+	assert.Equal(t, admin, conv.Members[0]["user_id"])
+	assert.Equal(t, "owner", conv.Members[0]["role"])
+
+	status, body = doRequest(t, app, http.MethodDelete, base+"/members/"+member, "", admin, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, _ = doRequest(t, app, http.MethodGet, base+"/messages", "", member, "alumnus")
+	assert.Equal(t, http.StatusNotFound, status)
+}
+
+func TestEventChat(t *testing.T) {
+	app, db := newTestApp(t)
+
+	organizer := db.PutUser(map[string]interface{}{"username": "organizer", "role": "alumnus"})
+	attendee := db.PutUser(map[string]interface{}{"username": "attendee", "role": "user"})
+
+	status, _ := doRequest(t, app, http.MethodPost, "/v1/post",
+		`{"title":"Homecoming","content":"Faculty homecoming night","post_type":"story","visibility":"all","create_chat":true}`, organizer, "alumnus")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	var created map[string]string
+	for _, visibility := range []string{"all", "alumnus"} {
+		status, body := doRequest(t, app, http.MethodPost, "/v1/post",
+			`{"title":"Homecoming","content":"Faculty homecoming night","post_type":"event","visibility":"`+visibility+`","create_chat":true}`, organizer, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+		require.NoError(t, json.Unmarshal(body.Data, &created))
+		require.NotEmpty(t, created["conversation_id"])
+
+		status, body = doRequest(t, app, http.MethodPost, "/v1/post/"+created["post_id"]+"/chat", "", attendee, "user")
+		if visibility != "all" {
+			assert.Equal(t, http.StatusNotFound, status)
+			continue
+		}
+		require.Equal(t, http.StatusOK, status, body.Message)
+
+		var conv map[string]interface{}
+		require.NoError(t, json.Unmarshal(body.Data, &conv))
+		assert.Equal(t, "Homecoming", conv["title"])
+		assert.Equal(t, created["post_id"], conv["post_id"])
+		assert.Len(t, conv["members"], 2)
+	}
+}
@@ -0,0 +1,106 @@
+package tests
+
+import (
+	"alumni_api/internal/auth"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type roleRequest struct {
+	User struct {
+		UserID string `json:"user_id"`
+	} `json:"user"`
+	Request struct {
+		RequestID string `json:"request_id"`
+	} `json:"request"`
+}
+
+func TestDepartmentAdmin(t *testing.T) {
+	app, db := newTestApp(t)
+
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	alice := db.PutUser(map[string]interface{}{"username": "alice", "user_password": hash, "role": "alumnus", "is_verify": true})
+	admin := db.PutUser(map[string]interface{}{"username": "admin", "role": "admin", "is_verify": true})
+	cs := db.PutUser(map[string]interface{}{"username": "cs", "role": "user", "generation": "60", "is_verify": true})
+	ee := db.PutUser(map[string]interface{}{"username": "ee", "role": "user", "generation": "60", "is_verify": true})
+
+	for userID, department := range map[string]string{cs: "Computer Engineering", ee: "Electrical Engineering"} {
+		info := `{"faculty":"Engineering","department":"` + department + `","field":"General","student_type":"Regular"}`
+		status, body := doRequest(t, app, http.MethodPost, "/v1/users/"+userID+"/student_info", info, admin, "admin")
+		require.Equal(t, http.StatusOK, status, body.Message)
+
+		status, body = doRequest(t, app, http.MethodPost, "/v1/auth/request/role", "", userID, "user")
+		require.Equal(t, http.StatusOK, status, body.Message)
+	}
+
+	status, body := doRequest(t, app, http.MethodGet, "/v1/departments", "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var departments []struct {
+		DepartmentID string `json:"department_id"`
+		Department   string `json:"department"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &departments))
+	require.Len(t, departments, 2)
+	require.Equal(t, "Computer Engineering", departments[0].Department)
+	computer := departments[0].DepartmentID
+
+	status, _ = doRequest(t, app, http.MethodPost, "/v1/roles/department_admin/users/"+alice, "", admin, "admin")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	before := login(t, app, "browser")
+
+	status, body = doRequest(t, app, http.MethodPut, "/v1/departments/"+computer+"/admins/"+alice, "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	// The old token had no department, so it must not outlive the appointment.
+	status, _ = before.do(t, app, http.MethodGet, "/v1/auth/request", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	browser := login(t, app, "browser")
+
+	status, body = browser.do(t, app, http.MethodGet, "/v1/auth/request", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var requests []roleRequest
+	require.NoError(t, json.Unmarshal(body.Data, &requests))
+	require.Len(t, requests, 1)
+	assert.Equal(t, cs, requests[0].User.UserID)
+
+	status, body = browser.do(t, app, http.MethodGet, "/v1/stat/registry", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var registry struct {
+		OverallStats struct {
+			TotalUsers int `json:"total_users"`
+		} `json:"overall_stats"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &registry))
+	assert.Equal(t, 1, registry.OverallStats.TotalUsers)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/auth/request", "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	require.NoError(t, json.Unmarshal(body.Data, &requests))
+	require.Len(t, requests, 2)
+
+	for _, request := range requests {
+		status, _ = browser.do(t, app, http.MethodPost, "/v1/auth/request/"+request.Request.RequestID+"/approve", "")
+		if request.User.UserID == cs {
+			assert.Equal(t, http.StatusOK, status)
+		} else {
+			assert.Equal(t, http.StatusNotFound, status)
+		}
+	}
+
+	status, _ = browser.do(t, app, http.MethodDelete, "/v1/departments/"+computer+"/admins/"+alice, "")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, body = doRequest(t, app, http.MethodDelete, "/v1/departments/"+computer+"/admins/"+alice, "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	browser = login(t, app, "browser")
+	status, _ = browser.do(t, app, http.MethodGet, "/v1/auth/request", "")
+	assert.Equal(t, http.StatusForbidden, status)
+}
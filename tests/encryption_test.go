@@ -0,0 +1,358 @@
+package tests
+
+import (
+	"alumni_api/internal/encrypt"
+	"alumni_api/internal/models"
+	"alumni_api/internal/reencrypt"
+	"alumni_api/pkg/customtypes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func randomKey(t *testing.T) []byte {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+// useKeys encrypts with local master keys from here on.
+func useKeys(t *testing.T, keys string, legacyKey []byte) *encrypt.KeyRing {
+	t.Helper()
+
+	ring, err := encrypt.NewKeyRing(keys, legacyKey)
+	require.NoError(t, err)
+	encrypt.SetEnvelope(encrypt.NewEnvelope(ring, ring))
+	return ring
+}
+
+func resetKeys(t *testing.T) {
+	t.Cleanup(func() { useKeys(t, "1:"+base64.StdEncoding.EncodeToString(randomKey(t)), nil) })
+}
+
+// legacyEncrypt produces the IV-prefixed AES-CBC ciphertext values were
+// stored as before keys had versions.
+func legacyEncrypt(t *testing.T, key, data []byte) []byte {
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+
+	iv := make([]byte, aes.BlockSize)
+	_, err = rand.Read(iv)
+	require.NoError(t, err)
+
+	data = encrypt.PKCS7Pad(data, aes.BlockSize)
+	out := make([]byte, len(data))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(out, data)
+	return append(iv, out...)
+}
+
+func float32Header(value float32) []byte {
+	data := make([]byte, 5)
+	data[0] = encrypt.TypeHeaderFloat32
+	binary.BigEndian.PutUint32(data[1:], math.Float32bits(value))
+	return data
+}
+
+func int16Header(value int16) []byte {
+	data := make([]byte, 9)
+	data[0] = encrypt.TypeHeaderInt
+	binary.BigEndian.PutUint64(data[1:], uint64(value))
+	return data
+}
+
+func TestKeyRotation(t *testing.T) {
+	resetKeys(t)
+
+	legacy := randomKey(t)
+	first := "1:" + base64.StdEncoding.EncodeToString(randomKey(t))
+	second := "2:" + base64.StdEncoding.EncodeToString(randomKey(t))
+
+	ring := useKeys(t, first, legacy)
+
+	// Values encrypted directly with a master key, before envelopes.
+	admitYear, err := ring.WrapKey(context.Background(), int16Header(2560))
+	require.NoError(t, err)
+
+	graduateYear := customtypes.Encrypted[int16]{Value: 2564}
+	require.NoError(t, graduateYear.Encrypt())
+
+	app, db := newTestApp(t)
+	userID := db.PutUser(map[string]interface{}{
+		"user_id":       uuid.New().String(),
+		"username":      "alice",
+		"role":          "alumnus",
+		"gpax":          legacyEncrypt(t, legacy, float32Header(3.5)),
+		"admit_year":    admitYear,
+		"graduate_year": graduateYear.Raw,
+	})
+
+	checkProfile := func() {
+		t.Helper()
+
+		status, body := doRequest(t, app, http.MethodGet, "/v1/users/"+userID, "", userID, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+		var user struct {
+			StudentInfo map[string]interface{} `json:"student_info"`
+		}
+		require.NoError(t, json.Unmarshal(body.Data, &user))
+		assert.Equal(t, 3.5, user.StudentInfo["gpax"])
+		assert.Equal(t, float64(2560), user.StudentInfo["admit_year"])
+		assert.Equal(t, float64(2564), user.StudentInfo["graduate_year"])
+	}
+
+	// The new key wraps while old data stays readable.
+	useKeys(t, second+","+first, legacy)
+	checkProfile()
+
+	job := reencrypt.NewJob(db.Store().Encrypted, zap.NewNop())
+	result, err := job.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, reencrypt.Result{Scanned: 3, Updated: 3}, result)
+
+	result, err = job.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, reencrypt.Result{Scanned: 3}, result)
+
+	// Once re-encrypted, the old keys can go.
+	useKeys(t, second, nil)
+	checkProfile()
+
+	// Tampered ciphertext is rejected rather than decrypted to garbage.
+	position := customtypes.Encrypted[string]{Value: "Engineer"}
+	require.NoError(t, position.Encrypt())
+	position.Raw[len(position.Raw)-1] ^= 1
+	assert.Error(t, position.Decrypt())
+}
+
+func TestLegacyKeyRing(t *testing.T) {
+	ctx := context.Background()
+
+	// AES-CBC took 16 and 24 byte keys too.
+	legacy := randomKey(t)[:16]
+	ring, err := encrypt.NewLegacyKeyRing(legacy)
+	require.NoError(t, err)
+
+	plain, err := ring.UnwrapKey(ctx, legacyEncrypt(t, legacy, []byte("legacy")))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("legacy"), plain)
+
+	wrapped, err := ring.WrapKey(ctx, []byte("new"))
+	require.NoError(t, err)
+	plain, err = ring.UnwrapKey(ctx, wrapped)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("new"), plain)
+
+	// Key version 1 is not the legacy key itself.
+	legacy = randomKey(t)
+	ring, err = encrypt.NewLegacyKeyRing(legacy)
+	require.NoError(t, err)
+	wrapped, err = ring.WrapKey(ctx, []byte("new"))
+	require.NoError(t, err)
+	raw, err := encrypt.NewKeyRing("1:"+base64.StdEncoding.EncodeToString(legacy), nil)
+	require.NoError(t, err)
+	_, err = raw.UnwrapKey(ctx, wrapped)
+	assert.Error(t, err)
+
+	_, err = encrypt.NewLegacyKeyRing(randomKey(t)[:20])
+	assert.ErrorContains(t, err, "AES_ENCRYPTION_KEY")
+}
+
+// transitStandIn answers the encrypt, decrypt and rewrap endpoints of a Vault
+// transit key, keeping the plaintexts instead of encrypting them.
+type transitStandIn struct {
+	mu        sync.Mutex
+	version   int
+	keys      map[string][]byte
+	wrapped   int
+	unwrapped int
+}
+
+func (s *transitStandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if r.Header.Get("X-Vault-Token") != "test-token" {
+		w.WriteHeader(http.StatusForbidden)
+		json.NewEncoder(w).Encode(map[string]interface{}{"errors": []string{"permission denied"}})
+		return
+	}
+
+	var body map[string]string
+	json.NewDecoder(r.Body).Decode(&body)
+
+	wrap := func(plaintext []byte) string {
+		ciphertext := fmt.Sprintf("vault:v%d:%s", s.version, uuid.New().String())
+		s.keys[ciphertext] = plaintext
+		return ciphertext
+	}
+
+	data := map[string]string{}
+	switch strings.TrimPrefix(r.URL.Path, "/v1/transit/") {
+	case "encrypt/alumni":
+		s.wrapped++
+		plaintext, _ := base64.StdEncoding.DecodeString(body["plaintext"])
+		data["ciphertext"] = wrap(plaintext)
+	case "decrypt/alumni":
+		s.unwrapped++
+		data["plaintext"] = base64.StdEncoding.EncodeToString(s.keys[body["ciphertext"]])
+	case "rewrap/alumni":
+		data["ciphertext"] = wrap(s.keys[body["ciphertext"]])
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+}
+
+func TestVaultKeyProvider(t *testing.T) {
+	resetKeys(t)
+
+	transit := &transitStandIn{version: 1, keys: map[string][]byte{}}
+	server := httptest.NewServer(transit)
+	defer server.Close()
+
+	_, err := encrypt.NewVault(encrypt.VaultConfig{Address: server.URL, Key: "alumni"})
+	assert.Error(t, err, "a token is required")
+
+	vault, err := encrypt.NewVault(encrypt.VaultConfig{Address: server.URL, Token: "test-token", Key: "alumni"})
+	require.NoError(t, err)
+	encrypt.SetEnvelope(encrypt.NewEnvelope(vault, nil))
+
+	gpax := customtypes.Encrypted[float32]{Value: 3.25}
+	require.NoError(t, gpax.Encrypt())
+	assert.Contains(t, string(gpax.Raw), "vault:v1:")
+
+	// Data keys are cached, so reading a value again does not go back to
+	// Vault.
+	encrypt.SetEnvelope(encrypt.NewEnvelope(vault, nil))
+	for i := 0; i < 3; i++ {
+		gpax.Value = 0
+		require.NoError(t, gpax.Decrypt())
+		assert.Equal(t, float32(3.25), gpax.Value)
+	}
+	assert.Equal(t, 1, transit.unwrapped)
+
+	// The fields of a record share its data key: writing two companies
+	// wraps two keys, and reading them back unwraps two.
+	req := models.UserRequestCompany{Companies: []models.Company{
+		{Company: "Acme", Position: customtypes.Encrypted[string]{Value: "Engineer"},
+			SalaryMin: customtypes.Encrypted[float32]{Value: 50000}, SalaryMax: customtypes.Encrypted[float32]{Value: 70000}},
+		{Company: "Initech", Position: customtypes.Encrypted[string]{Value: "Analyst"},
+			SalaryMin: customtypes.Encrypted[float32]{Value: 40000}, SalaryMax: customtypes.Encrypted[float32]{Value: 60000}},
+	}}
+	wrapped := transit.wrapped
+	require.NoError(t, encrypt.EncryptStruct(&req))
+	assert.Equal(t, wrapped+2, transit.wrapped)
+
+	rows := []map[string]interface{}{}
+	for _, company := range req.Companies {
+		rows = append(rows, map[string]interface{}{
+			"position":   company.Position.Raw,
+			"salary_min": company.SalaryMin.Raw,
+			"salary_max": company.SalaryMax.Raw,
+		})
+	}
+	encrypt.SetEnvelope(encrypt.NewEnvelope(vault, nil))
+	unwrapped := transit.unwrapped
+	require.NoError(t, encrypt.DecryptMaps(rows))
+	assert.Equal(t, unwrapped+2, transit.unwrapped)
+	assert.Equal(t, "Analyst", rows[1]["position"])
+	assert.Equal(t, float32(70000), rows[0]["salary_max"])
+
+	app, db := newTestApp(t)
+	userID := db.PutUser(map[string]interface{}{
+		"user_id":  uuid.New().String(),
+		"username": "alice",
+		"role":     "alumnus",
+		"gpax":     gpax.Raw,
+	})
+
+	// The first run only adds the blind index the value was stored without.
+	job := reencrypt.NewJob(db.Store().Encrypted, zap.NewNop())
+	result, err := job.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, reencrypt.Result{Scanned: 1, Updated: 1}, result)
+
+	result, err = job.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, reencrypt.Result{Scanned: 1}, result)
+
+	// After Vault rotates the transit key, the job rewraps the data key.
+	transit.mu.Lock()
+	transit.version = 2
+	transit.mu.Unlock()
+
+	result, err = job.Run(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, reencrypt.Result{Scanned: 1, Updated: 1}, result)
+
+	status, body := doRequest(t, app, http.MethodGet, "/v1/users/"+userID, "", userID, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Contains(t, string(body.Data), `"gpax":3.25`)
+}
+
+func TestEncryptTags(t *testing.T) {
+	type untagged struct {
+		Note customtypes.Encrypted[string] `mapstructure:"note"`
+	}
+	type misnamed struct {
+		Note customtypes.Encrypted[string] `mapstructure:"note" encrypt:"notes"`
+	}
+	type notEncrypted struct {
+		Note string `mapstructure:"note" encrypt:"note"`
+	}
+	type noIndexField struct {
+		Note customtypes.Encrypted[string] `mapstructure:"note" encrypt:"note" index:"NoteIndex"`
+	}
+	for _, model := range []interface{}{untagged{}, misnamed{}, notEncrypted{}, noIndexField{}} {
+		assert.Error(t, encrypt.Register(nil, model), "%T", model)
+	}
+
+	type note struct {
+		Note      customtypes.Encrypted[string] `mapstructure:"test_note" encrypt:"test_note,test_reply_note" index:"NoteIndex"`
+		NoteIndex string                        `mapstructure:"test_note_index"`
+	}
+	type notebook struct {
+		Notes []note
+	}
+	noteIndex := encrypt.BlindIndex{Name: "test_note"}
+	require.NoError(t, encrypt.Register([]encrypt.BlindIndex{noteIndex}, notebook{}))
+
+	book := notebook{Notes: []note{{Note: customtypes.Encrypted[string]{Value: "Remember the milk"}}}}
+	require.NoError(t, encrypt.EncryptStruct(&book))
+	require.NotEmpty(t, book.Notes[0].Note.Raw)
+
+	token, err := noteIndex.Token(reflect.ValueOf("remember  the MILK"))
+	require.NoError(t, err)
+	assert.Equal(t, token, book.Notes[0].NoteIndex)
+
+	// Responses are decrypted under the stored name and its aliases alike.
+	response := map[string]interface{}{
+		"notes": []map[string]interface{}{{"test_note": book.Notes[0].Note.Raw}},
+		"reply": map[string]interface{}{"test_reply_note": book.Notes[0].Note.Raw},
+		"other": book.Notes[0].Note.Raw,
+	}
+	require.NoError(t, encrypt.DecryptMaps(response))
+	assert.Equal(t, "Remember the milk", response["notes"].([]map[string]interface{})[0]["test_note"])
+	assert.Equal(t, "Remember the milk", response["reply"].(map[string]interface{})["test_reply_note"])
+	assert.IsType(t, []byte{}, response["other"])
+}
@@ -0,0 +1,60 @@
+package tests
+
+import (
+	"alumni_api/internal/auth"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJWKS(t *testing.T) {
+	app, db := newTestApp(t)
+	userID := db.PutUser(map[string]interface{}{"username": "alice", "role": "alumnus", "is_verify": true})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var set struct {
+		Keys []map[string]string `json:"keys"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&set))
+	require.NotEmpty(t, set.Keys)
+
+	// Access tokens name the published key that signed them.
+	token := accessToken(t, app, userID, "alumnus")
+	header, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	require.NoError(t, err)
+	var jose map[string]string
+	require.NoError(t, json.Unmarshal(header, &jose))
+
+	var published map[string]string
+	for _, key := range set.Keys {
+		if key["kid"] == jose["kid"] {
+			published = key
+		}
+	}
+	require.NotNil(t, published, "kid %q is not in the JWKS", jose["kid"])
+	assert.Equal(t, jose["alg"], published["alg"])
+	assert.Equal(t, "sig", published["use"])
+
+	status, body := doRequest(t, app, http.MethodGet, "/v1/auth/verify-token", "", userID, "alumnus")
+	assert.Equal(t, http.StatusOK, status, body.Message)
+
+	// A verification link is signed by the same key but cannot sign anyone in.
+	verification, err := auth.GenerateVerificationJWT(userID, auth.GenerateVerificationToken())
+	require.NoError(t, err)
+	status, _ = (&device{cookies: map[string]string{"jwt": verification}}).do(t, app, http.MethodGet, "/v1/auth/verify-token", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	_, err = auth.ParseResetPassword(verification)
+	assert.Error(t, err)
+	_, err = auth.ParseVerification(verification)
+	assert.NoError(t, err)
+}
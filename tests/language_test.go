@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"alumni_api/internal/auth"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLanguage(t *testing.T) {
+	app, db := newTestApp(t)
+
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	db.PutUser(map[string]interface{}{"username": "alice", "email": "alice@example.com", "user_password": hash, "role": "alumnus", "is_verify": true})
+
+	// Without a preference the Accept-Language header picks the language.
+	browser := &device{agent: "browser", cookies: map[string]string{}}
+	status, body := browser.doWithLanguage(t, app, http.MethodPost, "/v1/auth/login", `{"username": "nobody", "password": "wrong horse"}`, "th-TH,th;q=0.9")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, "ผู้ใช้ยังไม่ได้ยืนยันบัญชี", body.Message)
+
+	laptop := login(t, app, "laptop")
+	status, body = laptop.do(t, app, http.MethodGet, "/v1/auth/sessions", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Equal(t, "Get Sessions Successfully", body.Message)
+
+	status, body = laptop.do(t, app, http.MethodPut, "/v1/auth/language", `{"language": "fr"}`)
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, body = laptop.do(t, app, http.MethodPut, "/v1/auth/language", `{"language": "th"}`)
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Equal(t, "เปลี่ยนภาษาสำเร็จ", body.Message)
+
+	// The stored preference wins over the header from the next sign-in on.
+	phone := login(t, app, "phone")
+	status, body = phone.doWithLanguage(t, app, http.MethodGet, "/v1/auth/sessions", "", "en")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Equal(t, "ดึงข้อมูลเซสชันสำเร็จ", body.Message)
+
+	// Mails follow the stored preference too.
+	status, body = doRequest(t, app, http.MethodPost, "/v1/auth/request/password_reset", `{"email":"alice@example.com"}`, "", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	mails := db.Mails()
+	require.Len(t, mails, 1)
+	assert.Equal(t, "th", mails[0].Language)
+}
@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"alumni_api/internal/auth"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginBackoff(t *testing.T) {
+	t.Setenv("LOGIN_FREE_ATTEMPTS", "2")
+	t.Setenv("LOGIN_BACKOFF_BASE_SECONDS", "60")
+	app, db := newTestApp(t)
+
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	db.PutUser(map[string]interface{}{"username": "alice", "user_password": hash, "role": "alumnus", "is_verify": true})
+
+	browser := &device{agent: "browser", cookies: map[string]string{}}
+	wrong := `{"username": "alice", "password": "battery staple"}`
+	for i := 0; i < 2; i++ {
+		status, _ := browser.do(t, app, http.MethodPost, "/v1/auth/login", wrong)
+		require.Equal(t, http.StatusUnauthorized, status)
+	}
+
+	// Past the free attempts even the right password has to wait.
+	status, body := browser.do(t, app, http.MethodPost, "/v1/auth/login", `{"username": "alice", "password": "correct horse"}`)
+	assert.Equal(t, http.StatusTooManyRequests, status, body.Message)
+}
+
+func TestAccountLockout(t *testing.T) {
+	t.Setenv("LOGIN_FREE_ATTEMPTS", "100")
+	t.Setenv("LOGIN_LOCK_THRESHOLD", "3")
+	app, db := newTestApp(t)
+
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	alice := db.PutUser(map[string]interface{}{"username": "alice", "user_password": hash, "role": "alumnus", "is_verify": true})
+	admin := db.PutUser(map[string]interface{}{"username": "admin", "role": "admin", "is_verify": true})
+
+	browser := &device{agent: "browser", cookies: map[string]string{}}
+	wrong := `{"username": "alice", "password": "battery staple"}`
+	for i := 0; i < 3; i++ {
+		status, _ := browser.do(t, app, http.MethodPost, "/v1/auth/login", wrong)
+		require.Equal(t, http.StatusUnauthorized, status)
+	}
+
+	status, body := browser.do(t, app, http.MethodPost, "/v1/auth/login", `{"username": "alice", "password": "correct horse"}`)
+	require.Equal(t, http.StatusLocked, status, body.Message)
+
+	status, _ = doRequest(t, app, http.MethodPost, "/v1/auth/unlock/"+admin, "", alice, "alumnus")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, body = doRequest(t, app, http.MethodPost, "/v1/auth/unlock/"+alice, "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	login(t, app, "browser")
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/auth/security_events", "", alice, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var events []struct {
+		Type    string `json:"type"`
+		ActorID string `json:"actor_id"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &events))
+
+	var types []string
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, "unlocked,locked,login_failed,login_failed,login_failed", strings.Join(types, ","))
+	assert.Equal(t, admin, events[0].ActorID)
+}
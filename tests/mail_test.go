@@ -0,0 +1,124 @@
+package tests
+
+import (
+	"alumni_api/config"
+	"alumni_api/internal/mailer"
+	"alumni_api/internal/models"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, msg mailer.Message) error {
+	return errors.New("relay refused")
+}
+
+// testMailConfig retries failed mails at once and gives up on the second
+// failure.
+var testMailConfig = config.MailConfig{
+	Client:      "https://alumni.test",
+	BatchSize:   10,
+	MaxAttempts: 2,
+}
+
+func TestMailOutbox(t *testing.T) {
+	app, db := newTestApp(t)
+
+	req, err := http.NewRequest(http.MethodPost, "/v1/auth/registry/user",
+		strings.NewReader(`{"username":"bob","email":"bob@example.com","password":"correct horse"}`))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "th-TH,th;q=0.9,en;q=0.8")
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body jsend
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.NotContains(t, string(body.Data), "token")
+
+	mails := db.Mails()
+	require.Len(t, mails, 1)
+	assert.Equal(t, models.MailVerifyEmail, mails[0].Template)
+	assert.Equal(t, "th", mails[0].Language)
+	assert.Equal(t, models.MailPending, mails[0].Status)
+
+	sender := mailer.NewMemory()
+	worker, err := mailer.NewWorker(db.Store().Outbox, sender, testMailConfig, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, worker.Flush(context.Background()))
+
+	messages := sender.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "bob@example.com", messages[0].To)
+	assert.Equal(t, "ยืนยันบัญชีศิษย์เก่า", messages[0].Subject)
+	assert.Contains(t, messages[0].HTML, "https://alumni.test/registry?token=")
+	assert.Contains(t, messages[0].HTML, `lang="th"`)
+
+	mails = db.Mails()
+	assert.Equal(t, models.MailSent, mails[0].Status)
+	assert.Empty(t, mails[0].Data)
+
+	// Sent mails are not sent again.
+	require.NoError(t, worker.Flush(context.Background()))
+	assert.Len(t, sender.Messages(), 1)
+}
+
+func TestMailDeadLetter(t *testing.T) {
+	app, db := newTestApp(t)
+
+	db.PutUser(map[string]interface{}{"username": "bob", "email": "bob@example.com", "role": "user", "is_verify": true})
+	admin := db.PutUser(map[string]interface{}{"username": "admin", "role": "admin", "is_verify": true})
+	user := db.PutUser(map[string]interface{}{"username": "user", "role": "user", "is_verify": true})
+
+	status, body := doRequest(t, app, http.MethodPost, "/v1/auth/request/password_reset", `{"email":"bob@example.com"}`, "", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	worker, err := mailer.NewWorker(db.Store().Outbox, failingMailer{}, testMailConfig, zap.NewNop())
+	require.NoError(t, err)
+
+	require.NoError(t, worker.Flush(context.Background()))
+	require.Equal(t, models.MailPending, db.Mails()[0].Status)
+	require.NoError(t, worker.Flush(context.Background()))
+	require.Equal(t, models.MailDead, db.Mails()[0].Status)
+
+	status, _ = doRequest(t, app, http.MethodGet, "/v1/mail/dead_letters", "", user, "user")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/mail/dead_letters", "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var dead page
+	require.NoError(t, json.Unmarshal(body.Data, &dead))
+	require.Len(t, dead.Items, 1)
+	assert.Equal(t, "relay refused", dead.Items[0]["last_error"])
+	assert.Equal(t, models.MailResetPassword, dead.Items[0]["template"])
+	assert.NotContains(t, dead.Items[0], "data")
+	mailID := dead.Items[0]["mail_id"].(string)
+
+	status, body = doRequest(t, app, http.MethodPost, "/v1/mail/dead_letters/"+mailID+"/retry", "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, _ = doRequest(t, app, http.MethodPost, "/v1/mail/dead_letters/"+mailID+"/retry", "", admin, "admin")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	sender := mailer.NewMemory()
+	worker, err = mailer.NewWorker(db.Store().Outbox, sender, testMailConfig, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, worker.Flush(context.Background()))
+
+	messages := sender.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Alumni Password Reset", messages[0].Subject)
+	assert.Equal(t, models.MailSent, db.Mails()[0].Status)
+}
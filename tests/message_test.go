@@ -0,0 +1,197 @@
+package tests
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestConversations(t *testing.T) {
+	app, db := newTestApp(t)
+
+	alice := db.PutUser(map[string]interface{}{"username": "alice", "first_name": "Alice", "last_name": "A", "role": "alumnus"})
+	bob := db.PutUser(map[string]interface{}{"username": "bob", "first_name": "Bob", "last_name": "B", "role": "alumnus"})
+	carol := db.PutUser(map[string]interface{}{"username": "carol", "first_name": "Carol", "last_name": "C", "role": "alumnus"})
+
+	send := func(from, to, content string) string {
+		status, body := doRequest(t, app, http.MethodPost, "/v1/user/"+from+"/message/send",
+			`{"receiver_id":"`+to+`","content":"`+content+`"}`, from, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(body.Data, &msg))
+		return msg["message_id"].(string)
+	}
+
+	fromBob := send(bob, alice, "Are you free on Friday?")
+	send(carol, alice, "Congrats on the new job")
+	send(carol, alice, "Let's catch up")
+	send(alice, bob, "Yes, see you then")
+
+	var conversations []map[string]interface{}
+	path := "/v1/user/" + alice + "/conversations?limit=1"
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 2)
+
+		status, body := doRequest(t, app, http.MethodGet, path, "", alice, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+
+		var inbox page
+		require.NoError(t, json.Unmarshal(body.Data, &inbox))
+		require.Len(t, inbox.Items, 1)
+		conversations = append(conversations, inbox.Items...)
+
+		if inbox.NextCursor == nil {
+			break
+		}
+		path = "/v1/user/" + alice + "/conversations?limit=1&cursor=" + *inbox.NextCursor
+	}
+	require.Len(t, conversations, 2)
+
+	assert.Equal(t, bob, conversations[0]["user_id"])
+	assert.Equal(t, "Bob B", conversations[0]["name"])
+	assert.EqualValues(t, 1, conversations[0]["unread_count"])
+	last := conversations[0]["last_message"].(map[string]interface{})
+	assert.Equal(t, "Yes, see you then", last["content"])
+	assert.Equal(t, alice, last["sender_id"])
+
+	assert.Equal(t, carol, conversations[1]["user_id"])
+	assert.EqualValues(t, 2, conversations[1]["unread_count"])
+	assert.Equal(t, "Let's catch up", conversations[1]["last_message"].(map[string]interface{})["content"])
+
+	_, err := db.Store().Message.MarkRead(context.Background(), alice, fromBob, zap.NewNop())
+	require.NoError(t, err)
+
+	status, body := doRequest(t, app, http.MethodGet, "/v1/user/"+alice+"/conversations", "", alice, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var inbox page
+	require.NoError(t, json.Unmarshal(body.Data, &inbox))
+	require.Len(t, inbox.Items, 2)
+	assert.EqualValues(t, 0, inbox.Items[0]["unread_count"])
+
+	status, _ = doRequest(t, app, http.MethodGet, "/v1/user/"+alice+"/conversations", "", bob, "alumnus")
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+// TestDeleteMessage checks the message named in the path is the one
+// deleted, rather than one under a freshly generated ID.
+func TestDeleteMessage(t *testing.T) {
+	app, db := newTestApp(t)
+
+	alice := db.PutUser(map[string]interface{}{"username": "alice", "role": "alumnus"})
+	bob := db.PutUser(map[string]interface{}{"username": "bob", "role": "alumnus"})
+
+	var ids []string
+	for _, content := range []string{"Sent by mistake", "Hello Bob"} {
+		status, body := doRequest(t, app, http.MethodPost, "/v1/user/"+alice+"/message/send",
+			`{"receiver_id":"`+bob+`","content":"`+content+`"}`, alice, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(body.Data, &msg))
+		ids = append(ids, msg["message_id"].(string))
+	}
+
+	status, body := doRequest(t, app, http.MethodDelete, "/v1/user/"+alice+"/message/"+ids[0], `{}`, alice, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/user/"+alice+"/chat_message/"+bob, "", alice, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.NotContains(t, string(body.Data), "Sent by mistake")
+	assert.Contains(t, string(body.Data), "Hello Bob")
+}
+
+// uploadAttachment posts a multipart file as the user and returns the new
+// attachment_id.
+func uploadAttachment(t *testing.T, app *fiber.App, userID, fileName, contentType string, content []byte) string {
+	t.Helper()
+
+	status, body := doUpload(t, app, "/v1/user/"+userID+"/message/attachment", fileName, contentType, content, userID)
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var attachment map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &attachment))
+	assert.Equal(t, fileName, attachment["file_name"])
+	return attachment["attachment_id"].(string)
+}
+
+func download(t *testing.T, app *fiber.App, attachmentID, userID string) (*http.Response, []byte) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, "/v1/attachments/"+attachmentID, nil)
+	require.NoError(t, err)
+	req.AddCookie(&http.Cookie{Name: "jwt", Value: accessToken(t, app, userID, "alumnus")})
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return resp, data
+}
+
+func TestMessageAttachments(t *testing.T) {
+	app, db := newTestApp(t)
+
+	alice := db.PutUser(map[string]interface{}{"username": "alice", "first_name": "Alice", "last_name": "A", "role": "alumnus"})
+	bob := db.PutUser(map[string]interface{}{"username": "bob", "first_name": "Bob", "last_name": "B", "role": "alumnus"})
+	carol := db.PutUser(map[string]interface{}{"username": "carol", "first_name": "Carol", "last_name": "C", "role": "alumnus"})
+
+	content := []byte("%PDF-1.4 reunion schedule")
+	attachmentID := uploadAttachment(t, app, alice, "schedule.pdf", "application/pdf", content)
+
+	// Nobody else can send someone's upload, and it is not readable by
+	// outsiders before it is sent.
+	status, body := doRequest(t, app, http.MethodPost, "/v1/user/"+bob+"/message/send",
+		`{"receiver_id":"`+alice+`","content":"Mine now","attachment_id":"`+attachmentID+`"}`, bob, "alumnus")
+	assert.Equal(t, http.StatusNotFound, status, body.Message)
+
+	resp, _ := download(t, app, attachmentID, bob)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	status, body = doRequest(t, app, http.MethodPost, "/v1/user/"+alice+"/message/send",
+		`{"receiver_id":"`+bob+`","content":"Schedule attached","attachment_id":"`+attachmentID+`"}`, alice, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var msg map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &msg))
+	require.NotNil(t, msg["attachment"])
+	assert.Equal(t, "schedule.pdf", msg["attachment"].(map[string]interface{})["file_name"])
+
+	status, body = doRequest(t, app, http.MethodPost, "/v1/user/"+alice+"/message/send",
+		`{"receiver_id":"`+carol+`","content":"Forwarding","attachment_id":"`+attachmentID+`"}`, alice, "alumnus")
+	assert.Equal(t, http.StatusConflict, status, body.Message)
+
+	for _, userID := range []string{alice, bob} {
+		resp, data := download(t, app, attachmentID, userID)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, content, data)
+		assert.Equal(t, "application/pdf", resp.Header.Get("Content-Type"))
+		assert.Contains(t, resp.Header.Get("Content-Disposition"), "schedule.pdf")
+	}
+
+	resp, _ = download(t, app, attachmentID, carol)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/user/"+bob+"/chat_message/"+alice, "", bob, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var chat struct {
+		Other []struct {
+			Message map[string]interface{} `json:"message"`
+		} `json:"other"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &chat))
+	require.Len(t, chat.Other, 1)
+	assert.Equal(t, "schedule.pdf", chat.Other[0].Message["attachment"].(map[string]interface{})["file_name"])
+}
@@ -0,0 +1,137 @@
+package tests
+
+import (
+	"alumni_api/internal/auth"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTOTPVector checks the code generator against the SHA-1 test vector of
+// RFC 6238, cut down to six digits.
+func TestTOTPVector(t *testing.T) {
+	code, err := auth.TOTPCode("GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", auth.TOTPStep(time.Unix(59, 0)))
+	require.NoError(t, err)
+	assert.Equal(t, "287082", code)
+}
+
+type mfaChallenge struct {
+	Status         string `json:"status"`
+	ChallengeToken string `json:"challenge_token"`
+}
+
+func passwordStep(t *testing.T, app *fiber.App, username string) (*device, mfaChallenge) {
+	t.Helper()
+
+	d := &device{agent: "browser", cookies: map[string]string{}}
+	status, body := d.do(t, app, http.MethodPost, "/v1/auth/login", fmt.Sprintf(`{"username": %q, "password": "correct horse"}`, username))
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Empty(t, d.cookies["refresh_token"], "no session before the second factor")
+
+	var challenge mfaChallenge
+	require.NoError(t, json.Unmarshal(body.Data, &challenge))
+	return d, challenge
+}
+
+func totp(t *testing.T, secret string, offset int64) string {
+	t.Helper()
+
+	code, err := auth.TOTPCode(secret, auth.TOTPStep(time.Now())+offset)
+	require.NoError(t, err)
+	return code
+}
+
+func TestMFA(t *testing.T) {
+	app, db := newTestApp(t)
+
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	db.PutUser(map[string]interface{}{"username": "alice", "user_password": hash, "role": "admin", "is_verify": true})
+	bob := db.PutUser(map[string]interface{}{"username": "bob", "user_password": hash, "role": "alumnus", "is_verify": true})
+
+	laptop := login(t, app, "laptop")
+
+	status, body := laptop.do(t, app, http.MethodPost, "/v1/auth/mfa/enroll", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var enrollment struct {
+		Secret          string `json:"secret"`
+		ProvisioningURI string `json:"provisioning_uri"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &enrollment))
+	assert.True(t, strings.HasPrefix(enrollment.ProvisioningURI, "otpauth://totp/Alumni:alice?"), enrollment.ProvisioningURI)
+	assert.Contains(t, enrollment.ProvisioningURI, "secret="+enrollment.Secret)
+
+	status, _ = laptop.do(t, app, http.MethodPost, "/v1/auth/mfa/enable", `{"code": "000000"}`)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, body = laptop.do(t, app, http.MethodPost, "/v1/auth/mfa/enable", fmt.Sprintf(`{"code": %q}`, totp(t, enrollment.Secret, 0)))
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var enabled struct {
+		BackupCodes []string `json:"backup_codes"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &enabled))
+	require.Len(t, enabled.BackupCodes, 10)
+
+	// The password alone now only yields a challenge.
+	phone, challenge := passwordStep(t, app, "alice")
+	assert.Equal(t, "mfa_required", challenge.Status)
+
+	// The challenge is not an access token.
+	status, _ = (&device{cookies: map[string]string{"jwt": challenge.ChallengeToken}}).do(t, app, http.MethodGet, "/v1/auth/verify-token", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	// The code used for enrollment cannot be replayed; the next one works.
+	status, _ = phone.do(t, app, http.MethodPost, "/v1/auth/login/mfa", fmt.Sprintf(`{"challenge_token": %q, "code": %q}`, challenge.ChallengeToken, totp(t, enrollment.Secret, 0)))
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, body = phone.do(t, app, http.MethodPost, "/v1/auth/login/mfa", fmt.Sprintf(`{"challenge_token": %q, "code": %q}`, challenge.ChallengeToken, totp(t, enrollment.Secret, 1)))
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.NotEmpty(t, phone.cookies["refresh_token"])
+
+	// Backup codes work once each.
+	tablet, challenge := passwordStep(t, app, "alice")
+	backup := fmt.Sprintf(`{"challenge_token": %q, "code": %q}`, challenge.ChallengeToken, strings.ToUpper(enabled.BackupCodes[0]))
+	status, body = tablet.do(t, app, http.MethodPost, "/v1/auth/login/mfa", backup)
+	require.Equal(t, http.StatusOK, status, body.Message)
+	status, _ = tablet.do(t, app, http.MethodPost, "/v1/auth/login/mfa", backup)
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, body = laptop.do(t, app, http.MethodGet, "/v1/auth/mfa", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.JSONEq(t, `{"enabled": true, "required": false, "backup_codes_remaining": 9}`, string(body.Data))
+
+	// An admin requires it on bob, who enrolls during his next login.
+	status, body = laptop.do(t, app, http.MethodPut, "/v1/auth/mfa/required/"+bob, `{"required": true}`)
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	browser, challenge := passwordStep(t, app, "bob")
+	require.Equal(t, "mfa_setup_required", challenge.Status)
+
+	status, body = browser.do(t, app, http.MethodPost, "/v1/auth/login/mfa/setup", fmt.Sprintf(`{"challenge_token": %q}`, challenge.ChallengeToken))
+	require.Equal(t, http.StatusOK, status, body.Message)
+	require.NoError(t, json.Unmarshal(body.Data, &enrollment))
+
+	status, body = browser.do(t, app, http.MethodPost, "/v1/auth/login/mfa", fmt.Sprintf(`{"challenge_token": %q, "code": %q}`, challenge.ChallengeToken, totp(t, enrollment.Secret, 0)))
+	require.Equal(t, http.StatusOK, status, body.Message)
+	require.NoError(t, json.Unmarshal(body.Data, &enabled))
+	assert.Len(t, enabled.BackupCodes, 10)
+
+	status, _ = browser.do(t, app, http.MethodPost, "/v1/auth/mfa/disable", fmt.Sprintf(`{"code": %q}`, enabled.BackupCodes[0]))
+	assert.Equal(t, http.StatusForbidden, status)
+
+	// Wrong codes are throttled, even once a right one comes along.
+	_, challenge = passwordStep(t, app, "bob")
+	wrong := fmt.Sprintf(`{"challenge_token": %q, "code": "000000"}`, challenge.ChallengeToken)
+	for i := 0; i < 5; i++ {
+		status, _ = browser.do(t, app, http.MethodPost, "/v1/auth/login/mfa", wrong)
+		require.Equal(t, http.StatusUnauthorized, status)
+	}
+	status, _ = browser.do(t, app, http.MethodPost, "/v1/auth/login/mfa", fmt.Sprintf(`{"challenge_token": %q, "code": %q}`, challenge.ChallengeToken, totp(t, enrollment.Secret, 1)))
+	assert.Equal(t, http.StatusTooManyRequests, status)
+}
@@ -0,0 +1,225 @@
+package tests
+
+import (
+	"alumni_api/config"
+	"alumni_api/internal/auth"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// mockIdP is a minimal OpenID Connect provider: discovery, JWKS, an
+// authorize endpoint that signs in whoever is set as next, and a token
+// endpoint that enforces PKCE.
+type mockIdP struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	next  jwt.MapClaims
+	codes map[string]mockGrant
+}
+
+type mockGrant struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &mockIdP{key: key, codes: map[string]mockGrant{}}
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                                idp.URL,
+			"authorization_endpoint":                idp.URL + "/authorize",
+			"token_endpoint":                        idp.URL + "/token",
+			"jwks_uri":                              idp.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		b64 := base64.RawURLEncoding.EncodeToString
+		json.NewEncoder(w).Encode(map[string]interface{}{"keys": []map[string]string{{
+			"kty": "RSA", "alg": "RS256", "use": "sig", "kid": "mock",
+			"n": b64(key.N.Bytes()), "e": b64(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("code_challenge_method") != "S256" {
+			http.Error(w, "PKCE required", http.StatusBadRequest)
+			return
+		}
+
+		idp.mu.Lock()
+		claims := jwt.MapClaims{"nonce": query.Get("nonce"), "aud": query.Get("client_id")}
+		for name, value := range idp.next {
+			claims[name] = value
+		}
+		code := auth.GenerateVerificationToken()
+		idp.codes[code] = mockGrant{challenge: query.Get("code_challenge"), claims: claims}
+		idp.mu.Unlock()
+
+		http.Redirect(w, r, query.Get("redirect_uri")+"?"+url.Values{"code": {code}, "state": {query.Get("state")}}.Encode(), http.StatusFound)
+	})
+
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		grant, ok := idp.codes[r.FormValue("code")]
+		delete(idp.codes, r.FormValue("code"))
+		idp.mu.Unlock()
+
+		sum := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != grant.challenge {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error": "invalid_grant"}`))
+			return
+		}
+
+		grant.claims["iss"] = idp.URL
+		grant.claims["iat"] = time.Now().Unix()
+		grant.claims["exp"] = time.Now().Add(time.Minute).Unix()
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, grant.claims)
+		token.Header["kid"] = "mock"
+		idToken, err := token.SignedString(key)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "mock", "token_type": "Bearer", "expires_in": 60, "id_token": idToken,
+		})
+	})
+
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+	return idp
+}
+
+// signIn runs a provider sign-in as subject and returns the callback
+// response, following the browser through the provider's redirect.
+func (idp *mockIdP) signIn(t *testing.T, app *fiber.App, subject, email string, verified bool) (int, jsend) {
+	t.Helper()
+
+	idp.mu.Lock()
+	idp.next = jwt.MapClaims{"sub": subject, "email": email, "email_verified": verified}
+	idp.mu.Unlock()
+
+	browser := &device{agent: "browser", cookies: map[string]string{}}
+	status, body := browser.do(t, app, http.MethodGet, "/v1/auth/oidc/university", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var start struct {
+		AuthorizationURL string `json:"authorization_url"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &start))
+	require.NotEmpty(t, browser.cookies["oidc_state"])
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	resp, err := client.Get(start.AuthorizationURL)
+	require.NoError(t, err)
+	resp.Body.Close()
+	require.Equal(t, http.StatusFound, resp.StatusCode)
+
+	location, err := url.Parse(resp.Header.Get("Location"))
+	require.NoError(t, err)
+	callback := fmt.Sprintf(`{"code": %q, "state": %q}`, location.Query().Get("code"), location.Query().Get("state"))
+	return browser.do(t, app, http.MethodPost, "/v1/auth/oidc/university/callback", callback)
+}
+
+func TestOIDCLogin(t *testing.T) {
+	idp := newMockIdP(t)
+	app, db, _ := newTestAppWithProviders(t, auth.NewOIDCProviders(config.OIDCConfig{
+		StateTTL: time.Minute,
+		Providers: []config.OIDCProviderConfig{{
+			Name:         "university",
+			DisplayName:  "University SSO",
+			Issuer:       idp.URL,
+			ClientID:     "alumni",
+			ClientSecret: "secret",
+			RedirectURL:  "http://localhost:3000/oidc/callback",
+			Scopes:       []string{"openid", "email"},
+		}},
+	}))
+
+	alice := db.PutUser(map[string]interface{}{"username": "alice", "email": "alice@example.com", "role": "alumnus", "is_verify": true})
+
+	type signedIn struct {
+		UserID   string `json:"user_id"`
+		UserRole string `json:"user_role"`
+		Pending  bool   `json:"pending"`
+	}
+	decode := func(body jsend) signedIn {
+		var ret signedIn
+		require.NoError(t, json.Unmarshal(body.Data, &ret))
+		return ret
+	}
+
+	status, body := (&device{cookies: map[string]string{}}).do(t, app, http.MethodGet, "/v1/auth/oidc", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.JSONEq(t, `[{"provider": "university", "name": "University SSO"}]`, string(body.Data))
+
+	// A verified email links to the existing profile, and the link holds even
+	// once the provider reports a different email.
+	status, body = idp.signIn(t, app, "u-1", "alice@example.com", true)
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Equal(t, signedIn{UserID: alice, UserRole: "alumnus"}, decode(body))
+
+	status, body = idp.signIn(t, app, "u-1", "alice@elsewhere.example", false)
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Equal(t, alice, decode(body).UserID)
+
+	// An unverified email is never trusted to link.
+	status, body = idp.signIn(t, app, "u-2", "alice@example.com", false)
+	require.Equal(t, http.StatusOK, status, body.Message)
+	mallory := decode(body)
+	assert.NotEqual(t, alice, mallory.UserID)
+	assert.True(t, mallory.Pending)
+
+	// An unknown identity gets a pending profile it keeps signing in to.
+	status, body = idp.signIn(t, app, "u-3", "bob@example.com", true)
+	require.Equal(t, http.StatusOK, status, body.Message)
+	bob := decode(body)
+	assert.Equal(t, signedIn{UserID: bob.UserID, UserRole: "user", Pending: true}, bob)
+
+	status, body = idp.signIn(t, app, "u-3", "bob@example.com", true)
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Equal(t, bob.UserID, decode(body).UserID)
+
+	// The callback only completes for the browser that started the sign-in.
+	browser := &device{agent: "browser", cookies: map[string]string{}}
+	status, _ = browser.do(t, app, http.MethodGet, "/v1/auth/oidc/university", "")
+	require.Equal(t, http.StatusOK, status)
+	status, _ = browser.do(t, app, http.MethodPost, "/v1/auth/oidc/university/callback", `{"code": "forged", "state": "forged"}`)
+	assert.Equal(t, http.StatusUnauthorized, status)
+	status, _ = browser.do(t, app, http.MethodPost, "/v1/auth/oidc/university/callback", `{"code": "forged", "state": "forged"}`)
+	assert.Equal(t, http.StatusUnauthorized, status, "the state cookie is single use")
+
+	status, _ = browser.do(t, app, http.MethodGet, "/v1/auth/oidc/unknown", "")
+	assert.Equal(t, http.StatusNotFound, status)
+}
@@ -0,0 +1,273 @@
+package tests
+
+import (
+	"alumni_api/config"
+	"alumni_api/internal/models"
+	"alumni_api/internal/services"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostLifecycle(t *testing.T) {
+	app, db := newTestApp(t)
+
+	author := db.PutUser(map[string]interface{}{"username": "author", "first_name": "Author", "last_name": "One", "role": "alumnus"})
+	reader := db.PutUser(map[string]interface{}{"username": "reader", "first_name": "Reader", "last_name": "Two", "role": "user"})
+
+	status, _ := doRequest(t, app, http.MethodPost, "/v1/post",
+		`{"title":"Reunion","content":"Class of 2560 reunion dinner","post_type":"story","visibility":"all"}`, "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	status, body := doRequest(t, app, http.MethodPost, "/v1/post",
+		`{"title":"Reunion","content":"Class of 2560 reunion dinner","post_type":"story","visibility":"all"}`, author, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var created map[string]string
+	require.NoError(t, json.Unmarshal(body.Data, &created))
+	postID := created["post_id"]
+	require.NotEmpty(t, postID)
+
+	status, body = doRequest(t, app, http.MethodPost, "/v1/post/"+postID+"/like", "", reader, "user")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, body = doRequest(t, app, http.MethodPost, "/v1/post/"+postID+"/comment", `{"comment":"See you there"}`, reader, "user")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var comment map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &comment))
+
+	status, body = doRequest(t, app, http.MethodPost, "/v1/post/"+postID+"/comment/"+comment["comment_id"].(string),
+		`{"comment":"Me too"}`, author, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/post/"+postID, "", reader, "user")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var post map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &post))
+	assert.Equal(t, "Reunion", post["title"])
+	assert.Equal(t, "Author One", post["author_name"])
+	assert.EqualValues(t, 1, post["likes_count"])
+	assert.EqualValues(t, 1, post["views_count"])
+	assert.Equal(t, true, post["has_liked"])
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/post/"+postID+"/comment", "", "", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var comments page
+	require.NoError(t, json.Unmarshal(body.Data, &comments))
+	require.Len(t, comments.Items, 1)
+	assert.Equal(t, "See you there", comments.Items[0]["content"])
+	require.Len(t, comments.Items[0]["replies"], 1)
+	assert.Nil(t, comments.NextCursor)
+
+	status, _ = doRequest(t, app, http.MethodDelete, "/v1/post/"+postID, "", reader, "user")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, body = doRequest(t, app, http.MethodDelete, "/v1/post/"+postID, "", author, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/post/all", "", "", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var posts page
+	require.NoError(t, json.Unmarshal(body.Data, &posts))
+	assert.Empty(t, posts.Items)
+}
+
+func TestPostPagination(t *testing.T) {
+	app, db := newTestApp(t)
+
+	author := db.PutUser(map[string]interface{}{"username": "author", "first_name": "Author", "last_name": "One", "role": "alumnus"})
+
+	for _, title := range []string{"First", "Second", "Third", "Fourth", "Fifth"} {
+		status, body := doRequest(t, app, http.MethodPost, "/v1/post",
+			`{"title":"`+title+`","content":"Pagination","post_type":"story","visibility":"all"}`, author, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+	}
+
+	var titles []string
+	path := "/v1/post/all?limit=2"
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 3)
+
+		status, body := doRequest(t, app, http.MethodGet, path, "", "", "")
+		require.Equal(t, http.StatusOK, status, body.Message)
+
+		var posts page
+		require.NoError(t, json.Unmarshal(body.Data, &posts))
+		require.LessOrEqual(t, len(posts.Items), 2)
+		for _, post := range posts.Items {
+			titles = append(titles, post["title"].(string))
+		}
+
+		if posts.NextCursor == nil {
+			break
+		}
+		path = "/v1/post/all?limit=2&cursor=" + *posts.NextCursor
+	}
+	assert.Equal(t, []string{"Fifth", "Fourth", "Third", "Second", "First"}, titles)
+
+	status, _ := doRequest(t, app, http.MethodGet, "/v1/post/all?cursor=not-a-cursor", "", "", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = doRequest(t, app, http.MethodGet, "/v1/post/all?limit=500", "", "", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+}
+
+func TestFeedRanking(t *testing.T) {
+	app, db := newTestApp(t)
+
+	viewer := db.PutUser(map[string]interface{}{"username": "viewer", "first_name": "View", "last_name": "Er", "role": "alumnus", "generation": "CPE30"})
+	friend := db.PutUser(map[string]interface{}{"username": "friend", "first_name": "Friend", "last_name": "Ly", "role": "alumnus", "generation": "CPE30"})
+	stranger := db.PutUser(map[string]interface{}{"username": "stranger", "first_name": "Stran", "last_name": "Ger", "role": "alumnus", "generation": "CPE12"})
+
+	status, body := doRequest(t, app, http.MethodPost, "/v1/users/"+viewer+"/friends", `{"user_id":"`+friend+`"}`, viewer, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	for _, author := range []string{friend, stranger} {
+		status, body := doRequest(t, app, http.MethodPost, "/v1/post",
+			`{"title":"Hello","content":"Posted for the feed","post_type":"story","visibility":"all"}`, author, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+	}
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/post/feed", "", viewer, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var feed page
+	require.NoError(t, json.Unmarshal(body.Data, &feed))
+	require.Len(t, feed.Items, 2)
+	assert.Equal(t, friend, feed.Items[0]["user_id"])
+
+	explanation := feed.Items[0]["explanation"].(map[string]interface{})
+	assert.Contains(t, explanation, "friend")
+	assert.Contains(t, explanation, "generation")
+	assert.NotContains(t, feed.Items[1]["explanation"], "friend")
+
+	// Anonymous callers have no graph signals.
+	status, body = doRequest(t, app, http.MethodGet, "/v1/post/feed", "", "", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	require.NoError(t, json.Unmarshal(body.Data, &feed))
+	require.Len(t, feed.Items, 2)
+	for _, item := range feed.Items {
+		explanation := item["explanation"].(map[string]interface{})
+		assert.Len(t, explanation, 1)
+		assert.Contains(t, explanation, "recency")
+	}
+}
+
+func TestPostVisibility(t *testing.T) {
+	app, db := newTestApp(t)
+
+	author := db.PutUser(map[string]interface{}{"username": "author", "first_name": "Author", "last_name": "One", "role": "admin"})
+	viewers := map[string]string{
+		"user":    db.PutUser(map[string]interface{}{"username": "user", "role": "user"}),
+		"alumnus": db.PutUser(map[string]interface{}{"username": "alumnus", "role": "alumnus"}),
+		"admin":   db.PutUser(map[string]interface{}{"username": "admin", "role": "admin"}),
+	}
+
+	postIDs := make(map[string]string)
+	for _, visibility := range []string{"all", "alumnus", "admin"} {
+		status, body := doRequest(t, app, http.MethodPost, "/v1/post",
+			`{"title":"Gathering","content":"Visible to `+visibility+`","post_type":"event","visibility":"`+visibility+`"}`, author, "admin")
+		require.Equal(t, http.StatusOK, status, body.Message)
+
+		var created map[string]string
+		require.NoError(t, json.Unmarshal(body.Data, &created))
+		postIDs[visibility] = created["post_id"]
+	}
+
+	cases := []struct {
+		role    string
+		visible []string
+	}{
+		{"", []string{"all"}},
+		{"user", []string{"all"}},
+		{"alumnus", []string{"all", "alumnus"}},
+		{"admin", []string{"all", "alumnus", "admin"}},
+	}
+
+	for _, tc := range cases {
+		t.Run("role="+tc.role, func(t *testing.T) {
+			userID := viewers[tc.role]
+
+			for _, path := range []string{"/v1/post/all", "/v1/post/feed"} {
+				status, body := doRequest(t, app, http.MethodGet, path, "", userID, tc.role)
+				require.Equal(t, http.StatusOK, status, body.Message)
+
+				var posts page
+				require.NoError(t, json.Unmarshal(body.Data, &posts))
+				assert.Len(t, posts.Items, len(tc.visible), path)
+			}
+
+			for visibility, postID := range postIDs {
+				want := http.StatusNotFound
+				if slices.Contains(tc.visible, visibility) {
+					want = http.StatusOK
+				}
+
+				status, _ := doRequest(t, app, http.MethodGet, "/v1/post/"+postID, "", userID, tc.role)
+				assert.Equal(t, want, status, visibility)
+
+				status, _ = doRequest(t, app, http.MethodGet, "/v1/post/"+postID+"/comment", "", userID, tc.role)
+				assert.Equal(t, want, status, visibility)
+			}
+
+			status, body := doRequest(t, app, http.MethodGet, "/v1/stat/activity", "", userID, tc.role)
+			require.Equal(t, http.StatusOK, status, body.Message)
+
+			var activity map[string]interface{}
+			require.NoError(t, json.Unmarshal(body.Data, &activity))
+			assert.EqualValues(t, len(tc.visible), activity["event_count"])
+		})
+	}
+
+	// Authors keep seeing their own posts whatever the visibility.
+	status, body := doRequest(t, app, http.MethodGet, "/v1/post/all", "", author, "user")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var posts page
+	require.NoError(t, json.Unmarshal(body.Data, &posts))
+	assert.Len(t, posts.Items, 3)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/stat/post", "", viewers["admin"], "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var stats []map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &stats))
+	assert.Len(t, stats, 3)
+}
+
+// TestFeedPagesOverTime asks for the second page of the feed long after the
+// first. Recency has decayed the new post below the older post from a friend
+// by then, which must not bring it back.
+func TestFeedPagesOverTime(t *testing.T) {
+	cfg := config.FeedConfig{Recency: 3, Friend: 2, HalfLifeHours: 48}
+	start := time.Now()
+
+	candidates := func() []models.FeedCandidate {
+		return []models.FeedCandidate{
+			{Post: map[string]interface{}{"post_id": "new"}, CreatedAt: start.UnixMilli()},
+			{Post: map[string]interface{}{"post_id": "friend"}, CreatedAt: start.Add(-96 * time.Hour).UnixMilli(), IsFriend: true},
+		}
+	}
+
+	first, cursor := services.RankFeed(candidates(), cfg, start, models.Page{Limit: 1})
+	require.Len(t, first, 1)
+	assert.Equal(t, "new", first[0]["post_id"])
+	require.NotEmpty(t, cursor)
+
+	after, err := models.DecodeCursor(cursor)
+	require.NoError(t, err)
+	second, cursor := services.RankFeed(candidates(), cfg, start.Add(96*time.Hour), models.Page{After: after, Limit: 1})
+	require.Len(t, second, 1)
+	assert.Equal(t, "friend", second[0]["post_id"])
+	assert.Empty(t, cursor)
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModeratorRole(t *testing.T) {
+	app, db := newTestApp(t)
+
+	author := db.PutUser(map[string]interface{}{"username": "author", "role": "alumnus"})
+	mod := db.PutUser(map[string]interface{}{"username": "mod", "role": "alumnus"})
+	admin := db.PutUser(map[string]interface{}{"username": "admin", "role": "admin"})
+
+	status, body := doRequest(t, app, http.MethodPost, "/v1/post",
+		`{"title":"Spam","content":"Buy cheap watches now","post_type":"story","visibility":"all"}`, author, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var created map[string]string
+	require.NoError(t, json.Unmarshal(body.Data, &created))
+	postID := created["post_id"]
+
+	// Before the role is assigned mod is an alumnus like any other.
+	status, _ = doRequest(t, app, http.MethodDelete, "/v1/post/"+postID, "", mod, "alumnus")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, _ = doRequest(t, app, http.MethodGet, "/v1/utils/report", "", mod, "alumnus")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, _ = doRequest(t, app, http.MethodPost, "/v1/roles/moderator/users/"+mod, "", mod, "alumnus")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, body = doRequest(t, app, http.MethodPost, "/v1/roles/moderator/users/"+mod, "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, _ = doRequest(t, app, http.MethodPost, "/v1/roles/admin/users/"+mod, "", admin, "admin")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/auth/permissions", "", mod, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var permissions []string
+	require.NoError(t, json.Unmarshal(body.Data, &permissions))
+	assert.ElementsMatch(t, []string{"post:read:alumnus", "profile:read:alumni", "post:delete:any", "comment:delete:any", "report:review"}, permissions)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/utils/report", "", mod, "alumnus")
+	assert.Equal(t, http.StatusOK, status, body.Message)
+
+	// Moderators are not admins.
+	status, _ = doRequest(t, app, http.MethodGet, "/v1/stat/registry", "", mod, "alumnus")
+	assert.Equal(t, http.StatusForbidden, status)
+
+	status, body = doRequest(t, app, http.MethodDelete, "/v1/post/"+postID, "", mod, "alumnus")
+	assert.Equal(t, http.StatusOK, status, body.Message)
+
+	status, body = doRequest(t, app, http.MethodDelete, "/v1/roles/moderator/users/"+mod, "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, _ = doRequest(t, app, http.MethodGet, "/v1/utils/report", "", mod, "alumnus")
+	assert.Equal(t, http.StatusForbidden, status)
+}
+
+// TestModeratorReadsAlumniPosts checks post visibility follows the roles a
+// user is assigned, not only the role on their profile.
+func TestModeratorReadsAlumniPosts(t *testing.T) {
+	app, db := newTestApp(t)
+
+	author := db.PutUser(map[string]interface{}{"username": "author", "role": "alumnus"})
+	mod := db.PutUser(map[string]interface{}{"username": "mod", "role": "user"})
+	admin := db.PutUser(map[string]interface{}{"username": "admin", "role": "admin"})
+
+	status, body := doRequest(t, app, http.MethodPost, "/v1/post",
+		`{"title":"Alumni only","content":"Reunion details for alumni","post_type":"story","visibility":"alumnus"}`, author, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var created map[string]string
+	require.NoError(t, json.Unmarshal(body.Data, &created))
+	postID := created["post_id"]
+
+	status, _ = doRequest(t, app, http.MethodGet, "/v1/post/"+postID, "", mod, "user")
+	assert.Equal(t, http.StatusNotFound, status)
+
+	status, body = doRequest(t, app, http.MethodPost, "/v1/roles/moderator/users/"+mod, "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/post/"+postID, "", mod, "user")
+	assert.Equal(t, http.StatusOK, status, body.Message)
+}
+
+func TestCustomRole(t *testing.T) {
+	app, db := newTestApp(t)
+
+	analyst := db.PutUser(map[string]interface{}{"username": "analyst", "role": "user"})
+	admin := db.PutUser(map[string]interface{}{"username": "admin", "role": "admin"})
+
+	status, _ := doRequest(t, app, http.MethodPut, "/v1/roles/analyst", `{"permissions":["stat:fly"]}`, admin, "admin")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = doRequest(t, app, http.MethodPut, "/v1/roles/moderator", `{"permissions":["stat:read"]}`, admin, "admin")
+	assert.Equal(t, http.StatusConflict, status)
+
+	status, body := doRequest(t, app, http.MethodPut, "/v1/roles/analyst", `{"permissions":["stat:read"]}`, admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, body = doRequest(t, app, http.MethodPost, "/v1/roles/analyst/users/"+analyst, "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/stat/registry", "", analyst, "user")
+	assert.Equal(t, http.StatusOK, status, body.Message)
+
+	status, body = doRequest(t, app, http.MethodDelete, "/v1/roles/analyst", "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	status, _ = doRequest(t, app, http.MethodGet, "/v1/stat/registry", "", analyst, "user")
+	assert.Equal(t, http.StatusForbidden, status)
+}
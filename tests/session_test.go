@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"alumni_api/internal/auth"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// device keeps the cookies one client got from the auth endpoints.
+type device struct {
+	agent   string
+	cookies map[string]string
+}
+
+func (d *device) do(t *testing.T, app *fiber.App, method, path, body string) (int, jsend) {
+	t.Helper()
+
+	return d.doWithLanguage(t, app, method, path, body, "")
+}
+
+// doWithLanguage is do with the given Accept-Language header.
+func (d *device) doWithLanguage(t *testing.T, app *fiber.App, method, path, body, language string) (int, jsend) {
+	t.Helper()
+
+	req, err := http.NewRequest(method, path, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", d.agent)
+	if language != "" {
+		req.Header.Set("Accept-Language", language)
+	}
+	for name, value := range d.cookies {
+		req.AddCookie(&http.Cookie{Name: name, Value: value})
+	}
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	for _, cookie := range resp.Cookies() {
+		d.cookies[cookie.Name] = cookie.Value
+	}
+
+	var out jsend
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+	return resp.StatusCode, out
+}
+
+func login(t *testing.T, app *fiber.App, agent string) *device {
+	t.Helper()
+
+	d := &device{agent: agent, cookies: map[string]string{}}
+	status, body := d.do(t, app, http.MethodPost, "/v1/auth/login", `{"username": "alice", "password": "correct horse"}`)
+	require.Equal(t, http.StatusOK, status, body.Message)
+	require.NotEmpty(t, d.cookies["refresh_token"])
+	return d
+}
+
+func TestSessions(t *testing.T) {
+	app, db := newTestApp(t)
+
+	hash, err := auth.HashPassword("correct horse")
+	require.NoError(t, err)
+	db.PutUser(map[string]interface{}{"username": "alice", "user_password": hash, "role": "alumnus", "is_verify": true})
+
+	laptop := login(t, app, "laptop")
+	phone := login(t, app, "phone")
+
+	status, body := laptop.do(t, app, http.MethodGet, "/v1/auth/sessions", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var sessions []map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &sessions))
+	require.Len(t, sessions, 2)
+	for _, session := range sessions {
+		assert.Equal(t, session["user_agent"] == "laptop", session["current"])
+	}
+
+	// Rotation hands out a new refresh token and the old one stops working.
+	stolen := laptop.cookies["refresh_token"]
+	status, body = laptop.do(t, app, http.MethodPost, "/v1/auth/refresh", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	require.NotEqual(t, stolen, laptop.cookies["refresh_token"])
+
+	status, _ = laptop.do(t, app, http.MethodGet, "/v1/auth/verify-token", "")
+	assert.Equal(t, http.StatusOK, status)
+
+	// Replaying it revokes the session, taking the fresh tokens down too.
+	thief := &device{agent: "thief", cookies: map[string]string{"refresh_token": stolen}}
+	status, body = thief.do(t, app, http.MethodPost, "/v1/auth/refresh", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+	assert.Equal(t, "Refresh token reuse detected", body.Message)
+
+	status, _ = laptop.do(t, app, http.MethodGet, "/v1/auth/verify-token", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	// Logging out revokes the access token server-side, not just the cookie.
+	token := phone.cookies["jwt"]
+	status, _ = phone.do(t, app, http.MethodPost, "/v1/auth/logout", "")
+	require.Equal(t, http.StatusOK, status)
+	replay := &device{agent: "phone", cookies: map[string]string{"jwt": token}}
+	status, _ = replay.do(t, app, http.MethodGet, "/v1/auth/verify-token", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	tablet := login(t, app, "tablet")
+	desktop := login(t, app, "desktop")
+
+	status, body = tablet.do(t, app, http.MethodGet, "/v1/auth/sessions", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	require.NoError(t, json.Unmarshal(body.Data, &sessions))
+	require.Len(t, sessions, 2)
+
+	var desktopID string
+	for _, session := range sessions {
+		if session["user_agent"] == "desktop" {
+			desktopID = session["session_id"].(string)
+		}
+	}
+
+	status, body = tablet.do(t, app, http.MethodDelete, "/v1/auth/sessions/"+desktopID, "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	status, _ = desktop.do(t, app, http.MethodGet, "/v1/auth/verify-token", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+
+	token = tablet.cookies["jwt"]
+	status, body = tablet.do(t, app, http.MethodDelete, "/v1/auth/sessions", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	replay = &device{agent: "tablet", cookies: map[string]string{"jwt": token}}
+	status, _ = replay.do(t, app, http.MethodGet, "/v1/auth/verify-token", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
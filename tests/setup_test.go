@@ -0,0 +1,182 @@
+package tests
+
+import (
+	"alumni_api/config"
+	"alumni_api/internal/auth"
+	"alumni_api/internal/models"
+	"alumni_api/internal/repositories/memory"
+	"alumni_api/internal/routes"
+	"alumni_api/internal/storage"
+	"alumni_api/internal/validators"
+	"alumni_api/internal/websockets"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/textproto"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type jsend struct {
+	Status  string          `json:"status"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+}
+
+type page struct {
+	Items      []map[string]interface{} `json:"items"`
+	NextCursor *string                  `json:"next_cursor"`
+}
+
+func init() {
+	validators.Init()
+}
+
+// testDBs maps every test app to its store, so helpers acting as a user can
+// open the session their access token has to belong to.
+var testDBs sync.Map
+
+// accessToken signs in userID on a fresh session of app's store.
+func accessToken(t testing.TB, app *fiber.App, userID, role string) string {
+	t.Helper()
+
+	db, ok := testDBs.Load(app)
+	require.True(t, ok, "app was not built by newTestApp")
+
+	token, err := auth.GenerateJWT(userID, role, "", "", 0, db.(*memory.DB).PutSession(userID))
+	require.NoError(t, err)
+	return token
+}
+
+// newTestApp mounts the API routes on top of a fresh in-memory store.
+func newTestApp(t testing.TB) (*fiber.App, *memory.DB) {
+	t.Helper()
+
+	app, db, _ := newTestAppWithHub(t)
+	return app, db
+}
+
+func newTestAppWithHub(t testing.TB) (*fiber.App, *memory.DB, *websockets.Hub) {
+	t.Helper()
+
+	return newTestAppWithProviders(t, auth.NewOIDCProviders(config.OIDCConfig{}))
+}
+
+// newTestAppWithProviders is newTestAppWithHub with the given OIDC providers,
+// for tests that sign in against a mock identity provider.
+func newTestAppWithProviders(t testing.TB, providers *auth.OIDCProviders) (*fiber.App, *memory.DB, *websockets.Hub) {
+	t.Helper()
+
+	db := memory.New()
+	store := db.Store()
+	require.NoError(t, store.Role.SyncBuiltInRoles(context.Background(), models.BuiltInRoles, zap.NewNop()))
+	logger := zap.NewNop()
+	hub := websockets.NewHub(logger)
+	files := storage.NewMemory(storage.NewSigner([]byte("test_signing_key"), "/v1/files"))
+
+	// The in-memory store keeps request strings such as route params, which
+	// fasthttp would otherwise reuse once the handler returns.
+	app := fiber.New(fiber.Config{Immutable: true, DisableStartupMessage: true})
+	api := app.Group("/v1")
+	routes.UserRoutes(api, store, logger)
+	routes.OIDCRoutes(api, store, providers, logger)
+	routes.AuthRoutes(api, store, logger)
+	routes.PostRoutes(api, store, logger)
+	routes.UploadRoutes(api, store, files, logger)
+	routes.MessageRoutes(api, store, hub, files, logger)
+	routes.ConversationRoutes(api, store, hub, logger)
+	routes.StatRoutes(api, store, logger)
+	routes.UtilsRoute(api, store, logger)
+	routes.RoleRoutes(api, store, logger)
+	routes.DepartmentRoutes(api, store, logger)
+	routes.MailRoutes(api, store, logger)
+	routes.WellKnownRoutes(app, logger)
+
+	testDBs.Store(app, db)
+	t.Cleanup(func() { testDBs.Delete(app) })
+
+	return app, db, hub
+}
+
+// serve starts the app on a free local port for tests that need a real
+// connection, and returns its address.
+func serve(t testing.TB, app *fiber.App) string {
+	t.Helper()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go app.Listener(ln)
+	t.Cleanup(func() { app.Shutdown() })
+
+	return ln.Addr().String()
+}
+
+// doRequest sends a request as the given user (no cookie when userID is
+// empty) and decodes the JSend envelope.
+func doRequest(t testing.TB, app *fiber.App, method, path, body, userID, role string) (int, jsend) {
+	t.Helper()
+
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, path, reader)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+
+	if userID != "" {
+		req.AddCookie(&http.Cookie{Name: "jwt", Value: accessToken(t, app, userID, role)})
+	}
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var out jsend
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	return resp.StatusCode, out
+}
+
+// doUpload posts content as the multipart "file" field as the given user and
+// decodes the JSend envelope.
+func doUpload(t testing.TB, app *fiber.App, path, fileName, contentType string, content []byte, userID string) (int, jsend) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	form := multipart.NewWriter(&buf)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", `form-data; name="file"; filename="`+fileName+`"`)
+	header.Set("Content-Type", contentType)
+	part, err := form.CreatePart(header)
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, form.Close())
+
+	req, err := http.NewRequest(http.MethodPost, path, &buf)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+
+	req.AddCookie(&http.Cookie{Name: "jwt", Value: accessToken(t, app, userID, "alumnus")})
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var out jsend
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&out))
+
+	return resp.StatusCode, out
+}
@@ -0,0 +1,119 @@
+package tests
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// exifJPEG encodes a width×height JPEG carrying an EXIF block with the given
+// orientation and a GPS marker string.
+func exifJPEG(t *testing.T, width, height int, orientation uint16) []byte {
+	t.Helper()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	// Little-endian TIFF with a single IFD entry: Orientation (SHORT).
+	tiff := []byte("II*\x00\x08\x00\x00\x00\x01\x00")
+	entry := make([]byte, 12)
+	binary.LittleEndian.PutUint16(entry[0:], 0x0112)
+	binary.LittleEndian.PutUint16(entry[2:], 3)
+	binary.LittleEndian.PutUint32(entry[4:], 1)
+	binary.LittleEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+	tiff = append(tiff, []byte("GPSLatitude 13.6512N")...)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:], uint16(len(payload)+2))
+	segment = append(segment, payload...)
+
+	data := encoded.Bytes()
+	return append(append([]byte{0xFF, 0xD8}, segment...), data[2:]...)
+}
+
+func fetch(t *testing.T, app *fiber.App, path string) []byte {
+	t.Helper()
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil), -1)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode, path)
+
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	return data
+}
+
+func TestImageUpload(t *testing.T) {
+	app, db := newTestApp(t)
+	alice := db.PutUser(map[string]interface{}{"username": "alice", "role": "alumnus"})
+
+	photo := exifJPEG(t, 400, 200, 6)
+	require.Contains(t, string(photo), "GPSLatitude")
+
+	// The declared type and extension are ignored in favour of the bytes.
+	status, body := doUpload(t, app, "/v1/upload", "photo.png", "image/png", photo, alice)
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var upload struct {
+		URL         string            `json:"url"`
+		ContentType string            `json:"content_type"`
+		Width       int               `json:"width"`
+		Height      int               `json:"height"`
+		Renditions  map[string]string `json:"renditions"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &upload))
+	assert.Equal(t, "image/jpeg", upload.ContentType)
+	assert.Regexp(t, `^/uploads/[0-9a-f-]+\.jpg$`, upload.URL)
+
+	// Orientation 6 is applied to the pixels before the metadata is dropped.
+	assert.Equal(t, 200, upload.Width)
+	assert.Equal(t, 400, upload.Height)
+
+	original := fetch(t, app, "/v1"+upload.URL)
+	assert.NotContains(t, string(original), "Exif")
+	assert.NotContains(t, string(original), "GPSLatitude")
+
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(original))
+	require.NoError(t, err)
+	assert.Equal(t, 200, cfg.Width)
+	assert.Equal(t, 400, cfg.Height)
+
+	sizes := map[string][2]int{"thumbnail": {80, 160}, "medium": {200, 400}}
+	require.Len(t, upload.Renditions, len(sizes))
+	for name, size := range sizes {
+		data := fetch(t, app, "/v1"+upload.Renditions[name])
+		assert.NotContains(t, string(data), "GPSLatitude")
+
+		cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+		require.NoError(t, err, name)
+		assert.Equal(t, size, [2]int{cfg.Width, cfg.Height}, name)
+	}
+
+	status, _ = doUpload(t, app, "/v1/upload", "evil.png", "image/png", []byte("<html><script>alert(1)</script></html>"), alice)
+	assert.Equal(t, http.StatusUnsupportedMediaType, status)
+
+	status, _ = doUpload(t, app, "/v1/upload", "broken.png", "image/png", append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...), alice)
+	assert.Equal(t, http.StatusBadRequest, status)
+}
@@ -1,18 +1,280 @@
 package tests
 
 import (
-	"github.com/stretchr/testify/assert"
+	"encoding/json"
+	"net/http"
+	"sort"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestGetUser(t *testing.T) {
-	// Simulate Neo4j and Redis interactions for a unit test
-	assert.Equal(t, 1, 1) // Replace with actual assertions
+	app, db := newTestApp(t)
+
+	userID := db.PutUser(map[string]interface{}{
+		"username":   "somchai",
+		"first_name": "Somchai",
+		"last_name":  "Jaidee",
+		"role":       "alumnus",
+		"is_verify":  true,
+		"email":      "somchai@example.com",
+	})
+	otherID := db.PutUser(map[string]interface{}{"username": "other", "role": "user"})
+
+	status, body := doRequest(t, app, http.MethodGet, "/v1/users/"+userID, "", userID, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var user map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &user))
+	assert.Equal(t, userID, user["user_id"])
+	assert.Equal(t, "Somchai Jaidee", user["name"])
+
+	assert.Contains(t, string(body.Data), "somchai@example.com")
+
+	// Others get the profile without what the owner hides from them.
+	status, body = doRequest(t, app, http.MethodGet, "/v1/users/"+userID, "", otherID, "user")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Contains(t, string(body.Data), "Somchai Jaidee")
+	assert.NotContains(t, string(body.Data), "somchai@example.com")
+
+	status, _ = doRequest(t, app, http.MethodGet, "/v1/users/"+userID, "", "", "")
+	assert.Equal(t, http.StatusUnauthorized, status)
+}
+
+func TestFriends(t *testing.T) {
+	app, db := newTestApp(t)
+
+	a := db.PutUser(map[string]interface{}{"username": "a", "first_name": "Anan", "last_name": "A"})
+	b := db.PutUser(map[string]interface{}{"username": "b", "first_name": "Bua", "last_name": "B"})
+	c := db.PutUser(map[string]interface{}{"username": "c", "first_name": "Chai", "last_name": "C"})
+
+	for _, pair := range [][2]string{{a, b}, {b, c}} {
+		status, body := doRequest(t, app, http.MethodPost, "/v1/users/"+pair[0]+"/friends",
+			`{"user_id":"`+pair[1]+`"}`, pair[0], "user")
+		require.Equal(t, http.StatusOK, status, body.Message)
+	}
+
+	status, body := doRequest(t, app, http.MethodGet, "/v1/users/"+a+"/foaf/"+c, "", a, "user")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var foaf []map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &foaf))
+	require.Len(t, foaf, 1)
+	assert.Equal(t, b, foaf[0]["user_id"])
+}
+
+func TestPrivacy(t *testing.T) {
+	app, db := newTestApp(t)
+
+	owner := db.PutUser(map[string]interface{}{
+		"username":   "owner",
+		"first_name": "Owner",
+		"last_name":  "One",
+		"role":       "alumnus",
+		"email":      "owner@example.com",
+		"dob":        "1990-05-01",
+		"gender":     "female",
+	})
+	friend := db.PutUser(map[string]interface{}{"username": "friend", "role": "user"})
+	alumnus := db.PutUser(map[string]interface{}{"username": "alumnus", "role": "alumnus"})
+	stranger := db.PutUser(map[string]interface{}{"username": "stranger", "role": "user"})
+	admin := db.PutUser(map[string]interface{}{"username": "admin", "role": "admin"})
+
+	status, body := doRequest(t, app, http.MethodPut, "/v1/users/"+owner, `{"student_info":{"gpax":3.5}}`, owner, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	status, body = doRequest(t, app, http.MethodPost, "/v1/users/"+owner+"/companies",
+		`{"companies":[{"company":"Acme","position":"Engineer","salary_min":50000,"salary_max":70000}]}`, owner, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	for _, pair := range [][2]string{{owner, friend}, {friend, stranger}} {
+		status, body = doRequest(t, app, http.MethodPost, "/v1/users/"+pair[0]+"/friends", `{"user_id":"`+pair[1]+`"}`, pair[0], "user")
+		require.Equal(t, http.StatusOK, status, body.Message)
+	}
+
+	profile := func(viewer, role, query string) map[string]interface{} {
+		t.Helper()
+
+		status, body := doRequest(t, app, http.MethodGet, "/v1/users/"+owner+query, "", viewer, role)
+		require.Equal(t, http.StatusOK, status, body.Message)
+		var user map[string]interface{}
+		require.NoError(t, json.Unmarshal(body.Data, &user))
+		return user
+	}
+
+	// By default personal and contact details go to friends, education and
+	// career to alumni, and salaries to nobody.
+	user := profile(owner, "alumnus", "")
+	assert.Equal(t, map[string]interface{}{"personal": "friends", "contact": "friends", "education": "alumni", "career": "alumni", "salary": "private"}, user["privacy"])
+	assert.Contains(t, user["companies"].([]interface{})[0], "salary_max")
+
+	user = profile(friend, "user", "")
+	assert.Contains(t, user, "dob")
+	assert.Contains(t, user, "contact_info")
+	assert.Contains(t, user, "student_info")
+	assert.NotContains(t, user, "privacy")
+	assert.NotContains(t, user["companies"].([]interface{})[0], "salary_max")
+
+	user = profile(alumnus, "alumnus", "")
+	assert.NotContains(t, user, "dob")
+	assert.NotContains(t, user, "contact_info")
+	assert.Equal(t, 3.5, user["student_info"].(map[string]interface{})["gpax"])
+
+	user = profile(stranger, "user", "")
+	assert.Equal(t, "Owner One", user["name"])
+	assert.NotContains(t, user, "dob")
+	assert.NotContains(t, user, "gender")
+	assert.NotContains(t, user, "contact_info")
+	assert.NotContains(t, user, "student_info")
+	assert.NotContains(t, user, "college_info")
+	assert.NotContains(t, user, "companies")
+
+	// What alumni see comes with the permission, whatever the base role.
+	registrar := db.PutUser(map[string]interface{}{"username": "registrar", "role": "user"})
+	status, body = doRequest(t, app, http.MethodPut, "/v1/roles/registrar", `{"permissions":["profile:read:alumni"]}`, admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	status, body = doRequest(t, app, http.MethodPost, "/v1/roles/registrar/users/"+registrar, "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	user = profile(registrar, "user", "")
+	assert.Contains(t, user, "student_info")
+	assert.NotContains(t, user, "contact_info")
+
+	// Listing who works at a company shows their career to the caller.
+	status, body = doRequest(t, app, http.MethodGet, "/v1/users/company_associate?company=Acme", "", "", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.NotContains(t, string(body.Data), owner)
+	status, body = doRequest(t, app, http.MethodGet, "/v1/users/company_associate?company=Acme", "", alumnus, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Contains(t, string(body.Data), owner)
+	assert.NotContains(t, string(body.Data), "privacy")
+
+	status, _ = doRequest(t, app, http.MethodPut, "/v1/users/"+owner+"/privacy", `{"contact":"public"}`, stranger, "user")
+	assert.Equal(t, http.StatusForbidden, status)
+	status, _ = doRequest(t, app, http.MethodPut, "/v1/users/"+owner+"/privacy", `{"contact":"everyone"}`, owner, "alumnus")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	status, body = doRequest(t, app, http.MethodPut, "/v1/users/"+owner+"/privacy", `{"contact":"public","education":"private","salary":"alumni"}`, owner, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.JSONEq(t, `{"personal":"friends","contact":"public","education":"private","career":"alumni","salary":"alumni"}`, string(body.Data))
+
+	user = profile(stranger, "user", "")
+	assert.Equal(t, "owner@example.com", user["contact_info"].(map[string]interface{})["email"])
+	user = profile(alumnus, "alumnus", "")
+	assert.NotContains(t, user, "student_info")
+	assert.Contains(t, user["companies"].([]interface{})[0], "salary_max")
+
+	// FOAF paths show each user's contact details as they set them.
+	status, body = doRequest(t, app, http.MethodGet, "/v1/users/"+stranger+"/foaf/"+owner, "", stranger, "user")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.NotContains(t, string(body.Data), "privacy")
+	var foaf []map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &foaf))
+	require.Len(t, foaf, 1)
+	assert.Equal(t, friend, foaf[0]["user_id"])
+	assert.Contains(t, foaf[0], "contact")
+	status, body = doRequest(t, app, http.MethodGet, "/v1/users/"+stranger+"/foaf/"+owner, "", alumnus, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.NotContains(t, string(body.Data), `"contact"`)
+
+	// Salary statistics only count the salaries shared with the caller.
+	status, body = doRequest(t, app, http.MethodGet, "/v1/stat/salary", "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Contains(t, string(body.Data), "70000")
+	status, _ = doRequest(t, app, http.MethodPut, "/v1/users/"+owner+"/privacy", `{"contact":"public","education":"private","salary":"friends"}`, owner, "alumnus")
+	require.Equal(t, http.StatusOK, status)
+	status, body = doRequest(t, app, http.MethodGet, "/v1/stat/salary", "", admin, "admin")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.NotContains(t, string(body.Data), "70000")
+
+	// Admins see hidden fields only by auditing, which the owner is shown.
+	assert.NotContains(t, profile(admin, "admin", ""), "student_info")
+	status, _ = doRequest(t, app, http.MethodGet, "/v1/users/"+owner+"?audit=curious", "", alumnus, "alumnus")
+	assert.Equal(t, http.StatusForbidden, status)
+	user = profile(admin, "admin", "?audit=support%20ticket%2042")
+	assert.Equal(t, 3.5, user["student_info"].(map[string]interface{})["gpax"])
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/auth/security_events", "", owner, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	var events []struct {
+		Type    string `json:"type"`
+		ActorID string `json:"actor_id"`
+		Reason  string `json:"reason"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &events))
+	require.Len(t, events, 1)
+	assert.Equal(t, "profile_audited", events[0].Type)
+	assert.Equal(t, admin, events[0].ActorID)
+	assert.Equal(t, "support ticket 42", events[0].Reason)
+}
+
+func TestSearchEncryptedFields(t *testing.T) {
+	app, db := newTestApp(t)
+
+	profiles := map[string]string{
+		"alice": `{"student_info":{"admit_year":2560,"graduate_year":2564,"gpax":3.6}}`,
+		"bob":   `{"student_info":{"admit_year":2562,"graduate_year":2566,"gpax":3.3}}`,
+		"carol": `{"student_info":{"admit_year":2560,"graduate_year":2564,"gpax":3.1}}`,
+	}
+	positions := map[string]string{"alice": "Software  Engineer", "bob": "Designer", "carol": "Designer"}
+
+	ids := map[string]string{}
+	for _, name := range []string{"alice", "bob", "carol"} {
+		id := db.PutUser(map[string]interface{}{"username": name, "role": "alumnus"})
+		ids[id] = name
+
+		status, body := doRequest(t, app, http.MethodPut, "/v1/users/"+id, profiles[name], id, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+		assert.NotContains(t, string(body.Data), "_index")
+
+		status, body = doRequest(t, app, http.MethodPost, "/v1/users/"+id+"/companies",
+			`{"companies":[{"company":"Acme","position":"`+positions[name]+`"}]}`, id, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+	}
+
+	viewer := db.PutUser(map[string]interface{}{"username": "dave", "role": "alumnus"})
+
+	search := func(query string) []string {
+		t.Helper()
+
+		status, body := doRequest(t, app, http.MethodGet, "/v1/users/search?"+query, "", viewer, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+		assert.NotContains(t, string(body.Data), "_index")
+
+		var users []map[string]interface{}
+		require.NoError(t, json.Unmarshal(body.Data, &users))
+		names := []string{}
+		for _, user := range users {
+			names = append(names, ids[user["user_id"].(string)])
+		}
+		sort.Strings(names)
+		return names
+	}
+
+	assert.Equal(t, []string{"alice", "carol"}, search("graduate_year_from=2564&graduate_year_to=2564"))
+	assert.Equal(t, []string{"bob"}, search("admit_year_from=2561"))
+	assert.Equal(t, []string{"alice", "bob"}, search("gpax_min=3.3"))
+	// Bob's 3.3 shares a quarter point bucket with 3.4, so only the
+	// decrypted value leaves him out.
+	assert.Equal(t, []string{"alice"}, search("gpax_min=3.4"))
+	assert.Equal(t, []string{"alice"}, search("company=Acme&position=software%20engineer"))
+	assert.Equal(t, []string{"carol"}, search("position=Designer&gpax_max=3.2"))
+	assert.Equal(t, []string{}, search("company=Initech&position=Designer"))
+
+	status, _ := doRequest(t, app, http.MethodGet, "/v1/users/search?gpax_min=3.5&gpax_max=3", "", "", "")
+	assert.Equal(t, http.StatusBadRequest, status)
+
+	// Education is shown to alumni only by default, so searching it
+	// anonymously would give it away.
+	status, body := doRequest(t, app, http.MethodGet, "/v1/users/search?gpax_min=3.3", "", "", "")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.JSONEq(t, `[]`, string(body.Data))
 }
 
 func BenchmarkGetUser(b *testing.B) {
-	// Benchmark user fetch handler
+	app, db := newTestApp(b)
+	userID := db.PutUser(map[string]interface{}{"username": "bench", "role": "user"})
+
 	for i := 0; i < b.N; i++ {
-		// Call the handler in a mock setup
+		doRequest(b, app, http.MethodGet, "/v1/users/"+userID, "", userID, "user")
 	}
 }
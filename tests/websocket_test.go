@@ -0,0 +1,198 @@
+package tests
+
+import (
+	"alumni_api/internal/websockets"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dial opens a hub connection for the user.
+func dial(t testing.TB, app *fiber.App, addr, userID, role string) *websocket.Conn {
+	t.Helper()
+
+	token := accessToken(t, app, userID, role)
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+addr+"/v1/ws?token="+token, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn
+}
+
+func readFrame(t testing.TB, conn *websocket.Conn) websockets.Envelope {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+
+	var env websockets.Envelope
+	require.NoError(t, conn.ReadJSON(&env))
+	return env
+}
+
+func TestWebSocketDelivery(t *testing.T) {
+	app, db, hub := newTestAppWithHub(t)
+	addr := serve(t, app)
+
+	alice := db.PutUser(map[string]interface{}{"username": "alice", "first_name": "Alice", "last_name": "A", "role": "alumnus"})
+	bob := db.PutUser(map[string]interface{}{"username": "bob", "first_name": "Bob", "last_name": "B", "role": "alumnus"})
+
+	laptop := dial(t, app, addr, alice, "alumnus")
+	phone := dial(t, app, addr, alice, "alumnus")
+	require.Eventually(t, func() bool { return hub.Connections(alice) == 2 }, time.Second, 10*time.Millisecond)
+
+	status, body := doRequest(t, app, http.MethodPost, "/v1/user/"+bob+"/message/send",
+		`{"receiver_id":"`+alice+`","content":"Are you coming to the reunion?"}`, bob, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+	assert.Equal(t, 2, hub.Pending(alice))
+
+	for _, conn := range []*websocket.Conn{laptop, phone} {
+		env := readFrame(t, conn)
+		require.Equal(t, websockets.TypeMessage, env.Type)
+		require.NotEmpty(t, env.ID)
+
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(env.Payload, &msg))
+		assert.Equal(t, "Are you coming to the reunion?", msg["content"])
+		assert.Equal(t, bob, msg["sender_id"])
+
+		require.NoError(t, conn.WriteJSON(websockets.Envelope{Type: websockets.TypeAck, ID: env.ID}))
+	}
+	require.Eventually(t, func() bool { return hub.Pending(alice) == 0 }, time.Second, 10*time.Millisecond)
+
+	// Rooms are limited to their members and every client frame is answered.
+	require.NoError(t, laptop.WriteJSON(map[string]interface{}{
+		"type": websockets.TypeSubscribe, "id": "join-other", "payload": map[string]string{"room": websockets.DirectRoom(bob, "someone-else")},
+	}))
+	env := readFrame(t, laptop)
+	assert.Equal(t, websockets.TypeError, env.Type)
+	assert.Equal(t, "join-other", env.ID)
+
+	require.NoError(t, laptop.WriteJSON(map[string]interface{}{
+		"type": websockets.TypeSubscribe, "id": "join-own", "payload": map[string]string{"room": websockets.DirectRoom(alice, bob)},
+	}))
+	env = readFrame(t, laptop)
+	assert.Equal(t, websockets.TypeAck, env.Type)
+	assert.Equal(t, "join-own", env.ID)
+
+	require.NoError(t, laptop.WriteJSON(map[string]interface{}{"type": "no-such-type", "id": "bogus"}))
+	env = readFrame(t, laptop)
+	assert.Equal(t, websockets.TypeError, env.Type)
+
+	// Closing one tab keeps the other connected.
+	phone.Close()
+	require.Eventually(t, func() bool { return hub.Connections(alice) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestReadReceiptsAndPresence(t *testing.T) {
+	app, db, _ := newTestAppWithHub(t)
+	addr := serve(t, app)
+
+	alice := db.PutUser(map[string]interface{}{"username": "alice", "first_name": "Alice", "last_name": "A", "role": "alumnus"})
+	bob := db.PutUser(map[string]interface{}{"username": "bob", "first_name": "Bob", "last_name": "B", "role": "alumnus"})
+
+	status, body := doRequest(t, app, http.MethodPost, "/v1/users/"+alice+"/friends", `{"user_id":"`+bob+`"}`, alice, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	bobConn := dial(t, app, addr, bob, "alumnus")
+	aliceConn := dial(t, app, addr, alice, "alumnus")
+
+	var presence websockets.PresencePayload
+	env := readFrame(t, bobConn)
+	require.Equal(t, websockets.TypePresence, env.Type)
+	require.NoError(t, json.Unmarshal(env.Payload, &presence))
+	assert.Equal(t, alice, presence.UserID)
+	assert.True(t, presence.Online)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/user/"+bob+"/friends/online", "", bob, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var online []map[string]interface{}
+	require.NoError(t, json.Unmarshal(body.Data, &online))
+	require.Len(t, online, 1)
+	assert.Equal(t, alice, online[0]["user_id"])
+
+	require.NoError(t, aliceConn.WriteJSON(map[string]interface{}{
+		"type": websockets.TypeTyping, "payload": map[string]interface{}{"user_id": bob, "typing": true},
+	}))
+	env = readFrame(t, bobConn)
+	require.Equal(t, websockets.TypeTyping, env.Type)
+	assert.Empty(t, env.ID)
+	assert.JSONEq(t, `{"user_id":"`+alice+`","typing":true}`, string(env.Payload))
+
+	// Strangers cannot push typing indicators at users.
+	carol := db.PutUser(map[string]interface{}{"username": "carol", "first_name": "Carol", "last_name": "C", "role": "alumnus"})
+	carolConn := dial(t, app, addr, carol, "alumnus")
+	require.NoError(t, carolConn.WriteJSON(map[string]interface{}{
+		"type": websockets.TypeTyping, "id": "typing-stranger", "payload": map[string]interface{}{"user_id": bob, "typing": true},
+	}))
+	env = readFrame(t, carolConn)
+	assert.Equal(t, websockets.TypeError, env.Type)
+	assert.Equal(t, "typing-stranger", env.ID)
+
+	var messageIDs []string
+	for _, content := range []string{"Hi Alice", "Lunch tomorrow?"} {
+		status, body := doRequest(t, app, http.MethodPost, "/v1/user/"+bob+"/message/send",
+			`{"receiver_id":"`+alice+`","content":"`+content+`"}`, bob, "alumnus")
+		require.Equal(t, http.StatusOK, status, body.Message)
+
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(body.Data, &msg))
+		messageIDs = append(messageIDs, msg["message_id"].(string))
+
+		for _, conn := range []*websocket.Conn{aliceConn, bobConn} {
+			require.Equal(t, websockets.TypeMessage, readFrame(t, conn).Type)
+		}
+	}
+
+	// Reading the latest message marks the earlier one as read too.
+	require.NoError(t, aliceConn.WriteJSON(map[string]interface{}{
+		"type": websockets.TypeRead, "id": "read-1", "payload": map[string]string{"message_id": messageIDs[1]},
+	}))
+
+	env = readFrame(t, bobConn)
+	require.Equal(t, websockets.TypeRead, env.Type)
+
+	var receipt map[string]interface{}
+	require.NoError(t, json.Unmarshal(env.Payload, &receipt))
+	assert.Equal(t, alice, receipt["reader_id"])
+	assert.ElementsMatch(t, messageIDs, receipt["message_ids"])
+
+	assert.Equal(t, websockets.TypeRead, readFrame(t, aliceConn).Type)
+	env = readFrame(t, aliceConn)
+	assert.Equal(t, websockets.TypeAck, env.Type)
+	assert.Equal(t, "read-1", env.ID)
+
+	status, body = doRequest(t, app, http.MethodGet, "/v1/user/"+bob+"/chat_message/"+alice, "", bob, "alumnus")
+	require.Equal(t, http.StatusOK, status, body.Message)
+
+	var chat struct {
+		Me []struct {
+			Message map[string]interface{} `json:"message"`
+		} `json:"me"`
+	}
+	require.NoError(t, json.Unmarshal(body.Data, &chat))
+	require.Len(t, chat.Me, 2)
+	for _, entry := range chat.Me {
+		assert.NotNil(t, entry.Message["read_at"])
+	}
+
+	// Only the receiver can mark a message as read.
+	require.NoError(t, bobConn.WriteJSON(map[string]interface{}{
+		"type": websockets.TypeRead, "id": "read-own", "payload": map[string]string{"message_id": messageIDs[0]},
+	}))
+	env = readFrame(t, bobConn)
+	assert.Equal(t, websockets.TypeError, env.Type)
+
+	aliceConn.Close()
+	env = readFrame(t, bobConn)
+	require.Equal(t, websockets.TypePresence, env.Type)
+	require.NoError(t, json.Unmarshal(env.Payload, &presence))
+	assert.False(t, presence.Online)
+	assert.NotZero(t, presence.LastSeen)
+}